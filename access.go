@@ -0,0 +1,243 @@
+package union
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// As returns the value of the active variant in the union as type T.
+// It reports false if no variant is set, multiple variants are set, or the
+// active variant is not of type T.
+//
+// Pointer and non-pointer variant fields are handled transparently:
+// a *Circle field can be read as Circle and a Circle field can be read as *Circle
+// (which points to a copy of the variant's value).
+func As[T any](u interface{ GetValue() any }) (T, bool) {
+	return as[T](u.GetValue())
+}
+
+// as converts a variant value to type T, dereferencing or addressing it as needed.
+func as[T any](value any) (T, bool) {
+	var zero T
+	if value == nil {
+		return zero, false
+	}
+	if v, ok := value.(T); ok {
+		return v, true
+	}
+
+	v := reflect.ValueOf(value)
+	t := reflect.TypeFor[T]()
+	switch {
+	case v.Kind() == reflect.Pointer && v.Type().Elem() == t:
+		return v.Elem().Interface().(T), true
+	case t.Kind() == reflect.Pointer && t.Elem() == v.Type():
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		return ptr.Interface().(T), true
+	}
+	return zero, false
+}
+
+// Is reports whether the active variant in the union is of type T.
+// Like As, it treats pointer and non-pointer variant fields the same.
+func Is[T any](u interface{ GetValue() any }) bool {
+	_, ok := As[T](u)
+	return ok
+}
+
+// GetPtr returns a pointer to the payload of the active variant in the union if it is of
+// type T, or nil otherwise, so the payload can be modified in place without copying it
+// out and setting it again:
+//
+//	if circle := union.GetPtr[Circle](&shape); circle != nil {
+//		circle.Radius *= 2
+//	}
+//
+// The pointer of a *T variant field is returned as is, and a T variant field is addressed
+// inside the union. Like As, the union must hold exactly one variant, or a variant made
+// active by Select.
+func GetPtr[T any](u interface {
+	specValue() reflect.Value
+	selectedField() *fieldPlan
+}) *T {
+	ptr, ok := activePtr(u.specValue(), u.selectedField())
+	if !ok || ptr.Type().Elem() != reflect.TypeFor[T]() {
+		return nil
+	}
+	return ptr.Interface().(*T)
+}
+
+// Ptr returns a pointer to the payload of the active variant, such as a *Circle for both
+// *Circle and Circle variant fields, or nil if no variant or multiple variants are set.
+// See GetPtr.
+func (u *TaggedUnion[Spec]) Ptr() any {
+	return ptrOrNil(activePtr(u.specValue(), u.selected))
+}
+
+// Ptr returns a pointer to the payload of the active variant. See TaggedUnion.Ptr.
+func (u *ExternallyTagged[Spec]) Ptr() any {
+	return ptrOrNil(activePtr(u.specValue(), u.selected))
+}
+
+// Ptr returns a pointer to the payload of the active variant. See TaggedUnion.Ptr.
+func (u *Union[Spec]) Ptr() any {
+	return ptrOrNil(activePtr(u.specValue(), u.selected))
+}
+
+// activePtr returns a pointer to the payload of the active field of the addressable
+// spec value v: the field itself for pointer fields, or its address otherwise.
+func activePtr(v reflect.Value, selected *fieldPlan) (reflect.Value, bool) {
+	f, err := planOf(v.Type()).current(v, selected)
+	if err != nil {
+		return reflect.Value{}, false
+	}
+	fv := v.FieldByIndex(f.index)
+	if f.pointer {
+		return fv, true
+	}
+	return fv.Addr(), true
+}
+
+func ptrOrNil(ptr reflect.Value, ok bool) any {
+	if !ok {
+		return nil
+	}
+	return ptr.Interface()
+}
+
+// Set makes value the active variant of the union. It assigns value to the spec
+// field whose type matches value's type and clears all other variant fields.
+//
+// Pointer and non-pointer variant fields are handled transparently:
+// a Circle can be assigned to a *Circle field (as a pointer to a copy) and
+// a *Circle can be assigned to a Circle field (dereferenced).
+//
+// Returns an error and leaves the union unchanged if:
+//   - The Spec type is not a struct
+//   - The value is zero (it would not be a valid active variant)
+//   - No field matches the value's type
+//   - Multiple fields match the value's type
+func Set[Spec any](u interface {
+	spec() *Spec
+	setSelected(f *fieldPlan)
+}, value any) error {
+	v := reflect.ValueOf(u.spec()).Elem()
+	p := planOf(v.Type())
+
+	if !p.isStruct {
+		return ErrSpecNotStruct
+	}
+
+	vv := reflect.ValueOf(value)
+	if !vv.IsValid() || isZeroValue(vv) {
+		return ErrZeroVariants
+	}
+
+	f, err := p.match(vv.Type())
+	if err != nil {
+		return err
+	}
+
+	p.clear(v)
+	v.FieldByIndex(f.index).Set(adapt(f, vv))
+	u.setSelected(nil)
+	return nil
+}
+
+// SetVariant makes value the active variant named variant, clearing all other variant fields.
+// Unlike Set, the spec field is located by its variant name, so it can be used
+// when several variants share the same type. Values are adapted to pointer and
+// non-pointer fields as in Set.
+//
+// Returns an error and leaves the union unchanged if:
+//   - The Spec type is not a struct
+//   - No field declares the variant (*UnknownVariantError)
+//   - Multiple fields declare the variant (invalid Spec definition)
+//   - The value is zero (it would not be a valid active variant)
+//   - The value's type does not match the variant field
+func SetVariant[Spec any](u interface {
+	spec() *Spec
+	setSelected(f *fieldPlan)
+}, variant string, value any) error {
+	v := reflect.ValueOf(u.spec()).Elem()
+	p := planOf(v.Type())
+
+	if !p.isStruct {
+		return ErrSpecNotStruct
+	}
+
+	f, err := p.lookup(variant)
+	if err != nil {
+		return err
+	}
+	if err := setField(p, v, f, value); err != nil {
+		return err
+	}
+	u.setSelected(nil)
+	return nil
+}
+
+// setField makes value the only non-zero variant field f of the spec struct value v.
+func setField(p *specPlan, v reflect.Value, f *fieldPlan, value any) error {
+	vv := reflect.ValueOf(value)
+	if !vv.IsValid() || isZeroValue(vv) {
+		return ErrZeroVariants
+	}
+	if !f.accepts(vv.Type()) {
+		return fmt.Errorf("%w: %s", ErrNoFieldMatched, f.variant)
+	}
+
+	p.clear(v)
+	v.FieldByIndex(f.index).Set(adapt(f, vv))
+	return nil
+}
+
+// mustActive returns the active field of the spec value v, panicking with
+// the spec type and the reason if there is none.
+func mustActive(v reflect.Value, selected *fieldPlan) *fieldPlan {
+	f, err := planOf(v.Type()).current(v, selected)
+	if err != nil {
+		panic(fmt.Sprintf("union: %v: %v", v.Type(), err))
+	}
+	return f
+}
+
+// adapt converts vv to the type of field f, addressing or dereferencing it as needed.
+// The caller must ensure the field accepts vv's type.
+func adapt(f *fieldPlan, vv reflect.Value) reflect.Value {
+	switch {
+	case vv.Type() == f.typ:
+		return vv
+	case f.pointer:
+		ptr := reflect.New(vv.Type())
+		ptr.Elem().Set(vv)
+		return ptr
+	default:
+		return vv.Elem()
+	}
+}
+
+// New returns a Union with value set as its active variant.
+// See Set for how the matching spec field is located.
+func New[Spec any](value any) (Union[Spec], error) {
+	var u Union[Spec]
+	err := Set(&u, value)
+	return u, err
+}
+
+// NewTagged returns a TaggedUnion with value set as its active variant.
+// See Set for how the matching spec field is located.
+func NewTagged[Spec any](value any) (TaggedUnion[Spec], error) {
+	var u TaggedUnion[Spec]
+	err := Set(&u, value)
+	return u, err
+}
+
+// NewExternallyTagged returns an ExternallyTagged with value set as its active variant.
+// See Set for how the matching spec field is located.
+func NewExternallyTagged[Spec any](value any) (ExternallyTagged[Spec], error) {
+	var u ExternallyTagged[Spec]
+	err := Set(&u, value)
+	return u, err
+}
@@ -0,0 +1,308 @@
+package union
+
+import (
+	"errors"
+	"testing"
+)
+
+type Shaper interface{ isShape() }
+
+func (c Circle) isShape() {}
+
+func TestAs(t *testing.T) {
+	circle := &Circle{Radius: 5.0}
+
+	t.Run("returns value from pointer variant", func(t *testing.T) {
+		shape := TaggedUnion[Shape]{Value: Shape{Circle: circle}}
+		c, ok := As[Circle](shape)
+		if !ok || c != *circle {
+			t.Errorf("expected %+v, got %+v (ok=%v)", *circle, c, ok)
+		}
+	})
+
+	t.Run("returns pointer from pointer variant", func(t *testing.T) {
+		shape := TaggedUnion[Shape]{Value: Shape{Circle: circle}}
+		c, ok := As[*Circle](shape)
+		if !ok || c != circle {
+			t.Errorf("expected %p, got %p (ok=%v)", circle, c, ok)
+		}
+	})
+
+	t.Run("returns pointer from non-pointer variant", func(t *testing.T) {
+		shape := Union[UnionNonPointerShape]{Value: UnionNonPointerShape{Circle: *circle}}
+		c, ok := As[*Circle](shape)
+		if !ok || *c != *circle {
+			t.Errorf("expected %+v, got %+v (ok=%v)", circle, c, ok)
+		}
+	})
+
+	t.Run("returns interface implemented by variant", func(t *testing.T) {
+		shape := Union[UnionNonPointerShape]{Value: UnionNonPointerShape{Circle: *circle}}
+		if _, ok := As[Shaper](shape); !ok {
+			t.Error("expected variant to implement Shaper")
+		}
+	})
+
+	t.Run("returns false for other variant type", func(t *testing.T) {
+		shape := TaggedUnion[Shape]{Value: Shape{Circle: circle}}
+		if r, ok := As[Rectangle](shape); ok {
+			t.Errorf("expected false, got %+v", r)
+		}
+	})
+
+	t.Run("returns false when no variant is set", func(t *testing.T) {
+		if _, ok := As[Circle](ExternallyTagged[Shape]{}); ok {
+			t.Error("expected false for zero union")
+		}
+	})
+}
+
+func TestIs(t *testing.T) {
+	shape := TaggedUnion[Shape]{Value: Shape{Rectangle: &Rectangle{Width: 10, Height: 5}}}
+
+	if !Is[Rectangle](shape) {
+		t.Error("expected shape to be Rectangle")
+	}
+	if !Is[*Rectangle](shape) {
+		t.Error("expected shape to be *Rectangle")
+	}
+	if Is[Circle](shape) {
+		t.Error("expected shape not to be Circle")
+	}
+	if Is[Circle](TaggedUnion[Shape]{}) {
+		t.Error("expected zero union not to be Circle")
+	}
+}
+
+func TestGetPtr(t *testing.T) {
+	t.Run("returns pointer of pointer variant", func(t *testing.T) {
+		circle := &Circle{Radius: 5.0}
+		shape := TaggedUnion[Shape]{Value: Shape{Circle: circle}}
+		if c := GetPtr[Circle](&shape); c != circle {
+			t.Errorf("expected %p, got %p", circle, c)
+		}
+	})
+
+	t.Run("modifies non-pointer variant in place", func(t *testing.T) {
+		shape := Union[UnionNonPointerShape]{Value: UnionNonPointerShape{Rectangle: Rectangle{Width: 2, Height: 3}}}
+		GetPtr[Rectangle](&shape).Width = 4
+		if shape.Value.Rectangle.Width != 4 {
+			t.Errorf("expected 4, got %v", shape.Value.Rectangle.Width)
+		}
+	})
+
+	t.Run("modifies selected zero variant", func(t *testing.T) {
+		var shape ExternallyTagged[NonPointerShape]
+		if err := shape.Select("rectangle"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		GetPtr[Rectangle](&shape).Height = 1
+		if v := shape.MustGetValue(); v != (Rectangle{Height: 1}) {
+			t.Errorf("expected %+v, got %+v", Rectangle{Height: 1}, v)
+		}
+	})
+
+	t.Run("returns nil for other variant type", func(t *testing.T) {
+		shape := TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 5.0}}}
+		if r := GetPtr[Rectangle](&shape); r != nil {
+			t.Errorf("expected nil, got %+v", r)
+		}
+		if c := GetPtr[*Circle](&shape); c != nil {
+			t.Errorf("expected nil, got %+v", c)
+		}
+	})
+
+	t.Run("returns nil when no variant is set", func(t *testing.T) {
+		if c := GetPtr[Circle](&TaggedUnion[Shape]{}); c != nil {
+			t.Errorf("expected nil, got %+v", c)
+		}
+	})
+}
+
+func TestPtr(t *testing.T) {
+	circle := &Circle{Radius: 5.0}
+	shape := TaggedUnion[Shape]{Value: Shape{Circle: circle}}
+	if p := shape.Ptr(); p != circle {
+		t.Errorf("expected %p, got %v", circle, p)
+	}
+
+	nonPointer := Union[UnionNonPointerShape]{Value: UnionNonPointerShape{Circle: *circle}}
+	if p, ok := nonPointer.Ptr().(*Circle); !ok || p != &nonPointer.Value.Circle {
+		t.Errorf("expected %p, got %v", &nonPointer.Value.Circle, nonPointer.Ptr())
+	}
+
+	if p := (&ExternallyTagged[Shape]{}).Ptr(); p != nil {
+		t.Errorf("expected nil, got %v", p)
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       any
+		expected    any
+		expectedErr error
+	}{
+		{
+			name:     "sets pointer variant from pointer",
+			value:    &Circle{Radius: 5.0},
+			expected: Circle{Radius: 5.0},
+		},
+		{
+			name:     "sets pointer variant from value",
+			value:    Rectangle{Width: 10, Height: 5},
+			expected: Rectangle{Width: 10, Height: 5},
+		},
+		{
+			name:        "returns error for unknown type",
+			value:       "hexagon",
+			expectedErr: ErrNoFieldMatched,
+		},
+		{
+			name:        "returns error for zero value",
+			value:       (*Circle)(nil),
+			expectedErr: ErrZeroVariants,
+		},
+		{
+			name:        "returns error for nil",
+			value:       nil,
+			expectedErr: ErrZeroVariants,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shape := TaggedUnion[Shape]{Value: Shape{Triangle: &Triangle{Base: 8, Height: 4}}}
+			err := Set(&shape, tt.value)
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Fatalf("expected error '%v', got '%v'", tt.expectedErr, err)
+				}
+				assertValueEquals(t, shape.GetValue(), Triangle{Base: 8, Height: 4})
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertValueEquals(t, shape.GetValue(), tt.expected)
+		})
+	}
+}
+
+func TestSetNonPointerField(t *testing.T) {
+	var shape Union[UnionNonPointerShape]
+	if err := Set(&shape, &Circle{Radius: 5.0}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, shape.GetValue(), Circle{Radius: 5.0})
+
+	if err := Set(&shape, Rectangle{Width: 10, Height: 5}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, shape.GetValue(), Rectangle{Width: 10, Height: 5})
+}
+
+func TestSetErrors(t *testing.T) {
+	var dup TaggedUnion[DuplicateVariantShape]
+	if err := Set(&dup, &Circle{Radius: 5.0}); !errors.Is(err, ErrMultipleFieldsMatched) {
+		t.Errorf("expected error '%v', got '%v'", ErrMultipleFieldsMatched, err)
+	}
+
+	var nonStruct ExternallyTagged[NonStructType]
+	if err := Set(&nonStruct, 1); !errors.Is(err, ErrSpecNotStruct) {
+		t.Errorf("expected error '%v', got '%v'", ErrSpecNotStruct, err)
+	}
+}
+
+func TestSetVariant(t *testing.T) {
+	var shape TaggedUnion[Shape]
+	if err := SetVariant(&shape, "circle", Circle{Radius: 5.0}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, shape.GetValue(), Circle{Radius: 5.0})
+
+	if err := SetVariant(&shape, "triangle", &Triangle{Base: 8, Height: 4}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, shape.GetValue(), Triangle{Base: 8, Height: 4})
+
+	if err := SetVariant(&shape, "hexagon", Circle{Radius: 5.0}); !errors.Is(err, ErrUnknownVariant) {
+		t.Errorf("expected error '%v', got '%v'", ErrUnknownVariant, err)
+	}
+	if err := SetVariant(&shape, "circle", Rectangle{Width: 1}); !errors.Is(err, ErrNoFieldMatched) {
+		t.Errorf("expected error '%v', got '%v'", ErrNoFieldMatched, err)
+	}
+	if err := SetVariant(&shape, "circle", Circle{}); !errors.Is(err, ErrZeroVariants) {
+		t.Errorf("expected error '%v', got '%v'", ErrZeroVariants, err)
+	}
+	assertValueEquals(t, shape.GetValue(), Triangle{Base: 8, Height: 4})
+}
+
+func TestNew(t *testing.T) {
+	u, err := New[UnionShape](Rectangle{Width: 10, Height: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, u.GetValue(), Rectangle{Width: 10, Height: 5})
+
+	tu, err := NewTagged[Shape](&Circle{Radius: 5.0})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, tu.GetValue(), Circle{Radius: 5.0})
+
+	et, err := NewExternallyTagged[Shape](Triangle{Base: 8, Height: 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, et.GetValue(), Triangle{Base: 8, Height: 4})
+
+	if _, err := NewTagged[Shape]("hexagon"); !errors.Is(err, ErrNoFieldMatched) {
+		t.Errorf("expected error '%v', got '%v'", ErrNoFieldMatched, err)
+	}
+}
+
+func TestMustGetValue(t *testing.T) {
+	shape := TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 5.0}}}
+	assertValueEquals(t, shape.MustGetValue(), Circle{Radius: 5.0})
+	if variant := shape.MustVariant(); variant != "circle" {
+		t.Errorf("expected variant circle, got %q", variant)
+	}
+
+	tests := []struct {
+		name     string
+		call     func()
+		expected string
+	}{
+		{
+			name:     "zero variants",
+			call:     func() { TaggedUnion[Shape]{}.MustGetValue() },
+			expected: "union: union.Shape: zero variants set",
+		},
+		{
+			name: "multiple variants",
+			call: func() {
+				ExternallyTagged[Shape]{Value: Shape{Circle: &Circle{}, Rectangle: &Rectangle{}}}.MustVariant()
+			},
+			expected: "union: union.Shape: multiple variants set",
+		},
+		{
+			name:     "untagged zero variants",
+			call:     func() { Union[UnionNonPointerShape]{}.MustVariant() },
+			expected: "union: union.UnionNonPointerShape: zero variants set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != tt.expected {
+					t.Errorf("expected panic '%v', got '%v'", tt.expected, r)
+				}
+			}()
+			tt.call()
+		})
+	}
+}
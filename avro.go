@@ -0,0 +1,99 @@
+package union
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// Avro encodes a union as the zero-based index of its branch in the schema
+// followed by the branch's value. The JSON encoding instead writes an object
+// with a single key naming the branch type, which is the ExternallyTagged
+// representation when variant names match the Avro type names.
+//
+// For the binary encoding the branch index is the position of the variant's
+// field among the variant fields of the Spec struct, so spec fields should be declared in the same
+// order as the branches of the union schema. The value itself is encoded by
+// the Avro library using the branch's schema.
+
+// AvroBranch returns the Avro branch index of the active variant, which is the
+// position of its field among the variant fields of the Spec struct, Raw fields excluded.
+// A variant chosen with Select is active while no field is set. It reports
+// false if no variant is active or multiple fields are set.
+func AvroBranch[Spec any](u interface {
+	spec() *Spec
+	selectedField() *fieldPlan
+}) (int, bool) {
+	v := reflect.ValueOf(u.spec()).Elem()
+	p := planOf(v.Type())
+	f, err := p.current(v, u.selectedField())
+	if err != nil {
+		return 0, false
+	}
+	return slices.Index(avroBranches(p), f), true
+}
+
+// avroBranches returns the fields of the Avro branches of the spec, which are its fields other than Raw.
+func avroBranches(p *specPlan) []*fieldPlan {
+	var branches []*fieldPlan
+	for i := range p.fields {
+		if !p.fields[i].raw {
+			branches = append(branches, &p.fields[i])
+		}
+	}
+	return branches
+}
+
+// SetAvroBranch makes value the active variant at the given Avro branch index,
+// clearing all other variant fields. Values are adapted to pointer and non-pointer
+// fields as in Set.
+//
+// Returns an error and leaves the union unchanged if:
+//   - The Spec type is not a struct
+//   - The index is out of range (ErrUnknownVariant)
+//   - The value is zero (it would not be a valid active variant)
+//   - The value's type does not match the branch's field
+func SetAvroBranch[Spec any](u interface {
+	spec() *Spec
+	setSelected(f *fieldPlan)
+}, index int, value any) error {
+	v := reflect.ValueOf(u.spec()).Elem()
+	p := planOf(v.Type())
+
+	if !p.isStruct {
+		return ErrSpecNotStruct
+	}
+	branches := avroBranches(p)
+	if index < 0 || index >= len(branches) {
+		return fmt.Errorf("%w: branch %d", ErrUnknownVariant, index)
+	}
+	if err := setField(p, v, branches[index], value); err != nil {
+		return err
+	}
+	u.setSelected(nil)
+	return nil
+}
+
+// AppendAvroBranch appends the Avro binary encoding of a union branch index,
+// a zigzag varint long, to dst.
+func AppendAvroBranch(dst []byte, index int) []byte {
+	return binary.AppendVarint(dst, int64(index))
+}
+
+// ReadAvroBranch reads the Avro binary union branch index at the start of data.
+// It returns the index and the number of bytes read, the branch's value
+// starts at data[n:].
+func ReadAvroBranch(data []byte) (index, n int, err error) {
+	i, n := binary.Varint(data)
+	switch {
+	case n == 0:
+		return 0, 0, errors.New("avro: unexpected end of data")
+	case n < 0:
+		return 0, 0, errors.New("avro: branch index overflows long")
+	case i < 0:
+		return 0, 0, fmt.Errorf("avro: negative branch index %d", i)
+	}
+	return int(i), n, nil
+}
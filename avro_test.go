@@ -0,0 +1,92 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type RawFirstShape struct {
+	Unknown   *Raw
+	Circle    *Circle    `variant:"circle"`
+	Rectangle *Rectangle `variant:"rectangle"`
+}
+
+func TestAvroBranch(t *testing.T) {
+	shape := TaggedUnion[Shape]{Value: Shape{Rectangle: &Rectangle{Width: 10, Height: 5}}}
+	if index, ok := AvroBranch(&shape); !ok || index != 1 {
+		t.Errorf("expected branch 1, got %d (%v)", index, ok)
+	}
+
+	var empty TaggedUnion[Shape]
+	if _, ok := AvroBranch(&empty); ok {
+		t.Error("expected no branch for zero union")
+	}
+
+	var selected TaggedUnion[NonPointerShape]
+	if err := selected.Select("rectangle"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if index, ok := AvroBranch(&selected); !ok || index != 1 {
+		t.Errorf("expected branch 1 for selected zero variant, got %d (%v)", index, ok)
+	}
+
+	raw := TaggedUnion[RawFirstShape]{Value: RawFirstShape{Rectangle: &Rectangle{Width: 1}}}
+	if index, ok := AvroBranch(&raw); !ok || index != 1 {
+		t.Errorf("expected branch 1 after raw field, got %d (%v)", index, ok)
+	}
+	if err := SetAvroBranch(&raw, 0, Circle{Radius: 5}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, raw.GetValue(), Circle{Radius: 5})
+}
+
+func TestSetAvroBranch(t *testing.T) {
+	var shape ExternallyTagged[Shape]
+	if err := SetAvroBranch(&shape, 2, Triangle{Base: 8, Height: 4}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, shape.GetValue(), Triangle{Base: 8, Height: 4})
+
+	// the JSON encoding of an Avro union is the externally tagged representation
+	data, err := json.Marshal(shape)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `{"triangle":{"base":8,"height":4}}`; string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	if err := SetAvroBranch(&shape, 3, Circle{Radius: 5}); !errors.Is(err, ErrUnknownVariant) {
+		t.Errorf("expected error '%v', got '%v'", ErrUnknownVariant, err)
+	}
+	if err := SetAvroBranch(&shape, 0, Rectangle{Width: 1}); !errors.Is(err, ErrNoFieldMatched) {
+		t.Errorf("expected error '%v', got '%v'", ErrNoFieldMatched, err)
+	}
+	assertValueEquals(t, shape.GetValue(), Triangle{Base: 8, Height: 4})
+}
+
+func TestAvroBranchEncoding(t *testing.T) {
+	for _, index := range []int{0, 1, 2, 63, 64, 1000} {
+		data := AppendAvroBranch([]byte{0xff}, index)[1:]
+		got, n, err := ReadAvroBranch(append(data, 0xaa))
+		if err != nil {
+			t.Fatalf("unexpected error for %d: %v", index, err)
+		}
+		if got != index || n != len(data) {
+			t.Errorf("expected %d (%d bytes), got %d (%d bytes)", index, len(data), got, n)
+		}
+	}
+
+	// zigzag encoding: 0 -> 0x00, 1 -> 0x02, 2 -> 0x04
+	if data := AppendAvroBranch(nil, 2); len(data) != 1 || data[0] != 0x04 {
+		t.Errorf("expected [0x04], got %x", data)
+	}
+
+	if _, _, err := ReadAvroBranch(nil); err == nil || err.Error() != "avro: unexpected end of data" {
+		t.Errorf("expected unexpected end of data, got %v", err)
+	}
+	if _, _, err := ReadAvroBranch([]byte{0x01}); err == nil || err.Error() != "avro: negative branch index -1" {
+		t.Errorf("expected negative branch index, got %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+package union
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// The binary encoding of a union, used by MarshalBinary and UnmarshalBinary, is compact
+// and self-delimiting so union values can be stored in caches such as Redis, groupcache
+// or bigcache as is:
+//
+//	version    byte, binaryVersion
+//	field      uvarint, position of the variant's field among the spec fields plus one, or 0 for null
+//	variant    uvarint length and bytes, the variant name when it differs from the field's,
+//	           as for Raw fields and registered implementations, or empty
+//	payload    uvarint length and bytes, the JSON encoding of the payload
+//
+// Like the Avro branch index, the field position ties the encoding to the declaration
+// order of the spec fields, so fields should only be appended to specs of stored values.
+
+// binaryVersion is the version of the binary encoding written by MarshalBinary.
+const binaryVersion = 1
+
+// errBinaryData is returned when binary data is truncated or otherwise malformed.
+var errBinaryData = errors.New("malformed binary union data")
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface, encoding the union
+// as the position of its variant's field in the spec followed by its JSON payload.
+// Returns the errors of MarshalJSON for unions without exactly one variant.
+func (u TaggedUnion[Spec]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(reflect.ValueOf(u.Value), u.selected)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface, decoding data
+// written by MarshalBinary.
+func (u *TaggedUnion[Spec]) UnmarshalBinary(data []byte) error {
+	var zero Spec
+	u.Value = zero
+	var err error
+	u.selected, err = unmarshalBinary(u.specValue(), data)
+	return err
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. See TaggedUnion.MarshalBinary.
+func (u ExternallyTagged[Spec]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(reflect.ValueOf(u.Value), u.selected)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface. See TaggedUnion.UnmarshalBinary.
+func (u *ExternallyTagged[Spec]) UnmarshalBinary(data []byte) error {
+	var zero Spec
+	u.Value = zero
+	var err error
+	u.selected, err = unmarshalBinary(u.specValue(), data)
+	return err
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. See TaggedUnion.MarshalBinary.
+// Unlike JSON, the encoding names the variant, so untagged unions decode into the variant they held.
+func (u Union[Spec]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(reflect.ValueOf(u.Value), u.selected)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface. See TaggedUnion.UnmarshalBinary.
+func (u *Union[Spec]) UnmarshalBinary(data []byte) error {
+	var zero Spec
+	u.Value = zero
+	var err error
+	u.selected, err = unmarshalBinary(u.specValue(), data)
+	return err
+}
+
+// marshalBinary encodes the spec value v with the selected field.
+func marshalBinary(v reflect.Value, selected *fieldPlan) ([]byte, error) {
+	p := planOf(v.Type())
+	if !p.isStruct {
+		return nil, ErrSpecNotStruct
+	}
+	f, err := p.current(v, selected)
+	if errors.Is(err, ErrZeroVariants) && p.json.nullable {
+		return []byte{binaryVersion, 0}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	variant, value := variantValue(v, f)
+	payload, err := marshalPayload(encodingJSON, value)
+	if err != nil {
+		return nil, err
+	}
+	if variant == f.variant {
+		variant = ""
+	}
+
+	buf := make([]byte, 0, 1+3*binary.MaxVarintLen64+len(variant)+len(payload))
+	buf = append(buf, binaryVersion)
+	buf = binary.AppendUvarint(buf, uint64(p.fieldPosition(f)+1))
+	buf = binary.AppendUvarint(buf, uint64(len(variant)))
+	buf = append(buf, variant...)
+	buf = binary.AppendUvarint(buf, uint64(len(payload)))
+	return append(buf, payload...), nil
+}
+
+// unmarshalBinary decodes data written by marshalBinary into the zero spec value v,
+// returning the field to keep selected.
+func unmarshalBinary(v reflect.Value, data []byte) (*fieldPlan, error) {
+	p := planOf(v.Type())
+	if !p.isStruct {
+		return nil, ErrSpecNotStruct
+	}
+	if len(data) == 0 {
+		return nil, errBinaryData
+	}
+	if data[0] != binaryVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", errBinaryData, data[0])
+	}
+	data = data[1:]
+
+	position, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, errBinaryData
+	}
+	data = data[n:]
+	if position == 0 {
+		if len(data) > 0 {
+			return nil, errBinaryData
+		}
+		if !p.json.nullable {
+			return nil, ErrZeroVariants
+		}
+		return nil, nil
+	}
+	if position > uint64(len(p.fields)) {
+		return nil, fmt.Errorf("%w: field %d", ErrUnknownVariant, position-1)
+	}
+	f := &p.fields[position-1]
+
+	variant, data, ok := readBinaryBytes(data)
+	if !ok {
+		return nil, errBinaryData
+	}
+	payload, data, ok := readBinaryBytes(data)
+	if !ok || len(data) > 0 {
+		return nil, errBinaryData
+	}
+
+	name := f.variant
+	if len(variant) > 0 {
+		name = string(variant)
+	}
+	p.noteDeprecated(f, name)
+	opts := decodeOptions{useNumber: usesNumber(v.Interface())}
+	switch {
+	case f.raw:
+		v.FieldByIndex(f.index).Set(adapt(f, reflect.ValueOf(Raw{Variant: name, Value: bytes.Clone(payload)})))
+		return nil, nil
+	case name != f.variant:
+		if ok, err := p.setImpl(encodingJSON, v, name, payload, opts); ok {
+			return nil, err
+		}
+		return nil, &UnknownVariantError{Spec: p.typ, Variant: name, Known: p.knownVariants()}
+	}
+
+	target, err := decodeField(encodingJSON, f, payload, opts)
+	if err != nil {
+		return nil, payloadError(p.typ, name, f, "", err)
+	}
+	v.FieldByIndex(f.index).Set(target.Elem())
+	return selection(v, f), nil
+}
+
+// readBinaryBytes reads a uvarint length-prefixed byte string at the start of data,
+// returning it and the data after it.
+func readBinaryBytes(data []byte) ([]byte, []byte, bool) {
+	size, n := binary.Uvarint(data)
+	if n <= 0 || size > uint64(len(data)-n) {
+		return nil, nil, false
+	}
+	data = data[n:]
+	return data[:size:size], data[size:], true
+}
+
+// fieldPosition returns the position of the field f among the spec fields.
+func (p *specPlan) fieldPosition(f *fieldPlan) int {
+	for i := range p.fields {
+		if &p.fields[i] == f {
+			return i
+		}
+	}
+	return -1
+}
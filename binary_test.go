@@ -0,0 +1,158 @@
+package union
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	tests := []struct {
+		name  string
+		value encoding.BinaryMarshaler
+		into  interface {
+			encoding.BinaryUnmarshaler
+			GetValue() any
+		}
+	}{
+		{
+			name:  "tagged union",
+			value: TaggedUnion[Shape]{Value: Shape{Rectangle: &Rectangle{Width: 2, Height: 3}}},
+			into:  &TaggedUnion[Shape]{},
+		},
+		{
+			name:  "externally tagged union",
+			value: ExternallyTagged[NonPointerShape]{Value: NonPointerShape{Circle: Circle{Radius: 1}}},
+			into:  &ExternallyTagged[NonPointerShape]{},
+		},
+		{
+			name:  "untagged union keeps variant",
+			value: Union[UnionShape]{Value: UnionShape{Triangle: &Triangle{}}},
+			into:  &Union[UnionShape]{},
+		},
+		{
+			name:  "raw variant",
+			value: TaggedUnion[ForwardShape]{Value: ForwardShape{Unknown: &Raw{Variant: "hexagon", Value: json.RawMessage(`{"sides":6}`)}}},
+			into:  &TaggedUnion[ForwardShape]{},
+		},
+		{
+			name:  "registered implementation",
+			value: TaggedUnion[Task]{Value: Task{Plugin: &SleepPlugin{Ms: 5}}},
+			into:  &TaggedUnion[Task]{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.value.MarshalBinary()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := tt.into.UnmarshalBinary(data); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected := reflect.ValueOf(tt.value).MethodByName("GetValue").Call(nil)[0].Interface()
+			if got := tt.into.GetValue(); !reflect.DeepEqual(got, expected) {
+				t.Errorf("expected %+v, got %+v", expected, got)
+			}
+		})
+	}
+
+	t.Run("selected zero variant", func(t *testing.T) {
+		var u ExternallyTagged[NonPointerShape]
+		if err := u.Select("rectangle"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		data, err := u.MarshalBinary()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var got ExternallyTagged[NonPointerShape]
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if variant := got.MustVariant(); variant != "rectangle" {
+			t.Errorf("expected rectangle, got %v", variant)
+		}
+	})
+
+	t.Run("nullable", func(t *testing.T) {
+		data, err := TaggedUnion[NullableShape]{}.MarshalBinary()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got := TaggedUnion[NullableShape]{Value: NullableShape{Circle: &Circle{}}}
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !got.IsZero() {
+			t.Errorf("expected zero union, got %v", got)
+		}
+		if err := (&TaggedUnion[Shape]{}).UnmarshalBinary(data); !errors.Is(err, ErrZeroVariants) {
+			t.Errorf("expected error %v, got %v", ErrZeroVariants, err)
+		}
+	})
+
+	t.Run("gob", func(t *testing.T) {
+		type entry struct{ Shape TaggedUnion[Shape] }
+		var buf bytes.Buffer
+		in := entry{Shape: TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 4}}}}
+		if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var out entry
+		if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !Equal(in.Shape, out.Shape) {
+			t.Errorf("expected %v, got %v", in.Shape, out.Shape)
+		}
+	})
+}
+
+func TestUnmarshalBinaryErrors(t *testing.T) {
+	valid, err := TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 1}}}.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected error
+	}{
+		{"empty", nil, errBinaryData},
+		{"unsupported version", append([]byte{9}, valid[1:]...), errBinaryData},
+		{"truncated", valid[:len(valid)-1], errBinaryData},
+		{"trailing data", append(bytes.Clone(valid), 0), errBinaryData},
+		{"unknown field", []byte{binaryVersion, 9, 0, 0}, ErrUnknownVariant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var u TaggedUnion[Shape]
+			if err := u.UnmarshalBinary(tt.data); !errors.Is(err, tt.expected) {
+				t.Errorf("expected error %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	t.Run("invalid payload", func(t *testing.T) {
+		var u TaggedUnion[Shape]
+		var decodeErr *DecodeError
+		if err := u.UnmarshalBinary([]byte{binaryVersion, 1, 0, 1, '['}); !errors.As(err, &decodeErr) {
+			t.Errorf("expected *DecodeError, got %v", err)
+		}
+	})
+
+	t.Run("invalid union", func(t *testing.T) {
+		_, err := TaggedUnion[Shape]{}.MarshalBinary()
+		if !errors.Is(err, ErrZeroVariants) {
+			t.Errorf("expected error %v, got %v", ErrZeroVariants, err)
+		}
+	})
+}
@@ -0,0 +1,379 @@
+package union
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// The CBOR methods implement the Marshaler and Unmarshaler interfaces of
+// github.com/fxamacker/cbor without depending on it. Unions are converted
+// through their JSON representation, so variant payloads use their json struct tags
+// and the same variant rules as MarshalJSON and UnmarshalJSON apply.
+
+// cborTags returns the CBOR tag number of each variant if the Spec type
+// implements CBORTags() map[string]uint64, otherwise nil.
+func (u *TaggedUnion[Spec]) cborTags() map[string]uint64 {
+	if tc, ok := any(u.Value).(interface{ CBORTags() map[string]uint64 }); ok {
+		return tc.CBORTags()
+	}
+	return nil
+}
+
+// MarshalCBOR implements the cbor.Marshaler interface.
+// It serializes the union as a CBOR map with the same shape as MarshalJSON.
+//
+// If the Spec type implements CBORTags() map[string]uint64, the variant's data
+// is instead serialized on its own, wrapped in the CBOR tag number of the active variant.
+func (u TaggedUnion[Spec]) MarshalCBOR() ([]byte, error) {
+	tags := u.cborTags()
+	if tags == nil {
+		return MarshalWith(u, Options{Format: CBOR})
+	}
+
+	v := reflect.ValueOf(u.Value)
+	f, err := planOf(v.Type()).current(v, u.selected)
+	if err != nil {
+		return nil, err
+	}
+	tag, ok := tags[f.variant]
+	if !ok {
+		return nil, fmt.Errorf("cbor: no tag number for variant %q", f.variant)
+	}
+
+	data, err := marshalPayload(encodingJSON, v.FieldByIndex(f.index).Interface())
+	if err != nil {
+		return nil, err
+	}
+	value, err := decodeJSONValue(data)
+	if err != nil {
+		return nil, err
+	}
+	return appendCBOR(appendCBORHeader(nil, 6, tag), value)
+}
+
+// UnmarshalCBOR implements the cbor.Unmarshaler interface.
+// It deserializes CBOR data with the same rules as UnmarshalJSON.
+//
+// If the Spec type implements CBORTags() map[string]uint64, the variant is
+// instead determined by the CBOR tag number wrapping the variant's data.
+func (u *TaggedUnion[Spec]) UnmarshalCBOR(data []byte) error {
+	tags := u.cborTags()
+	if tags == nil {
+		return UnmarshalWith(data, u, Options{Format: CBOR})
+	}
+
+	var zero Spec
+	u.Value = zero
+	u.selected = nil
+
+	v := reflect.ValueOf(&u.Value).Elem()
+	t := v.Type()
+	p := planOf(t)
+
+	if !p.isStruct {
+		return ErrSpecNotStruct
+	}
+
+	if len(data) == 0 || data[0]>>5 != 6 {
+		return fmt.Errorf("%w: expected cbor tag", ErrMissingVariantField)
+	}
+	tag, content, err := readCBORHeader(data)
+	if err != nil {
+		return err
+	}
+	value, err := readCBORValue(content)
+	if err != nil {
+		return err
+	}
+
+	variant, ok := "", false
+	for name, number := range tags {
+		if number == tag {
+			variant, ok = name, true
+			break
+		}
+	}
+	if !ok {
+		return &UnknownVariantError{Spec: t, Variant: strconv.FormatUint(tag, 10), Known: p.knownVariants()}
+	}
+	f, err := p.resolve(variant)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	target, err := decodeField(encodingJSON, f, raw, payloadOptions(encodingJSON, u.Value))
+	if err != nil {
+		return &DecodeError{Spec: t, Variant: variant, Field: f.name, Err: err}
+	}
+
+	v.FieldByIndex(f.index).Set(target.Elem())
+	u.selected = selection(v, f)
+	return nil
+}
+
+// MarshalCBOR implements the cbor.Marshaler interface.
+// It serializes the union as a CBOR map with the same shape as MarshalJSON.
+func (u ExternallyTagged[Spec]) MarshalCBOR() ([]byte, error) {
+	return MarshalWith(u, Options{Format: CBOR})
+}
+
+// UnmarshalCBOR implements the cbor.Unmarshaler interface.
+// It deserializes CBOR data with the same rules as UnmarshalJSON.
+func (u *ExternallyTagged[Spec]) UnmarshalCBOR(data []byte) error {
+	return UnmarshalWith(data, u, Options{Format: CBOR})
+}
+
+// MarshalCBOR implements the cbor.Marshaler interface.
+// It serializes the union's active variant data directly as a CBOR value.
+func (u Union[Spec]) MarshalCBOR() ([]byte, error) {
+	return MarshalWith(u, Options{Format: CBOR})
+}
+
+// UnmarshalCBOR implements the cbor.Unmarshaler interface.
+// It deserializes CBOR data with the same rules as UnmarshalJSON.
+func (u *Union[Spec]) UnmarshalCBOR(data []byte) error {
+	return UnmarshalWith(data, u, Options{Format: CBOR})
+}
+
+// appendCBOR appends the CBOR encoding of a value decoded from JSON.
+func appendCBOR(b []byte, value any) ([]byte, error) {
+	switch value := value.(type) {
+	case nil:
+		return append(b, 0xf6), nil
+	case bool:
+		if value {
+			return append(b, 0xf5), nil
+		}
+		return append(b, 0xf4), nil
+	case json.Number:
+		if n, err := strconv.ParseInt(string(value), 10, 64); err == nil {
+			if n < 0 {
+				return appendCBORHeader(b, 1, uint64(-1-n)), nil
+			}
+			return appendCBORHeader(b, 0, uint64(n)), nil
+		}
+		if n, err := strconv.ParseUint(string(value), 10, 64); err == nil {
+			return appendCBORHeader(b, 0, n), nil
+		}
+		f, err := value.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return binary.BigEndian.AppendUint64(append(b, 0xfb), math.Float64bits(f)), nil
+	case string:
+		b = appendCBORHeader(b, 3, uint64(len(value)))
+		return append(b, value...), nil
+	case []any:
+		b = appendCBORHeader(b, 4, uint64(len(value)))
+		for _, elem := range value {
+			var err error
+			if b, err = appendCBOR(b, elem); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		b = appendCBORHeader(b, 5, uint64(len(value)))
+		for _, key := range keys {
+			var err error
+			if b, err = appendCBOR(b, key); err != nil {
+				return nil, err
+			}
+			if b, err = appendCBOR(b, value[key]); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("cbor: unsupported type %T", value)
+}
+
+// appendCBORHeader appends the initial byte of a data item of the major type
+// followed by its argument in the shortest form.
+func appendCBORHeader(b []byte, major byte, n uint64) []byte {
+	major <<= 5
+	switch {
+	case n < 24:
+		return append(b, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(b, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, major|26), uint32(n))
+	}
+	return binary.BigEndian.AppendUint64(append(b, major|27), n)
+}
+
+var (
+	errCBORShort = errors.New("cbor: unexpected end of data")
+	errCBORDepth = fmt.Errorf("cbor: %w: data nested deeper than %d levels", ErrLimitExceeded, maxNestingDepth)
+)
+
+// readCBORValue decodes a single CBOR data item into a JSON compatible value.
+func readCBORValue(data []byte) (any, error) {
+	value, rest, err := readCBOR(data, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("cbor: trailing data")
+	}
+	return value, nil
+}
+
+// readCBORHeader reads the argument of the data item's initial byte and returns the remaining data.
+// Indefinite lengths are not supported.
+func readCBORHeader(b []byte) (uint64, []byte, error) {
+	if len(b) == 0 {
+		return 0, nil, errCBORShort
+	}
+	info, b := b[0]&0x1f, b[1:]
+	if info < 24 {
+		return uint64(info), b, nil
+	}
+	if info > 27 {
+		return 0, nil, fmt.Errorf("cbor: unsupported additional information %d", info)
+	}
+	size := 1 << (info - 24)
+	if len(b) < size {
+		return 0, nil, errCBORShort
+	}
+	var n uint64
+	for _, x := range b[:size] {
+		n = n<<8 | uint64(x)
+	}
+	return n, b[size:], nil
+}
+
+// readCBOR decodes a single CBOR data item, nested in depth arrays, maps and tags,
+// into a JSON compatible value and returns the remaining data. Tags are ignored in
+// favor of their content.
+func readCBOR(b []byte, depth int) (any, []byte, error) {
+	if len(b) == 0 {
+		return nil, nil, errCBORShort
+	}
+	major, info := b[0]>>5, b[0]&0x1f
+
+	if major == 7 {
+		switch info {
+		case 20:
+			return false, b[1:], nil
+		case 21:
+			return true, b[1:], nil
+		case 22, 23:
+			return nil, b[1:], nil
+		}
+	}
+
+	n, rest, err := readCBORHeader(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if major >= 4 && major <= 6 && depth >= maxNestingDepth {
+		return nil, nil, errCBORDepth
+	}
+
+	switch major {
+	case 0:
+		return n, rest, nil
+	case 1:
+		if n > math.MaxInt64 {
+			return nil, nil, errors.New("cbor: negative integer overflows int64")
+		}
+		return -1 - int64(n), rest, nil
+	case 2, 3:
+		if uint64(len(rest)) < n {
+			return nil, nil, errCBORShort
+		}
+		if major == 2 {
+			return slices.Clone(rest[:n]), rest[n:], nil
+		}
+		return string(rest[:n]), rest[n:], nil
+	case 4:
+		// each element takes at least one byte
+		if uint64(len(rest)) < n {
+			return nil, nil, errCBORShort
+		}
+		out := make([]any, n)
+		for i := range out {
+			if out[i], rest, err = readCBOR(rest, depth+1); err != nil {
+				return nil, nil, err
+			}
+		}
+		return out, rest, nil
+	case 5:
+		// each entry takes at least two bytes
+		if uint64(len(rest)) < 2*n {
+			return nil, nil, errCBORShort
+		}
+		out := make(map[string]any, n)
+		for range n {
+			var key any
+			if key, rest, err = readCBOR(rest, depth+1); err != nil {
+				return nil, nil, err
+			}
+			s, ok := key.(string)
+			if !ok {
+				return nil, nil, fmt.Errorf("cbor: unsupported map key type %T", key)
+			}
+			if out[s], rest, err = readCBOR(rest, depth+1); err != nil {
+				return nil, nil, err
+			}
+		}
+		return out, rest, nil
+	case 6:
+		return readCBOR(rest, depth+1)
+	}
+
+	switch info {
+	case 25:
+		return halfToFloat64(uint16(n)), rest, nil
+	case 26:
+		return float64(math.Float32frombits(uint32(n))), rest, nil
+	case 27:
+		return math.Float64frombits(n), rest, nil
+	}
+	return nil, nil, fmt.Errorf("cbor: unsupported simple value %d", n)
+}
+
+// halfToFloat64 converts an IEEE 754 half-precision float to float64.
+func halfToFloat64(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
@@ -0,0 +1,195 @@
+package union
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type CBORTaggedShape struct {
+	Circle    *Circle    `variant:"circle"`
+	Rectangle *Rectangle `variant:"rectangle"`
+	Triangle  *Triangle  `variant:"triangle"`
+}
+
+func (s CBORTaggedShape) CBORTags() map[string]uint64 {
+	return map[string]uint64{"circle": 1000, "rectangle": 1001}
+}
+
+func TestMarshalCBOR(t *testing.T) {
+	tests := []struct {
+		name        string
+		shape       interface{ MarshalCBOR() ([]byte, error) }
+		expected    string
+		expectedErr string
+	}{
+		{
+			// {"type":"circle","value":{"radius":5}}
+			name:     "marshals tagged union as map",
+			shape:    TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 5}}},
+			expected: "a2647479706566636972636c656576616c7565a166726164697573" + "05",
+		},
+		{
+			// 1000({"radius":2.5})
+			name:     "marshals tagged union with cbor tag",
+			shape:    TaggedUnion[CBORTaggedShape]{Value: CBORTaggedShape{Circle: &Circle{Radius: 2.5}}},
+			expected: "d903e8" + "a166726164697573fb4004000000000000",
+		},
+		{
+			// {"circle":{"radius":-300}}
+			name:     "marshals externally tagged union",
+			shape:    ExternallyTagged[Shape]{Value: Shape{Circle: &Circle{Radius: -300}}},
+			expected: "a166636972636c65a16672616469757339012b",
+		},
+		{
+			// {"base":8,"height":4}
+			name:     "marshals untagged union",
+			shape:    Union[UnionShape]{Value: UnionShape{Triangle: &Triangle{Base: 8, Height: 4}}},
+			expected: "a26462617365086668656967687404",
+		},
+		{
+			name:        "returns error for variant without cbor tag",
+			shape:       TaggedUnion[CBORTaggedShape]{Value: CBORTaggedShape{Triangle: &Triangle{Base: 8, Height: 4}}},
+			expectedErr: `cbor: no tag number for variant "triangle"`,
+		},
+		{
+			name:        "returns error when no variant is set",
+			shape:       TaggedUnion[Shape]{},
+			expectedErr: "zero variants set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.shape.MarshalCBOR()
+
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("expected error '%s', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := hex.EncodeToString(data); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestUnmarshalCBOR(t *testing.T) {
+	tests := []struct {
+		name  string
+		shape interface {
+			GetValue() any
+			UnmarshalCBOR([]byte) error
+		}
+		data        string
+		expected    any
+		expectedErr error
+	}{
+		{
+			// {"type":"rectangle","value":{"width":uint8(200),"height":float16(5)}}
+			name:     "unmarshals tagged union",
+			shape:    &TaggedUnion[Shape]{},
+			data:     "a264747970656972656374616e676c656576616c7565a265776964746818c866686569676874f94500",
+			expected: Rectangle{Width: 200, Height: 5},
+		},
+		{
+			// 1001({"width":10,"height":float32(5)})
+			name:     "unmarshals tagged union with cbor tag",
+			shape:    &TaggedUnion[CBORTaggedShape]{},
+			data:     "d903e9" + "a2657769647468" + "0a" + "66686569676874" + "fa40a00000",
+			expected: Rectangle{Width: 10, Height: 5},
+		},
+		{
+			// {"circle":{"radius":-300}}
+			name:     "unmarshals externally tagged union",
+			shape:    &ExternallyTagged[Shape]{},
+			data:     "a166636972636c65a16672616469757339012b",
+			expected: Circle{Radius: -300},
+		},
+		{
+			// {"base":8,"height":4}
+			name:     "unmarshals untagged union",
+			shape:    &Union[UnionShape]{},
+			data:     "a26462617365086668656967687404",
+			expected: Triangle{Base: 8, Height: 4},
+		},
+		{
+			// 9999({})
+			name:        "returns error for unknown cbor tag",
+			shape:       &TaggedUnion[CBORTaggedShape]{},
+			data:        "d9270fa0",
+			expectedErr: ErrUnknownVariant,
+		},
+		{
+			// {}
+			name:        "returns error for missing cbor tag",
+			shape:       &TaggedUnion[CBORTaggedShape]{},
+			data:        "a0",
+			expectedErr: ErrMissingVariantField,
+		},
+		{
+			// {"type":"hexagon","value":{}}
+			name:        "returns error for unknown variant",
+			shape:       &TaggedUnion[Shape]{},
+			data:        "a264747970656768657861676f6e6576616c7565a0",
+			expectedErr: ErrUnknownVariant,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = tt.shape.UnmarshalCBOR(data)
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("expected error '%v', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertValueEquals(t, tt.shape.GetValue(), tt.expected)
+		})
+	}
+}
+
+func TestUnmarshalCBORErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		expectedErr string
+	}{
+		{name: "truncated data", data: "a264747970", expectedErr: "cbor: unexpected end of data"},
+		{name: "trailing data", data: "a0f6", expectedErr: "cbor: trailing data"},
+		{name: "non-string map keys", data: "a10102", expectedErr: "cbor: unsupported map key type uint64"},
+		{name: "indefinite length", data: "bfff", expectedErr: "cbor: unsupported additional information 31"},
+		{name: "deeply nested arrays", data: strings.Repeat("81", 100000) + "f6", expectedErr: "cbor: decode limit exceeded: data nested deeper than 10000 levels"},
+		{name: "deeply nested tags", data: strings.Repeat("c0", 100000) + "f6", expectedErr: "cbor: decode limit exceeded: data nested deeper than 10000 levels"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = (&Union[UnionShape]{}).UnmarshalCBOR(data)
+			if err == nil || err.Error() != tt.expectedErr {
+				t.Errorf("expected error '%s', got '%v'", tt.expectedErr, err)
+			}
+		})
+	}
+}
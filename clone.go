@@ -0,0 +1,108 @@
+package union
+
+import "reflect"
+
+// Clone returns a deep copy of the union, so the copy's payload can be modified
+// without affecting u. Pointers, slices, maps and interfaces reachable through
+// exported fields of the variant fields are copied; unexported fields are copied
+// by value, and fields that are not variants are shared with u.
+func (u TaggedUnion[Spec]) Clone() TaggedUnion[Spec] {
+	u.Value = cloneSpec(u.Value)
+	return u
+}
+
+// Clone returns a deep copy of the union. See TaggedUnion.Clone.
+func (u ExternallyTagged[Spec]) Clone() ExternallyTagged[Spec] {
+	u.Value = cloneSpec(u.Value)
+	return u
+}
+
+// Clone returns a deep copy of the union. See TaggedUnion.Clone.
+func (u Union[Spec]) Clone() Union[Spec] {
+	u.Value = cloneSpec(u.Value)
+	return u
+}
+
+// cloneSpec returns a copy of the spec value with deep copies of its variant fields.
+func cloneSpec[Spec any](spec Spec) Spec {
+	v := reflect.ValueOf(&spec).Elem()
+	p := planOf(v.Type())
+	if !p.isStruct {
+		return spec
+	}
+
+	seen := make(map[copyKey]reflect.Value)
+	for _, f := range p.fields {
+		fv := v.FieldByIndex(f.index)
+		if !fv.IsZero() {
+			fv.Set(deepCopy(fv, seen))
+		}
+	}
+	return spec
+}
+
+// copyKey identifies a pointer already copied by deepCopy, so shared and cyclic
+// pointers keep their shape in the copy.
+type copyKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// deepCopy returns a copy of v that shares no pointers, slices or maps with it.
+func deepCopy(v reflect.Value, seen map[copyKey]reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		key := copyKey{v.Pointer(), v.Type()}
+		if c, ok := seen[key]; ok {
+			return c
+		}
+		c := reflect.New(v.Type().Elem())
+		seen[key] = c
+		c.Elem().Set(deepCopy(v.Elem(), seen))
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopy(v.Elem(), seen))
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := range v.Len() {
+			c.Index(i).Set(deepCopy(v.Index(i), seen))
+		}
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := range v.Len() {
+			c.Index(i).Set(deepCopy(v.Index(i), seen))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			c.SetMapIndex(deepCopy(iter.Key(), seen), deepCopy(iter.Value(), seen))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := range v.NumField() {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(deepCopy(v.Field(i), seen))
+			}
+		}
+		return c
+	}
+	return v
+}
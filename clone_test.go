@@ -0,0 +1,62 @@
+package union
+
+import (
+	"reflect"
+	"testing"
+)
+
+type Polygon struct {
+	Points []Point        `json:"points"`
+	Labels map[string]any `json:"labels"`
+	Parent *Polygon       `json:"-"`
+}
+
+type Point struct{ X, Y float64 }
+
+type PolygonShape struct {
+	Polygon *Polygon `variant:"polygon"`
+	Circle  Circle   `variant:"circle"`
+}
+
+func TestClone(t *testing.T) {
+	t.Run("copies pointer payload", func(t *testing.T) {
+		shape := TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 5.0}}}
+		clone := shape.Clone()
+		clone.Value.Circle.Radius = 10
+		if shape.Value.Circle.Radius != 5.0 {
+			t.Errorf("expected original radius 5, got %v", shape.Value.Circle.Radius)
+		}
+	})
+
+	t.Run("copies nested slices, maps and pointers", func(t *testing.T) {
+		polygon := &Polygon{
+			Points: []Point{{0, 0}, {1, 1}},
+			Labels: map[string]any{"tags": []string{"a"}},
+		}
+		polygon.Parent = polygon
+		shape := ExternallyTagged[PolygonShape]{Value: PolygonShape{Polygon: polygon}}
+
+		clone := shape.Clone()
+		if !reflect.DeepEqual(clone.Value.Polygon.Points, polygon.Points) {
+			t.Errorf("expected %v, got %v", polygon.Points, clone.Value.Polygon.Points)
+		}
+		clone.Value.Polygon.Points[0].X = 5
+		clone.Value.Polygon.Labels["tags"].([]string)[0] = "b"
+		if polygon.Points[0].X != 0 || polygon.Labels["tags"].([]string)[0] != "a" {
+			t.Errorf("expected original to be unchanged, got %+v", polygon)
+		}
+		if clone.Value.Polygon.Parent != clone.Value.Polygon {
+			t.Error("expected cyclic pointer to point to the copy")
+		}
+	})
+
+	t.Run("keeps selection", func(t *testing.T) {
+		var shape Union[UnionNonPointerShape]
+		if err := shape.Select("Rectangle"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if variant, ok := shape.Clone().Variant(); !ok || variant != "Rectangle" {
+			t.Errorf("expected variant Rectangle, got %q (ok=%v)", variant, ok)
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	unionPackage      = protogen.GoImportPath("github.com/eriicafes/union")
+	unionprotoPackage = protogen.GoImportPath("github.com/eriicafes/union/unionproto")
+)
+
+// generateFile writes the spec structs of the oneofs declared in f, if it declares any.
+func generateFile(gen *protogen.Plugin, f *protogen.File, conversions bool) *protogen.GeneratedFile {
+	var oneofs []*protogen.Oneof
+	var walk func(messages []*protogen.Message)
+	walk = func(messages []*protogen.Message) {
+		for _, m := range messages {
+			for _, o := range m.Oneofs {
+				// skip the synthetic oneofs generated for proto3 optional fields
+				if !o.Desc.IsSynthetic() {
+					oneofs = append(oneofs, o)
+				}
+			}
+			walk(m.Messages)
+		}
+	}
+	walk(f.Messages)
+	if len(oneofs) == 0 {
+		return nil
+	}
+
+	g := gen.NewGeneratedFile(f.GeneratedFilenamePrefix+"_union.pb.go", f.GoImportPath)
+	g.P("// Code generated by protoc-gen-union. DO NOT EDIT.")
+	g.P("// source: ", f.Desc.Path())
+	g.P()
+	g.P("package ", f.GoPackageName)
+	for _, o := range oneofs {
+		generateSpec(g, o)
+		if conversions {
+			generateConversions(g, o)
+		}
+	}
+	return g
+}
+
+// specName returns the name of the spec struct of the oneof, such as PaymentMethod.
+func specName(o *protogen.Oneof) string {
+	return o.Parent.GoIdent.GoName + o.GoName
+}
+
+// generateSpec writes the spec struct of the oneof and its ProtoOneof method.
+func generateSpec(g *protogen.GeneratedFile, o *protogen.Oneof) {
+	name := specName(o)
+	g.P()
+	g.P("// ", name, " is the union spec of the ", o.Desc.Name(), " oneof of ", o.Parent.GoIdent.GoName, ".")
+	g.P("type ", name, " struct {")
+	for _, field := range o.Fields {
+		g.P(field.GoName, " ", fieldType(g, field), " `variant:\"", field.Desc.Name(), "\"`")
+	}
+	g.P("}")
+	g.P()
+	g.P("func (", name, ") ProtoOneof() string { return \"", o.Desc.Name(), "\" }")
+}
+
+// generateConversions writes the methods converting the oneof of the message to and from a union.
+func generateConversions(g *protogen.GeneratedFile, o *protogen.Oneof) {
+	name, msg := specName(o), o.Parent.GoIdent.GoName
+	union := g.QualifiedGoIdent(unionPackage.Ident("TaggedUnion")) + "[" + name + "]"
+
+	g.P()
+	g.P("// ", o.GoName, "Union returns a union holding the populated field of the ", o.Desc.Name(), " oneof.")
+	g.P("func (x *", msg, ") ", o.GoName, "Union() (", union, ", error) {")
+	g.P("return ", unionprotoPackage.Ident("FromOneof"), "[", name, "](x)")
+	g.P("}")
+	g.P()
+	g.P("// Set", o.GoName, "Union populates the field of the ", o.Desc.Name(), " oneof named like the active variant of u.")
+	g.P("func (x *", msg, ") Set", o.GoName, "Union(u ", union, ") error {")
+	g.P("return ", unionprotoPackage.Ident("ToOneof"), "(u, x)")
+	g.P("}")
+}
+
+// fieldType returns the Go type of the variant of a oneof field: the generated message
+// pointer, or a pointer to the enum type or to the Go type protoc-gen-go gives the scalar,
+// so a populated field holding a zero value such as 0, "" or false is still a non-zero variant.
+func fieldType(g *protogen.GeneratedFile, field *protogen.Field) string {
+	switch field.Desc.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return "*" + g.QualifiedGoIdent(field.Message.GoIdent)
+	case protoreflect.EnumKind:
+		return "*" + g.QualifiedGoIdent(field.Enum.GoIdent)
+	case protoreflect.BoolKind:
+		return "*bool"
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return "*int32"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return "*int64"
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return "*uint32"
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "*uint64"
+	case protoreflect.FloatKind:
+		return "*float32"
+	case protoreflect.DoubleKind:
+		return "*float64"
+	case protoreflect.StringKind:
+		return "*string"
+	case protoreflect.BytesKind:
+		return "*[]byte"
+	}
+	return "any"
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// paymentFile describes payment.proto:
+//
+//	message Card { string number = 1; }
+//	enum Wallet { WALLET_UNSPECIFIED = 0; WALLET_APPLE = 1; }
+//	message Payment {
+//	  oneof method {
+//	    Card card = 1;
+//	    Wallet wallet = 2;
+//	    string voucher_code = 3;
+//	  }
+//	  optional string note = 4;
+//	}
+func paymentFile() *descriptorpb.FileDescriptorProto {
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string, oneof *int32) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:       proto.String(name),
+			Number:     proto.Int32(number),
+			Label:      descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:       typ.Enum(),
+			OneofIndex: oneof,
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+
+	return &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("payment.proto"),
+		Package: proto.String("payments"),
+		Syntax:  proto.String("proto3"),
+		Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/payments;payments")},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Wallet"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("WALLET_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("WALLET_APPLE"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name:  proto.String("Card"),
+				Field: []*descriptorpb.FieldDescriptorProto{field("number", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, "", nil)},
+			},
+			{
+				Name: proto.String("Payment"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("card", 1, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".payments.Card", proto.Int32(0)),
+					field("wallet", 2, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".payments.Wallet", proto.Int32(0)),
+					field("voucher_code", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING, "", proto.Int32(0)),
+					func() *descriptorpb.FieldDescriptorProto {
+						f := field("note", 4, descriptorpb.FieldDescriptorProto_TYPE_STRING, "", proto.Int32(1))
+						f.Proto3Optional = proto.Bool(true)
+						return f
+					}(),
+				},
+				OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("method")}, {Name: proto.String("_note")}},
+			},
+		},
+	}
+}
+
+// generate runs the plugin on payment.proto and returns the content of the files it writes.
+func generate(t *testing.T, parameter string) map[string]string {
+	t.Helper()
+
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"payment.proto"},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{paymentFile()},
+	}
+	if parameter != "" {
+		req.Parameter = proto.String(parameter)
+	}
+	gen, err := protogen.Options{}.New(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, f := range gen.Files {
+		if f.Generate {
+			generateFile(gen, f, parameter == "conversions=true")
+		}
+	}
+
+	resp := gen.Response()
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.GetError())
+	}
+	files := make(map[string]string)
+	for _, f := range resp.File {
+		files[f.GetName()] = f.GetContent()
+	}
+	return files
+}
+
+func TestGenerateFile(t *testing.T) {
+	files := generate(t, "")
+
+	expected := "// Code generated by protoc-gen-union. DO NOT EDIT.\n" + `// source: payment.proto
+
+package payments
+
+// PaymentMethod is the union spec of the method oneof of Payment.
+type PaymentMethod struct {
+	Card        *Card   ` + "`variant:\"card\"`" + `
+	Wallet      *Wallet ` + "`variant:\"wallet\"`" + `
+	VoucherCode *string ` + "`variant:\"voucher_code\"`" + `
+}
+
+func (PaymentMethod) ProtoOneof() string { return "method" }
+`
+	if got := files["example.com/payments/payment_union.pb.go"]; got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestGenerateFileConversions(t *testing.T) {
+	src := generate(t, "conversions=true")["example.com/payments/payment_union.pb.go"]
+
+	for _, s := range []string{
+		`union "github.com/eriicafes/union"`,
+		`unionproto "github.com/eriicafes/union/unionproto"`,
+		"func (x *Payment) MethodUnion() (union.TaggedUnion[PaymentMethod], error) {\n\treturn unionproto.FromOneof[PaymentMethod](x)\n}",
+		"func (x *Payment) SetMethodUnion(u union.TaggedUnion[PaymentMethod]) error {\n\treturn unionproto.ToOneof(u, x)\n}",
+	} {
+		if !strings.Contains(src, s) {
+			t.Errorf("expected generated code to contain %q, got:\n%s", s, src)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Protoc-gen-union generates union spec structs for the oneofs of protobuf messages.
+//
+// Usage:
+//
+//	protoc --go_out=. --union_out=. [--union_opt=conversions=true] payment.proto
+//
+// For every oneof of every message it writes, next to the code generated by
+// protoc-gen-go in <file>_union.pb.go, a spec struct named after the message and
+// the oneof with one variant per oneof field, tagged with the proto field name
+// as unionproto expects, and a ProtoOneof method selecting the oneof:
+//
+//	type PaymentMethod struct {
+//		Card   *Card   `variant:"card"`
+//		Wallet *Wallet `variant:"wallet"`
+//	}
+//
+//	func (PaymentMethod) ProtoOneof() string { return "method" }
+//
+// With conversions=true it also writes methods on the message converting the oneof
+// to and from a TaggedUnion of the spec with unionproto.FromOneof and unionproto.ToOneof:
+//
+//	u, err := payment.MethodUnion()
+//	err = payment.SetMethodUnion(u)
+//
+// The proto file is the single source of truth: regenerating it keeps the REST
+// representation of the union and the gRPC message in step.
+package main
+
+import (
+	"flag"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+func main() {
+	var flags flag.FlagSet
+	conversions := flags.Bool("conversions", false, "generate methods converting the oneofs to and from unions")
+
+	protogen.Options{ParamFunc: flags.Set}.Run(func(gen *protogen.Plugin) error {
+		for _, f := range gen.Files {
+			if f.Generate {
+				generateFile(gen, f, *conversions)
+			}
+		}
+		return nil
+	})
+}
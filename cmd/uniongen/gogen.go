@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"text/template"
+)
+
+var goTemplate = template.Must(template.New("go").Parse(`// Code generated by uniongen; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .Imports}}
+	{{.}}
+{{- end}}
+{{if .Imports}}
+{{end}}	"github.com/eriicafes/union"
+)
+
+// {{.Name}}Variant is the name of a variant of {{.Name}}, as returned by Variant.
+type {{.Name}}Variant string
+
+// The variants of {{.Name}}.
+const (
+{{- range .Variants}}
+	{{$.Name}}Variant{{.Field}} {{$.Name}}Variant = {{printf "%q" .Name}}
+{{- end}}
+)
+
+// {{.Name}}Visitor handles each variant of {{.Name}}.
+type {{.Name}}Visitor interface {
+{{- range .Variants}}
+	Visit{{.Field}}({{.Type}}) error
+{{- end}}
+}
+
+// Accept calls the visitor method of the active variant.
+// It returns union.ErrNoCaseMatched if no variant or multiple variants are set.
+func (s {{.Name}}) Accept(v {{.Name}}Visitor) error {
+	variant, _ := union.Union[{{.Name}}]{Value: s}.Variant()
+	switch variant {
+{{- range .Variants}}
+	case {{printf "%q" .Name}}:
+		return v.Visit{{.Field}}(s.{{.Field}})
+{{- end}}
+	}
+	return union.ErrNoCaseMatched
+}
+
+// Switch{{.Name}} calls the function of the active variant of s and returns its result.
+// It takes one function per variant, so adding a variant to {{.Name}} breaks every call
+// until it handles the new variant. It returns union.ErrNoCaseMatched if no variant
+// or multiple variants are set.
+func Switch{{.Name}}[T any](s {{.Name}}
+{{- range .Variants}}, on{{.Field}} func({{.Type}}) (T, error){{end}}) (T, error) {
+	variant, _ := union.Union[{{.Name}}]{Value: s}.Variant()
+	switch variant {
+{{- range .Variants}}
+	case {{printf "%q" .Name}}:
+		return on{{.Field}}(s.{{.Field}})
+{{- end}}
+	}
+	var zero T
+	return zero, union.ErrNoCaseMatched
+}
+`))
+
+// generateGo returns the formatted Go source of the variant constants, visitor and switch helpers for the spec.
+func generateGo(sp *spec) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := goTemplate.Execute(&buf, sp); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return src, nil
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const shapeSource = `package shapes
+
+import "image/color"
+
+type Circle struct {
+	Radius float64 ` + "`json:\"radius\"`" + `
+}
+
+type Rectangle struct {
+	Width, Height float64
+}
+
+type Shape struct {
+	Circle    *Circle    ` + "`variant:\"circle\"`" + `
+	Rectangle *Rectangle ` + "`variant:\"rectangle\"`" + `
+	Fill      color.RGBA
+}
+`
+
+// writePackage writes the Go source files to a temporary directory and returns it.
+func writePackage(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestParseSpec(t *testing.T) {
+	dir := writePackage(t, map[string]string{"shape.go": shapeSource})
+
+	sp, err := parseSpec(dir, "Shape")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []variant{
+		{Field: "Circle", Name: "circle", Type: "*Circle"},
+		{Field: "Rectangle", Name: "rectangle", Type: "*Rectangle"},
+		{Field: "Fill", Name: "Fill", Type: "color.RGBA"},
+	}
+	if len(sp.Variants) != len(expected) {
+		t.Fatalf("expected %d variants, got %+v", len(expected), sp.Variants)
+	}
+	for i, v := range expected {
+		if sp.Variants[i] != v {
+			t.Errorf("expected variant %+v, got %+v", v, sp.Variants[i])
+		}
+	}
+	if len(sp.Imports) != 1 || sp.Imports[0] != `"image/color"` {
+		t.Errorf("expected image/color import, got %v", sp.Imports)
+	}
+}
+
+func TestParseSpecNaming(t *testing.T) {
+	dir := writePackage(t, map[string]string{"shape.go": shapeSource + `
+func (Shape) VariantNaming() union.Naming { return union.SnakeCase }
+
+type Pair struct {
+	FillColor *int
+	Fill_Color *int
+}
+
+func (Pair) VariantNaming() union.Naming { return union.SnakeCase }
+
+type Dynamic struct {
+	Fill *int
+}
+
+func (Dynamic) VariantNaming() union.Naming { return naming }
+`})
+
+	sp, err := parseSpec(dir, "Shape")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names := []string{sp.Variants[0].Name, sp.Variants[1].Name, sp.Variants[2].Name}
+	if expected := []string{"circle", "rectangle", "fill"}; !slices.Equal(names, expected) {
+		t.Errorf("expected variants %v, got %v", expected, names)
+	}
+
+	if _, err := parseSpec(dir, "Pair"); err == nil || err.Error() != `type Pair: duplicate variant "fill_color"` {
+		t.Errorf("expected duplicate variant error, got '%v'", err)
+	}
+	if _, err := parseSpec(dir, "Dynamic"); err == nil || err.Error() != "type Dynamic: VariantNaming must return a union.Naming constant" {
+		t.Errorf("expected naming error, got '%v'", err)
+	}
+}
+
+func TestParseSpecSkippedFields(t *testing.T) {
+	dir := writePackage(t, map[string]string{"shape.go": `package shapes
+
+type Shape struct {
+	Circle *int ` + "`variant:\"circle\"`" + `
+	Cached *int ` + "`variant:\"-\"`" + `
+	hits   int
+	Extras union.Extras
+}
+`})
+
+	sp, err := parseSpec(dir, "Shape")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sp.Variants) != 1 || sp.Variants[0].Field != "Circle" {
+		t.Errorf("expected only the Circle variant, got %+v", sp.Variants)
+	}
+}
+
+func TestParseSpecErrors(t *testing.T) {
+	dir := writePackage(t, map[string]string{"shape.go": `package shapes
+
+type NotStruct int
+
+type Duplicate struct {
+	A *int ` + "`variant:\"x\"`" + `
+	B *int ` + "`variant:\"x\"`" + `
+}
+`})
+
+	tests := []struct {
+		typeName    string
+		expectedErr string
+	}{
+		{typeName: "Missing", expectedErr: "type Missing not found"},
+		{typeName: "NotStruct", expectedErr: "type NotStruct is not a struct"},
+		{typeName: "Duplicate", expectedErr: `type Duplicate: duplicate variant "x"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typeName, func(t *testing.T) {
+			_, err := parseSpec(dir, tt.typeName)
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("expected error '%s', got '%v'", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestGenerateGo(t *testing.T) {
+	dir := writePackage(t, map[string]string{"shape.go": shapeSource})
+
+	sp, err := parseSpec(dir, "Shape")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	src, err := generateGo(sp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, s := range []string{
+		"// Code generated by uniongen; DO NOT EDIT.",
+		"package shapes",
+		`"image/color"`,
+		"type ShapeVariant string",
+		"ShapeVariantCircle    ShapeVariant = \"circle\"",
+		"ShapeVariantFill      ShapeVariant = \"Fill\"",
+		"type ShapeVisitor interface {",
+		"VisitCircle(*Circle) error",
+		"VisitFill(color.RGBA) error",
+		"func (s Shape) Accept(v ShapeVisitor) error {",
+		"case \"rectangle\":\n\t\treturn v.VisitRectangle(s.Rectangle)",
+		"func SwitchShape[T any](s Shape, onCircle func(*Circle) (T, error), onRectangle func(*Rectangle) (T, error), onFill func(color.RGBA) (T, error)) (T, error) {",
+		"case \"rectangle\":\n\t\treturn onRectangle(s.Rectangle)",
+	} {
+		if !strings.Contains(string(src), s) {
+			t.Errorf("expected generated code to contain %q, got:\n%s", s, src)
+		}
+	}
+}
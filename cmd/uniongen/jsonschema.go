@@ -0,0 +1,574 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// jsonSchema is the subset of a JSON Schema that spec generation reads.
+type jsonSchema struct {
+	Ref                  string         `json:"$ref"`
+	Description          string         `json:"description"`
+	Type                 schemaType     `json:"type"`
+	Format               string         `json:"format"`
+	ContentEncoding      string         `json:"contentEncoding"`
+	Const                any            `json:"const"`
+	Enum                 []any          `json:"enum"`
+	Nullable             bool           `json:"nullable"`
+	Properties           schemaMap      `json:"properties"`
+	Required             []string       `json:"required"`
+	AdditionalProperties *jsonSchema    `json:"additionalProperties"`
+	Items                *jsonSchema    `json:"items"`
+	AllOf                []*jsonSchema  `json:"allOf"`
+	OneOf                []*jsonSchema  `json:"oneOf"`
+	AnyOf                []*jsonSchema  `json:"anyOf"`
+	Discriminator        *discriminator `json:"discriminator"`
+	Defs                 schemaMap      `json:"$defs"`
+	Definitions          schemaMap      `json:"definitions"`
+}
+
+// discriminator is the discriminator keyword shared by OpenAPI and many JSON Schemas.
+type discriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping"`
+}
+
+// UnmarshalJSON decodes a schema, reading the boolean schemas true and false as
+// the empty schema, which is all spec generation needs of them.
+func (s *jsonSchema) UnmarshalJSON(data []byte) error {
+	if b := bytes.TrimSpace(data); string(b) == "true" || string(b) == "false" {
+		*s = jsonSchema{}
+		return nil
+	}
+	type plain jsonSchema
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+// schemaType is the type keyword, a single type name or a list of them.
+type schemaType []string
+
+func (t *schemaType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*t = schemaType{name}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// namedSchema is a schema with the name it is declared under.
+type namedSchema struct {
+	Name   string
+	Schema *jsonSchema
+}
+
+// schemaMap holds the schemas of an object keyword such as properties or $defs
+// in declaration order, so generated types keep the order of the document.
+type schemaMap []namedSchema
+
+func (m *schemaMap) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return fmt.Errorf("expected an object of schemas")
+	}
+	*m = nil
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		schema := new(jsonSchema)
+		if err := dec.Decode(schema); err != nil {
+			return err
+		}
+		*m = append(*m, namedSchema{Name: tok.(string), Schema: schema})
+	}
+	return nil
+}
+
+// get returns the schema named name.
+func (m schemaMap) get(name string) *jsonSchema {
+	for _, s := range m {
+		if s.Name == name {
+			return s.Schema
+		}
+	}
+	return nil
+}
+
+// parseJSONSchema decodes a JSON Schema or OpenAPI document and returns the schema
+// of the union named typeName along with the definitions its references resolve to.
+// In a JSON Schema, the union is the root schema if it has a oneOf or anyOf, and
+// otherwise the definition whose Go name is typeName. In an OpenAPI document, the
+// definitions are the component schemas and the union is the one named typeName.
+func parseJSONSchema(data []byte, typeName string) (*jsonSchema, schemaMap, error) {
+	var doc struct {
+		OpenAPI    string `json:"openapi"`
+		Components struct {
+			Schemas schemaMap `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, nil, err
+	}
+	if doc.OpenAPI != "" {
+		for _, def := range doc.Components.Schemas {
+			if goName(def.Name) == typeName {
+				return def.Schema, doc.Components.Schemas, nil
+			}
+		}
+		return nil, nil, fmt.Errorf("document has no component schema %s", typeName)
+	}
+
+	var root jsonSchema
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, nil, err
+	}
+	defs := append(slices.Clone(root.Defs), root.Definitions...)
+	if len(root.OneOf) > 0 || len(root.AnyOf) > 0 {
+		return &root, defs, nil
+	}
+	for _, def := range defs {
+		if goName(def.Name) == typeName {
+			return def.Schema, defs, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("schema has no oneOf and no definition %s", typeName)
+}
+
+// generateSpec returns the formatted Go source of a union spec struct named typeName
+// in package pkg for the oneOf or anyOf schema u, with a struct type for each payload
+// schema and for the definitions the payloads reference. A schema u without oneOf or
+// anyOf has a variant per schema of its discriminator mapping, the OpenAPI form for
+// payload schemas extending u with allOf. Payloads extending u get the properties of u,
+// and other references to u become TaggedUnions of the spec.
+//
+// Variants are named from the discriminator mapping, from the const or single enum
+// value of the discriminator property in each variant schema, or from the name of
+// the definition. The discriminator property is the propertyName of a discriminator
+// keyword, or the first property holding a const or single enum value in every
+// variant. With a discriminator property, the spec uses the flat representation and
+// the property is left out of the payload types, since the union writes it. Without
+// one, the spec uses the default envelope of TaggedUnion.
+func generateSpec(u *jsonSchema, defs schemaMap, typeName, pkg, source string) ([]byte, error) {
+	g := &specGen{
+		defs:     defs,
+		union:    u,
+		typeName: typeName,
+		defined:  map[string]bool{typeName: true},
+		omit:     make(map[string]string),
+		imports:  make(map[string]bool),
+	}
+
+	variants := u.OneOf
+	if len(variants) == 0 {
+		variants = u.AnyOf
+	}
+	if len(variants) == 0 && u.Discriminator != nil {
+		for _, key := range slices.Sorted(maps.Keys(u.Discriminator.Mapping)) {
+			ref := u.Discriminator.Mapping[key]
+			if !strings.Contains(ref, "/") {
+				// OpenAPI mappings may name the schema instead of referencing it
+				ref = "#/components/schemas/" + ref
+			}
+			variants = append(variants, &jsonSchema{Ref: ref})
+		}
+	}
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("schema of %s has no oneOf, anyOf or discriminator mapping", typeName)
+	}
+
+	property := ""
+	if u.Discriminator != nil {
+		property = u.Discriminator.PropertyName
+	}
+	if property == "" {
+		property = g.commonTag(variants)
+	}
+
+	var fields strings.Builder
+	seen := make(map[string]bool, len(variants))
+	for _, v := range variants {
+		name, typ, err := g.variant(v, u.Discriminator, property, typeName)
+		if err != nil {
+			return nil, err
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("schema of %s: duplicate variant %q", typeName, name)
+		}
+		seen[name] = true
+		fmt.Fprintf(&fields, "\t%s *%s `variant:%q`\n", typ, typ, name)
+	}
+	if g.err != nil {
+		return nil, g.err
+	}
+
+	var buf bytes.Buffer
+	if source != "" {
+		fmt.Fprintf(&buf, "// Code generated by uniongen from %s; DO NOT EDIT.\n\n", source)
+	} else {
+		buf.WriteString("// Code generated by uniongen; DO NOT EDIT.\n\n")
+	}
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	if len(g.imports) > 0 {
+		buf.WriteString("import (\n")
+		for _, imp := range slices.Sorted(maps.Keys(g.imports)) {
+			fmt.Fprintf(&buf, "\t%q\n", imp)
+		}
+		buf.WriteString(")\n\n")
+	}
+	if u.Description != "" {
+		writeComment(&buf, "", typeName+" is "+lowerFirst(u.Description))
+	}
+	fmt.Fprintf(&buf, "type %s struct {\n%s}\n", typeName, fields.String())
+	if property != "" {
+		fmt.Fprintf(&buf, "\nfunc (%s) JSONDiscriminator() string { return %q }\n", typeName, property)
+	}
+	for _, decl := range g.decls {
+		buf.WriteByte('\n')
+		buf.WriteString(decl)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return src, nil
+}
+
+// specGen maps schemas to Go types, adding a type declaration for every
+// definition and inline object schema that it encounters.
+type specGen struct {
+	defs     schemaMap         // definitions by name, in declaration order
+	union    *jsonSchema       // schema of the union
+	typeName string            // name of the spec struct
+	defined  map[string]bool   // Go types already declared or reserved
+	omit     map[string]string // discriminator property left out of the payload type by Go type name
+	imports  map[string]bool   // import paths of the generated types
+	decls    []string          // declarations in the order they were added
+	err      error             // first unresolved reference
+}
+
+// variant returns the variant name and the payload type name of the variant schema v.
+func (g *specGen) variant(v *jsonSchema, d *discriminator, property, typeName string) (string, string, error) {
+	name, typ, mapped := "", "", false
+	schema := v
+	if v.Ref != "" {
+		ref, err := refName(v.Ref)
+		if err != nil {
+			return "", "", err
+		}
+		if schema = g.defs.get(ref); schema == nil {
+			return "", "", fmt.Errorf("unresolved reference %s", v.Ref)
+		}
+		name, typ = ref, goName(ref)
+		if d != nil {
+			for key, target := range d.Mapping {
+				if target == v.Ref || target == ref {
+					name, mapped = key, true
+				}
+			}
+		}
+	}
+	if tag, ok := g.tag(schema, property); ok && !mapped {
+		name = tag
+	}
+	if name == "" {
+		return "", "", fmt.Errorf("schema of %s: cannot name an inline variant without a discriminator", typeName)
+	}
+	if typ == "" {
+		typ = typeName + goName(name)
+	}
+
+	if property != "" {
+		g.omit[typ] = property
+	}
+	g.define(typ, schema)
+	return name, typ, nil
+}
+
+// commonTag returns the first property of the first variant holding a constant
+// that every variant holds a constant in, or "" if there is none.
+func (g *specGen) commonTag(variants []*jsonSchema) string {
+	first := g.resolve(variants[0])
+	if first == nil {
+		return ""
+	}
+	for _, p := range g.properties(first) {
+		if slices.IndexFunc(variants, func(v *jsonSchema) bool {
+			_, ok := g.tag(g.resolve(v), p.Name)
+			return !ok
+		}) < 0 {
+			return p.Name
+		}
+	}
+	return ""
+}
+
+// tag returns the const or single enum string value of the property of s.
+func (g *specGen) tag(s *jsonSchema, property string) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	for _, p := range g.properties(s) {
+		if p.Name != property {
+			continue
+		}
+		if value, ok := p.Schema.Const.(string); ok {
+			return value, true
+		}
+		if len(p.Schema.Enum) == 1 {
+			value, ok := p.Schema.Enum[0].(string)
+			return value, ok
+		}
+	}
+	return "", false
+}
+
+// properties returns the properties of s, including those of its allOf schemas.
+func (g *specGen) properties(s *jsonSchema) schemaMap {
+	var props schemaMap
+	for _, part := range s.AllOf {
+		if part = g.resolve(part); part != nil {
+			props = append(props, g.properties(part)...)
+		}
+	}
+	return append(props, s.Properties...)
+}
+
+// resolve returns the schema s refers to, or s if it is not a reference.
+func (g *specGen) resolve(s *jsonSchema) *jsonSchema {
+	if s == nil || s.Ref == "" {
+		return s
+	}
+	name, err := refName(s.Ref)
+	if err != nil {
+		return nil
+	}
+	return g.defs.get(name)
+}
+
+// define declares the Go type typ for the schema s.
+func (g *specGen) define(typ string, s *jsonSchema) {
+	if g.defined[typ] {
+		return
+	}
+	g.defined[typ] = true
+	// reserve the declaration before building it so referenced types follow it
+	i := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	var buf strings.Builder
+	if s.Description != "" {
+		writeComment(&buf, "", typ+" is "+lowerFirst(s.Description))
+	}
+	if isObject(s) {
+		fmt.Fprintf(&buf, "type %s struct {\n", typ)
+		g.fields(&buf, typ, s, g.omit[typ])
+		buf.WriteString("}\n")
+	} else {
+		fmt.Fprintf(&buf, "type %s %s\n", typ, g.goType(s, typ, true))
+	}
+	g.decls[i] = buf.String()
+}
+
+// fields writes the struct fields of the object schema s, embedding the definitions
+// it extends with allOf and leaving out the property omit.
+func (g *specGen) fields(buf *strings.Builder, typ string, s *jsonSchema, omit string) {
+	for _, part := range s.AllOf {
+		if part.Ref != "" && g.resolve(part) == g.union {
+			g.fields(buf, typ, g.union, omit)
+			continue
+		}
+		if part.Ref != "" {
+			buf.WriteString("\t" + g.goType(part, typ, true) + "\n")
+			continue
+		}
+		g.fields(buf, typ, part, omit)
+	}
+	for _, p := range s.Properties {
+		if p.Name == omit {
+			continue
+		}
+		required := slices.Contains(s.Required, p.Name)
+		name := goName(p.Name)
+		if p.Schema.Description != "" {
+			writeComment(buf, "\t", name+" is "+lowerFirst(p.Schema.Description))
+		}
+		tag := p.Name
+		if !required {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(buf, "\t%s %s `json:%q`\n", name, g.goType(p.Schema, typ+name, required), tag)
+	}
+}
+
+// goType returns the Go type of the values of s, declaring a type named context for
+// inline objects. Optional objects and nullable values are pointers.
+func (g *specGen) goType(s *jsonSchema, context string, required bool) string {
+	if s.Ref != "" {
+		name, err := refName(s.Ref)
+		if err != nil {
+			g.fail(err)
+			return "any"
+		}
+		def := g.defs.get(name)
+		if def == nil {
+			g.fail(fmt.Errorf("unresolved reference %s", s.Ref))
+			return "any"
+		}
+		if def == g.union {
+			g.imports["github.com/eriicafes/union"] = true
+			return "union.TaggedUnion[" + g.typeName + "]"
+		}
+		typ := goName(name)
+		g.define(typ, def)
+		if isObject(def) && !required {
+			return "*" + typ
+		}
+		return typ
+	}
+	if len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+		return "any"
+	}
+
+	types := slices.DeleteFunc(slices.Clone(s.Type), func(t string) bool { return t == "null" })
+	nullable := len(types) < len(s.Type) || s.Nullable
+	if len(types) > 1 {
+		return "any"
+	}
+
+	var typ string
+	switch {
+	case isObject(s):
+		g.define(context, s)
+		typ, nullable = context, nullable || !required
+	case len(types) == 0:
+		return "any"
+	case types[0] == "string" && s.Format == "date-time":
+		g.imports["time"] = true
+		typ = "time.Time"
+	case types[0] == "string" && (s.Format == "byte" || s.ContentEncoding == "base64"):
+		return "[]byte"
+	case types[0] == "string":
+		typ = "string"
+	case types[0] == "integer" && s.Format == "int32":
+		typ = "int32"
+	case types[0] == "integer":
+		typ = "int64"
+	case types[0] == "number" && s.Format == "float":
+		typ = "float32"
+	case types[0] == "number":
+		typ = "float64"
+	case types[0] == "boolean":
+		typ = "bool"
+	case types[0] == "array":
+		if s.Items == nil {
+			return "[]any"
+		}
+		return "[]" + g.goType(s.Items, context+"Item", true)
+	case types[0] == "object":
+		if s.AdditionalProperties == nil {
+			return "map[string]any"
+		}
+		return "map[string]" + g.goType(s.AdditionalProperties, context+"Value", true)
+	default:
+		return "any"
+	}
+	if nullable {
+		return "*" + typ
+	}
+	return typ
+}
+
+// fail records the first error of the generation.
+func (g *specGen) fail(err error) {
+	if g.err == nil {
+		g.err = err
+	}
+}
+
+// isObject reports whether s is an object schema with properties, generated as a struct.
+func isObject(s *jsonSchema) bool {
+	if len(s.Properties) == 0 && len(s.AllOf) == 0 {
+		return false
+	}
+	return len(s.Type) == 0 || slices.Contains(s.Type, "object")
+}
+
+// refName returns the name of the definition a local reference such as
+// #/$defs/Created or #/components/schemas/Created points to.
+func refName(ref string) (string, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return "", fmt.Errorf("unsupported reference %s: only local references are supported", ref)
+	}
+	name := ref[strings.LastIndexByte(ref, '/')+1:]
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(name), nil
+}
+
+// initialisms are the words goName writes in upper case, following Go naming conventions.
+var initialisms = map[string]bool{
+	"API": true, "CPU": true, "DNS": true, "HTML": true, "HTTP": true, "HTTPS": true,
+	"ID": true, "IP": true, "JSON": true, "SQL": true, "TLS": true, "TTL": true,
+	"UI": true, "URI": true, "URL": true, "UTC": true, "UUID": true, "XML": true,
+}
+
+// goName returns an exported Go identifier for a schema or property name such as
+// created_at, user-id or orderPlaced, writing initialisms in upper case.
+func goName(s string) string {
+	var words []string
+	var word []rune
+	for _, r := range s {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			if len(word) > 0 {
+				words, word = append(words, string(word)), nil
+			}
+		case unicode.IsUpper(r) && len(word) > 0 && unicode.IsLower(word[len(word)-1]):
+			words, word = append(words, string(word)), []rune{r}
+		default:
+			word = append(word, r)
+		}
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+
+	var b strings.Builder
+	for _, w := range words {
+		if upper := strings.ToUpper(w); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		r := []rune(w)
+		b.WriteString(string(unicode.ToUpper(r[0])) + string(r[1:]))
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// lowerFirst returns s with its first letter in lower case, for doc comments
+// starting with the name of the declaration.
+func lowerFirst(s string) string {
+	r := []rune(strings.TrimSpace(s))
+	if len(r) > 1 && unicode.IsUpper(r[0]) && !unicode.IsUpper(r[1]) {
+		r[0] = unicode.ToLower(r[0])
+	}
+	return string(r)
+}
+
+// writeComment writes text as a line comment with the given indent.
+func writeComment(buf interface{ WriteString(string) (int, error) }, indent, text string) {
+	for line := range strings.SplitSeq(strings.TrimSpace(text), "\n") {
+		buf.WriteString(strings.TrimRight(indent+"// "+line, " ") + "\n")
+	}
+}
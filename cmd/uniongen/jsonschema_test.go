@@ -0,0 +1,360 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const eventSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "description": "An order event.",
+  "oneOf": [
+    {"$ref": "#/$defs/OrderPlaced"},
+    {"$ref": "#/$defs/order_cancelled"}
+  ],
+  "$defs": {
+    "Base": {
+      "type": "object",
+      "properties": {
+        "id": {"type": "string", "format": "uuid"},
+        "occurred_at": {"type": "string", "format": "date-time"}
+      },
+      "required": ["id", "occurred_at"]
+    },
+    "OrderPlaced": {
+      "description": "Emitted when a customer places an order.",
+      "allOf": [{"$ref": "#/$defs/Base"}],
+      "properties": {
+        "type": {"const": "order.placed"},
+        "items": {
+          "type": "array",
+          "items": {"type": "object", "properties": {"sku": {"type": "string"}, "quantity": {"type": "integer"}}, "required": ["sku", "quantity"]}
+        },
+        "shipping": {"$ref": "#/$defs/Address"},
+        "metadata": {"type": "object", "additionalProperties": {"type": "string"}}
+      },
+      "required": ["type", "items"]
+    },
+    "order_cancelled": {
+      "type": "object",
+      "properties": {
+        "type": {"enum": ["order.cancelled"]},
+        "reason": {"type": ["string", "null"]},
+        "refund": {"type": "number"}
+      },
+      "required": ["type"]
+    },
+    "Address": {
+      "type": "object",
+      "properties": {"line1": {"type": "string"}, "zip_code": {"type": "string"}},
+      "required": ["line1"]
+    }
+  }
+}`
+
+// generateSpecSource generates the spec named typeName from the JSON Schema src.
+func generateSpecSource(t *testing.T, src, typeName string) (string, error) {
+	t.Helper()
+
+	u, defs, err := parseJSONSchema([]byte(src), typeName)
+	if err != nil {
+		return "", err
+	}
+	out, err := generateSpec(u, defs, typeName, "events", "event.schema.json")
+	return string(out), err
+}
+
+func TestGenerateSpec(t *testing.T) {
+	src, err := generateSpecSource(t, eventSchema, "OrderEvent")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "// Code generated by uniongen from event.schema.json; DO NOT EDIT.\n" + `
+package events
+
+import (
+	"time"
+)
+
+// OrderEvent is an order event.
+type OrderEvent struct {
+	OrderPlaced    *OrderPlaced    ` + "`variant:\"order.placed\"`" + `
+	OrderCancelled *OrderCancelled ` + "`variant:\"order.cancelled\"`" + `
+}
+
+func (OrderEvent) JSONDiscriminator() string { return "type" }
+
+// OrderPlaced is emitted when a customer places an order.
+type OrderPlaced struct {
+	Base
+	Items    []OrderPlacedItemsItem ` + "`json:\"items\"`" + `
+	Shipping *Address               ` + "`json:\"shipping,omitempty\"`" + `
+	Metadata map[string]string      ` + "`json:\"metadata,omitempty\"`" + `
+}
+
+type Base struct {
+	ID         string    ` + "`json:\"id\"`" + `
+	OccurredAt time.Time ` + "`json:\"occurred_at\"`" + `
+}
+
+type OrderPlacedItemsItem struct {
+	Sku      string ` + "`json:\"sku\"`" + `
+	Quantity int64  ` + "`json:\"quantity\"`" + `
+}
+
+type Address struct {
+	Line1   string ` + "`json:\"line1\"`" + `
+	ZipCode string ` + "`json:\"zip_code,omitempty\"`" + `
+}
+
+type OrderCancelled struct {
+	Reason *string ` + "`json:\"reason,omitempty\"`" + `
+	Refund float64 ` + "`json:\"refund,omitempty\"`" + `
+}
+`
+	if src != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, src)
+	}
+
+	// the generated spec is read back by the other subcommands
+	sp, err := parseSpec(writePackage(t, map[string]string{"event.go": src}), "OrderEvent")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sp.VariantField != "type" || sp.ValueField != "" || sp.Variants[1].Name != "order.cancelled" {
+		t.Errorf("expected flat spec with variant order.cancelled, got %+v", sp)
+	}
+}
+
+const petsDocument = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Pets", "version": "1"},
+  "paths": {},
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "description": "A pet in the store.",
+        "properties": {
+          "petType": {"type": "string"},
+          "name": {"type": "string"},
+          "owner": {"$ref": "#/components/schemas/Owner"}
+        },
+        "required": ["petType", "name"],
+        "discriminator": {
+          "propertyName": "petType",
+          "mapping": {"dog": "#/components/schemas/Dog", "cat": "Cat"}
+        }
+      },
+      "Cat": {
+        "allOf": [
+          {"$ref": "#/components/schemas/Pet"},
+          {"type": "object", "properties": {"huntingSkill": {"type": "string", "enum": ["clueless", "lazy"]}}, "required": ["huntingSkill"]}
+        ]
+      },
+      "Dog": {
+        "allOf": [
+          {"$ref": "#/components/schemas/Pet"},
+          {"type": "object", "properties": {"packSize": {"type": "integer", "format": "int32", "nullable": true}}}
+        ]
+      },
+      "Owner": {
+        "type": "object",
+        "properties": {"id": {"type": "integer", "format": "int64"}, "pets": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}},
+        "required": ["id"]
+      }
+    }
+  }
+}`
+
+func TestGenerateSpecOpenAPI(t *testing.T) {
+	src, err := generateSpecSource(t, petsDocument, "Pet")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "// Code generated by uniongen from event.schema.json; DO NOT EDIT.\n" + `
+package events
+
+import (
+	"github.com/eriicafes/union"
+)
+
+// Pet is a pet in the store.
+type Pet struct {
+	Cat *Cat ` + "`variant:\"cat\"`" + `
+	Dog *Dog ` + "`variant:\"dog\"`" + `
+}
+
+func (Pet) JSONDiscriminator() string { return "petType" }
+
+type Cat struct {
+	Name         string ` + "`json:\"name\"`" + `
+	Owner        *Owner ` + "`json:\"owner,omitempty\"`" + `
+	HuntingSkill string ` + "`json:\"huntingSkill\"`" + `
+}
+
+type Owner struct {
+	ID   int64                    ` + "`json:\"id\"`" + `
+	Pets []union.TaggedUnion[Pet] ` + "`json:\"pets,omitempty\"`" + `
+}
+
+type Dog struct {
+	Name     string ` + "`json:\"name\"`" + `
+	Owner    *Owner ` + "`json:\"owner,omitempty\"`" + `
+	PackSize *int32 ` + "`json:\"packSize,omitempty\"`" + `
+}
+`
+	if src != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, src)
+	}
+}
+
+func TestGenerateSpecDiscriminator(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		contains []string
+	}{
+		{
+			name: "mapping",
+			schema: `{
+				"oneOf": [{"$ref": "#/definitions/Card"}, {"$ref": "#/definitions/BankTransfer"}],
+				"discriminator": {"propertyName": "method", "mapping": {"card": "#/definitions/Card", "bank_transfer": "#/definitions/BankTransfer"}},
+				"definitions": {
+					"Card": {"type": "object", "properties": {"method": {"type": "string"}, "last4": {"type": "string"}}},
+					"BankTransfer": {"type": "object", "properties": {"method": {"type": "string"}, "iban": {"type": "string"}}}
+				}
+			}`,
+			contains: []string{
+				"Card         *Card         `variant:\"card\"`",
+				"BankTransfer *BankTransfer `variant:\"bank_transfer\"`",
+				`func (Payment) JSONDiscriminator() string { return "method" }`,
+				"type Card struct {\n\tLast4 string `json:\"last4,omitempty\"`\n}",
+			},
+		},
+		{
+			name: "no discriminator",
+			schema: `{
+				"anyOf": [{"$ref": "#/$defs/Card"}, {"$ref": "#/$defs/Cash"}],
+				"$defs": {
+					"Card": {"type": "object", "properties": {"method": {"type": "string"}}},
+					"Cash": {"type": "string", "description": "The currency paid in."}
+				}
+			}`,
+			contains: []string{
+				"Card *Card `variant:\"Card\"`",
+				"Cash *Cash `variant:\"Cash\"`",
+				"type Card struct {\n\tMethod string `json:\"method,omitempty\"`\n}",
+				"// Cash is the currency paid in.\ntype Cash string",
+			},
+		},
+		{
+			name: "inline variants",
+			schema: `{
+				"$defs": {
+					"payment": {
+						"oneOf": [
+							{"type": "object", "properties": {"kind": {"const": "card"}, "id": {"type": "integer", "format": "int32"}}},
+							{"type": "object", "properties": {"kind": {"const": "cash"}}}
+						]
+					}
+				}
+			}`,
+			contains: []string{
+				"PaymentCard *PaymentCard `variant:\"card\"`",
+				`func (Payment) JSONDiscriminator() string { return "kind" }`,
+				"type PaymentCard struct {\n\tID int32 `json:\"id,omitempty\"`\n}",
+				"type PaymentCash struct {\n}",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := generateSpecSource(t, tt.schema, "Payment")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(src, s) {
+					t.Errorf("expected generated code to contain %q, got:\n%s", s, src)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateSpecErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		schema      string
+		expectedErr string
+	}{
+		{
+			name:        "no union",
+			schema:      `{"type": "object"}`,
+			expectedErr: "schema has no oneOf and no definition Payment",
+		},
+		{
+			name:        "missing component schema",
+			schema:      `{"openapi": "3.1.0", "components": {"schemas": {"Card": {"type": "object"}}}}`,
+			expectedErr: "document has no component schema Payment",
+		},
+		{
+			name:        "no variants",
+			schema:      `{"openapi": "3.1.0", "components": {"schemas": {"Payment": {"type": "object"}}}}`,
+			expectedErr: "schema of Payment has no oneOf, anyOf or discriminator mapping",
+		},
+		{
+			name:        "unresolved reference",
+			schema:      `{"oneOf": [{"$ref": "#/$defs/Card"}]}`,
+			expectedErr: "unresolved reference #/$defs/Card",
+		},
+		{
+			name:        "remote reference",
+			schema:      `{"oneOf": [{"$ref": "card.json"}]}`,
+			expectedErr: "unsupported reference card.json: only local references are supported",
+		},
+		{
+			name:        "unnamed inline variant",
+			schema:      `{"oneOf": [{"type": "object", "properties": {"id": {"type": "string"}}}]}`,
+			expectedErr: "schema of Payment: cannot name an inline variant without a discriminator",
+		},
+		{
+			name: "duplicate variant",
+			schema: `{"oneOf": [{"$ref": "#/$defs/A"}, {"$ref": "#/$defs/B"}], "$defs": {
+				"A": {"properties": {"kind": {"const": "x"}}},
+				"B": {"properties": {"kind": {"const": "x"}}}
+			}}`,
+			expectedErr: `schema of Payment: duplicate variant "x"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generateSpecSource(t, tt.schema, "Payment")
+			if err == nil || err.Error() != tt.expectedErr {
+				t.Errorf("expected error '%s', got '%v'", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"created_at":   "CreatedAt",
+		"user-id":      "UserID",
+		"orderPlaced":  "OrderPlaced",
+		"order.placed": "OrderPlaced",
+		"apiURL":       "APIURL",
+		"3ds":          "X3ds",
+		"":             "X",
+	}
+	for name, expected := range tests {
+		if got := goName(name); got != expected {
+			t.Errorf("goName(%q): expected %v, got %v", name, expected, got)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// Uniongen generates code for union spec structs.
+//
+// Usage:
+//
+//	uniongen go -type Shape [-output shape_union.go] [-dir .]
+//	uniongen openapi -type Shape [-output shape_openapi.json] [-dir .]
+//	uniongen ts -type Shape [-output shape_union.ts] [-dir .]
+//	uniongen jsonschema -type Event -input event.schema.json [-output event_spec.go] [-dir .] [-package events]
+//
+// The go subcommand reads the spec struct named by -type from the Go package
+// in -dir and writes a companion file to the same package containing:
+//
+//   - A ShapeVariant string type with a ShapeVariantCircle style constant per variant
+//   - A ShapeVisitor interface with one Visit method per variant
+//   - An Accept(v ShapeVisitor) error method on the spec struct
+//   - A SwitchShape function taking one function per variant and returning the result of the active one
+//
+// The openapi subcommand writes an OpenAPI 3.1 document with the component
+// schemas of the TaggedUnion JSON representation of the spec, including a
+// discriminator mapping each variant name to its schema, and the schemas of
+// the payload types declared in the package. Field names declared by a
+// JSONDiscriminator method on the spec are honored.
+//
+// The ts subcommand writes TypeScript definitions of the TaggedUnion JSON
+// representation of the spec as a discriminated union type, such as
+// { type: "circle"; value: Circle } | ..., followed by interfaces for the
+// payload types declared in the package.
+//
+// The jsonschema subcommand goes the other way: it reads a JSON Schema whose
+// root, or whose definition named after -type, is a oneOf or anyOf of variant
+// schemas and writes the spec struct with its variant tags, along with structs
+// for the payload schemas and the definitions they reference. A discriminator
+// keyword, or a property holding a const in every variant, selects the flat
+// representation with a JSONDiscriminator method; the package name defaults to
+// that of the Go files in -dir. The input may also be an OpenAPI document, whose
+// component schema named after -type is the union, either with a oneOf or with a
+// discriminator mapping to schemas extending it with allOf.
+//
+// They are typically invoked from a go:generate directive next to the spec:
+//
+//	//go:generate go run github.com/eriicafes/union/cmd/uniongen go -type Shape
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "go":
+		err = runGo(args)
+	case "openapi":
+		err = runOpenAPI(args)
+	case "ts":
+		err = runTS(args)
+	case "jsonschema":
+		err = runJSONSchema(args)
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "uniongen:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: uniongen go -type Spec [-output file] [-dir dir]")
+	fmt.Fprintln(os.Stderr, "       uniongen openapi -type Spec [-output file] [-dir dir]")
+	fmt.Fprintln(os.Stderr, "       uniongen ts -type Spec [-output file] [-dir dir]")
+	fmt.Fprintln(os.Stderr, "       uniongen jsonschema -type Spec -input file [-output file] [-dir dir] [-package name]")
+	os.Exit(2)
+}
+
+func runGo(args []string) error {
+	fs := flag.NewFlagSet("go", flag.ExitOnError)
+	typeName := fs.String("type", "", "name of the spec struct type (required)")
+	output := fs.String("output", "", "output file name (default <type>_union.go)")
+	dir := fs.String("dir", ".", "directory of the package containing the spec")
+	fs.Parse(args)
+
+	if *typeName == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	spec, err := parseSpec(*dir, *typeName)
+	if err != nil {
+		return err
+	}
+	src, err := generateGo(spec)
+	if err != nil {
+		return err
+	}
+	return writeOutput(*dir, *output, defaultOutput(spec.Name, "_union.go"), src)
+}
+
+func runOpenAPI(args []string) error {
+	fs := flag.NewFlagSet("openapi", flag.ExitOnError)
+	typeName := fs.String("type", "", "name of the spec struct type (required)")
+	output := fs.String("output", "", "output file name (default <type>_openapi.json)")
+	dir := fs.String("dir", ".", "directory of the package containing the spec")
+	fs.Parse(args)
+
+	if *typeName == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	spec, err := parseSpec(*dir, *typeName)
+	if err != nil {
+		return err
+	}
+	src, err := generateOpenAPI(spec)
+	if err != nil {
+		return err
+	}
+	return writeOutput(*dir, *output, defaultOutput(spec.Name, "_openapi.json"), src)
+}
+
+func runTS(args []string) error {
+	fs := flag.NewFlagSet("ts", flag.ExitOnError)
+	typeName := fs.String("type", "", "name of the spec struct type (required)")
+	output := fs.String("output", "", "output file name (default <type>_union.ts)")
+	dir := fs.String("dir", ".", "directory of the package containing the spec")
+	fs.Parse(args)
+
+	if *typeName == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	spec, err := parseSpec(*dir, *typeName)
+	if err != nil {
+		return err
+	}
+	src, err := generateTS(spec)
+	if err != nil {
+		return err
+	}
+	return writeOutput(*dir, *output, defaultOutput(spec.Name, "_union.ts"), src)
+}
+
+func runJSONSchema(args []string) error {
+	fs := flag.NewFlagSet("jsonschema", flag.ExitOnError)
+	typeName := fs.String("type", "", "name of the generated spec struct type (required)")
+	input := fs.String("input", "", "JSON Schema or OpenAPI document in JSON (required)")
+	output := fs.String("output", "", "output file name (default <type>_spec.go)")
+	dir := fs.String("dir", ".", "directory of the package to write the spec to")
+	pkg := fs.String("package", "", "package name (default the package in -dir)")
+	fs.Parse(args)
+
+	if *typeName == "" || *input == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(*input)
+	if err != nil {
+		return err
+	}
+	u, defs, err := parseJSONSchema(data, *typeName)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *input, err)
+	}
+	if *pkg == "" {
+		if *pkg, err = packageName(*dir); err != nil {
+			return err
+		}
+	}
+	src, err := generateSpec(u, defs, *typeName, *pkg, filepath.Base(*input))
+	if err != nil {
+		return err
+	}
+	return writeOutput(*dir, *output, defaultOutput(*typeName, "_spec.go"), src)
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"maps"
+	"slices"
+)
+
+// generateOpenAPI returns an OpenAPI 3.1 document containing the component schemas
+// of the TaggedUnion representation of the spec and of the payload types declared
+// in the spec's package.
+//
+// The union schema is a oneOf of one schema per variant, named <Spec><Field>, with a
+// discriminator whose mapping points each variant name to its schema. Variant schemas
+// hold the variant name as a const in the variant field and the payload in the value
+// field, or extend the payload with the variant field in the flat representation.
+func generateOpenAPI(sp *spec) ([]byte, error) {
+	g := &schemaGen{types: sp.types, schemas: make(map[string]any)}
+
+	oneOf := make([]any, 0, len(sp.Variants))
+	mapping := make(map[string]string, len(sp.Variants))
+	for _, v := range sp.Variants {
+		payload := g.schema(parseTypeExpr(v.Type))
+		tag := map[string]any{"const": v.Name}
+
+		var schema map[string]any
+		if sp.ValueField == "" {
+			schema = map[string]any{"allOf": []any{payload, map[string]any{
+				"type":       "object",
+				"properties": map[string]any{sp.VariantField: tag},
+				"required":   []string{sp.VariantField},
+			}}}
+		} else {
+			schema = map[string]any{
+				"type":       "object",
+				"properties": map[string]any{sp.VariantField: tag, sp.ValueField: payload},
+				"required":   []string{sp.VariantField, sp.ValueField},
+			}
+		}
+
+		name := sp.Name + v.Field
+		g.schemas[name] = schema
+		oneOf = append(oneOf, schemaRef(name))
+		mapping[v.Name] = schemaRef(name)["$ref"].(string)
+	}
+	g.schemas[sp.Name] = map[string]any{
+		"oneOf": oneOf,
+		"discriminator": map[string]any{
+			"propertyName": sp.VariantField,
+			"mapping":      mapping,
+		},
+	}
+
+	doc := map[string]any{
+		"openapi":    "3.1.0",
+		"components": map[string]any{"schemas": g.schemas},
+	}
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// schemaRef returns a reference to the component schema named name.
+func schemaRef(name string) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+// parseTypeExpr parses a type expression recorded by parseSpec.
+func parseTypeExpr(src string) ast.Expr {
+	expr, err := parser.ParseExpr(src)
+	if err != nil {
+		return &ast.Ident{Name: "any"}
+	}
+	return expr
+}
+
+// schemaGen builds JSON schemas for Go type expressions, adding a component
+// schema for every type declared in the spec's package that it encounters.
+type schemaGen struct {
+	types   map[string]ast.Expr // package-level type declarations by name
+	schemas map[string]any      // component schemas by name
+}
+
+// schema returns the JSON schema of the values encoding/json produces for expr.
+// Types declared outside the package, other than time.Time, accept any value.
+func (g *schemaGen) schema(expr ast.Expr) map[string]any {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return g.schema(e.X)
+	case *ast.Ident:
+		switch e.Name {
+		case "string":
+			return map[string]any{"type": "string"}
+		case "bool":
+			return map[string]any{"type": "boolean"}
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte", "rune":
+			return map[string]any{"type": "integer"}
+		case "float32", "float64":
+			return map[string]any{"type": "number"}
+		}
+		if decl, ok := g.types[e.Name]; ok {
+			g.define(e.Name, decl)
+			return schemaRef(e.Name)
+		}
+	case *ast.SelectorExpr:
+		if x, ok := e.X.(*ast.Ident); ok && x.Name == "time" && e.Sel.Name == "Time" {
+			return map[string]any{"type": "string", "format": "date-time"}
+		}
+	case *ast.ArrayType:
+		if id, ok := e.Elt.(*ast.Ident); ok && e.Len == nil && (id.Name == "byte" || id.Name == "uint8") {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": "array", "items": g.schema(e.Elt)}
+	case *ast.MapType:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(e.Value)}
+	case *ast.StructType:
+		return g.object(e)
+	}
+	return map[string]any{}
+}
+
+// define adds the component schema of the package type name declared as expr.
+func (g *schemaGen) define(name string, expr ast.Expr) {
+	if _, ok := g.schemas[name]; ok {
+		return
+	}
+	// register the schema before building it so recursive types refer to it
+	schema := make(map[string]any)
+	g.schemas[name] = schema
+	maps.Copy(schema, g.schema(expr))
+}
+
+// object returns the schema of a struct type, following encoding/json field rules.
+func (g *schemaGen) object(st *ast.StructType) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+	for _, f := range jsonFields(st, g.types) {
+		properties[f.Key] = g.schema(f.Type)
+		if !f.Optional {
+			required = append(required, f.Key)
+		}
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		slices.Sort(required)
+		schema["required"] = required
+	}
+	return schema
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const eventSource = `package events
+
+import "time"
+
+type Base struct {
+	ID   string    ` + "`json:\"id\"`" + `
+	Time time.Time ` + "`json:\"time\"`" + `
+}
+
+type Created struct {
+	Base
+	Tags []string ` + "`json:\"tags,omitempty\"`" + `
+}
+
+type Deleted struct {
+	Base
+	Reason string ` + "`json:\"-\"`" + `
+}
+
+type Event struct {
+	Created *Created ` + "`variant:\"created\"`" + `
+	Deleted *Deleted ` + "`variant:\"deleted\"`" + `
+}
+
+func (Event) JSONDiscriminator() string { return "kind" }
+`
+
+// generateOpenAPIDoc generates the OpenAPI document of the spec and decodes its component schemas.
+func generateOpenAPIDoc(t *testing.T, src, typeName string) map[string]any {
+	t.Helper()
+
+	dir := writePackage(t, map[string]string{"spec.go": src})
+	sp, err := parseSpec(dir, typeName)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := generateOpenAPI(sp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var doc struct {
+		OpenAPI    string `json:"openapi"`
+		Components struct {
+			Schemas map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("expected openapi 3.1.0, got %q", doc.OpenAPI)
+	}
+	return doc.Components.Schemas
+}
+
+// assertSchema checks that the JSON encoding of a generated schema equals expected.
+func assertSchema(t *testing.T, schemas map[string]any, name, expected string) {
+	t.Helper()
+
+	var want any
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(schemas[name], want) {
+		got, _ := json.Marshal(schemas[name])
+		t.Errorf("schema %s: expected %s, got %s", name, expected, got)
+	}
+}
+
+func TestGenerateOpenAPI(t *testing.T) {
+	schemas := generateOpenAPIDoc(t, shapeSource, "Shape")
+
+	assertSchema(t, schemas, "Shape", `{
+		"oneOf": [
+			{"$ref": "#/components/schemas/ShapeCircle"},
+			{"$ref": "#/components/schemas/ShapeRectangle"},
+			{"$ref": "#/components/schemas/ShapeFill"}
+		],
+		"discriminator": {
+			"propertyName": "type",
+			"mapping": {
+				"circle": "#/components/schemas/ShapeCircle",
+				"rectangle": "#/components/schemas/ShapeRectangle",
+				"Fill": "#/components/schemas/ShapeFill"
+			}
+		}
+	}`)
+	assertSchema(t, schemas, "ShapeCircle", `{
+		"type": "object",
+		"properties": {
+			"type": {"const": "circle"},
+			"value": {"$ref": "#/components/schemas/Circle"}
+		},
+		"required": ["type", "value"]
+	}`)
+	assertSchema(t, schemas, "ShapeFill", `{
+		"type": "object",
+		"properties": {"type": {"const": "Fill"}, "value": {}},
+		"required": ["type", "value"]
+	}`)
+	assertSchema(t, schemas, "Circle", `{
+		"type": "object",
+		"properties": {"radius": {"type": "number"}},
+		"required": ["radius"]
+	}`)
+	assertSchema(t, schemas, "Rectangle", `{
+		"type": "object",
+		"properties": {"Width": {"type": "number"}, "Height": {"type": "number"}},
+		"required": ["Height", "Width"]
+	}`)
+}
+
+func TestGenerateOpenAPIFlat(t *testing.T) {
+	schemas := generateOpenAPIDoc(t, eventSource, "Event")
+
+	assertSchema(t, schemas, "EventCreated", `{
+		"allOf": [
+			{"$ref": "#/components/schemas/Created"},
+			{"type": "object", "properties": {"kind": {"const": "created"}}, "required": ["kind"]}
+		]
+	}`)
+	assertSchema(t, schemas, "Created", `{
+		"type": "object",
+		"properties": {
+			"id": {"type": "string"},
+			"time": {"type": "string", "format": "date-time"},
+			"tags": {"type": "array", "items": {"type": "string"}}
+		},
+		"required": ["id", "time"]
+	}`)
+	assertSchema(t, schemas, "Deleted", `{
+		"type": "object",
+		"properties": {
+			"id": {"type": "string"},
+			"time": {"type": "string", "format": "date-time"}
+		},
+		"required": ["id", "time"]
+	}`)
+	if _, ok := schemas["Base"]; ok {
+		t.Error("expected embedded struct to be inlined")
+	}
+}
+
+func TestParseSpecDiscriminator(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		variantField  string
+		valueField    string
+		expectedError string
+	}{
+		{name: "defaults", method: "", variantField: "type", valueField: "value"},
+		{name: "flat", method: `func (Shape) JSONDiscriminator() string { return "kind" }`, variantField: "kind"},
+		{name: "custom", method: `func (*Shape) JSONDiscriminator() (string, string) { return "kind", "" }`, variantField: "kind", valueField: "value"},
+		{name: "not literal", method: `func (Shape) JSONDiscriminator() string { return name }`, expectedError: "type Shape: JSONDiscriminator must return string literals"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writePackage(t, map[string]string{"shape.go": shapeSource + tt.method})
+			sp, err := parseSpec(dir, "Shape")
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("expected error '%s', got '%v'", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sp.VariantField != tt.variantField || sp.ValueField != tt.valueField {
+				t.Errorf("expected fields %q, %q, got %q, %q", tt.variantField, tt.valueField, sp.VariantField, sp.ValueField)
+			}
+		})
+	}
+}
@@ -0,0 +1,412 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/eriicafes/union"
+)
+
+// spec describes a union spec struct parsed from Go source.
+type spec struct {
+	Package  string    // package name
+	Name     string    // spec struct type name
+	Variants []variant // variant fields in declaration order
+	Imports  []string  // import specs referenced by variant field types
+
+	// VariantField and ValueField are the TaggedUnion JSON field names declared by
+	// a JSONDiscriminator method on the spec. ValueField is empty for the flat representation.
+	VariantField, ValueField string
+
+	types    map[string]ast.Expr // package-level type declarations by name
+	untagged map[string]bool     // fields of variants without a `variant` struct tag
+}
+
+// variant describes a single variant field of a spec struct.
+type variant struct {
+	Field string // struct field name
+	Name  string // variant name from the `variant` struct tag or the field name
+	Type  string // field type expression
+}
+
+// parseSpec parses the Go package in dir and returns the spec struct named typeName.
+func parseSpec(dir, typeName string) (*spec, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var parsed []*ast.File
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, file)
+	}
+
+	for _, file := range parsed {
+		sp, ok, err := findSpec(file, typeName)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		sp.types = typeDecls(parsed)
+		if err := sp.readDiscriminator(parsed); err != nil {
+			return nil, err
+		}
+		if err := sp.readNaming(parsed); err != nil {
+			return nil, err
+		}
+		return sp, nil
+	}
+	return nil, fmt.Errorf("type %s not found in %s", typeName, dir)
+}
+
+// typeDecls returns the type expressions of the package-level types declared in files.
+func typeDecls(files []*ast.File) map[string]ast.Expr {
+	decls := make(map[string]ast.Expr)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, s := range gen.Specs {
+				ts := s.(*ast.TypeSpec)
+				decls[ts.Name.Name] = ts.Type
+			}
+		}
+	}
+	return decls
+}
+
+// readDiscriminator sets the JSON field names from the spec's JSONDiscriminator method,
+// defaulting to "type" and "value" like TaggedUnion. The method must return string literals.
+func (sp *spec) readDiscriminator(files []*ast.File) error {
+	sp.VariantField, sp.ValueField = "type", "value"
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Name.Name != "JSONDiscriminator" || fn.Recv == nil || receiverName(fn.Recv) != sp.Name {
+				continue
+			}
+			values, ok := returnedStrings(fn)
+			if !ok {
+				return fmt.Errorf("type %s: JSONDiscriminator must return string literals", sp.Name)
+			}
+			switch len(values) {
+			case 1:
+				if values[0] != "" {
+					sp.VariantField, sp.ValueField = values[0], ""
+				}
+			case 2:
+				sp.VariantField = cmp.Or(values[0], sp.VariantField)
+				sp.ValueField = cmp.Or(values[1], sp.ValueField)
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// namings maps the names of the union.Naming constants to their values.
+var namings = map[string]union.Naming{
+	"ExactNaming": union.ExactNaming,
+	"SnakeCase":   union.SnakeCase,
+	"CamelCase":   union.CamelCase,
+	"KebabCase":   union.KebabCase,
+	"LowerCase":   union.LowerCase,
+}
+
+// readNaming converts the names of untagged variants with the spec's VariantNaming method,
+// which must return a union.Naming constant, and checks the resulting names are unique.
+func (sp *spec) readNaming(files []*ast.File) error {
+	naming := union.ExactNaming
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Name.Name != "VariantNaming" || fn.Recv == nil || receiverName(fn.Recv) != sp.Name {
+				continue
+			}
+			n, ok := returnedNaming(fn)
+			if !ok {
+				return fmt.Errorf("type %s: VariantNaming must return a union.Naming constant", sp.Name)
+			}
+			naming = n
+		}
+	}
+
+	seen := make(map[string]bool, len(sp.Variants))
+	for i := range sp.Variants {
+		v := &sp.Variants[i]
+		if sp.untagged[v.Field] {
+			v.Name = naming.Apply(v.Field)
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("type %s: duplicate variant %q", sp.Name, v.Name)
+		}
+		seen[v.Name] = true
+	}
+	return nil
+}
+
+// returnedNaming returns the union.Naming constant returned by a function made of a single return statement.
+func returnedNaming(fn *ast.FuncDecl) (union.Naming, bool) {
+	if fn.Body == nil || len(fn.Body.List) != 1 {
+		return 0, false
+	}
+	ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return 0, false
+	}
+	sel, ok := ret.Results[0].(*ast.SelectorExpr)
+	if !ok {
+		return 0, false
+	}
+	naming, ok := namings[sel.Sel.Name]
+	return naming, ok
+}
+
+// receiverName returns the type name of a method receiver.
+func receiverName(recv *ast.FieldList) string {
+	expr := recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if id, ok := expr.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+// returnedStrings returns the string literals of a function made of a single return statement.
+func returnedStrings(fn *ast.FuncDecl) ([]string, bool) {
+	if fn.Body == nil || len(fn.Body.List) != 1 {
+		return nil, false
+	}
+	ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+	if !ok {
+		return nil, false
+	}
+	values := make([]string, len(ret.Results))
+	for i, result := range ret.Results {
+		lit, ok := result.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return nil, false
+		}
+		value, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return nil, false
+		}
+		values[i] = value
+	}
+	return values, true
+}
+
+// findSpec looks up the spec struct named typeName in file.
+func findSpec(file *ast.File, typeName string) (*spec, bool, error) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, s := range gen.Specs {
+			ts := s.(*ast.TypeSpec)
+			if ts.Name.Name != typeName {
+				continue
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				return nil, true, fmt.Errorf("type %s is not a struct", typeName)
+			}
+			sp, err := newSpec(file, typeName, st)
+			return sp, true, err
+		}
+	}
+	return nil, false, nil
+}
+
+func newSpec(file *ast.File, typeName string, st *ast.StructType) (*spec, error) {
+	sp := &spec{Package: file.Name.Name, Name: typeName, untagged: make(map[string]bool)}
+	used := make(map[string]bool)
+
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 0 {
+			return nil, fmt.Errorf("type %s: embedded fields are not supported", typeName)
+		}
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			value, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			tag = reflect.StructTag(value)
+		}
+		// union.Extras fields capture envelope members and are not variants
+		if sel, ok := field.Type.(*ast.SelectorExpr); ok && sel.Sel.Name == "Extras" {
+			if id, ok := sel.X.(*ast.Ident); ok && id.Name == "union" {
+				continue
+			}
+		}
+		ast.Inspect(field.Type, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if id, ok := sel.X.(*ast.Ident); ok {
+					used[id.Name] = true
+				}
+			}
+			return true
+		})
+
+		for _, name := range field.Names {
+			// unexported and `variant:"-"` fields are not variants
+			if !name.IsExported() || tag.Get("variant") == "-" {
+				continue
+			}
+			v := variant{
+				Field: name.Name,
+				Name:  cmp.Or(tag.Get("variant"), name.Name),
+				Type:  types.ExprString(field.Type),
+			}
+			if tag.Get("variant") == "" {
+				sp.untagged[name.Name] = true
+			}
+			sp.Variants = append(sp.Variants, v)
+		}
+	}
+
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		name := filepath.Base(path)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if used[name] {
+			spec := imp.Path.Value
+			if imp.Name != nil {
+				spec = imp.Name.Name + " " + spec
+			}
+			sp.Imports = append(sp.Imports, spec)
+		}
+	}
+	return sp, nil
+}
+
+// defaultOutput returns the default output file name for the spec type name.
+func defaultOutput(typeName, suffix string) string {
+	return strings.ToLower(typeName) + suffix
+}
+
+// packageName returns the name of the Go package in dir, or the name of dir
+// if it holds no Go files.
+func packageName(dir string) (string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), name, nil, parser.PackageClauseOnly)
+		if err != nil {
+			return "", err
+		}
+		return file.Name.Name, nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	name := strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, filepath.Base(abs))
+	if !token.IsIdentifier(name) {
+		return "", fmt.Errorf("cannot derive a package name from %s, set -package", dir)
+	}
+	return name, nil
+}
+
+// writeOutput writes src to output, or to defaultName in dir if output is empty.
+func writeOutput(dir, output, defaultName string, src []byte) error {
+	if output == "" {
+		output = filepath.Join(dir, defaultName)
+	}
+	return os.WriteFile(output, src, 0o644)
+}
+
+// jsonField describes a property of the JSON object encoding/json produces for a struct.
+type jsonField struct {
+	Key      string   // JSON object key
+	Type     ast.Expr // field type expression
+	Optional bool     // whether the field is tagged omitempty or omitzero
+}
+
+// jsonFields returns the JSON properties of the struct's fields in declaration order,
+// inlining the fields of untagged embedded structs declared in the package.
+func jsonFields(st *ast.StructType, types map[string]ast.Expr) []jsonField {
+	var fields []jsonField
+	for _, field := range st.Fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			if value, err := strconv.Unquote(field.Tag.Value); err == nil {
+				tag = reflect.StructTag(value)
+			}
+		}
+		jsonTag := tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		key, opts, _ := strings.Cut(jsonTag, ",")
+		optional := slices.ContainsFunc(strings.Split(opts, ","), func(opt string) bool {
+			return opt == "omitempty" || opt == "omitzero"
+		})
+
+		names := field.Names
+		if len(names) == 0 {
+			typ := field.Type
+			if star, ok := typ.(*ast.StarExpr); ok {
+				typ = star.X
+			}
+			id, ok := typ.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			if embedded, ok := types[id.Name].(*ast.StructType); ok && key == "" {
+				fields = append(fields, jsonFields(embedded, types)...)
+				continue
+			}
+			names = []*ast.Ident{id}
+		}
+
+		for _, name := range names {
+			if name.IsExported() {
+				fields = append(fields, jsonField{Key: cmp.Or(key, name.Name), Type: field.Type, Optional: optional})
+			}
+		}
+	}
+	return fields
+}
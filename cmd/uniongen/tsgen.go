@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"regexp"
+	"strconv"
+)
+
+// generateTS returns TypeScript definitions of the TaggedUnion JSON representation
+// of the spec as a discriminated union type, followed by the payload types declared
+// in the spec's package in the order they are first referenced.
+func generateTS(sp *spec) ([]byte, error) {
+	g := &tsGen{types: sp.types, declared: make(map[string]bool)}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by uniongen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "export type %s =\n", sp.Name)
+	for i, v := range sp.Variants {
+		payload := g.typ(stripPointer(parseTypeExpr(v.Type)))
+		tag := fmt.Sprintf("%s: %s", tsKey(sp.VariantField), strconv.Quote(v.Name))
+
+		if sp.ValueField == "" {
+			fmt.Fprintf(&buf, "  | ({ %s } & %s)", tag, payload)
+		} else {
+			fmt.Fprintf(&buf, "  | { %s; %s: %s }", tag, tsKey(sp.ValueField), payload)
+		}
+		if i == len(sp.Variants)-1 {
+			buf.WriteByte(';')
+		}
+		buf.WriteByte('\n')
+	}
+
+	for _, decl := range g.decls {
+		buf.WriteByte('\n')
+		buf.WriteString(decl)
+	}
+	return buf.Bytes(), nil
+}
+
+// stripPointer returns the element type of a pointer type expression.
+// The value of an active variant is never null, even for pointer fields.
+func stripPointer(expr ast.Expr) ast.Expr {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		return star.X
+	}
+	return expr
+}
+
+// tsGen maps Go type expressions to TypeScript types, adding a declaration
+// for every type declared in the spec's package that it encounters.
+type tsGen struct {
+	types    map[string]ast.Expr // package-level type declarations by name
+	declared map[string]bool     // package types already declared
+	decls    []string            // declarations in the order they were added
+}
+
+// typ returns the TypeScript type of the values encoding/json produces for expr.
+// Types declared outside the package, other than time.Time, map to unknown.
+func (g *tsGen) typ(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return g.typ(e.X) + " | null"
+	case *ast.Ident:
+		switch e.Name {
+		case "string":
+			return "string"
+		case "bool":
+			return "boolean"
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte", "rune",
+			"float32", "float64":
+			return "number"
+		}
+		if decl, ok := g.types[e.Name]; ok {
+			g.declare(e.Name, decl)
+			return e.Name
+		}
+	case *ast.SelectorExpr:
+		if x, ok := e.X.(*ast.Ident); ok && x.Name == "time" && e.Sel.Name == "Time" {
+			return "string"
+		}
+	case *ast.ArrayType:
+		if id, ok := e.Elt.(*ast.Ident); ok && e.Len == nil && (id.Name == "byte" || id.Name == "uint8") {
+			return "string"
+		}
+		elem := g.typ(e.Elt)
+		if _, ok := e.Elt.(*ast.StarExpr); ok {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case *ast.MapType:
+		return "Record<string, " + g.typ(e.Value) + ">"
+	case *ast.StructType:
+		return g.object(e, "")
+	}
+	return "unknown"
+}
+
+// declare adds the declaration of the package type name declared as expr.
+func (g *tsGen) declare(name string, expr ast.Expr) {
+	if g.declared[name] {
+		return
+	}
+	// mark the type before building it so recursive types refer to it
+	g.declared[name] = true
+	i := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	if st, ok := expr.(*ast.StructType); ok {
+		g.decls[i] = fmt.Sprintf("export interface %s %s\n", name, g.object(st, ""))
+	} else {
+		g.decls[i] = fmt.Sprintf("export type %s = %s;\n", name, g.typ(expr))
+	}
+}
+
+// object returns the object type of a struct, following encoding/json field rules.
+// Properties are indented by indent plus two spaces.
+func (g *tsGen) object(st *ast.StructType, indent string) string {
+	fields := jsonFields(st, g.types)
+	if len(fields) == 0 {
+		return "{}"
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	for _, f := range fields {
+		optional := ""
+		if f.Optional {
+			optional = "?"
+		}
+		typ := g.typ(f.Type)
+		if nested, ok := f.Type.(*ast.StructType); ok {
+			typ = g.object(nested, indent+"  ")
+		}
+		fmt.Fprintf(&buf, "%s  %s%s: %s;\n", indent, tsKey(f.Key), optional, typ)
+	}
+	buf.WriteString(indent + "}")
+	return buf.String()
+}
+
+// tsIdentifier matches the TypeScript identifiers that can be used as unquoted property names.
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsKey returns key as a TypeScript property name, quoting it unless it is an identifier.
+func tsKey(key string) string {
+	if tsIdentifier.MatchString(key) {
+		return key
+	}
+	return strconv.Quote(key)
+}
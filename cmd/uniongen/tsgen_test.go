@@ -0,0 +1,112 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestGenerateTS(t *testing.T) {
+	dir := writePackage(t, map[string]string{"shape.go": shapeSource})
+
+	sp, err := parseSpec(dir, "Shape")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	src, err := generateTS(sp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `// Code generated by uniongen; DO NOT EDIT.
+
+export type Shape =
+  | { type: "circle"; value: Circle }
+  | { type: "rectangle"; value: Rectangle }
+  | { type: "Fill"; value: unknown };
+
+export interface Circle {
+  radius: number;
+}
+
+export interface Rectangle {
+  Width: number;
+  Height: number;
+}
+`
+	if string(src) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, src)
+	}
+}
+
+func TestGenerateTSFieldNames(t *testing.T) {
+	dir := writePackage(t, map[string]string{"event.go": eventSource + `
+type Kind string
+
+type Renamed struct {
+	Kind  Kind               ` + "`json:\"kind-name\"`" + `
+	Prev  *Renamed           ` + "`json:\"prev,omitempty\"`" + `
+	Attrs map[string][]int64 ` + "`json:\"attrs\"`" + `
+}
+
+type Journal struct {
+	Renamed Renamed ` + "`variant:\"renamed\"`" + `
+}
+
+func (Journal) JSONDiscriminator() (string, string) { return "@type", "" }
+`})
+
+	sp, err := parseSpec(dir, "Journal")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	src, err := generateTS(sp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `// Code generated by uniongen; DO NOT EDIT.
+
+export type Journal =
+  | { "@type": "renamed"; value: Renamed };
+
+export interface Renamed {
+  "kind-name": Kind;
+  prev?: Renamed | null;
+  attrs: Record<string, number[]>;
+}
+
+export type Kind = string;
+`
+	if string(src) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, src)
+	}
+
+	sp, err = parseSpec(dir, "Event")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	src, err = generateTS(sp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected = `// Code generated by uniongen; DO NOT EDIT.
+
+export type Event =
+  | ({ kind: "created" } & Created)
+  | ({ kind: "deleted" } & Deleted);
+
+export interface Created {
+  id: string;
+  time: string;
+  tags?: string[];
+}
+
+export interface Deleted {
+  id: string;
+  time: string;
+}
+`
+	if string(src) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, src)
+	}
+}
@@ -0,0 +1,18 @@
+// Unionvet reports invalid union spec structs and values with multiple variants set.
+//
+// It can be run directly or through go vet:
+//
+//	unionvet ./...
+//	go vet -vettool=$(which unionvet) ./...
+//
+// See package github.com/eriicafes/union/unionvet for the checks it performs.
+package main
+
+import (
+	"github.com/eriicafes/union/unionvet"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(unionvet.Analyzer)
+}
@@ -0,0 +1,89 @@
+package union
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// deprecations holds the variant names marked deprecated with Deprecate.
+var deprecations sync.Map // map[variantKey]struct{}
+
+// deprecatedHook holds the callback set with OnDeprecated.
+var deprecatedHook atomic.Pointer[func(spec reflect.Type, variant string)]
+
+// Deprecate marks variant names of the Spec type as deprecated, so decoding them calls
+// the OnDeprecated callback. Names can be variants, aliases, or variants of registered
+// implementations and upcasts, which lets a renamed variant deprecate only its old alias:
+//
+//	union.Deprecate[Shape]("rect")
+//
+// Variants can also be deprecated with the `deprecated` option of the `union` struct
+// tag, which deprecates all names of the field:
+//
+//	type Shape struct {
+//		Circle *Circle `variant:"circle" union:"deprecated"`
+//	}
+//
+// Deprecated variants are still decoded and encoded as usual.
+func Deprecate[Spec any](variants ...string) {
+	t := reflect.TypeFor[Spec]()
+	for _, variant := range variants {
+		deprecations.Store(variantKey{t, variant}, struct{}{})
+	}
+}
+
+// IsDeprecated reports whether the variant name of the Spec type is deprecated, by the
+// `deprecated` option of the field declaring it or by Deprecate.
+func IsDeprecated[Spec any](variant string) bool {
+	p := planOf(reflect.TypeFor[Spec]())
+	f, _ := p.lookup(variant)
+	return p.isDeprecated(f, variant)
+}
+
+// OnDeprecated sets the callback called with the Spec type and the variant name when
+// TaggedUnion, ExternallyTagged and the streaming decoder read a deprecated variant, so
+// producers still sending it can be found through warnings or metrics during migrations:
+//
+//	union.OnDeprecated(func(spec reflect.Type, variant string) {
+//		slog.Warn("deprecated variant decoded", "spec", spec, "variant", variant)
+//	})
+//
+// The callback is called once the variant is resolved, before its payload is decoded,
+// and must be safe for concurrent use. Setting a callback replaces the previous one,
+// and a nil callback removes it.
+func OnDeprecated(fn func(spec reflect.Type, variant string)) {
+	if fn == nil {
+		deprecatedHook.Store(nil)
+		return
+	}
+	deprecatedHook.Store(&fn)
+}
+
+// isDeprecated reports whether the variant name, declared by the field f if it is
+// not nil, is deprecated.
+func (p *specPlan) isDeprecated(f *fieldPlan, variant string) bool {
+	if f != nil && f.deprecated {
+		return true
+	}
+	_, ok := deprecations.Load(variantKey{p.typ, variant})
+	return ok
+}
+
+// noteDeprecated calls the OnDeprecated callback if the decoded variant name, declared
+// by the field f if it is not nil, is deprecated.
+func (p *specPlan) noteDeprecated(f *fieldPlan, variant string) {
+	if fn := deprecatedHook.Load(); fn != nil && p.isDeprecated(f, variant) {
+		(*fn)(p.typ, variant)
+	}
+}
+
+// resolve is lookup for decoding, which reports deprecated variants to the
+// OnDeprecated callback.
+func (p *specPlan) resolve(variant string) (*fieldPlan, error) {
+	f, err := p.lookup(variant)
+	if err == nil {
+		p.noteDeprecated(f, variant)
+	}
+	return f, err
+}
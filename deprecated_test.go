@@ -0,0 +1,108 @@
+package union
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type DeprecatedShape struct {
+	Circle    *Circle    `variant:"circle" union:"deprecated"`
+	Rectangle *Rectangle `variant:"rectangle" variantAliases:"rect"`
+	Triangle  *Triangle  `variant:"triangle"`
+}
+
+func init() {
+	Deprecate[DeprecatedShape]("rect")
+}
+
+func TestOnDeprecated(t *testing.T) {
+	var mu sync.Mutex
+	var decoded []string
+	OnDeprecated(func(spec reflect.Type, variant string) {
+		if spec != reflect.TypeFor[DeprecatedShape]() {
+			t.Errorf("expected spec DeprecatedShape, got %v", spec)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		decoded = append(decoded, variant)
+	})
+	defer OnDeprecated(nil)
+
+	tests := []struct {
+		name     string
+		jsonData string
+		expected []string
+	}{
+		{"tagged variant", `{"type":"circle","value":{"radius":1}}`, []string{"circle"}},
+		{"deprecated alias", `{"type":"rect","value":{"width":1,"height":2}}`, []string{"rect"}},
+		{"current name of aliased variant", `{"type":"rectangle","value":{"width":1,"height":2}}`, nil},
+		{"other variant", `{"type":"triangle","value":{"base":1,"height":2}}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded = nil
+			var u TaggedUnion[DeprecatedShape]
+			if err := json.Unmarshal([]byte(tt.jsonData), &u); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, decoded)
+			}
+		})
+	}
+
+	t.Run("externally tagged", func(t *testing.T) {
+		decoded = nil
+		var u ExternallyTagged[DeprecatedShape]
+		if err := json.Unmarshal([]byte(`{"circle":{"radius":1}}`), &u); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(decoded, []string{"circle"}) {
+			t.Errorf("expected [circle], got %v", decoded)
+		}
+	})
+
+	t.Run("bind query", func(t *testing.T) {
+		decoded = nil
+		var u TaggedUnion[DeprecatedShape]
+		if err := BindQuery(httptest.NewRequest("GET", "/shapes?kind=rect&width=1&height=2", nil), "kind", &u); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(decoded, []string{"rect"}) {
+			t.Errorf("expected [rect], got %v", decoded)
+		}
+	})
+
+	t.Run("encoding is not reported", func(t *testing.T) {
+		decoded = nil
+		if _, err := json.Marshal(TaggedUnion[DeprecatedShape]{Value: DeprecatedShape{Circle: &Circle{Radius: 1}}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if decoded != nil {
+			t.Errorf("expected no calls, got %v", decoded)
+		}
+	})
+}
+
+func TestIsDeprecated(t *testing.T) {
+	tests := []struct {
+		variant  string
+		expected bool
+	}{
+		{"circle", true},
+		{"rect", true},
+		{"rectangle", false},
+		{"triangle", false},
+		{"hexagon", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.variant, func(t *testing.T) {
+			if got := IsDeprecated[DeprecatedShape](tt.variant); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package union
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// DiscriminatorKind selects the JSON type of the variant field of a TaggedUnion.
+// A Spec type selects it with a JSONDiscriminatorKind() DiscriminatorKind method.
+type DiscriminatorKind int
+
+const (
+	// StringDiscriminator writes and reads the variant name as a JSON string. It is the default.
+	StringDiscriminator DiscriminatorKind = iota
+	// NumberDiscriminator writes the variant name as a JSON number, so `variant:"1"` is written as 1.
+	// Both numbers and strings holding numbers are accepted when unmarshaling.
+	NumberDiscriminator
+	// BoolDiscriminator writes the variant names "true" and "false" as JSON booleans,
+	// for two-way unions such as {"success": true, "value": {...}}.
+	BoolDiscriminator
+)
+
+// nullVariant is the variant name matching a null variant field with the
+// NumberDiscriminator and BoolDiscriminator kinds, for three-way unions.
+const nullVariant = "null"
+
+// discriminatorKind returns the DiscriminatorKind selected by the Spec type.
+func discriminatorKind(spec any) DiscriminatorKind {
+	if s, ok := spec.(interface{ JSONDiscriminatorKind() DiscriminatorKind }); ok {
+		return s.JSONDiscriminatorKind()
+	}
+	return StringDiscriminator
+}
+
+// appendDiscriminator appends the value written in the variant field for the variant name.
+func appendDiscriminator(dst []byte, kind DiscriminatorKind, variant string) ([]byte, error) {
+	switch {
+	case kind == StringDiscriminator:
+		return appendJSONString(dst, variant), nil
+	case variant == nullVariant:
+		return append(dst, "null"...), nil
+	}
+	data, err := json.Marshal(encodeDiscriminator(kind, variant))
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, data...), nil
+}
+
+// encodeDiscriminator returns the value written in the variant field for the variant name.
+func encodeDiscriminator(kind DiscriminatorKind, variant string) any {
+	switch {
+	case kind == StringDiscriminator:
+		return variant
+	case variant == nullVariant:
+		return nil
+	case kind == NumberDiscriminator:
+		// json.Number rejects variant names that are not valid numbers
+		return json.Number(variant)
+	}
+	// json.RawMessage rejects variant names that are not valid JSON
+	return json.RawMessage(variant)
+}
+
+// decodeDiscriminator returns the variant name held by the variant field.
+func decodeDiscriminator(kind DiscriminatorKind, data json.RawMessage) (string, error) {
+	if kind != StringDiscriminator && string(bytes.TrimSpace(data)) == "null" {
+		return nullVariant, nil
+	}
+	switch kind {
+	case NumberDiscriminator:
+		var n json.Number
+		if err := json.Unmarshal(data, &n); err != nil {
+			return "", err
+		}
+		return n.String(), nil
+	case BoolDiscriminator:
+		var b bool
+		if err := json.Unmarshal(data, &b); err != nil {
+			return "", err
+		}
+		return strconv.FormatBool(b), nil
+	}
+	var variant string
+	if err := json.Unmarshal(data, &variant); err != nil {
+		return "", err
+	}
+	return variant, nil
+}
+
+// checkDiscriminator reports whether the variant name can be written as the given kind.
+func checkDiscriminator(kind DiscriminatorKind, variant string) error {
+	switch {
+	case kind == StringDiscriminator || variant == nullVariant:
+		return nil
+	case kind == NumberDiscriminator && !isJSONNumber(variant):
+		return fmt.Errorf("variant %q is not a number", variant)
+	case kind == BoolDiscriminator && variant != "true" && variant != "false":
+		return fmt.Errorf("variant %q is not a boolean", variant)
+	}
+	return nil
+}
+
+// isJSONNumber reports whether s is a JSON number literal.
+func isJSONNumber(s string) bool {
+	if s == "" || (s[0] != '-' && (s[0] < '0' || s[0] > '9')) {
+		return false
+	}
+	return json.Valid([]byte(s))
+}
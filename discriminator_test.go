@@ -0,0 +1,203 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type NumberTagShape struct {
+	Circle    *Circle    `variant:"1"`
+	Rectangle *Rectangle `variant:"2"`
+}
+
+func (s NumberTagShape) JSONDiscriminatorKind() DiscriminatorKind { return NumberDiscriminator }
+
+type FlatNumberTagShape struct {
+	Circle *Circle `variant:"1"`
+}
+
+func (s FlatNumberTagShape) JSONDiscriminator() string                { return "code" }
+func (s FlatNumberTagShape) JSONDiscriminatorKind() DiscriminatorKind { return NumberDiscriminator }
+
+type StringNumberTagShape struct {
+	Circle *Circle `variant:"1"`
+}
+
+type InvalidNumberTagShape struct {
+	Circle *Circle `variant:"circle"`
+}
+
+func (s InvalidNumberTagShape) JSONDiscriminatorKind() DiscriminatorKind { return NumberDiscriminator }
+
+func TestNumberDiscriminator(t *testing.T) {
+	tests := []struct {
+		name  string
+		shape interface {
+			json.Marshaler
+			json.Unmarshaler
+			GetValue() any
+		}
+		jsonData  string
+		expected  any
+		marshaled string
+	}{
+		{
+			name:      "reads and writes numbers",
+			shape:     &TaggedUnion[NumberTagShape]{},
+			jsonData:  `{"type":2,"value":{"width":10,"height":5}}`,
+			expected:  Rectangle{Width: 10, Height: 5},
+			marshaled: `{"type":2,"value":{"width":10,"height":5}}`,
+		},
+		{
+			name:      "accepts strings holding numbers",
+			shape:     &TaggedUnion[NumberTagShape]{},
+			jsonData:  `{"type":"1","value":{"radius":5}}`,
+			expected:  Circle{Radius: 5.0},
+			marshaled: `{"type":1,"value":{"radius":5}}`,
+		},
+		{
+			name:      "reads and writes flat numbers",
+			shape:     &TaggedUnion[FlatNumberTagShape]{},
+			jsonData:  `{"code":1,"radius":5}`,
+			expected:  Circle{Radius: 5.0},
+			marshaled: `{"code":1,"radius":5}`,
+		},
+		{
+			name:      "writes numeric names as strings by default",
+			shape:     &TaggedUnion[StringNumberTagShape]{},
+			jsonData:  `{"type":"1","value":{"radius":5}}`,
+			expected:  Circle{Radius: 5.0},
+			marshaled: `{"type":"1","value":{"radius":5}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.shape.UnmarshalJSON([]byte(tt.jsonData)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertValueEquals(t, tt.shape.GetValue(), tt.expected)
+
+			data, err := tt.shape.MarshalJSON()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(data) != tt.marshaled {
+				t.Errorf("expected %s, got %s", tt.marshaled, data)
+			}
+		})
+	}
+
+	var shape TaggedUnion[StringNumberTagShape]
+	if err := json.Unmarshal([]byte(`{"type":1,"value":{"radius":5}}`), &shape); err == nil {
+		t.Error("expected string discriminator to reject numbers")
+	}
+
+	invalid := TaggedUnion[InvalidNumberTagShape]{Value: InvalidNumberTagShape{Circle: &Circle{Radius: 5}}}
+	if _, err := json.Marshal(invalid); err == nil {
+		t.Error("expected error marshaling non-numeric variant as a number")
+	}
+	err := CheckSpec[InvalidNumberTagShape]()
+	if !errors.Is(err, ErrInvalidSpec) || !strings.Contains(err.Error(), `field Circle: variant "circle" is not a number`) {
+		t.Errorf("expected invalid spec error, got '%v'", err)
+	}
+}
+
+type (
+	Failure struct {
+		Message string `json:"message"`
+	}
+	ResultShape struct {
+		Success *Circle  `variant:"true"`
+		Failure *Failure `variant:"false"`
+		Pending *Idle    `variant:"null"`
+	}
+)
+
+func (s ResultShape) JSONDiscriminator() (string, string) { return "success", "value" }
+
+func (s ResultShape) JSONDiscriminatorKind() DiscriminatorKind { return BoolDiscriminator }
+
+func (s ResultShape) JSONOptionalValue() bool { return true }
+
+type InvalidBoolTagShape struct {
+	Circle *Circle `variant:"yes"`
+}
+
+func (s InvalidBoolTagShape) JSONDiscriminatorKind() DiscriminatorKind { return BoolDiscriminator }
+
+func TestBoolDiscriminator(t *testing.T) {
+	tests := []struct {
+		name     string
+		jsonData string
+		expected any
+	}{
+		{
+			name:     "reads true",
+			jsonData: `{"success":true,"value":{"radius":5}}`,
+			expected: Circle{Radius: 5.0},
+		},
+		{
+			name:     "reads false",
+			jsonData: `{"success":false,"value":{"message":"failed"}}`,
+			expected: Failure{Message: "failed"},
+		},
+		{
+			name:     "reads null",
+			jsonData: `{"success":null}`,
+			expected: Idle{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var shape TaggedUnion[ResultShape]
+			if err := json.Unmarshal([]byte(tt.jsonData), &shape); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			switch value := shape.GetValue().(type) {
+			case *Failure:
+				if *value != tt.expected {
+					t.Errorf("expected %+v, got %+v", tt.expected, *value)
+				}
+			case *Idle:
+				if *value != tt.expected {
+					t.Errorf("expected %+v, got %+v", tt.expected, *value)
+				}
+			default:
+				assertValueEquals(t, value, tt.expected)
+			}
+		})
+	}
+
+	data, err := json.Marshal(TaggedUnion[ResultShape]{Value: ResultShape{Failure: &Failure{Message: "failed"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `{"success":false,"value":{"message":"failed"}}`; string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	data, err = json.Marshal(TaggedUnion[ResultShape]{Value: ResultShape{Pending: &Idle{}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `{"success":null}`; string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	var shape TaggedUnion[ResultShape]
+	if err := json.Unmarshal([]byte(`{"success":"true","value":{"radius":5}}`), &shape); err == nil {
+		t.Error("expected bool discriminator to reject strings")
+	}
+
+	if err := CheckSpec[ResultShape](); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err = CheckSpec[InvalidBoolTagShape]()
+	if !errors.Is(err, ErrInvalidSpec) || !strings.Contains(err.Error(), `field Circle: variant "yes" is not a boolean`) {
+		t.Errorf("expected invalid spec error, got '%v'", err)
+	}
+}
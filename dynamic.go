@@ -0,0 +1,208 @@
+package union
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// Registry holds the variants of DynamicUnion values, registered at runtime with
+// their payload types. It is safe for concurrent use, so plugin systems can register
+// variant types at startup while unions are decoded.
+type Registry struct {
+	mu       sync.RWMutex
+	types    map[string]reflect.Type
+	variants []string // variant names in registration order
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{types: map[string]reflect.Type{}}
+}
+
+// Register registers the variant named variant with payloads of type t:
+//
+//	reg.Register("circle", reflect.TypeOf(Circle{}))
+//
+// Registering a variant again replaces its payload type. Register panics if t is nil.
+func (r *Registry) Register(variant string, t reflect.Type) {
+	if t == nil {
+		panic(fmt.Sprintf("union: Register: nil type for variant %q", variant))
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.types[variant]; !ok {
+		r.variants = append(r.variants, variant)
+	}
+	r.types[variant] = t
+}
+
+// Variants returns the registered variant names in registration order.
+func (r *Registry) Variants() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.variants)
+}
+
+// Type returns the payload type registered for the variant, reporting false if there is none.
+func (r *Registry) Type(variant string) (reflect.Type, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.types[variant]
+	return t, ok
+}
+
+// lookup returns the payload type of the variant, or an *UnknownVariantError.
+func (r *Registry) lookup(variant string) (reflect.Type, error) {
+	t, ok := r.Type(variant)
+	if !ok {
+		return nil, &UnknownVariantError{Variant: variant, Known: r.Variants()}
+	}
+	return t, nil
+}
+
+// errNoRegistry is returned when using a DynamicUnion without a Registry.
+var errNoRegistry = errors.New("dynamic union has no registry")
+
+// DynamicUnion is a discriminated union whose variants are registered at runtime
+// in a Registry instead of being declared by a Spec struct. It marshals to the same
+// envelope as TaggedUnion, {"type": "circle", "value": {...}}.
+//
+// The Registry must be set before unmarshaling:
+//
+//	u := union.DynamicUnion{Registry: reg}
+//	err := json.Unmarshal(data, &u)
+//
+// Decoded payloads have exactly the registered type, so a variant registered with
+// reflect.TypeOf(&Circle{}) holds a *Circle.
+type DynamicUnion struct {
+	Registry *Registry
+
+	variant string
+	value   any
+}
+
+// NewDynamic returns a DynamicUnion of the registry with value set as the variant named variant.
+// See DynamicUnion.Set for the accepted values.
+func NewDynamic(reg *Registry, variant string, value any) (DynamicUnion, error) {
+	u := DynamicUnion{Registry: reg}
+	err := u.Set(variant, value)
+	return u, err
+}
+
+// Variant returns the name of the active variant, reporting false if the union is empty.
+func (u DynamicUnion) Variant() (string, bool) {
+	return u.variant, u.variant != ""
+}
+
+// GetValue returns the value of the active variant, or nil if the union is empty.
+func (u DynamicUnion) GetValue() any {
+	return u.value
+}
+
+// IsZero reports whether no variant is set, which lets the omitzero struct tag
+// option of encoding/json omit unset unions.
+func (u DynamicUnion) IsZero() bool {
+	return u.variant == ""
+}
+
+// Set makes value the active variant named variant. Pointer and non-pointer values
+// are adapted to the registered payload type, as Set does for spec fields.
+//
+// Returns an error and leaves the union unchanged if:
+//   - The union has no Registry
+//   - The variant is not registered (*UnknownVariantError)
+//   - The value is nil or its type does not match the registered payload type
+func (u *DynamicUnion) Set(variant string, value any) error {
+	if u.Registry == nil {
+		return errNoRegistry
+	}
+	t, err := u.Registry.lookup(variant)
+	if err != nil {
+		return err
+	}
+	vv := reflect.ValueOf(value)
+	f := &fieldPlan{variant: variant, typ: t, pointer: t.Kind() == reflect.Pointer}
+	if !vv.IsValid() || !f.accepts(vv.Type()) {
+		return fmt.Errorf("%w: %s", ErrNoFieldMatched, variant)
+	}
+	u.variant = variant
+	u.value = adapt(f, vv).Interface()
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface. It serializes the union
+// like TaggedUnion, as an object with the variant name in the "type" field and the
+// payload in the "value" field.
+//
+// Returns ErrZeroVariants if no variant is set.
+func (u DynamicUnion) MarshalJSON() ([]byte, error) {
+	if u.IsZero() {
+		return nil, ErrZeroVariants
+	}
+	raw, err := marshalPayload(encodingJSON, u.value)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := writeMember(&buf, '{', "type", u.variant); err != nil {
+		return nil, err
+	}
+	if err := writeMember(&buf, ',', "value", json.RawMessage(raw)); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It reads the variant
+// name from the "type" field and decodes the "value" field into a new value of
+// the payload type registered for it.
+//
+// Returns an error if:
+//   - The union has no Registry
+//   - The JSON data is malformed
+//   - The variant or value fields are missing
+//   - The variant is not registered (*UnknownVariantError)
+//   - The value cannot be unmarshaled into the payload type (*DecodeError)
+func (u *DynamicUnion) UnmarshalJSON(data []byte) error {
+	u.variant, u.value = "", nil
+	if u.Registry == nil {
+		return errNoRegistry
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	rawVariant, ok := raw["type"]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrMissingVariantField, "type")
+	}
+	rawValue, ok := raw["value"]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrMissingValueField, "value")
+	}
+
+	var variant string
+	if err := json.Unmarshal(rawVariant, &variant); err != nil {
+		return err
+	}
+	t, err := u.Registry.lookup(variant)
+	if err != nil {
+		return err
+	}
+	target, err := decodeType(encodingJSON, t, rawValue, decodeOptions{})
+	if err != nil {
+		return &DecodeError{Variant: variant, Err: err}
+	}
+
+	u.variant = variant
+	u.value = target.Elem().Interface()
+	return nil
+}
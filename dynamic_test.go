@@ -0,0 +1,102 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func newShapeRegistry() *Registry {
+	reg := NewRegistry()
+	reg.Register("circle", reflect.TypeOf(Circle{}))
+	reg.Register("rectangle", reflect.TypeOf(&Rectangle{}))
+	return reg
+}
+
+func TestDynamicUnion(t *testing.T) {
+	t.Run("round trips registered variants", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			jsonData string
+			expected any
+		}{
+			{"value payload", `{"type":"circle","value":{"radius":5}}`, Circle{Radius: 5}},
+			{"pointer payload", `{"type":"rectangle","value":{"width":2,"height":3}}`, &Rectangle{Width: 2, Height: 3}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				u := DynamicUnion{Registry: newShapeRegistry()}
+				if err := json.Unmarshal([]byte(tt.jsonData), &u); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !reflect.DeepEqual(u.GetValue(), tt.expected) {
+					t.Errorf("expected %#v, got %#v", tt.expected, u.GetValue())
+				}
+
+				data, err := json.Marshal(u)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if string(data) != tt.jsonData {
+					t.Errorf("expected %s, got %s", tt.jsonData, data)
+				}
+			})
+		}
+	})
+
+	t.Run("set adapts pointers", func(t *testing.T) {
+		u, err := NewDynamic(newShapeRegistry(), "rectangle", Rectangle{Width: 1})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if r, ok := u.GetValue().(*Rectangle); !ok || r.Width != 1 {
+			t.Errorf("expected *Rectangle, got %#v", u.GetValue())
+		}
+		if variant, ok := u.Variant(); !ok || variant != "rectangle" {
+			t.Errorf("expected variant rectangle, got %q (ok=%v)", variant, ok)
+		}
+	})
+
+	t.Run("set rejects mismatched types", func(t *testing.T) {
+		_, err := NewDynamic(newShapeRegistry(), "circle", Rectangle{})
+		if !errors.Is(err, ErrNoFieldMatched) {
+			t.Errorf("expected ErrNoFieldMatched, got %v", err)
+		}
+	})
+
+	t.Run("unknown variant", func(t *testing.T) {
+		u := DynamicUnion{Registry: newShapeRegistry()}
+		err := json.Unmarshal([]byte(`{"type":"hexagon","value":{}}`), &u)
+		var unknown *UnknownVariantError
+		if !errors.As(err, &unknown) {
+			t.Fatalf("expected UnknownVariantError, got %v", err)
+		}
+		if !reflect.DeepEqual(unknown.Known, []string{"circle", "rectangle"}) {
+			t.Errorf("expected known variants in registration order, got %v", unknown.Known)
+		}
+	})
+
+	t.Run("decode error", func(t *testing.T) {
+		u := DynamicUnion{Registry: newShapeRegistry()}
+		err := json.Unmarshal([]byte(`{"type":"circle","value":{"radius":"big"}}`), &u)
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Variant != "circle" {
+			t.Errorf("expected DecodeError for circle, got %v", err)
+		}
+	})
+
+	t.Run("requires a registry", func(t *testing.T) {
+		var u DynamicUnion
+		if err := json.Unmarshal([]byte(`{"type":"circle","value":{}}`), &u); err == nil {
+			t.Error("expected error without a registry")
+		}
+	})
+
+	t.Run("empty union fails to marshal", func(t *testing.T) {
+		u := DynamicUnion{Registry: newShapeRegistry()}
+		if _, err := json.Marshal(u); !errors.Is(err, ErrZeroVariants) {
+			t.Errorf("expected ErrZeroVariants, got %v", err)
+		}
+	})
+}
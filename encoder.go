@@ -0,0 +1,121 @@
+package union
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// maxPooledBuffer is the capacity above which buffers are not returned to their pool,
+// so an occasional huge message doesn't pin its memory.
+const maxPooledBuffer = 64 << 10
+
+// encodeState holds the buffers of one Encoder.Encode call. It is a JSONLibrary whose
+// Marshal encodes payloads into an arena reused across calls instead of a new slice.
+type encodeState struct {
+	out   []byte
+	arena bytes.Buffer
+	enc   *json.Encoder
+}
+
+var encodeStatePool = sync.Pool{
+	New: func() any {
+		s := &encodeState{}
+		s.enc = json.NewEncoder(&s.arena)
+		return s
+	},
+}
+
+// Marshal returns the JSON encoding of v, as json.Marshal does, backed by the arena.
+// Encodings stay valid until the state is put back in its pool.
+func (s *encodeState) Marshal(v any) ([]byte, error) {
+	start := s.arena.Len()
+	if err := s.enc.Encode(v); err != nil {
+		s.arena.Truncate(start)
+		return nil, err
+	}
+	data := s.arena.Bytes()[start : s.arena.Len()-1] // without the newline
+	return data[:len(data):len(data)], nil
+}
+
+func (s *encodeState) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// jsonAppender is implemented by the union types of this package, and by pointers to them.
+type jsonAppender interface {
+	appendUsing(dst []byte, lib JSONLibrary) ([]byte, error)
+}
+
+// Encoder writes JSON values to an output stream, one per line, like json.Encoder.
+// Its buffers are taken from a pool shared by all Encoders for each call, so services
+// encoding large numbers of union messages don't allocate an output buffer and payload
+// encodings per message. Unions are written like their MarshalJSON methods write them,
+// and other values like json.Marshal does.
+type Encoder struct {
+	w io.Writer
+}
+
+// NewEncoder returns an Encoder writing to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes the JSON encoding of v, followed by a newline, to the stream.
+func (e *Encoder) Encode(v any) error {
+	s := encodeStatePool.Get().(*encodeState)
+	defer func() {
+		if cap(s.out) <= maxPooledBuffer && s.arena.Cap() <= maxPooledBuffer {
+			s.out = s.out[:0]
+			s.arena.Reset()
+			encodeStatePool.Put(s)
+		}
+	}()
+
+	var err error
+	if u, ok := v.(jsonAppender); ok {
+		s.out, err = u.appendUsing(s.out[:0], s)
+	} else {
+		s.out, err = appendResult(s.out[:0])(s.Marshal(v))
+	}
+	if err != nil {
+		return err
+	}
+	s.out = append(s.out, '\n')
+	_, err = e.w.Write(s.out)
+	return err
+}
+
+// Decoder reads JSON values from an input stream, like json.Decoder. TaggedUnion values
+// are decoded with DecodeFrom, which decodes their payload straight from the stream, and
+// reading stops at a union longer than its MaxBytes limit.
+type Decoder struct {
+	dec *json.Decoder
+	lr  *limitedReader
+}
+
+// NewDecoder returns a Decoder reading from r.
+func NewDecoder(r io.Reader) *Decoder {
+	lr := newLimitedReader(r)
+	return &Decoder{dec: json.NewDecoder(lr), lr: lr}
+}
+
+// More reports whether there is another value in the current array or object being read.
+func (d *Decoder) More() bool {
+	return d.dec.More()
+}
+
+// Decode reads the next JSON value from the stream into v, which must be a pointer.
+// Unions are decoded like their UnmarshalJSON methods decode them, and other values like
+// json.Unmarshal does, and reading stops at a union longer than its MaxBytes limit.
+// It returns io.EOF at the end of the stream.
+func (d *Decoder) Decode(v any) error {
+	if u, ok := v.(interface{ limits() DecodeLimits }); ok {
+		d.lr.start(d.dec, u.limits().MaxBytes)
+		defer d.lr.start(d.dec, 0)
+	}
+	if u, ok := v.(interface{ DecodeFrom(dec *json.Decoder) error }); ok {
+		return u.DecodeFrom(d.dec)
+	}
+
+	return d.dec.Decode(v)
+}
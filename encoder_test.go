@@ -0,0 +1,131 @@
+package union
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	values := []any{
+		TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 1}}},
+		&ExternallyTagged[Shape]{Value: Shape{Triangle: &Triangle{Base: 2, Height: 3}}},
+		Union[Shape]{Value: Shape{Rectangle: &Rectangle{Width: 4, Height: 5}}},
+		TaggedUnion[FlatShape]{Value: FlatShape{Circle: &Circle{Radius: 6}}},
+		map[string]int{"plain": 7},
+	}
+	var expected strings.Builder
+	for _, v := range values {
+		if err := enc.Encode(v); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected.Write(data)
+		expected.WriteByte('\n')
+	}
+	if buf.String() != expected.String() {
+		t.Errorf("expected %s, got %s", expected.String(), buf.String())
+	}
+
+	t.Run("error", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := NewEncoder(&buf).Encode(TaggedUnion[Shape]{})
+		if !errors.Is(err, ErrZeroVariants) {
+			t.Errorf("expected ErrZeroVariants, got %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("expected nothing written, got %s", buf.String())
+		}
+	})
+}
+
+func TestDecoder(t *testing.T) {
+	input := `{"type":"circle","value":{"radius":1}}
+{"triangle":{"base":2,"height":3}}
+{"width":4,"height":5}
+{"plain":7}
+`
+	dec := NewDecoder(strings.NewReader(input))
+
+	var tagged TaggedUnion[Shape]
+	if err := dec.Decode(&tagged); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, tagged.GetValue(), Circle{Radius: 1})
+
+	var external ExternallyTagged[Shape]
+	if err := dec.Decode(&external); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, external.GetValue(), Triangle{Base: 2, Height: 3})
+
+	var untagged Union[Shape]
+	if err := dec.Decode(&untagged); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, untagged.GetValue(), Rectangle{Width: 4, Height: 5})
+
+	var plain map[string]int
+	if err := dec.Decode(&plain); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plain["plain"] != 7 {
+		t.Errorf("expected 7, got %v", plain)
+	}
+
+	if err := dec.Decode(&tagged); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
+
+func BenchmarkEncoder(b *testing.B) {
+	u := TaggedUnion[Shape]{Value: Shape{Triangle: &Triangle{Base: 8, Height: 4}}}
+	b.Run("Encoder", func(b *testing.B) {
+		b.ReportAllocs()
+		enc := NewEncoder(io.Discard)
+		for b.Loop() {
+			if err := enc.Encode(u); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("json.Encoder", func(b *testing.B) {
+		b.ReportAllocs()
+		enc := json.NewEncoder(io.Discard)
+		for b.Loop() {
+			if err := enc.Encode(u); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkDecoder(b *testing.B) {
+	data := strings.Repeat(`{"triangle":{"base":8,"height":4}}`+"\n", 1000)
+	b.Run("Decoder", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			dec := NewDecoder(strings.NewReader(data))
+			var u ExternallyTagged[Shape]
+			for dec.Decode(&u) == nil {
+			}
+		}
+	})
+	b.Run("json.Decoder", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			dec := json.NewDecoder(strings.NewReader(data))
+			var u ExternallyTagged[Shape]
+			for dec.Decode(&u) == nil {
+			}
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package union
+
+import "reflect"
+
+// Equal reports whether the unions a and b hold the same variant with equal payloads.
+// Two unset unions are equal, and a union with multiple variants set equals no other union.
+//
+// Payloads are compared by value, so pointer fields are equal when they point to
+// equal values. A payload type with an Equal method taking the type or a pointer to it,
+// such as time.Time, is compared with it, and with reflect.DeepEqual otherwise.
+func Equal[U interface {
+	GetValue() any
+	Variant() (string, bool)
+	IsZero() bool
+}](a, b U) bool {
+	if a.IsZero() || b.IsZero() {
+		return a.IsZero() && b.IsZero()
+	}
+	variantA, okA := a.Variant()
+	variantB, okB := b.Variant()
+	if !okA || !okB || variantA != variantB {
+		return false
+	}
+	return equalPayload(reflect.ValueOf(a.GetValue()), reflect.ValueOf(b.GetValue()))
+}
+
+// equalPayload reports whether the variant values a and b, or the values they point to, are equal.
+func equalPayload(a, b reflect.Value) bool {
+	for a.Kind() == reflect.Pointer && !a.IsNil() {
+		a = a.Elem()
+	}
+	for b.Kind() == reflect.Pointer && !b.IsNil() {
+		b = b.Elem()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	t := a.Type()
+	if m, ok := reflect.PointerTo(t).MethodByName("Equal"); ok && m.Type.NumIn() == 2 &&
+		m.Type.NumOut() == 1 && m.Type.Out(0).Kind() == reflect.Bool {
+		recv := reflect.New(t)
+		recv.Elem().Set(a)
+		switch m.Type.In(1) {
+		case t:
+			return recv.Method(m.Index).Call([]reflect.Value{b})[0].Bool()
+		case reflect.PointerTo(t):
+			arg := reflect.New(t)
+			arg.Elem().Set(b)
+			return recv.Method(m.Index).Call([]reflect.Value{arg})[0].Bool()
+		}
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
@@ -0,0 +1,64 @@
+package union
+
+import (
+	"testing"
+	"time"
+)
+
+type EventShape struct {
+	Started *time.Time `variant:"started"`
+	Stopped time.Time  `variant:"stopped"`
+}
+
+func TestEqual(t *testing.T) {
+	circle := func(radius float64) TaggedUnion[Shape] {
+		return TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: radius}}}
+	}
+
+	tests := []struct {
+		name     string
+		a, b     TaggedUnion[Shape]
+		expected bool
+	}{
+		{name: "same payload behind different pointers", a: circle(5), b: circle(5), expected: true},
+		{name: "different payloads", a: circle(5), b: circle(4), expected: false},
+		{
+			name:     "different variants",
+			a:        circle(0),
+			b:        TaggedUnion[Shape]{Value: Shape{Rectangle: &Rectangle{}}},
+			expected: false,
+		},
+		{name: "both unset", a: TaggedUnion[Shape]{}, b: TaggedUnion[Shape]{}, expected: true},
+		{name: "one unset", a: circle(5), b: TaggedUnion[Shape]{}, expected: false},
+		{
+			name:     "multiple variants",
+			a:        TaggedUnion[Shape]{Value: Shape{Circle: &Circle{}, Rectangle: &Rectangle{}}},
+			b:        TaggedUnion[Shape]{Value: Shape{Circle: &Circle{}, Rectangle: &Rectangle{}}},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Equal(tt.a, tt.b); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("uses payload Equal method", func(t *testing.T) {
+		now := time.Now()
+		utc := now.UTC()
+		a := Union[EventShape]{Value: EventShape{Started: &now}}
+		b := Union[EventShape]{Value: EventShape{Started: &utc}}
+		if !Equal(a, b) {
+			t.Error("expected times in different locations to be equal")
+		}
+
+		c := ExternallyTagged[EventShape]{Value: EventShape{Stopped: now}}
+		d := ExternallyTagged[EventShape]{Value: EventShape{Stopped: now.Add(time.Second)}}
+		if Equal(c, d) {
+			t.Error("expected different times not to be equal")
+		}
+	})
+}
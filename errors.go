@@ -0,0 +1,126 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Sentinel errors returned (possibly wrapped) by the union types.
+// Use errors.Is to check for them.
+var (
+	// ErrSpecNotStruct is returned when the Spec type is not a struct.
+	ErrSpecNotStruct = errors.New("spec must be a struct")
+	// ErrZeroVariants is returned when marshaling a union with no variant set.
+	ErrZeroVariants = errors.New("zero variants set")
+	// ErrMultipleVariants is returned when marshaling a union with more than one variant set.
+	ErrMultipleVariants = errors.New("multiple variants set")
+	// ErrUnknownVariant is returned when the variant name doesn't match any spec field.
+	ErrUnknownVariant = errors.New("unknown variant")
+	// ErrMissingVariantField is returned when the variant field is missing from the JSON object.
+	ErrMissingVariantField = errors.New("missing variant field")
+	// ErrMissingValueField is returned when the value field is missing from the JSON object.
+	ErrMissingValueField = errors.New("missing value field")
+	// ErrMultipleFieldsMatched is returned when more than one spec field declares the same variant.
+	ErrMultipleFieldsMatched = errors.New("multiple fields matched")
+	// ErrNoFieldMatched is returned when no spec field matches the JSON data or value being set.
+	ErrNoFieldMatched = errors.New("no field matched")
+	// ErrVariantFieldConflict is returned when a flat variant already has a field named like the discriminator.
+	ErrVariantFieldConflict = errors.New("variant field conflicts with discriminator")
+	// ErrNoCaseMatched is returned by Match and MatchR when no case handles the active variant,
+	// and by MapValue and Fold when the functions don't cover every variant.
+	ErrNoCaseMatched = errors.New("no case matched")
+	// ErrInvalidSpec is returned by CheckSpec when the Spec struct is misconfigured.
+	ErrInvalidSpec = errors.New("invalid spec")
+	// ErrAmbiguousMatch is returned when several spec fields of a BestMatch Union match the JSON data equally well.
+	ErrAmbiguousMatch = errors.New("ambiguous match")
+	// ErrUnknownField is returned when a strict TaggedUnion object has keys other than the variant and value fields.
+	ErrUnknownField = errors.New("unknown field")
+	// ErrVariantKeyCount is returned when an ExternallyTagged object does not contain exactly one key.
+	ErrVariantKeyCount = errors.New("expected exactly one variant key")
+	// ErrTupleLength is returned when the JSON array of a JSONTuple TaggedUnion does not have one or two elements.
+	ErrTupleLength = errors.New("expected a [variant, value] array")
+	// ErrLimitExceeded is returned when JSON data being unmarshaled exceeds its DecodeLimits,
+	// or MessagePack and CBOR data nest deeper than the nesting limit of encoding/json.
+	ErrLimitExceeded = errors.New("decode limit exceeded")
+)
+
+// UnknownVariantError is returned when the variant name read from the JSON data
+// doesn't match any field in the Spec struct. It matches ErrUnknownVariant with errors.Is.
+type UnknownVariantError struct {
+	Spec    reflect.Type // Spec struct type
+	Variant string       // Variant name that was not recognized
+	Known   []string     // Variant names declared by the Spec struct
+}
+
+func (e *UnknownVariantError) Error() string {
+	return ErrUnknownVariant.Error() + ": " + e.Variant
+}
+
+func (e *UnknownVariantError) Is(target error) bool {
+	return target == ErrUnknownVariant
+}
+
+// DecodeError is returned when the JSON value of a known variant cannot be
+// unmarshaled into its spec field. It wraps the underlying JSON error.
+//
+// For type and syntax errors of encoding/json, Path and Offset locate the error,
+// as in `variant "circle": value.radius: cannot unmarshal string into float64 at offset 13`.
+type DecodeError struct {
+	Spec    reflect.Type // Spec struct type
+	Variant string       // Variant name being decoded
+	Field   string       // Spec struct field name
+	Path    string       // Dotted JSON path of the failing value in the union's JSON, such as "value.radius", if known
+	Offset  int64        // Byte offset of the error within the variant's payload, or 0 if unknown
+	Err     error        // Underlying JSON error
+}
+
+func (e *DecodeError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "variant %q: ", e.Variant)
+	if e.Path != "" {
+		b.WriteString(e.Path + ": ")
+	}
+	if typeErr, ok := e.Err.(*json.UnmarshalTypeError); ok && e.Offset > 0 {
+		// the path replaces the Go struct field named by the error
+		fmt.Fprintf(&b, "cannot unmarshal %s into %s", typeErr.Value, typeErr.Type)
+	} else {
+		b.WriteString(e.Err.Error())
+	}
+	if e.Offset > 0 {
+		fmt.Fprintf(&b, " at offset %d", e.Offset)
+	}
+	return b.String()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// payloadError returns the DecodeError of the payload of the variant's field f failing
+// to decode with err. The path is the JSON path of the payload in the union's JSON,
+// or "" if the payload is the union's JSON itself, as for untagged unions.
+// Only type and syntax errors of encoding/json are located, within the payload.
+func payloadError(spec reflect.Type, variant string, f *fieldPlan, path string, err error) *DecodeError {
+	e := &DecodeError{Spec: spec, Variant: variant, Field: f.name, Err: err}
+	switch err := err.(type) {
+	case *json.UnmarshalTypeError:
+		e.Path, e.Offset = joinPath(path, err.Field), err.Offset
+	case *json.SyntaxError:
+		e.Path, e.Offset = path, err.Offset
+	}
+	return e
+}
+
+// joinPath joins two dotted JSON paths, either of which may be empty.
+func joinPath(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	}
+	return a + "." + b
+}
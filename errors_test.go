@@ -0,0 +1,249 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		run      func() error
+		expected error
+	}{
+		{
+			name: "tagged union non-struct spec",
+			run: func() error {
+				_, err := json.Marshal(TaggedUnion[NonStructType]{})
+				return err
+			},
+			expected: ErrSpecNotStruct,
+		},
+		{
+			name: "tagged union zero variants",
+			run: func() error {
+				_, err := json.Marshal(TaggedUnion[Shape]{})
+				return err
+			},
+			expected: ErrZeroVariants,
+		},
+		{
+			name: "tagged union multiple variants",
+			run: func() error {
+				_, err := json.Marshal(TaggedUnion[Shape]{Value: Shape{Circle: &Circle{}, Triangle: &Triangle{}}})
+				return err
+			},
+			expected: ErrMultipleVariants,
+		},
+		{
+			name: "tagged union flat variant conflict",
+			run: func() error {
+				_, err := json.Marshal(TaggedUnion[ConflictingFlatShape]{Value: ConflictingFlatShape{Circle: &ConflictingCircle{}}})
+				return err
+			},
+			expected: ErrVariantFieldConflict,
+		},
+		{
+			name: "tagged union unknown variant",
+			run: func() error {
+				return json.Unmarshal([]byte(`{"type":"hexagon","value":{}}`), &TaggedUnion[Shape]{})
+			},
+			expected: ErrUnknownVariant,
+		},
+		{
+			name: "tagged union missing variant field",
+			run: func() error {
+				return json.Unmarshal([]byte(`{"value":{}}`), &TaggedUnion[Shape]{})
+			},
+			expected: ErrMissingVariantField,
+		},
+		{
+			name: "tagged union missing value field",
+			run: func() error {
+				return json.Unmarshal([]byte(`{"type":"circle"}`), &TaggedUnion[Shape]{})
+			},
+			expected: ErrMissingValueField,
+		},
+		{
+			name: "tagged union duplicate variant tags",
+			run: func() error {
+				return json.Unmarshal([]byte(`{"type":"circle","value":{}}`), &TaggedUnion[DuplicateVariantShape]{})
+			},
+			expected: ErrMultipleFieldsMatched,
+		},
+		{
+			name: "externally tagged key count",
+			run: func() error {
+				return json.Unmarshal([]byte(`{}`), &ExternallyTagged[Shape]{})
+			},
+			expected: ErrVariantKeyCount,
+		},
+		{
+			name: "externally tagged unknown variant",
+			run: func() error {
+				return json.Unmarshal([]byte(`{"hexagon":{}}`), &ExternallyTagged[Shape]{})
+			},
+			expected: ErrUnknownVariant,
+		},
+		{
+			name: "union zero variants",
+			run: func() error {
+				_, err := json.Marshal(Union[UnionShape]{})
+				return err
+			},
+			expected: ErrZeroVariants,
+		},
+		{
+			name: "union non-struct spec",
+			run: func() error {
+				return json.Unmarshal([]byte(`42`), &Union[UnionNonStructType]{})
+			},
+			expected: ErrSpecNotStruct,
+		},
+		{
+			name: "union no field matched",
+			run: func() error {
+				return json.Unmarshal([]byte(`{"sides":6}`), &Union[UnionShape]{})
+			},
+			expected: ErrNoFieldMatched,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("expected error to wrap '%v', got '%v'", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestUnknownVariantError(t *testing.T) {
+	err := json.Unmarshal([]byte(`{"type":"hexagon","value":{}}`), &TaggedUnion[Shape]{})
+
+	var unknownErr *UnknownVariantError
+	if !errors.As(err, &unknownErr) {
+		t.Fatalf("expected *UnknownVariantError, got %T", err)
+	}
+	if unknownErr.Variant != "hexagon" {
+		t.Errorf("expected variant 'hexagon', got '%s'", unknownErr.Variant)
+	}
+	if unknownErr.Spec != reflect.TypeFor[Shape]() {
+		t.Errorf("expected spec Shape, got %v", unknownErr.Spec)
+	}
+	if !slices.Equal(unknownErr.Known, []string{"circle", "rectangle", "triangle"}) {
+		t.Errorf("expected known variants [circle rectangle triangle], got %v", unknownErr.Known)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	err := json.Unmarshal([]byte(`{"type":"circle","value":{"radius":"5"}}`), &TaggedUnion[Shape]{})
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected *DecodeError, got %T", err)
+	}
+	if decodeErr.Variant != "circle" || decodeErr.Field != "Circle" {
+		t.Errorf("expected variant 'circle' and field 'Circle', got '%s' and '%s'", decodeErr.Variant, decodeErr.Field)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		t.Errorf("expected wrapped *json.UnmarshalTypeError, got %v", decodeErr.Err)
+	}
+}
+
+type Polyline struct {
+	Points []struct {
+		X float64 `json:"x"`
+		Y float64 `json:"y"`
+	} `json:"points"`
+}
+
+type PolylineShape struct {
+	Circle   *Circle   `variant:"circle"`
+	Polyline *Polyline `variant:"polyline"`
+}
+
+func TestDecodeErrorLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		target   json.Unmarshaler
+		path     string
+		offset   int64
+		expected string
+	}{
+		{
+			name:     "tagged union field",
+			data:     `{"type":"circle","value":{"radius":"5"}}`,
+			target:   &TaggedUnion[Shape]{},
+			path:     "value.radius",
+			offset:   13,
+			expected: `variant "circle": value.radius: cannot unmarshal string into float64 at offset 13`,
+		},
+		{
+			name:     "nested array element",
+			data:     `{"type":"polyline","value":{"points":[{"x":1,"y":2},{"x":3,"y":"4"}]}}`,
+			target:   &TaggedUnion[PolylineShape]{},
+			path:     "value.points.1.y",
+			offset:   39,
+			expected: `variant "polyline": value.points.1.y: cannot unmarshal string into float64 at offset 39`,
+		},
+		{
+			name:     "externally tagged",
+			data:     `{"circle":{"radius":true}}`,
+			target:   &ExternallyTagged[Shape]{},
+			path:     "circle.radius",
+			offset:   14,
+			expected: `variant "circle": circle.radius: cannot unmarshal bool into float64 at offset 14`,
+		},
+		{
+			name:     "discriminator path",
+			data:     `{"meta":{"type":"circle"},"payload":{"radius":"5"}}`,
+			target:   &TaggedUnion[WebhookShape]{},
+			path:     "payload.radius",
+			offset:   13,
+			expected: `variant "circle": payload.radius: cannot unmarshal string into float64 at offset 13`,
+		},
+		{
+			name:     "member discriminator",
+			data:     `{"type":"Circle","circle":{"radius":"5"}}`,
+			target:   &TaggedUnion[MemberShape]{},
+			path:     "circle.radius",
+			offset:   13,
+			expected: `variant "Circle": circle.radius: cannot unmarshal string into float64 at offset 13`,
+		},
+		{
+			name:     "payload of the wrong type",
+			data:     `{"type":"circle","value":[1]}`,
+			target:   &TaggedUnion[Shape]{},
+			path:     "value",
+			offset:   1,
+			expected: `variant "circle": value: cannot unmarshal array into union.Circle at offset 1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := json.Unmarshal([]byte(tt.data), tt.target)
+
+			var decodeErr *DecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("expected *DecodeError, got %v", err)
+			}
+			if decodeErr.Path != tt.path {
+				t.Errorf("expected path %v, got %v", tt.path, decodeErr.Path)
+			}
+			if decodeErr.Offset != tt.offset {
+				t.Errorf("expected offset %v, got %v", tt.offset, decodeErr.Offset)
+			}
+			if err.Error() != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, err.Error())
+			}
+		})
+	}
+}
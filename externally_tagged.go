@@ -0,0 +1,222 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+)
+
+// ExternallyTagged represents a discriminated union type that can hold one of several
+// variant types defined in the Spec struct. The Spec type should be a struct where
+// each field represents a possible variant of the union.
+//
+// Only one field in the Spec struct should be non-zero at any time. When marshaling
+// to JSON, the union is represented as an object with a single key (the variant name)
+// whose value is the variant's data.
+type ExternallyTagged[Spec any] struct {
+	Value Spec
+
+	selected *fieldPlan // field made active by Select, see current
+}
+
+func (u *ExternallyTagged[Spec]) spec() *Spec { return &u.Value }
+
+func (u *ExternallyTagged[Spec]) specValue() reflect.Value { return reflect.ValueOf(&u.Value).Elem() }
+
+func (u *ExternallyTagged[Spec]) selectedField() *fieldPlan { return u.selected }
+
+func (u *ExternallyTagged[Spec]) setSelected(f *fieldPlan) { u.selected = f }
+
+// GetValue returns the value of the active variant in the union.
+// It iterates through all fields in the Spec struct and returns the value
+// of the non-zero field. If no fields are set or multiple fields are set,
+// it returns nil (indicating an invalid state).
+func (u ExternallyTagged[Spec]) GetValue() any {
+	v := reflect.ValueOf(u.Value)
+	f, err := planOf(v.Type()).current(v, u.selected)
+	if err != nil {
+		return nil
+	}
+	return v.FieldByIndex(f.index).Interface()
+}
+
+// Variant returns the name of the active variant in the union, which is the
+// field's `variant` struct tag or the field name, converted by the spec's VariantNaming, if no tag is provided.
+// It reports false if no fields are set or multiple fields are set.
+func (u ExternallyTagged[Spec]) Variant() (string, bool) {
+	v := reflect.ValueOf(u.Value)
+	f, err := planOf(v.Type()).current(v, u.selected)
+	if err != nil {
+		return "", false
+	}
+	return f.variantOf(v.FieldByIndex(f.index)), true
+}
+
+// MustGetValue is like GetValue but panics if no fields are set or multiple fields are set.
+func (u ExternallyTagged[Spec]) MustGetValue() any {
+	v := reflect.ValueOf(u.Value)
+	f := mustActive(v, u.selected)
+	return v.FieldByIndex(f.index).Interface()
+}
+
+// MustVariant is like Variant but panics if no fields are set or multiple fields are set.
+func (u ExternallyTagged[Spec]) MustVariant() string {
+	v := reflect.ValueOf(u.Value)
+	f := mustActive(v, u.selected)
+	return f.variantOf(v.FieldByIndex(f.index))
+}
+
+// IsZero reports whether no variant is set or selected in the union, which
+// lets the omitzero struct tag option of encoding/json omit unset unions.
+func (u ExternallyTagged[Spec]) IsZero() bool {
+	v := reflect.ValueOf(u.Value)
+	_, err := planOf(v.Type()).current(v, u.selected)
+	return errors.Is(err, ErrZeroVariants)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// It serializes the union to JSON as an object with a single key, the variant name,
+// containing the variant's data.
+//
+// The variant name is determined by the struct field's `variant` struct tag,
+// or the field name (see Naming) if no variant is specified. Unit variants, tagged `union:"unit"`,
+// are serialized as a bare JSON string holding the variant name.
+//
+// Returns an error if:
+//   - The Spec type is not a struct
+//   - No fields are set (zero state), unless the Spec type returns true from JSONNullable
+//   - Multiple fields are set (invalid state)
+func (u ExternallyTagged[Spec]) MarshalJSON() ([]byte, error) {
+	return u.marshalUsing(encodingJSON)
+}
+
+// marshalUsing implements MarshalJSON, encoding the payload with lib.
+func (u ExternallyTagged[Spec]) marshalUsing(lib JSONLibrary) ([]byte, error) {
+	return u.appendUsing(nil, lib)
+}
+
+// appendUsing appends the JSON encoding of the union to dst like MarshalJSON,
+// encoding the payload with lib.
+func (u ExternallyTagged[Spec]) appendUsing(dst []byte, lib JSONLibrary) ([]byte, error) {
+	p := planOf(reflect.TypeFor[Spec]())
+	if c := codecOf(p.typ); c != nil {
+		return appendResult(dst)(c.marshal(u.Value))
+	}
+	if p.json.nullable && u.IsZero() {
+		return append(dst, "null"...), nil
+	}
+	v := reflect.ValueOf(u.Value)
+	if variant, ok := unitVariant(v, u.selected); ok {
+		return appendJSONString(dst, variant), nil
+	}
+	f, err := p.current(v, u.selected)
+	if err != nil {
+		return nil, err
+	}
+	variant, value := variantValue(v, f)
+
+	raw, err := marshalPayload(lib, value)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := slices.Grow(dst, len(variant)+len(raw)+8)
+	buf = appendMemberRaw(buf, '{', variant, raw)
+	return append(buf, '}'), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It deserializes JSON data into the union by:
+//  1. Reading the single key of the object to determine which variant is active
+//  2. Unmarshaling the key's value into the corresponding struct field
+//
+// A bare JSON string decodes into the unit variant it names.
+//
+// Returns an error if:
+//   - The JSON data is malformed
+//   - The Spec type is not a struct
+//   - The object does not contain exactly one key
+//   - The key doesn't match any known variant (*UnknownVariantError)
+//   - Multiple struct fields match the same variant (invalid Spec definition)
+//   - The value cannot be unmarshaled into the target field type (*DecodeError)
+//
+// Like TaggedUnion, a Spec type returning true from JSONStrict rejects payloads
+// with fields unknown to the variant's type, one returning true from JSONUseNumber
+// keeps numbers in interface values of payloads as json.Number, and one returning
+// true from JSONNullable represents the empty union as JSON null.
+func (u *ExternallyTagged[Spec]) UnmarshalJSON(data []byte) error {
+	return u.unmarshalUsing(encodingJSON, data)
+}
+
+// unmarshalUsing implements UnmarshalJSON, decoding the payload with lib.
+func (u *ExternallyTagged[Spec]) unmarshalUsing(lib JSONLibrary, data []byte) error {
+	var zero Spec
+	u.Value = zero
+	u.selected = nil
+	if err := decodeLimits(lib, u.Value).check(data); err != nil {
+		return err
+	}
+	if c := codecOf(reflect.TypeFor[Spec]()); c != nil {
+		return c.unmarshal(data, &u.Value)
+	}
+
+	v := reflect.ValueOf(&u.Value).Elem()
+	t := v.Type()
+	p := planOf(t)
+
+	if !p.isStruct {
+		return ErrSpecNotStruct
+	}
+	if isJSONNull(data) && isNullable(u.Value) {
+		return nil
+	}
+	if isJSONString(data) {
+		return unmarshalUnit(v, data)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := lib.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) != 1 {
+		return ErrVariantKeyCount
+	}
+
+	var variant string
+	var rawValue json.RawMessage
+	for variant, rawValue = range raw {
+	}
+	if err := decodeLimits(lib, u.Value).checkValue(rawValue); err != nil {
+		return err
+	}
+
+	f, err := p.resolve(variant)
+	opts := payloadOptions(lib, u.Value)
+	if err != nil {
+		if errors.Is(err, ErrUnknownVariant) {
+			if ok, err := p.setImpl(lib, v, variant, rawValue, opts); ok {
+				return err
+			}
+			if f, ok, err := p.setUpcast(lib, v, variant, rawValue, opts); ok {
+				if err == nil {
+					u.selected = selection(v, f)
+				}
+				return err
+			}
+			if p.setRaw(v, variant, rawValue) {
+				return nil
+			}
+		}
+		return err
+	}
+
+	target, err := decodeField(lib, f, rawValue, opts)
+	if err != nil {
+		return payloadError(t, variant, f, variant, err)
+	}
+
+	v.FieldByIndex(f.index).Set(target.Elem())
+	u.selected = selection(v, f)
+	return nil
+}
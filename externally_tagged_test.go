@@ -0,0 +1,258 @@
+package union
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestExternallyTaggedGetValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		shape    interface{ GetValue() any }
+		expected any
+	}{
+		{
+			name: "returns circle variant",
+			shape: ExternallyTagged[Shape]{
+				Value: Shape{
+					Circle: &Circle{Radius: 5.0},
+				},
+			},
+			expected: Circle{Radius: 5.0},
+		},
+		{
+			name:     "returns nil when no variant is set",
+			shape:    ExternallyTagged[Shape]{},
+			expected: nil,
+		},
+		{
+			name: "returns nil when multiple variants are set",
+			shape: ExternallyTagged[Shape]{
+				Value: Shape{
+					Circle:    &Circle{Radius: 5.0},
+					Rectangle: &Rectangle{Width: 10, Height: 5},
+				},
+			},
+			expected: nil,
+		},
+		{
+			name:     "returns nil for non-struct type",
+			shape:    ExternallyTagged[NonStructType]{},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := tt.shape.GetValue()
+			assertValueEquals(t, value, tt.expected)
+		})
+	}
+}
+
+func TestExternallyTaggedMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		shape       any
+		expected    string
+		expectErr   bool
+		expectedErr string
+	}{
+		{
+			name: "marshals circle variant",
+			shape: ExternallyTagged[Shape]{
+				Value: Shape{
+					Circle: &Circle{Radius: 5.0},
+				},
+			},
+			expected: `{"circle":{"radius":5}}`,
+		},
+		{
+			name: "marshals rectangle variant",
+			shape: ExternallyTagged[Shape]{
+				Value: Shape{
+					Rectangle: &Rectangle{Width: 10, Height: 5},
+				},
+			},
+			expected: `{"rectangle":{"width":10,"height":5}}`,
+		},
+		{
+			name: "marshals non-pointer variant",
+			shape: ExternallyTagged[NonPointerShape]{
+				Value: NonPointerShape{
+					Circle: Circle{Radius: 5.0},
+				},
+			},
+			expected: `{"circle":{"radius":5}}`,
+		},
+		{
+			name: "marshals without struct tags",
+			shape: ExternallyTagged[NonStructTagsShape]{
+				Value: NonStructTagsShape{
+					Circle: &Circle{Radius: 5.0},
+				},
+			},
+			expected: `{"Circle":{"radius":5}}`,
+		},
+		{
+			name:        "returns error when no variant is set",
+			shape:       ExternallyTagged[Shape]{},
+			expectErr:   true,
+			expectedErr: "zero variants set",
+		},
+		{
+			name: "returns error when multiple variants are set",
+			shape: ExternallyTagged[Shape]{
+				Value: Shape{
+					Circle:    &Circle{Radius: 5.0},
+					Rectangle: &Rectangle{Width: 10, Height: 5},
+				},
+			},
+			expectErr:   true,
+			expectedErr: "multiple variants set",
+		},
+		{
+			name:        "returns error for non-struct type",
+			shape:       ExternallyTagged[NonStructType]{},
+			expectErr:   true,
+			expectedErr: "spec must be a struct",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.shape)
+
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				if tt.expectedErr != "" && !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Errorf("expected error '%s', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(data) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, string(data))
+			}
+		})
+	}
+}
+
+func TestExternallyTaggedUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		shape       interface{ GetValue() any }
+		jsonData    string
+		expected    any
+		expectErr   bool
+		expectedErr string
+	}{
+		{
+			name:     "unmarshals circle variant",
+			shape:    &ExternallyTagged[Shape]{},
+			jsonData: `{"circle":{"radius":5}}`,
+			expected: Circle{Radius: 5.0},
+		},
+		{
+			name:     "unmarshals triangle variant",
+			shape:    &ExternallyTagged[Shape]{},
+			jsonData: `{"triangle":{"base":8,"height":4}}`,
+			expected: Triangle{Base: 8, Height: 4},
+		},
+		{
+			name:     "unmarshals non-pointer variant",
+			shape:    &ExternallyTagged[NonPointerShape]{},
+			jsonData: `{"circle":{"radius":5}}`,
+			expected: Circle{Radius: 5.0},
+		},
+		{
+			name:     "unmarshals without struct tags",
+			shape:    &ExternallyTagged[NonStructTagsShape]{},
+			jsonData: `{"Circle":{"radius":5}}`,
+			expected: Circle{Radius: 5.0},
+		},
+		{
+			name:        "returns error for unknown variant",
+			shape:       &ExternallyTagged[Shape]{},
+			jsonData:    `{"hexagon":{"sides":6}}`,
+			expectErr:   true,
+			expectedErr: "unknown variant: hexagon",
+		},
+		{
+			name:        "returns error for empty object",
+			shape:       &ExternallyTagged[Shape]{},
+			jsonData:    `{}`,
+			expectErr:   true,
+			expectedErr: "expected exactly one variant key",
+		},
+		{
+			name:        "returns error for multiple keys",
+			shape:       &ExternallyTagged[Shape]{},
+			jsonData:    `{"circle":{"radius":5},"triangle":{"base":8,"height":4}}`,
+			expectErr:   true,
+			expectedErr: "expected exactly one variant key",
+		},
+		{
+			name:      "returns error for malformed JSON",
+			shape:     &ExternallyTagged[Shape]{},
+			jsonData:  `{invalid json}`,
+			expectErr: true,
+		},
+		{
+			name:        "returns error for non-struct type",
+			shape:       &ExternallyTagged[NonStructType]{},
+			jsonData:    `{"int":42}`,
+			expectErr:   true,
+			expectedErr: "spec must be a struct",
+		},
+		{
+			name:      "returns error when JSON is array",
+			shape:     &ExternallyTagged[Shape]{},
+			jsonData:  `[1, 2, 3]`,
+			expectErr: true,
+		},
+		{
+			name:      "returns error when value cannot be unmarshaled",
+			shape:     &ExternallyTagged[Shape]{},
+			jsonData:  `{"circle":"not an object"}`,
+			expectErr: true,
+		},
+		{
+			name:        "returns error for duplicate variant tags",
+			shape:       &ExternallyTagged[DuplicateVariantShape]{},
+			jsonData:    `{"circle":{"radius":5}}`,
+			expectErr:   true,
+			expectedErr: "multiple fields matched",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := json.Unmarshal([]byte(tt.jsonData), tt.shape)
+
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				if tt.expectedErr != "" && err.Error() != tt.expectedErr {
+					t.Errorf("expected error '%s', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			value := tt.shape.GetValue()
+			assertValueEquals(t, value, tt.expected)
+		})
+	}
+}
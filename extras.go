@@ -0,0 +1,74 @@
+package union
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// Extras holds the members of a TaggedUnion object other than the variant and value fields.
+//
+// A spec field of type Extras captures them when unmarshaling, and marshaling writes them
+// back after the variant and value fields in key order, so gateways can forward envelopes
+// carrying ids, timestamps or signatures next to the union losslessly:
+//
+//	type Event struct {
+//		Created *Created     `variant:"created"`
+//		Deleted *Deleted     `variant:"deleted"`
+//		Extras  union.Extras // {"type": ..., "value": ..., "id": 1} keeps "id"
+//	}
+//
+// The Extras field is not a variant and is left unchanged by Set and SetVariant.
+// Strict specs accept the captured members instead of failing with ErrUnknownField.
+// Extras are not captured in the flat representation, where all members belong to the
+// variant, nor with JSONDiscriminatorPath.
+type Extras map[string]json.RawMessage
+
+var extrasType = reflect.TypeFor[Extras]()
+
+// extrasOf returns the Extras field of the spec struct value v, reporting false if the spec has none.
+func (p *specPlan) extrasOf(v reflect.Value) (reflect.Value, bool) {
+	if p.extras == nil {
+		return reflect.Value{}, false
+	}
+	return v.FieldByIndex(p.extras), true
+}
+
+// setExtras stores the members of the JSON object raw other than the variant and value fields
+// in the spec's Extras field, reporting false if it has none.
+func (p *specPlan) setExtras(v reflect.Value, raw map[string]json.RawMessage, variantField, valueField string) bool {
+	fv, ok := p.extrasOf(v)
+	if !ok {
+		return false
+	}
+	var extras Extras
+	for key, value := range raw {
+		if key == variantField || key == valueField {
+			continue
+		}
+		if extras == nil {
+			extras = make(Extras)
+		}
+		extras[key] = value
+	}
+	fv.Set(reflect.ValueOf(extras))
+	return true
+}
+
+// appendExtras appends the members held by the spec's Extras field in key order,
+// skipping the variant and value fields.
+func (p *specPlan) appendExtras(dst []byte, v reflect.Value, variantField, valueField string) ([]byte, error) {
+	fv, ok := p.extrasOf(v)
+	if !ok {
+		return dst, nil
+	}
+	extras := fv.Interface().(Extras)
+	for _, key := range slices.Sorted(maps.Keys(extras)) {
+		if key == variantField || key == valueField {
+			continue
+		}
+		dst = appendMemberRaw(dst, ',', key, extras[key])
+	}
+	return dst, nil
+}
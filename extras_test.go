@@ -0,0 +1,117 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type EnvelopeShape struct {
+	Circle    *Circle    `variant:"circle"`
+	Rectangle *Rectangle `variant:"rectangle"`
+	Extras    Extras
+}
+
+type StrictEnvelopeShape struct {
+	Circle *Circle `variant:"circle"`
+	Extras Extras
+}
+
+func (s StrictEnvelopeShape) JSONStrict() bool { return true }
+
+type DuplicateExtrasShape struct {
+	Circle *Circle `variant:"circle"`
+	A      Extras
+	B      Extras
+}
+
+func TestExtras(t *testing.T) {
+	t.Run("round trips extra members", func(t *testing.T) {
+		var shape TaggedUnion[EnvelopeShape]
+		input := `{"ts":"2024-01-01","type":"circle","id":1,"value":{"radius":5}}`
+		if err := json.Unmarshal([]byte(input), &shape); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(shape.Value.Extras) != 2 || string(shape.Value.Extras["id"]) != "1" {
+			t.Errorf("expected id and ts extras, got %v", shape.Value.Extras)
+		}
+
+		data, err := json.Marshal(shape)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := `{"type":"circle","value":{"radius":5},"id":1,"ts":"2024-01-01"}`
+		if string(data) != expected {
+			t.Errorf("expected %s, got %s", expected, data)
+		}
+	})
+
+	t.Run("leaves extras empty without extra members", func(t *testing.T) {
+		var shape TaggedUnion[EnvelopeShape]
+		shape.Value.Extras = Extras{"id": json.RawMessage("1")}
+		if err := json.Unmarshal([]byte(`{"type":"circle","value":{"radius":5}}`), &shape); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if shape.Value.Extras != nil {
+			t.Errorf("expected no extras, got %v", shape.Value.Extras)
+		}
+	})
+
+	t.Run("strict spec accepts extra members", func(t *testing.T) {
+		var shape TaggedUnion[StrictEnvelopeShape]
+		if err := json.Unmarshal([]byte(`{"type":"circle","value":{"radius":5},"id":1}`), &shape); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(shape.Value.Extras["id"]) != "1" {
+			t.Errorf("expected id extra, got %v", shape.Value.Extras)
+		}
+	})
+
+	t.Run("decodes extras from a stream", func(t *testing.T) {
+		dec := json.NewDecoder(strings.NewReader(`{"type":"circle","id":1,"value":{"radius":5},"ts":"x"}`))
+		var shape TaggedUnion[EnvelopeShape]
+		if err := shape.DecodeFrom(dec); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(shape.Value.Extras) != 2 || string(shape.Value.Extras["ts"]) != `"x"` {
+			t.Errorf("expected id and ts extras, got %v", shape.Value.Extras)
+		}
+	})
+
+	t.Run("set keeps extras", func(t *testing.T) {
+		var shape TaggedUnion[EnvelopeShape]
+		shape.Value.Extras = Extras{"id": json.RawMessage("1")}
+		if err := Set(&shape, &Rectangle{Width: 1, Height: 2}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(shape.Value.Extras["id"]) != "1" {
+			t.Errorf("expected extras to be kept, got %v", shape.Value.Extras)
+		}
+		if variant, ok := shape.Variant(); !ok || variant != "rectangle" {
+			t.Errorf("expected variant rectangle, got %q (ok=%v)", variant, ok)
+		}
+	})
+
+	t.Run("extras are not a variant", func(t *testing.T) {
+		var shape TaggedUnion[EnvelopeShape]
+		shape.Value.Extras = Extras{"id": json.RawMessage("1")}
+		if !shape.IsZero() {
+			t.Error("expected union with only extras to be zero")
+		}
+	})
+}
+
+func TestCheckSpecExtras(t *testing.T) {
+	if err := CheckSpec[EnvelopeShape](); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err := CheckSpec[DuplicateExtrasShape]()
+	if !errors.Is(err, ErrInvalidSpec) {
+		t.Fatalf("expected ErrInvalidSpec, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "fields A and B both capture extra members") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package union
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// VariantFlag is a flag.Value setting a union to the unit variant named by a command-line
+// flag, so enum-like unions can be used as flags validated against their spec. It also has
+// the Type method of pflag.Value, for cobra and other pflag-based command lines.
+// Create one with Flag.
+type VariantFlag struct {
+	spec reflect.Value // addressable spec value of the union
+}
+
+// Flag returns a VariantFlag setting the union u, a pointer to a union:
+//
+//	var status union.TaggedUnion[Status]
+//	flag.Var(union.Flag(&status), "status", "initial status")
+//
+// The flag accepts the names and aliases of the spec's unit variants, tagged `union:"unit"`,
+// and rejects other values with an error listing them, such as
+// `invalid value "paused" for flag -status: must be one of idle, stopped`.
+// The union's current variant, if it is a unit variant, is the flag's default.
+func Flag(u interface{ specValue() reflect.Value }) *VariantFlag {
+	return &VariantFlag{spec: u.specValue()}
+}
+
+// String returns the name of the union's unit variant, or "" if another variant or no variant is set.
+func (f *VariantFlag) String() string {
+	if f == nil || !f.spec.IsValid() {
+		return ""
+	}
+	variant, _ := unitVariant(f.spec, nil)
+	return variant
+}
+
+// Set makes the unit variant named value the active variant of the union.
+func (f *VariantFlag) Set(value string) error {
+	p := planOf(f.spec.Type())
+	if !p.isStruct {
+		return ErrSpecNotStruct
+	}
+	field, err := p.resolve(value)
+	if errors.Is(err, ErrUnknownVariant) || err == nil && !field.unit {
+		return fmt.Errorf("must be one of %s", strings.Join(p.unitVariants(), ", "))
+	}
+	if err != nil {
+		return err
+	}
+	p.clear(f.spec)
+	f.spec.FieldByIndex(field.index).Set(zeroPayload(field))
+	return nil
+}
+
+// Type implements the pflag.Value interface, naming the flag's value type in help output.
+func (f *VariantFlag) Type() string {
+	return "variant"
+}
+
+// unitVariants returns the names of the unit variants declared by the spec.
+func (p *specPlan) unitVariants() []string {
+	var names []string
+	for _, f := range p.fields {
+		if f.unit {
+			names = append(names, f.variant)
+		}
+	}
+	return names
+}
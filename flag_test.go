@@ -0,0 +1,81 @@
+package union
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+type Stopped struct{}
+
+type StatusFlagShape struct {
+	Idle    *Idle    `variant:"idle" union:"unit" variantAliases:"waiting"`
+	Stopped *Stopped `variant:"stopped" union:"unit"`
+	Circle  *Circle  `variant:"circle"`
+}
+
+func TestFlag(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+		err      string
+	}{
+		{name: "unit variant", args: []string{"-status=stopped"}, expected: "stopped"},
+		{name: "alias", args: []string{"-status", "waiting"}, expected: "idle"},
+		{name: "default", args: nil, expected: "idle"},
+		{name: "unknown variant", args: []string{"-status=paused"}, err: `invalid value "paused" for flag -status: must be one of idle, stopped`},
+		{name: "variant with payload", args: []string{"-status=circle"}, err: "must be one of idle, stopped"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := TaggedUnion[StatusFlagShape]{Value: StatusFlagShape{Idle: &Idle{}}}
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			fs.Var(Flag(&status), "status", "initial status")
+
+			err := fs.Parse(tt.args)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if variant := status.MustVariant(); variant != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, variant)
+			}
+		})
+	}
+
+	t.Run("prints default", func(t *testing.T) {
+		status := ExternallyTagged[StatusFlagShape]{Value: StatusFlagShape{Stopped: &Stopped{}}}
+		var out strings.Builder
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&out)
+		fs.Var(Flag(&status), "status", "initial `status`")
+		fs.PrintDefaults()
+		if expected := "(default stopped)"; !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q in %q", expected, out.String())
+		}
+	})
+
+	t.Run("non-struct spec", func(t *testing.T) {
+		var u Union[NonStructType]
+		if err := Flag(&u).Set("circle"); !errors.Is(err, ErrSpecNotStruct) {
+			t.Errorf("expected error %v, got %v", ErrSpecNotStruct, err)
+		}
+	})
+
+	t.Run("type", func(t *testing.T) {
+		var u TaggedUnion[StatusFlagShape]
+		if typ := Flag(&u).Type(); typ != "variant" {
+			t.Errorf("expected variant, got %v", typ)
+		}
+	})
+}
@@ -0,0 +1,257 @@
+package union
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FormKeys selects how the keys of nested form fields are written and read by
+// EncodeForm and DecodeForm.
+type FormKeys int
+
+const (
+	// DotKeys joins nested keys with dots, as in value.radius. It is the default.
+	DotKeys FormKeys = iota
+	// BracketKeys wraps nested keys in brackets, as in value[radius].
+	BracketKeys
+)
+
+// join returns the key of the member key of the object at prefix.
+func (k FormKeys) join(prefix, key string) string {
+	switch {
+	case prefix == "":
+		return key
+	case k == BracketKeys:
+		return prefix + "[" + key + "]"
+	}
+	return prefix + "." + key
+}
+
+// split returns the path of object keys making up a form key. An empty
+// trailing bracket pair, as in tags[], is dropped.
+func (k FormKeys) split(key string) []string {
+	if k != BracketKeys {
+		return strings.Split(key, ".")
+	}
+	head, rest, ok := strings.Cut(key, "[")
+	path := []string{head}
+	for ok {
+		var member string
+		member, rest, _ = strings.Cut(rest, "]")
+		if member != "" {
+			path = append(path, member)
+		}
+		_, rest, ok = strings.Cut(rest, "[")
+	}
+	return path
+}
+
+// EncodeForm returns the union as form values for application/x-www-form-urlencoded
+// bodies and query strings, such as type=circle&value.radius=5 with DotKeys. The form
+// has the shape of the union's JSON representation: object members become nested keys
+// and arrays of scalars repeated keys, while arrays of objects are keyed by index.
+// Null values are omitted.
+func EncodeForm[Spec any](u TaggedUnion[Spec], keys FormKeys) (url.Values, error) {
+	m, err := u.encodeMap()
+	if err != nil {
+		return nil, err
+	}
+	values := url.Values{}
+	encodeFormValue(values, "", m, keys)
+	return values, nil
+}
+
+// encodeFormValue adds the generic value at the form key prefix to values.
+func encodeFormValue(values url.Values, prefix string, value any, keys FormKeys) {
+	switch value := value.(type) {
+	case nil:
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(value)) {
+			encodeFormValue(values, keys.join(prefix, key), value[key], keys)
+		}
+	case []any:
+		if slices.ContainsFunc(value, isFormContainer) {
+			for i, elem := range value {
+				encodeFormValue(values, keys.join(prefix, strconv.Itoa(i)), elem, keys)
+			}
+			return
+		}
+		for _, elem := range value {
+			encodeFormValue(values, prefix, elem, keys)
+		}
+	case string:
+		values.Add(prefix, value)
+	case float64:
+		values.Add(prefix, strconv.FormatFloat(value, 'f', -1, 64))
+	default:
+		values.Add(prefix, fmt.Sprint(value))
+	}
+}
+
+// isFormContainer reports whether a generic value is an object or an array.
+func isFormContainer(value any) bool {
+	switch value.(type) {
+	case map[string]any, []any:
+		return true
+	}
+	return false
+}
+
+// DecodeForm returns a TaggedUnion decoded from form values written like EncodeForm
+// does, with the same variant rules as DecodeMap. Form values are strings, so they are
+// converted to the numbers and booleans of the variant's fields, and a key repeated
+// for a slice field fills the slice. Other fields read the first value of their key.
+func DecodeForm[Spec any](values url.Values, keys FormKeys) (TaggedUnion[Spec], error) {
+	var u TaggedUnion[Spec]
+	tree, err := formTree(values, keys)
+	if err != nil {
+		return u, err
+	}
+
+	// convert the payload to the types of the variant's field, leaving
+	// unknown variants for decodeMap to report
+	variantField, valueField := u.fieldNames()
+	m := map[string]any{}
+	for key, value := range tree {
+		m[key] = formValue(value, reflect.TypeFor[any]())
+	}
+	p := planOf(reflect.TypeFor[Spec]())
+	if variant, ok := m[variantField].(string); ok && p.isStruct {
+		if f, err := p.lookup(variant); err == nil {
+			if m[variantField], err = toGeneric(reflect.ValueOf(encodeDiscriminator(discriminatorKind(u.Value), variant))); err != nil {
+				return u, err
+			}
+			if valueField == "" {
+				payload := maps.Clone(tree)
+				delete(payload, variantField)
+				if payload, ok := formValue(payload, f.typ).(map[string]any); ok {
+					maps.Copy(m, payload)
+				}
+			} else if value, ok := tree[valueField]; ok {
+				m[valueField] = formValue(value, f.typ)
+			}
+		}
+	}
+	err = u.decodeMap(m)
+	return u, err
+}
+
+// formTree nests form values by their key paths into objects, whose leaves hold
+// the []string values of their key.
+func formTree(values url.Values, keys FormKeys) (map[string]any, error) {
+	tree := map[string]any{}
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		path := keys.split(key)
+		node := tree
+		for i, member := range path {
+			if i == len(path)-1 {
+				if _, exists := node[member]; exists {
+					return nil, fmt.Errorf("form key %q conflicts with a nested key", key)
+				}
+				node[member] = values[key]
+				break
+			}
+			child, ok := node[member].(map[string]any)
+			if !ok {
+				if _, exists := node[member]; exists {
+					return nil, fmt.Errorf("form key %q conflicts with a nested key", key)
+				}
+				child = map[string]any{}
+				node[member] = child
+			}
+			node = child
+		}
+	}
+	return tree, nil
+}
+
+// formValue converts a node of a form tree to the generic value of type t, converting
+// strings to numbers and booleans and collecting slices. Values that cannot be
+// converted are left as strings for fromGeneric to reject.
+func formValue(value any, t reflect.Type) any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch value := value.(type) {
+	case []string:
+		if isFormSlice(t) {
+			s := make([]any, len(value))
+			for i, elem := range value {
+				s[i] = formValue([]string{elem}, t.Elem())
+			}
+			return s
+		}
+		if len(value) == 0 {
+			return nil
+		}
+		return formScalar(value[0], t)
+	case map[string]any:
+		if isFormSlice(t) {
+			return formIndexed(value, t.Elem())
+		}
+		m := make(map[string]any, len(value))
+		for key, elem := range value {
+			m[key] = formValue(elem, formMemberType(t, key))
+		}
+		return m
+	}
+	return value
+}
+
+// isFormSlice reports whether values of type t are collected from repeated or indexed keys.
+func isFormSlice(t reflect.Type) bool {
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Uint8
+}
+
+// formIndexed converts an object keyed by indexes, as in value.0.name, to an array.
+func formIndexed(m map[string]any, elem reflect.Type) any {
+	indexes := slices.Collect(maps.Keys(m))
+	slices.SortFunc(indexes, func(a, b string) int {
+		i, _ := strconv.Atoi(a)
+		j, _ := strconv.Atoi(b)
+		return cmp.Or(cmp.Compare(i, j), strings.Compare(a, b))
+	})
+	s := make([]any, len(indexes))
+	for i, index := range indexes {
+		s[i] = formValue(m[index], elem)
+	}
+	return s
+}
+
+// formMemberType returns the type of the member key of an object of type t,
+// matching struct fields by their JSON names like encoding/json does.
+func formMemberType(t reflect.Type, key string) reflect.Type {
+	switch t.Kind() {
+	case reflect.Map:
+		return t.Elem()
+	case reflect.Struct:
+		if f, ok := jsonFieldOf(t, key); ok {
+			return t.FieldByIndex(f.index).Type
+		}
+	}
+	return reflect.TypeFor[any]()
+}
+
+// formScalar converts a form string to the generic value of type t.
+func formScalar(s string, t reflect.Type) any {
+	switch t.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		if isJSONNumber(s) {
+			return json.Number(s)
+		}
+	}
+	return s
+}
@@ -0,0 +1,132 @@
+package union
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type (
+	FormFilter struct {
+		Query  string      `json:"q"`
+		Limit  int         `json:"limit"`
+		Exact  bool        `json:"exact"`
+		Tags   []string    `json:"tags"`
+		Ranges []FormRange `json:"ranges"`
+		Owner  *Circle     `json:"owner,omitempty"`
+	}
+	FormRange struct {
+		From float64 `json:"from"`
+		To   float64 `json:"to"`
+	}
+	FormShape struct {
+		Circle *Circle     `variant:"circle"`
+		Filter *FormFilter `variant:"filter"`
+	}
+)
+
+func TestForm(t *testing.T) {
+	filter := TaggedUnion[FormShape]{Value: FormShape{Filter: &FormFilter{
+		Query:  "go",
+		Limit:  10,
+		Exact:  true,
+		Tags:   []string{"a", "b"},
+		Ranges: []FormRange{{From: 0.5, To: 2}},
+		Owner:  &Circle{Radius: 1},
+	}}}
+
+	tests := []struct {
+		name     string
+		keys     FormKeys
+		union    TaggedUnion[FormShape]
+		expected string
+	}{
+		{
+			name:     "dot keys",
+			keys:     DotKeys,
+			union:    TaggedUnion[FormShape]{Value: FormShape{Circle: &Circle{Radius: 5}}},
+			expected: "type=circle&value.radius=5",
+		},
+		{
+			name:     "bracket keys",
+			keys:     BracketKeys,
+			union:    TaggedUnion[FormShape]{Value: FormShape{Circle: &Circle{Radius: 2.5}}},
+			expected: "type=circle&value%5Bradius%5D=2.5",
+		},
+		{
+			name:     "nested values",
+			keys:     DotKeys,
+			union:    filter,
+			expected: "type=filter&value.exact=true&value.limit=10&value.owner.radius=1&value.q=go&value.ranges.0.from=0.5&value.ranges.0.to=2&value.tags=a&value.tags=b",
+		},
+		{
+			name:     "nested bracket values",
+			keys:     BracketKeys,
+			union:    filter,
+			expected: "type=filter&value%5Bexact%5D=true&value%5Blimit%5D=10&value%5Bowner%5D%5Bradius%5D=1&value%5Bq%5D=go&value%5Branges%5D%5B0%5D%5Bfrom%5D=0.5&value%5Branges%5D%5B0%5D%5Bto%5D=2&value%5Btags%5D=a&value%5Btags%5D=b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := EncodeForm(tt.union, tt.keys)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if encoded := values.Encode(); encoded != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, encoded)
+			}
+
+			decoded, err := DecodeForm[FormShape](values, tt.keys)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, tt.union) {
+				t.Errorf("expected %v, got %v", tt.union, decoded)
+			}
+		})
+	}
+
+	t.Run("decodes flat forms", func(t *testing.T) {
+		values, _ := url.ParseQuery("type=circle&radius=3")
+		u, err := DecodeForm[FlatShape](values, DotKeys)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertValueEquals(t, u.GetValue(), Circle{Radius: 3})
+	})
+
+	t.Run("accepts empty brackets for slices", func(t *testing.T) {
+		values, _ := url.ParseQuery("type=filter&value[tags][]=x&value[tags][]=y")
+		u, err := DecodeForm[FormShape](values, BracketKeys)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(u.Value.Filter.Tags, []string{"x", "y"}) {
+			t.Errorf("expected tags [x y], got %v", u.Value.Filter.Tags)
+		}
+	})
+
+	t.Run("rejects invalid numbers", func(t *testing.T) {
+		values, _ := url.ParseQuery("type=circle&value.radius=big")
+		_, err := DecodeForm[FormShape](values, DotKeys)
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Variant != "circle" {
+			t.Errorf("expected DecodeError for circle, got %v", err)
+		}
+	})
+
+	t.Run("rejects unknown variants", func(t *testing.T) {
+		values, _ := url.ParseQuery("type=hexagon&value.sides=6")
+		if _, err := DecodeForm[FormShape](values, DotKeys); !errors.Is(err, ErrUnknownVariant) {
+			t.Errorf("expected ErrUnknownVariant, got %v", err)
+		}
+	})
+
+	t.Run("rejects conflicting keys", func(t *testing.T) {
+		values, _ := url.ParseQuery("type=circle&value=1&value.radius=2")
+		if _, err := DecodeForm[FormShape](values, DotKeys); err == nil {
+			t.Error("expected error for conflicting keys")
+		}
+	})
+}
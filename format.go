@@ -0,0 +1,46 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// String implements the fmt.Stringer interface, formatting the union as its
+// spec type name and active variant, such as "Shape(circle: {Radius:5})",
+// or "Shape(<unset>)" if no variant is set.
+func (u TaggedUnion[Spec]) String() string {
+	return formatUnion(reflect.ValueOf(u.Value), u.selected)
+}
+
+// String implements the fmt.Stringer interface. See TaggedUnion.String.
+func (u ExternallyTagged[Spec]) String() string {
+	return formatUnion(reflect.ValueOf(u.Value), u.selected)
+}
+
+// String implements the fmt.Stringer interface. See TaggedUnion.String.
+func (u Union[Spec]) String() string {
+	return formatUnion(reflect.ValueOf(u.Value), u.selected)
+}
+
+// formatUnion formats the spec value v for String, dereferencing pointer payloads.
+func formatUnion(v reflect.Value, selected *fieldPlan) string {
+	f, err := planOf(v.Type()).current(v, selected)
+	switch {
+	case errors.Is(err, ErrZeroVariants):
+		return v.Type().Name() + "(<unset>)"
+	case err != nil:
+		return fmt.Sprintf("%s(<%v>)", v.Type().Name(), err)
+	}
+
+	variant, value := variantValue(v, f)
+	if raw, ok := value.(json.RawMessage); ok {
+		return fmt.Sprintf("%s(%s: %s)", v.Type().Name(), variant, raw)
+	}
+	payload := reflect.ValueOf(value)
+	for payload.Kind() == reflect.Pointer && !payload.IsNil() {
+		payload = payload.Elem()
+	}
+	return fmt.Sprintf("%s(%s: %+v)", v.Type().Name(), variant, payload.Interface())
+}
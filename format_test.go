@@ -0,0 +1,52 @@
+package union
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		name     string
+		shape    fmt.Stringer
+		expected string
+	}{
+		{
+			name:     "pointer variant",
+			shape:    TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 5.0}}},
+			expected: "Shape(circle: {Radius:5})",
+		},
+		{
+			name:     "non-pointer variant",
+			shape:    Union[UnionNonPointerShape]{Value: UnionNonPointerShape{Rectangle: Rectangle{Width: 2, Height: 3}}},
+			expected: "UnionNonPointerShape(Rectangle: {Width:2 Height:3})",
+		},
+		{
+			name:     "unset",
+			shape:    ExternallyTagged[Shape]{},
+			expected: "Shape(<unset>)",
+		},
+		{
+			name:     "multiple variants",
+			shape:    TaggedUnion[Shape]{Value: Shape{Circle: &Circle{}, Rectangle: &Rectangle{}}},
+			expected: "Shape(<multiple variants set>)",
+		},
+		{
+			name:     "raw variant",
+			shape:    TaggedUnion[ForwardShape]{Value: ForwardShape{Unknown: &Raw{Variant: "hexagon", Value: json.RawMessage(`{"sides":6}`)}}},
+			expected: `ForwardShape(hexagon: {"sides":6})`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.shape.String(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+			if got := fmt.Sprint(tt.shape); got != tt.expected {
+				t.Errorf("expected %q from fmt, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Format is a serialization format for unions, such as YAML, MessagePack or CBOR, selected
+// with Options.Format. MarshalWith and UnmarshalWith convert unions through their JSON
+// representation, so a format only encodes and decodes generic values: map[string]any,
+// []any, string, json.Number, bool and nil. Supporting a new format takes a Format, without
+// new methods on the union types; FormatFuncs adapts the package-level functions of a library:
+//
+//	yamlFormat := union.FormatFuncs{MarshalFunc: yaml.Marshal, UnmarshalFunc: yaml.Unmarshal, MediaType: "application/yaml"}
+//
+// Unmarshal is given a *any to decode into. ContentType returns the media type of the
+// format, for Content-Type and Accept headers.
+type Format interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+	ContentType() string
+}
+
+// FormatFuncs adapts a pair of Marshal and Unmarshal functions and a media type to a Format.
+type FormatFuncs struct {
+	MarshalFunc   func(v any) ([]byte, error)
+	UnmarshalFunc func(data []byte, v any) error
+	MediaType     string
+}
+
+func (f FormatFuncs) Marshal(v any) ([]byte, error) { return f.MarshalFunc(v) }
+
+func (f FormatFuncs) Unmarshal(data []byte, v any) error { return f.UnmarshalFunc(data, v) }
+
+func (f FormatFuncs) ContentType() string { return f.MediaType }
+
+// The built-in formats. Their Marshal methods encode values other than generic values
+// through encoding/json first, and their Unmarshal methods decode into other values than
+// *any through encoding/json, so they also work on their own, as in CBOR.Marshal(shape).
+var (
+	// JSON is the default format. MarshalWith and UnmarshalWith encode and decode the
+	// union's JSON representation directly with it.
+	JSON Format = jsonFormat{}
+
+	// MessagePack encodes values as MessagePack, like the MarshalMsgpack methods.
+	MessagePack Format = msgpackFormat{}
+
+	// CBOR encodes values as CBOR, like the MarshalCBOR methods of specs without CBORTags.
+	CBOR Format = cborFormat{}
+)
+
+type jsonFormat struct{}
+
+func (jsonFormat) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+func (jsonFormat) Unmarshal(data []byte, v any) error {
+	if p, ok := v.(*any); ok {
+		value, err := decodeJSONValue(data)
+		if err != nil {
+			return err
+		}
+		*p = value
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (jsonFormat) ContentType() string { return "application/json" }
+
+type msgpackFormat struct{}
+
+func (msgpackFormat) Marshal(v any) ([]byte, error) {
+	value, err := genericValue(v)
+	if err != nil {
+		return nil, err
+	}
+	return appendMsgpack(nil, value)
+}
+
+func (msgpackFormat) Unmarshal(data []byte, v any) error {
+	value, rest, err := readMsgpack(data, 0)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return errors.New("msgpack: trailing data")
+	}
+	return setGenericValue(v, value)
+}
+
+func (msgpackFormat) ContentType() string { return "application/msgpack" }
+
+type cborFormat struct{}
+
+func (cborFormat) Marshal(v any) ([]byte, error) {
+	value, err := genericValue(v)
+	if err != nil {
+		return nil, err
+	}
+	return appendCBOR(nil, value)
+}
+
+func (cborFormat) Unmarshal(data []byte, v any) error {
+	value, err := readCBORValue(data)
+	if err != nil {
+		return err
+	}
+	return setGenericValue(v, value)
+}
+
+func (cborFormat) ContentType() string { return "application/cbor" }
+
+// genericValue returns v if it is a generic value, otherwise its JSON encoding decoded into generic values.
+func genericValue(v any) (any, error) {
+	switch v.(type) {
+	case nil, bool, json.Number, string, []any, map[string]any:
+		return v, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSONValue(data)
+}
+
+// setGenericValue stores the generic value in v if it is a *any, otherwise it
+// unmarshals the value into v through its JSON encoding.
+func setGenericValue(v any, value any) error {
+	if p, ok := v.(*any); ok {
+		*p = value
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,120 @@
+package union
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// base64Format encodes generic values as base64 encoded JSON, standing in for a third-party format.
+var base64Format = FormatFuncs{
+	MarshalFunc: func(v any) ([]byte, error) {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return base64.StdEncoding.AppendEncode(nil, data), nil
+	},
+	UnmarshalFunc: func(data []byte, v any) error {
+		data, err := base64.StdEncoding.AppendDecode(nil, data)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(data, v)
+	},
+	MediaType: "application/x-base64-json",
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      Format
+		contentType string
+	}{
+		{"json", JSON, "application/json"},
+		{"msgpack", MessagePack, "application/msgpack"},
+		{"cbor", CBOR, "application/cbor"},
+		{"custom", base64Format, "application/x-base64-json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.format.ContentType(); got != tt.contentType {
+				t.Errorf("expected %v, got %v", tt.contentType, got)
+			}
+
+			shape := TaggedUnion[Shape]{Value: Shape{Triangle: &Triangle{Base: 1.5, Height: 1 << 40}}}
+			data, err := MarshalWith(shape, Options{Format: tt.format})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var decoded TaggedUnion[Shape]
+			if err := UnmarshalWith(data, &decoded, Options{Format: tt.format}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertValueEquals(t, decoded.GetValue(), Triangle{Base: 1.5, Height: 1 << 40})
+		})
+	}
+}
+
+func TestMarshalWithFormat(t *testing.T) {
+	shape := TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 5}}}
+
+	data, err := MarshalWith(shape, Options{Format: JSON})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `{"type":"circle","value":{"radius":5}}`; string(data) != expected {
+		t.Errorf("expected %v, got %v", expected, string(data))
+	}
+
+	data, err = MarshalWith(shape, Options{Format: MessagePack})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	direct, err := shape.MarshalMsgpack()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hex.EncodeToString(data) != hex.EncodeToString(direct) {
+		t.Errorf("expected %x, got %x", direct, data)
+	}
+
+	if _, err := MarshalWith(TaggedUnion[Shape]{}, Options{Format: CBOR}); !errors.Is(err, ErrZeroVariants) {
+		t.Errorf("expected error %v, got %v", ErrZeroVariants, err)
+	}
+}
+
+func TestUnmarshalWithFormatErrors(t *testing.T) {
+	var shape TaggedUnion[Shape]
+	data, err := MessagePack.Marshal(map[string]any{"type": "hexagon", "value": map[string]any{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := UnmarshalWith(data, &shape, Options{Format: MessagePack}); !errors.Is(err, ErrUnknownVariant) {
+		t.Errorf("expected error %v, got %v", ErrUnknownVariant, err)
+	}
+	if err := UnmarshalWith([]byte{0xff}, &shape, Options{Format: CBOR}); err == nil {
+		t.Error("expected error for malformed data")
+	}
+}
+
+func TestBuiltinFormatsStandalone(t *testing.T) {
+	for _, format := range []Format{JSON, MessagePack, CBOR} {
+		t.Run(format.ContentType(), func(t *testing.T) {
+			data, err := format.Marshal(ExternallyTagged[Shape]{Value: Shape{Circle: &Circle{Radius: 2}}})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var decoded ExternallyTagged[Shape]
+			if err := format.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertValueEquals(t, decoded.GetValue(), Circle{Radius: 2})
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package union
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"slices"
+)
+
+// Generate implements the quick.Generator interface of testing/quick, so property-based
+// tests can take unions as arguments. It returns a TaggedUnion with one randomly chosen
+// variant set to a random payload, see generateSpec.
+func (TaggedUnion[Spec]) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(TaggedUnion[Spec]{Value: generateSpec[Spec](r, size)})
+}
+
+// Generate implements the quick.Generator interface of testing/quick. See TaggedUnion.Generate.
+func (ExternallyTagged[Spec]) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(ExternallyTagged[Spec]{Value: generateSpec[Spec](r, size)})
+}
+
+// Generate implements the quick.Generator interface of testing/quick. See TaggedUnion.Generate.
+// Payloads of different variants may have the same JSON shape, so an untagged union can
+// decode a generated value into another variant than the one it was generated with.
+func (Union[Spec]) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(Union[Spec]{Value: generateSpec[Spec](r, size)})
+}
+
+// generateAttempts is the number of payloads generated for a non-pointer variant field
+// before giving up on a variant whose payloads keep coming out zero.
+const generateAttempts = 10
+
+// generateSpec returns a Spec value with one randomly chosen variant set, which makes a valid
+// union. Payloads implementing quick.Generator generate themselves, nested unions included.
+// Other payloads are filled with random values bounded by size: numbers within ±size and
+// strings, slices and maps of up to size elements. Unexported fields, interfaces, channels
+// and functions are left zero, and raw fields are never chosen.
+//
+// Nested unions and other payloads implementing quick.Generator are generated with half the
+// size, and at size 0 variants without nested unions are chosen first, so recursive specs
+// such as expression trees generate finite values.
+//
+// It panics if the Spec type is not a struct or none of its variants can hold a non-zero
+// payload, such as a spec of non-pointer empty structs.
+func generateSpec[Spec any](r *rand.Rand, size int) Spec {
+	var spec Spec
+	v := reflect.ValueOf(&spec).Elem()
+	p := planOf(v.Type())
+	order := r.Perm(len(p.fields))
+	if size <= 0 {
+		nested := func(i int) bool { return generatesNested(p.fields[i].typ) }
+		slices.SortStableFunc(order, func(a, b int) int {
+			switch {
+			case nested(a) == nested(b):
+				return 0
+			case nested(a):
+				return 1
+			}
+			return -1
+		})
+	}
+	for _, i := range order {
+		f := &p.fields[i]
+		if f.raw {
+			continue
+		}
+		fv := v.FieldByIndex(f.index)
+		if f.pointer {
+			ptr := reflect.New(f.typ.Elem())
+			ptr.Elem().Set(randomValue(f.typ.Elem(), r, size))
+			fv.Set(ptr)
+			return spec
+		}
+		for range generateAttempts {
+			fv.Set(randomValue(f.typ, r, size))
+			if !f.isZero(fv) {
+				return spec
+			}
+		}
+		fv.SetZero()
+	}
+	panic(fmt.Sprintf("union: cannot generate a variant of %s", p.typ))
+}
+
+// generatorType is the quick.Generator interface, declared here so the package doesn't
+// import testing/quick, which registers command-line flags.
+var generatorType = reflect.TypeFor[interface {
+	Generate(r *rand.Rand, size int) reflect.Value
+}]()
+
+// randomValue returns a random value of type t, like quick.Value with a size bound.
+// Elements of pointers, slices and maps and values implementing quick.Generator are
+// generated with half the size, so values of recursive types stay finite.
+func randomValue(t reflect.Type, r *rand.Rand, size int) reflect.Value {
+	if t.Kind() != reflect.Interface && t.Implements(generatorType) {
+		return reflect.Zero(t).Interface().(interface {
+			Generate(r *rand.Rand, size int) reflect.Value
+		}).Generate(r, size/2)
+	}
+	v := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Bool:
+		v.SetBool(r.Intn(2) == 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(r.Int63n(2*int64(size)+1) - int64(size))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v.SetUint(uint64(r.Int63n(int64(size) + 1)))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat((2*r.Float64() - 1) * float64(size))
+	case reflect.Complex64, reflect.Complex128:
+		v.SetComplex(complex((2*r.Float64()-1)*float64(size), (2*r.Float64()-1)*float64(size)))
+	case reflect.String:
+		runes := make([]rune, r.Intn(size+1))
+		for i := range runes {
+			runes[i] = rune(r.Intn(0xD800)) // below the surrogates, so always valid UTF-8
+		}
+		v.SetString(string(runes))
+	case reflect.Pointer:
+		if size > 0 && r.Intn(size+1) > 0 {
+			ptr := reflect.New(t.Elem())
+			ptr.Elem().Set(randomValue(t.Elem(), r, size/2))
+			v.Set(ptr)
+		}
+	case reflect.Slice:
+		n := r.Intn(size + 1)
+		v.Set(reflect.MakeSlice(t, n, n))
+		for i := range n {
+			v.Index(i).Set(randomValue(t.Elem(), r, size/2))
+		}
+	case reflect.Array:
+		for i := range v.Len() {
+			v.Index(i).Set(randomValue(t.Elem(), r, size))
+		}
+	case reflect.Map:
+		n := r.Intn(size + 1)
+		v.Set(reflect.MakeMapWithSize(t, n))
+		for range n {
+			v.SetMapIndex(randomValue(t.Key(), r, size/2), randomValue(t.Elem(), r, size/2))
+		}
+	case reflect.Struct:
+		for i := range t.NumField() {
+			if t.Field(i).IsExported() {
+				v.Field(i).Set(randomValue(t.Field(i).Type, r, size))
+			}
+		}
+	}
+	return v
+}
+
+// generatesNested reports whether random values of the payload type t always hold a value
+// implementing quick.Generator, such as a nested union, in a field or array element.
+// Pointers, slices and maps are empty at size 0, so only the payload of a pointer is followed.
+func generatesNested(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return reachesGenerator(t)
+}
+
+func reachesGenerator(t reflect.Type) bool {
+	if t.Kind() != reflect.Interface && t.Implements(generatorType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Array:
+		return t.Len() > 0 && reachesGenerator(t.Elem())
+	case reflect.Struct:
+		for i := range t.NumField() {
+			if t.Field(i).IsExported() && reachesGenerator(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package union
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+	"testing/quick"
+)
+
+type EmptyPayloadShape struct {
+	Empty struct{} `variant:"empty"`
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("tagged union round trips", func(t *testing.T) {
+		roundTrip := func(u TaggedUnion[Shape]) bool {
+			data, err := json.Marshal(u)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got TaggedUnion[Shape]
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			return Equal(u, got)
+		}
+		if err := quick.Check(roundTrip, nil); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("externally tagged union round trips", func(t *testing.T) {
+		roundTrip := func(u ExternallyTagged[NonPointerShape]) bool {
+			data, err := json.Marshal(u)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got ExternallyTagged[NonPointerShape]
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			return Equal(u, got)
+		}
+		if err := quick.Check(roundTrip, nil); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("generates valid unions", func(t *testing.T) {
+		valid := func(a TaggedUnion[NestedShape], b Union[UnionShape], c TaggedUnion[Expr]) bool {
+			return a.Validate() == nil && b.Validate() == nil && c.Validate() == nil
+		}
+		if err := quick.Check(valid, nil); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("chooses every variant", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		seen := map[string]bool{}
+		for range 100 {
+			u := TaggedUnion[Shape]{}.Generate(r, 10).Interface().(TaggedUnion[Shape])
+			seen[u.MustVariant()] = true
+		}
+		if len(seen) != 3 {
+			t.Errorf("expected 3 variants, got %v", seen)
+		}
+	})
+
+	t.Run("panics without generatable variants", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic")
+			}
+		}()
+		TaggedUnion[EmptyPayloadShape]{}.Generate(rand.New(rand.NewSource(1)), 10)
+	})
+}
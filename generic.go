@@ -0,0 +1,464 @@
+package union
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// jsonField holds the encoding/json metadata of a struct field.
+type jsonField struct {
+	name      string // JSON object key
+	index     []int  // field index sequence, longer for promoted fields
+	tagged    bool   // whether the name comes from a json struct tag
+	omitEmpty bool   // whether the field has the omitempty option
+	omitZero  bool   // whether the field has the omitzero option
+	quoted    bool   // whether the field has the string option
+}
+
+// jsonFieldCache caches the jsonFields of each struct type.
+var jsonFieldCache sync.Map // map[reflect.Type][]jsonField
+
+// jsonFields returns the fields of the struct type t as encoding/json sees them, with
+// fields of embedded structs promoted unless a shallower field has the same name.
+func jsonFields(t reflect.Type) []jsonField {
+	if fields, ok := jsonFieldCache.Load(t); ok {
+		return fields.([]jsonField)
+	}
+
+	byName := map[string][]jsonField{}
+	var names []string
+	for _, sf := range reflect.VisibleFields(t) {
+		tag := sf.Tag.Get("json")
+		if tag == "-" || (!sf.IsExported() && !sf.Anonymous) {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if sf.Anonymous && name == "" && indirect(sf.Type).Kind() == reflect.Struct {
+			// the fields of embedded structs are visited on their own
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		f := jsonField{name: name, index: sf.Index, tagged: name != ""}
+		if name == "" {
+			f.name = sf.Name
+		}
+		for opt := range strings.SplitSeq(opts, ",") {
+			switch opt {
+			case "omitempty":
+				f.omitEmpty = true
+			case "omitzero":
+				f.omitZero = true
+			case "string":
+				f.quoted = true
+			}
+		}
+		if _, ok := byName[f.name]; !ok {
+			names = append(names, f.name)
+		}
+		byName[f.name] = append(byName[f.name], f)
+	}
+
+	var fields []jsonField
+	for _, name := range names {
+		if f, ok := dominantField(byName[name]); ok {
+			fields = append(fields, f)
+		}
+	}
+	f, _ := jsonFieldCache.LoadOrStore(t, fields)
+	return f.([]jsonField)
+}
+
+// jsonFieldOf returns the field of the struct type t decoding the object key, preferring
+// an exact match of its name over a case-insensitive one like encoding/json does.
+func jsonFieldOf(t reflect.Type, key string) (jsonField, bool) {
+	fields := jsonFields(t)
+	i := slices.IndexFunc(fields, func(f jsonField) bool { return f.name == key })
+	if i < 0 {
+		i = slices.IndexFunc(fields, func(f jsonField) bool { return strings.EqualFold(f.name, key) })
+	}
+	if i < 0 {
+		return jsonField{}, false
+	}
+	return fields[i], true
+}
+
+// dominantField returns the field encoding/json uses among fields with the same name:
+// the shallowest one, preferring tagged fields. It reports false if there is no single one.
+func dominantField(fields []jsonField) (jsonField, bool) {
+	depth := slices.MinFunc(fields, func(a, b jsonField) int { return len(a.index) - len(b.index) })
+	var shallow []jsonField
+	for _, f := range fields {
+		if len(f.index) == len(depth.index) {
+			shallow = append(shallow, f)
+		}
+	}
+	if len(shallow) == 1 {
+		return shallow[0], true
+	}
+	var tagged []jsonField
+	for _, f := range shallow {
+		if f.tagged {
+			tagged = append(tagged, f)
+		}
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+	return jsonField{}, false
+}
+
+var (
+	jsonMarshalerType   = reflect.TypeFor[json.Marshaler]()
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// customJSON reports whether values of type t encode themselves, as json.Marshaler
+// or encoding.TextMarshaler implementations (or their pointers) do. Generic conversions
+// go through their JSON representation, like time.Time, json.RawMessage and nested unions.
+func customJSON(t reflect.Type, marshaler, unmarshaler reflect.Type) bool {
+	pt := reflect.PointerTo(t)
+	return t.Implements(marshaler) || pt.Implements(marshaler) ||
+		t.Implements(unmarshaler) || pt.Implements(unmarshaler)
+}
+
+// toGeneric converts v to the generic value (map[string]any, []any, string, int64,
+// float64, bool or nil) that decoding its JSON representation yields, without encoding it.
+// Numbers are int64 when they are integers and float64 otherwise, as with ToMap.
+func toGeneric(v reflect.Value) (any, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	t := v.Type()
+	if customJSON(t, jsonMarshalerType, textMarshalerType) || (v.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8) {
+		return genericThroughJSON(v)
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return toGeneric(v.Elem())
+	case reflect.Struct:
+		fields := jsonFields(t)
+		m := make(map[string]any, len(fields))
+		for _, f := range fields {
+			if f.quoted {
+				return genericThroughJSON(v)
+			}
+			fv, err := v.FieldByIndexErr(f.index)
+			if err != nil {
+				// promoted through a nil embedded pointer
+				continue
+			}
+			if (f.omitEmpty && isEmptyJSON(fv)) || (f.omitZero && isZeroValue(fv)) {
+				continue
+			}
+			if m[f.name], err = toGeneric(fv); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if t.Key().Kind() != reflect.String {
+			return genericThroughJSON(v)
+		}
+		m := make(map[string]any, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			elem, err := toGeneric(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			m[iter.Key().String()] = elem
+		}
+		return m, nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, nil
+		}
+		s := make([]any, v.Len())
+		for i := range s {
+			elem, err := toGeneric(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			s[i] = elem
+		}
+		return s, nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if n := v.Uint(); n <= math.MaxInt64 {
+			return int64(n), nil
+		}
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if v.Kind() == reflect.Float32 {
+			// keep the shortest representation encoding/json writes for float32
+			f, _ = strconv.ParseFloat(strconv.FormatFloat(f, 'g', -1, 32), 64)
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, &json.UnsupportedValueError{Value: v, Str: strconv.FormatFloat(f, 'g', -1, 64)}
+		}
+		if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+			return int64(f), nil
+		}
+		return f, nil
+	}
+	return nil, &json.UnsupportedTypeError{Type: t}
+}
+
+// genericThroughJSON converts v to a generic value through its JSON representation.
+func genericThroughJSON(v reflect.Value) (any, error) {
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil, err
+	}
+	value, err := decodeJSONValue(data)
+	if err != nil {
+		return nil, err
+	}
+	return numbersToGo(value), nil
+}
+
+// isEmptyJSON reports whether v is empty for the omitempty option of encoding/json.
+func isEmptyJSON(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	}
+	return v.IsZero() && v.Kind() != reflect.Struct
+}
+
+// fromGeneric decodes a generic value, as produced by decoding JSON or by config
+// loaders and document stores, into v with the rules of encoding/json, without
+// encoding it. Keys unknown to a struct are rejected when strict is set.
+func fromGeneric(value any, v reflect.Value, strict bool) error {
+	t := v.Type()
+	if customJSON(t, jsonUnmarshalerType, textUnmarshalerType) ||
+		(v.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8) {
+		return fromGenericThroughJSON(value, v, strict)
+	}
+	if value == nil {
+		// like JSON null, nil only clears values that can be nil
+		switch v.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+			v.SetZero()
+		}
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+		return fromGeneric(value, v.Elem(), strict)
+	case reflect.Interface:
+		if t.NumMethod() != 0 {
+			return &json.UnmarshalTypeError{Value: genericKind(value), Type: t}
+		}
+		v.Set(reflect.ValueOf(value))
+		return nil
+	case reflect.Struct:
+		m, ok := genericMap(value)
+		if !ok {
+			return &json.UnmarshalTypeError{Value: genericKind(value), Type: t}
+		}
+		for key, elem := range m {
+			f, ok := jsonFieldOf(t, key)
+			if !ok {
+				if strict {
+					return fmt.Errorf("json: unknown field %q", key)
+				}
+				continue
+			}
+			if f.quoted {
+				return fromGenericThroughJSON(value, v, strict)
+			}
+			if err := fromGeneric(elem, fieldByIndexAlloc(v, f.index), strict); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		m, ok := genericMap(value)
+		if !ok {
+			return &json.UnmarshalTypeError{Value: genericKind(value), Type: t}
+		}
+		if t.Key().Kind() != reflect.String {
+			return fromGenericThroughJSON(value, v, strict)
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(t, len(m)))
+		}
+		for key, elem := range m {
+			ev := reflect.New(t.Elem()).Elem()
+			if err := fromGeneric(elem, ev, strict); err != nil {
+				return err
+			}
+			v.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), ev)
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		s, ok := value.([]any)
+		if !ok {
+			return &json.UnmarshalTypeError{Value: genericKind(value), Type: t}
+		}
+		if v.Kind() == reflect.Slice {
+			v.Set(reflect.MakeSlice(t, len(s), len(s)))
+		}
+		for i, elem := range s {
+			if i >= v.Len() {
+				break
+			}
+			if err := fromGeneric(elem, v.Index(i), strict); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.String:
+		s, ok := value.(string)
+		if !ok {
+			return &json.UnmarshalTypeError{Value: genericKind(value), Type: t}
+		}
+		v.SetString(s)
+		return nil
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return &json.UnmarshalTypeError{Value: genericKind(value), Type: t}
+		}
+		v.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return setGenericNumber(value, v)
+	}
+	return &json.UnmarshalTypeError{Value: genericKind(value), Type: t}
+}
+
+// setGenericNumber sets the numeric value v from a generic number, rejecting
+// values that are not numbers or don't fit v like encoding/json does.
+func setGenericNumber(value any, v reflect.Value) error {
+	var s string
+	switch n := value.(type) {
+	case json.Number:
+		s = n.String()
+	case string, bool, map[string]any, map[any]any, []any:
+		return &json.UnmarshalTypeError{Value: genericKind(value), Type: v.Type()}
+	default:
+		nv := reflect.ValueOf(value)
+		switch nv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			s = strconv.FormatInt(nv.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			s = strconv.FormatUint(nv.Uint(), 10)
+		case reflect.Float32, reflect.Float64:
+			s = strconv.FormatFloat(nv.Float(), 'f', -1, 64)
+		default:
+			return &json.UnmarshalTypeError{Value: genericKind(value), Type: v.Type()}
+		}
+	}
+
+	numberErr := &json.UnmarshalTypeError{Value: "number " + s, Type: v.Type()}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || v.OverflowInt(n) {
+			return numberErr
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil || v.OverflowUint(n) {
+			return numberErr
+		}
+		v.SetUint(n)
+	default:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil || v.OverflowFloat(f) {
+			return numberErr
+		}
+		v.SetFloat(f)
+	}
+	return nil
+}
+
+// genericMap returns value as a map[string]any, converting maps with non-string
+// keys as produced by some YAML decoders.
+func genericMap(value any) (map[string]any, bool) {
+	switch m := value.(type) {
+	case map[string]any:
+		return m, true
+	case map[any]any:
+		return stringKeys(m).(map[string]any), true
+	}
+	return nil, false
+}
+
+// genericKind describes the JSON kind of a generic value for a *json.UnmarshalTypeError.
+func genericKind(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case map[string]any, map[any]any:
+		return "object"
+	case []any:
+		return "array"
+	}
+	return "number"
+}
+
+// fromGenericThroughJSON decodes a generic value into v through its JSON representation.
+func fromGenericThroughJSON(value any, v reflect.Value, strict bool) error {
+	data, err := json.Marshal(stringKeys(value))
+	if err != nil {
+		return err
+	}
+	target, err := decodeJSON(encodingJSON, v.Type(), data, decodeOptions{strict: strict})
+	if err != nil {
+		return err
+	}
+	v.Set(target.Elem())
+	return nil
+}
+
+// fieldByIndexAlloc returns the field of the struct value v at the index sequence,
+// allocating nil embedded struct pointers on the way.
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
@@ -1,3 +1,11 @@
 module github.com/eriicafes/union
 
 go 1.25.4
+
+require google.golang.org/protobuf v1.36.12
+
+require (
+	golang.org/x/mod v0.35.0 // indirect
+	golang.org/x/sync v0.20.0 // indirect
+	golang.org/x/tools v0.44.0
+)
@@ -0,0 +1,229 @@
+package union
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// GoString implements the fmt.GoStringer interface, formatting the union as a Go
+// composite literal for the %#v verb, such as
+// "union.TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 5}}}".
+//
+// Types declared in the package of the Spec type are written unqualified, as they
+// appear in that package's code, and other named types with their package name.
+// Zero fields are omitted, and pointers to values other than composite literals are
+// written with new, as in "new(float64(5))".
+func (u TaggedUnion[Spec]) GoString() string {
+	return goString(reflect.ValueOf(u), reflect.TypeFor[Spec]())
+}
+
+// GoString implements the fmt.GoStringer interface. See TaggedUnion.GoString.
+func (u ExternallyTagged[Spec]) GoString() string {
+	return goString(reflect.ValueOf(u), reflect.TypeFor[Spec]())
+}
+
+// GoString implements the fmt.GoStringer interface. See TaggedUnion.GoString.
+func (u Union[Spec]) GoString() string {
+	return goString(reflect.ValueOf(u), reflect.TypeFor[Spec]())
+}
+
+// goString formats the union struct v as a composite literal in the package of the spec type.
+func goString(v reflect.Value, spec reflect.Type) string {
+	w := goWriter{home: spec.PkgPath()}
+	w.writeComposite(v)
+	return w.String()
+}
+
+var goStringerType = reflect.TypeFor[fmt.GoStringer]()
+
+// qualifiedIdent matches the package path qualifying a type argument in the name of
+// an instantiated generic type, such as "github.com/eriicafes/union." in
+// "TaggedUnion[github.com/eriicafes/union.Shape]".
+var qualifiedIdent = regexp.MustCompile(`(?:[\w.-]+/)*[\w-]+\.`)
+
+// goWriter writes values in Go syntax as seen from the package with the path home.
+type goWriter struct {
+	strings.Builder
+	home string
+}
+
+// writeValue writes v. Typed reports whether the context declares the type of v, like
+// a struct field or a slice element, so untyped constants and nil need no conversion.
+func (w *goWriter) writeValue(v reflect.Value, typed bool) {
+	t := v.Type()
+	// pointers to GoStringers are written as pointers to the value's GoString
+	pointsToGoStringer := t.Kind() == reflect.Pointer && t.Elem().Implements(goStringerType)
+	if t.Kind() != reflect.Interface && !pointsToGoStringer && t.Implements(goStringerType) && v.CanInterface() &&
+		!(t.Kind() == reflect.Pointer && v.IsNil()) {
+		w.WriteString(v.Interface().(fmt.GoStringer).GoString())
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			w.WriteString("nil")
+			return
+		}
+		w.writeValue(v.Elem(), false)
+	case reflect.Pointer:
+		if v.IsNil() {
+			w.writeNil(t, typed)
+			return
+		}
+		switch t.Elem().Kind() {
+		case reflect.Struct, reflect.Array, reflect.Slice, reflect.Map:
+			w.WriteByte('&')
+			w.writeValue(v.Elem(), false)
+		default:
+			w.WriteString("new(")
+			w.writeValue(v.Elem(), false)
+			w.WriteByte(')')
+		}
+	case reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			w.writeNil(t, typed)
+			return
+		}
+		w.writeComposite(v)
+	case reflect.Struct, reflect.Array:
+		w.writeComposite(v)
+	case reflect.Bool:
+		w.writeConstant(t, strconv.FormatBool(v.Bool()), typed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		w.writeConstant(t, strconv.FormatInt(v.Int(), 10), typed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		w.writeConstant(t, strconv.FormatUint(v.Uint(), 10), typed)
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		switch {
+		case math.IsNaN(f):
+			w.writeConstant(t, "math.NaN()", t.Name() == "float64")
+		case math.IsInf(f, 0):
+			w.writeConstant(t, fmt.Sprintf("math.Inf(%d)", int(math.Copysign(1, f))), t.Name() == "float64")
+		default:
+			w.writeConstant(t, strconv.FormatFloat(f, 'g', -1, t.Bits()), typed)
+		}
+	case reflect.Complex64, reflect.Complex128:
+		w.writeConstant(t, fmt.Sprint(v.Complex()), typed)
+	case reflect.String:
+		w.writeConstant(t, strconv.Quote(v.String()), typed)
+	default:
+		// channels, functions and unsafe pointers have no literals
+		if v.IsNil() {
+			w.writeNil(t, typed)
+			return
+		}
+		fmt.Fprintf(w, "%#v", v.Interface())
+	}
+}
+
+// writeComposite writes the struct, array, slice or map v as a composite literal.
+// Byte slices are written as conversions of a string literal.
+func (w *goWriter) writeComposite(v reflect.Value) {
+	t := v.Type()
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		w.WriteString(w.typeName(t) + "(" + strconv.Quote(string(v.Bytes())) + ")")
+		return
+	}
+
+	w.WriteString(w.typeName(t))
+	w.WriteByte('{')
+	switch t.Kind() {
+	case reflect.Struct:
+		n := 0
+		for i := range t.NumField() {
+			if !t.Field(i).IsExported() || v.Field(i).IsZero() {
+				continue
+			}
+			if n++; n > 1 {
+				w.WriteString(", ")
+			}
+			w.WriteString(t.Field(i).Name + ": ")
+			w.writeValue(v.Field(i), true)
+		}
+	case reflect.Array, reflect.Slice:
+		for i := range v.Len() {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			w.writeValue(v.Index(i), true)
+		}
+	case reflect.Map:
+		// entries sorted by their formatted keys, for deterministic output
+		entries := make([]string, 0, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			e := goWriter{home: w.home}
+			e.writeValue(iter.Key(), true)
+			e.WriteString(": ")
+			e.writeValue(iter.Value(), true)
+			entries = append(entries, e.String())
+		}
+		slices.Sort(entries)
+		w.WriteString(strings.Join(entries, ", "))
+	}
+	w.WriteByte('}')
+}
+
+// writeConstant writes the literal of a value of type t, converted to t unless the
+// context declares it or the literal's default type is t.
+func (w *goWriter) writeConstant(t reflect.Type, literal string, typed bool) {
+	if typed || t.PkgPath() == "" && (t.Name() == "bool" || t.Name() == "string" || t.Name() == "int") {
+		w.WriteString(literal)
+		return
+	}
+	w.WriteString(w.typeName(t) + "(" + literal + ")")
+}
+
+// writeNil writes the nil value of type t, converted to t unless the context declares it.
+func (w *goWriter) writeNil(t reflect.Type, typed bool) {
+	switch {
+	case typed:
+		w.WriteString("nil")
+	case t.Kind() == reflect.Pointer:
+		w.WriteString("(" + w.typeName(t) + ")(nil)")
+	default:
+		w.WriteString(w.typeName(t) + "(nil)")
+	}
+}
+
+// typeName returns the name of the type t in Go syntax, unqualified for types of the home package.
+func (w *goWriter) typeName(t reflect.Type) string {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name() // predeclared
+		}
+		name := qualifiedIdent.ReplaceAllStringFunc(t.Name(), func(qualifier string) string {
+			path := strings.TrimSuffix(qualifier, ".")
+			if path == w.home {
+				return ""
+			}
+			return path[strings.LastIndexByte(path, '/')+1:] + "."
+		})
+		if t.PkgPath() == w.home {
+			return name
+		}
+		// the package name, which may differ from the last element of its path
+		return strings.TrimSuffix(t.String(), t.Name()) + name
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return "*" + w.typeName(t.Elem())
+	case reflect.Slice:
+		return "[]" + w.typeName(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), w.typeName(t.Elem()))
+	case reflect.Map:
+		return "map[" + w.typeName(t.Key()) + "]" + w.typeName(t.Elem())
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return "any"
+		}
+	}
+	return t.String()
+}
@@ -0,0 +1,100 @@
+package union
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type GoSyntaxShape struct {
+	Duration *time.Duration     `variant:"duration"`
+	Labels   map[string][]int   `variant:"labels"`
+	Any      *[]any             `variant:"any"`
+	Level    *float32           `variant:"level"`
+	Raw      []byte             `variant:"raw"`
+	Nested   *TaggedUnion[Expr] `variant:"nested"`
+}
+
+func TestGoString(t *testing.T) {
+	two := 2.0
+	duration := 5 * time.Second
+	level := float32(math.Inf(-1))
+
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{
+			name:     "pointer variant",
+			value:    TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 5}}},
+			expected: "TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 5}}}",
+		},
+		{
+			name:     "non-pointer variant",
+			value:    Union[UnionNonPointerShape]{Value: UnionNonPointerShape{Rectangle: Rectangle{Width: 2, Height: 3}}},
+			expected: "Union[UnionNonPointerShape]{Value: UnionNonPointerShape{Rectangle: Rectangle{Width: 2, Height: 3}}}",
+		},
+		{
+			name:     "unset",
+			value:    ExternallyTagged[Shape]{},
+			expected: "ExternallyTagged[Shape]{}",
+		},
+		{
+			name:     "nested union",
+			value:    TaggedUnion[Expr]{Value: Expr{Neg: &TaggedUnion[Expr]{Value: Expr{Num: &two}}}},
+			expected: "TaggedUnion[Expr]{Value: Expr{Neg: &TaggedUnion[Expr]{Value: Expr{Num: new(float64(2))}}}}",
+		},
+		{
+			name:     "qualified type",
+			value:    TaggedUnion[GoSyntaxShape]{Value: GoSyntaxShape{Duration: &duration}},
+			expected: "TaggedUnion[GoSyntaxShape]{Value: GoSyntaxShape{Duration: new(time.Duration(5000000000))}}",
+		},
+		{
+			name:     "map",
+			value:    TaggedUnion[GoSyntaxShape]{Value: GoSyntaxShape{Labels: map[string][]int{"b": {1, 2}, "a": nil}}},
+			expected: `TaggedUnion[GoSyntaxShape]{Value: GoSyntaxShape{Labels: map[string][]int{"a": nil, "b": []int{1, 2}}}}`,
+		},
+		{
+			name:     "interface elements",
+			value:    TaggedUnion[GoSyntaxShape]{Value: GoSyntaxShape{Any: &[]any{1, 1.5, "a", nil, []string(nil)}}},
+			expected: `TaggedUnion[GoSyntaxShape]{Value: GoSyntaxShape{Any: &[]any{1, float64(1.5), "a", nil, []string(nil)}}}`,
+		},
+		{
+			name:     "special float",
+			value:    TaggedUnion[GoSyntaxShape]{Value: GoSyntaxShape{Level: &level}},
+			expected: "TaggedUnion[GoSyntaxShape]{Value: GoSyntaxShape{Level: new(float32(math.Inf(-1)))}}",
+		},
+		{
+			name:     "bytes",
+			value:    TaggedUnion[GoSyntaxShape]{Value: GoSyntaxShape{Raw: []byte(`{"a":1}`)}},
+			expected: `TaggedUnion[GoSyntaxShape]{Value: GoSyntaxShape{Raw: []uint8("{\"a\":1}")}}`,
+		},
+		{
+			name:     "nested in other values",
+			value:    []TaggedUnion[Shape]{{Value: Shape{Triangle: &Triangle{Base: 1}}}},
+			expected: "[]union.TaggedUnion[github.com/eriicafes/union.Shape]{TaggedUnion[Shape]{Value: Shape{Triangle: &Triangle{Base: 1}}}}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmt.Sprintf("%#v", tt.value); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("other package", func(t *testing.T) {
+		u := TaggedUnion[ForwardShape]{Value: ForwardShape{Unknown: &Raw{Variant: "hexagon", Value: json.RawMessage(`{}`)}}}
+		// json.RawMessage is an alias of jsontext.Value with GOEXPERIMENT=jsonv2
+		expected := fmt.Sprintf(`union.TaggedUnion[union.ForwardShape]{Value: union.ForwardShape{Unknown: &union.Raw{Variant: "hexagon", Value: %s("{}")}}}`,
+			reflect.TypeFor[json.RawMessage]())
+		if got := goString(reflect.ValueOf(u), reflect.TypeFor[time.Time]()); got != expected {
+			t.Errorf("expected %s, got %s", expected, got)
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package union
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+)
+
+// typenameField is the GraphQL meta field carrying the variant name.
+const typenameField = "__typename"
+
+// MarshalGQL implements the graphql.Marshaler interface used by gqlgen.
+// The union is written as a flat object with the variant name in "__typename"
+// followed by the variant's own fields, regardless of any JSONDiscriminator
+// method on the Spec type. Variant names should therefore match the GraphQL
+// type names of the schema's union or interface members.
+//
+// The graphql.Marshaler interface cannot report errors, so an invalid union
+// (zero or multiple variants set) or a non-object payload is written as null.
+func (u TaggedUnion[Spec]) MarshalGQL(w io.Writer) {
+	data, err := u.appendJSON(nil, encodingJSON, reflect.ValueOf(u.Value), typenameField, "")
+	if err != nil {
+		data = []byte("null")
+	}
+	w.Write(data)
+}
+
+// UnmarshalGQL implements the graphql.Unmarshaler interface used by gqlgen.
+// It accepts the decoded input object, reads the variant from "__typename"
+// and decodes the remaining fields into the matching variant, with the same
+// errors as UnmarshalJSON.
+func (u *TaggedUnion[Spec]) UnmarshalGQL(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return u.unmarshalJSON(encodingJSON, data, typenameField, "")
+}
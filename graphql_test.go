@@ -0,0 +1,85 @@
+package union
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMarshalGQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		shape    TaggedUnion[Shape]
+		expected string
+	}{
+		{
+			name:     "writes typename and variant fields",
+			shape:    TaggedUnion[Shape]{Value: Shape{Rectangle: &Rectangle{Width: 10, Height: 5}}},
+			expected: `{"__typename":"rectangle","width":10,"height":5}`,
+		},
+		{
+			name:     "writes null when no variant is set",
+			shape:    TaggedUnion[Shape]{},
+			expected: `null`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			tt.shape.MarshalGQL(&buf)
+			if buf.String() != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestUnmarshalGQL(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       any
+		expected    any
+		expectedErr error
+	}{
+		{
+			name:     "decodes variant from typename",
+			input:    map[string]any{"__typename": "circle", "radius": 5},
+			expected: Circle{Radius: 5},
+		},
+		{
+			name:     "ignores JSONDiscriminator",
+			input:    map[string]any{"__typename": "triangle", "base": 8, "height": 4},
+			expected: Triangle{Base: 8, Height: 4},
+		},
+		{
+			name:        "returns error when typename is missing",
+			input:       map[string]any{"radius": 5},
+			expectedErr: ErrMissingVariantField,
+		},
+		{
+			name:        "returns error for unknown typename",
+			input:       map[string]any{"__typename": "hexagon"},
+			expectedErr: ErrUnknownVariant,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var u TaggedUnion[FlatShape]
+			err := u.UnmarshalGQL(tt.input)
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertValueEquals(t, u.GetValue(), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package union
+
+import (
+	"encoding/json"
+	"hash/fnv"
+)
+
+// Hash returns a hash of the union's active variant name and payload, stable
+// across processes and releases, so unions can be used as keys of caches and sets.
+// The payload is encoded canonically as its JSON representation, so pointer and
+// non-pointer payloads holding the same value hash the same. Unset unions all have the same hash.
+//
+// Unions equal by Equal have the same hash unless a payload type's Equal method treats
+// values with different JSON representations as equal, such as time.Time in different locations.
+//
+// Returns an error if:
+//   - Multiple fields are set (invalid state)
+//   - The payload cannot be marshaled to JSON
+func Hash[U interface {
+	GetValue() any
+	Variant() (string, bool)
+	IsZero() bool
+}](u U) (uint64, error) {
+	h := fnv.New64a()
+	if u.IsZero() {
+		return h.Sum64(), nil
+	}
+	variant, ok := u.Variant()
+	if !ok {
+		return 0, ErrMultipleVariants
+	}
+	payload, err := json.Marshal(u.GetValue())
+	if err != nil {
+		return 0, err
+	}
+
+	h.Write([]byte(variant))
+	h.Write([]byte{0})
+	h.Write(payload)
+	return h.Sum64(), nil
+}
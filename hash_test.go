@@ -0,0 +1,47 @@
+package union
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHash(t *testing.T) {
+	hash := func(u TaggedUnion[Shape]) uint64 {
+		t.Helper()
+		h, err := Hash(u)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return h
+	}
+	circle := TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 5.0}}}
+
+	if hash(circle) != hash(TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 5.0}}}) {
+		t.Error("expected equal unions to have the same hash")
+	}
+	if h := hash(circle); h != 0x7ccd3b421137694e {
+		t.Errorf("expected stable hash 0x7ccd3b421137694e, got %#x", h)
+	}
+	if hash(circle) == hash(TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 4.0}}}) {
+		t.Error("expected different payloads to have different hashes")
+	}
+	if hash(TaggedUnion[Shape]{Value: Shape{Circle: &Circle{}}}) == hash(TaggedUnion[Shape]{Value: Shape{Triangle: &Triangle{}}}) {
+		t.Error("expected different variants to have different hashes")
+	}
+	if hash(TaggedUnion[Shape]{}) != hash(TaggedUnion[Shape]{}) {
+		t.Error("expected unset unions to have the same hash")
+	}
+
+	a, err := Hash(TaggedUnion[NonPointerShape]{Value: NonPointerShape{Circle: Circle{Radius: 5.0}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a != hash(circle) {
+		t.Error("expected pointer and non-pointer payloads to have the same hash")
+	}
+
+	_, err = Hash(TaggedUnion[Shape]{Value: Shape{Circle: &Circle{}, Rectangle: &Rectangle{}}})
+	if !errors.Is(err, ErrMultipleVariants) {
+		t.Errorf("expected error '%v', got '%v'", ErrMultipleVariants, err)
+	}
+}
@@ -0,0 +1,57 @@
+package union
+
+import "encoding/json"
+
+// StringOrNumber is an untagged union of a JSON string and a JSON number, as found
+// in fields like ports or versions that APIs accept in either form.
+// Numbers are kept as json.Number, so they round trip without losing precision.
+//
+//	var port union.StringOrNumber
+//	json.Unmarshal([]byte(`8080`), &port) // *port.Value.Number == "8080"
+type StringOrNumber = Union[StringOrNumberSpec]
+
+// StringOrNumberSpec is the spec of StringOrNumber.
+type StringOrNumberSpec struct {
+	String *string      `variant:"string"`
+	Number *json.Number `variant:"number"`
+}
+
+// StringOrStringSlice is an untagged union of a JSON string and an array of strings,
+// as found in fields like Docker's CMD or JSON-LD's @type that take one value or several.
+//
+//	var cmd union.StringOrStringSlice
+//	json.Unmarshal([]byte(`["echo","hi"]`), &cmd) // cmd.Value.Strings = []string{"echo", "hi"}
+type StringOrStringSlice = Union[StringOrStringSliceSpec]
+
+// StringOrStringSliceSpec is the spec of StringOrStringSlice.
+type StringOrStringSliceSpec struct {
+	String  *string  `variant:"string"`
+	Strings []string `variant:"strings"`
+}
+
+// BoolOrString is an untagged union of a JSON boolean and a JSON string, as found
+// in YAML-ish configs where a flag is either true/false or a mode name such as "auto".
+//
+//	var color union.BoolOrString
+//	json.Unmarshal([]byte(`"auto"`), &color) // *color.Value.String == "auto"
+type BoolOrString = Union[BoolOrStringSpec]
+
+// BoolOrStringSpec is the spec of BoolOrString.
+type BoolOrStringSpec struct {
+	Bool   *bool   `variant:"bool"`
+	String *string `variant:"string"`
+}
+
+// OneOrMany is an untagged union of a single JSON value of type T and an array of them,
+// as found in APIs that accept either one object or a list of objects.
+//
+//	var targets union.OneOrMany[Target]
+//	json.Unmarshal([]byte(`{"id":1}`), &targets)   // targets.Value.One is set
+//	json.Unmarshal([]byte(`[{"id":1}]`), &targets) // targets.Value.Many is set
+type OneOrMany[T any] = Union[OneOrManySpec[T]]
+
+// OneOrManySpec is the spec of OneOrMany.
+type OneOrManySpec[T any] struct {
+	One  *T  `variant:"one"`
+	Many []T `variant:"many"`
+}
@@ -0,0 +1,73 @@
+package union
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestHelpers(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		decode   func([]byte) (string, error)
+		expected string
+	}{
+		{name: "StringOrNumber string", input: `"8080"`, decode: decodeVariant[StringOrNumberSpec], expected: "string"},
+		{name: "StringOrNumber number", input: `8080`, decode: decodeVariant[StringOrNumberSpec], expected: "number"},
+		{name: "StringOrNumber keeps precision", input: `12345678901234567890.5`, decode: decodeVariant[StringOrNumberSpec], expected: "number"},
+		{name: "StringOrStringSlice string", input: `"echo"`, decode: decodeVariant[StringOrStringSliceSpec], expected: "string"},
+		{name: "StringOrStringSlice slice", input: `["echo","hi"]`, decode: decodeVariant[StringOrStringSliceSpec], expected: "strings"},
+		{name: "StringOrStringSlice empty slice", input: `[]`, decode: decodeVariant[StringOrStringSliceSpec], expected: "strings"},
+		{name: "BoolOrString bool", input: `false`, decode: decodeVariant[BoolOrStringSpec], expected: "bool"},
+		{name: "BoolOrString string", input: `"auto"`, decode: decodeVariant[BoolOrStringSpec], expected: "string"},
+		{name: "OneOrMany one", input: `{"radius":5}`, decode: decodeVariant[OneOrManySpec[Circle]], expected: "one"},
+		{name: "OneOrMany many", input: `[{"radius":5}]`, decode: decodeVariant[OneOrManySpec[Circle]], expected: "many"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.decode([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if data != tt.expected+" "+tt.input {
+				t.Errorf("expected %s %s, got %s", tt.expected, tt.input, data)
+			}
+		})
+	}
+
+	t.Run("rejects other JSON types", func(t *testing.T) {
+		var value StringOrNumber
+		if err := json.Unmarshal([]byte(`true`), &value); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("sets values by type", func(t *testing.T) {
+		var cmd StringOrStringSlice
+		if err := Set(&cmd, []string{"echo", "hi"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		data, err := json.Marshal(cmd)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(data) != `["echo","hi"]` {
+			t.Errorf(`expected ["echo","hi"], got %s`, data)
+		}
+	})
+}
+
+// decodeVariant unmarshals data into a Union of Spec and returns its variant
+// followed by the data it marshals back to.
+func decodeVariant[Spec any](data []byte) (string, error) {
+	var u Union[Spec]
+	if err := json.Unmarshal(data, &u); err != nil {
+		return "", err
+	}
+	out, err := json.Marshal(u)
+	if err != nil {
+		return "", err
+	}
+	return u.MustVariant() + " " + string(out), nil
+}
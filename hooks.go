@@ -0,0 +1,49 @@
+package union
+
+import "reflect"
+
+// beforeMarshaler is implemented by payload types that prepare themselves for marshaling,
+// for example by filling defaults or canonicalizing their fields.
+type beforeMarshaler interface {
+	BeforeUnionMarshal() error
+}
+
+// afterUnmarshaler is implemented by payload types that complete or check themselves
+// after being unmarshaled.
+type afterUnmarshaler interface {
+	AfterUnionUnmarshal() error
+}
+
+var beforeMarshalerType = reflect.TypeFor[beforeMarshaler]()
+
+// beforeMarshal calls the BeforeUnionMarshal method of a variant payload and returns
+// the value to marshal. Pointer payloads are prepared in place, while non-pointer
+// payloads with a pointer receiver method are prepared on a copy, so the union's
+// value is left unchanged.
+func beforeMarshal(value any) (any, error) {
+	if h, ok := value.(beforeMarshaler); ok {
+		if isNilPointer(value) {
+			return value, nil
+		}
+		return value, h.BeforeUnionMarshal()
+	}
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || v.Kind() == reflect.Pointer || !reflect.PointerTo(v.Type()).Implements(beforeMarshalerType) {
+		return value, nil
+	}
+	ptr := reflect.New(v.Type())
+	ptr.Elem().Set(v)
+	err := ptr.Interface().(beforeMarshaler).BeforeUnionMarshal()
+	return ptr.Elem().Interface(), err
+}
+
+// afterUnmarshal calls the AfterUnionUnmarshal method of the payload decoded into target,
+// a pointer to a new value of the payload type. Payloads decoded from null are skipped.
+func afterUnmarshal(target reflect.Value) error {
+	for v := target; v.Kind() == reflect.Pointer && !v.IsNil(); v = v.Elem() {
+		if h, ok := v.Interface().(afterUnmarshaler); ok {
+			return h.AfterUnionUnmarshal()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package union
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+type (
+	Defaulted struct {
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	}
+	Canonical struct {
+		Tags []string `json:"tags"`
+	}
+	Checked struct {
+		Count int `json:"count"`
+	}
+	HookShape struct {
+		Defaulted *Defaulted `variant:"defaulted"`
+		Canonical *Canonical `variant:"canonical"`
+		Checked   Checked    `variant:"checked"`
+	}
+)
+
+// TaggedHookShape is HookShape with CBOR tag numbers.
+type TaggedHookShape HookShape
+
+func (TaggedHookShape) CBORTags() map[string]uint64 {
+	return map[string]uint64{"defaulted": 1, "canonical": 2, "checked": 3}
+}
+
+var errNegativeCount = errors.New("negative count")
+
+func (d *Defaulted) AfterUnionUnmarshal() error {
+	if d.Color == "" {
+		d.Color = "black"
+	}
+	return nil
+}
+
+func (c *Canonical) BeforeUnionMarshal() error {
+	if c.Tags == nil {
+		c.Tags = []string{}
+	}
+	return nil
+}
+
+func (c *Checked) BeforeUnionMarshal() error {
+	if c.Count < 0 {
+		return errNegativeCount
+	}
+	c.Count *= 10
+	return nil
+}
+
+func (c Checked) AfterUnionUnmarshal() error {
+	if c.Count < 0 {
+		return errNegativeCount
+	}
+	return nil
+}
+
+func TestAfterUnionUnmarshal(t *testing.T) {
+	t.Run("fills defaults", func(t *testing.T) {
+		var u TaggedUnion[HookShape]
+		if err := json.Unmarshal([]byte(`{"type":"defaulted","value":{"name":"a"}}`), &u); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := u.Value.Defaulted.Color; got != "black" {
+			t.Errorf("expected black, got %s", got)
+		}
+	})
+
+	t.Run("rejects payloads", func(t *testing.T) {
+		var u TaggedUnion[HookShape]
+		err := json.Unmarshal([]byte(`{"type":"checked","value":{"count":-1}}`), &u)
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || !errors.Is(err, errNegativeCount) {
+			t.Errorf("expected *DecodeError wrapping errNegativeCount, got %v", err)
+		}
+	})
+
+	t.Run("externally tagged", func(t *testing.T) {
+		var u ExternallyTagged[HookShape]
+		if err := json.Unmarshal([]byte(`{"defaulted":{}}`), &u); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := u.Value.Defaulted.Color; got != "black" {
+			t.Errorf("expected black, got %s", got)
+		}
+	})
+
+	t.Run("decode map", func(t *testing.T) {
+		u, err := DecodeMap[HookShape](map[string]any{"type": "defaulted", "value": map[string]any{}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := u.Value.Defaulted.Color; got != "black" {
+			t.Errorf("expected black, got %s", got)
+		}
+	})
+
+	t.Run("null payload", func(t *testing.T) {
+		var u TaggedUnion[HookShape]
+		if err := json.Unmarshal([]byte(`{"type":"defaulted","value":null}`), &u); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if u.Value.Defaulted != nil {
+			t.Errorf("expected nil payload, got %v", u.Value.Defaulted)
+		}
+	})
+}
+
+func TestBeforeUnionMarshal(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    HookShape
+		expected string
+	}{
+		{"pointer payload", HookShape{Canonical: &Canonical{}}, `{"type":"canonical","value":{"tags":[]}}`},
+		{"non-pointer payload", HookShape{Checked: Checked{Count: 2}}, `{"type":"checked","value":{"count":20}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := TaggedUnion[HookShape]{Value: tt.value}
+			data, err := json.Marshal(u)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, data)
+			}
+			m, err := ToMap(u)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if data, _ := json.Marshal(m); string(data) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, data)
+			}
+		})
+	}
+
+	t.Run("non-pointer payload is prepared on a copy", func(t *testing.T) {
+		u := TaggedUnion[HookShape]{Value: HookShape{Checked: Checked{Count: 2}}}
+		if _, err := json.Marshal(u); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if u.Value.Checked.Count != 2 {
+			t.Errorf("expected 2, got %d", u.Value.Checked.Count)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		u := TaggedUnion[HookShape]{Value: HookShape{Checked: Checked{Count: -1}}}
+		if _, err := json.Marshal(u); !errors.Is(err, errNegativeCount) {
+			t.Errorf("expected errNegativeCount, got %v", err)
+		}
+	})
+}
+
+func TestHooksOtherFormats(t *testing.T) {
+	t.Run("BindQuery", func(t *testing.T) {
+		var u TaggedUnion[HookShape]
+		if err := BindQuery(httptest.NewRequest("GET", "/?type=defaulted&name=a", nil), "type", &u); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := u.Value.Defaulted.Color; got != "black" {
+			t.Errorf("expected black, got %s", got)
+		}
+
+		err := BindQuery(httptest.NewRequest("GET", "/?type=checked&count=-1", nil), "type", &u)
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || !errors.Is(err, errNegativeCount) {
+			t.Errorf("expected *DecodeError wrapping errNegativeCount, got %v", err)
+		}
+	})
+
+	t.Run("XML", func(t *testing.T) {
+		var u TaggedUnion[HookShape]
+		if err := xml.Unmarshal([]byte(`<defaulted><Name>a</Name></defaulted>`), &u); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := u.Value.Defaulted.Color; got != "black" {
+			t.Errorf("expected black, got %s", got)
+		}
+
+		err := xml.Unmarshal([]byte(`<checked><Count>-1</Count></checked>`), &u)
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || !errors.Is(err, errNegativeCount) {
+			t.Errorf("expected *DecodeError wrapping errNegativeCount, got %v", err)
+		}
+
+		data, err := xml.Marshal(TaggedUnion[HookShape]{Value: HookShape{Checked: Checked{Count: 2}}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected := `<checked><Count>20</Count></checked>`; string(data) != expected {
+			t.Errorf("expected %s, got %s", expected, data)
+		}
+	})
+
+	t.Run("CBOR tags", func(t *testing.T) {
+		data, err := TaggedUnion[TaggedHookShape]{Value: TaggedHookShape{Checked: Checked{Count: 2}}}.MarshalCBOR()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var u TaggedUnion[TaggedHookShape]
+		if err := u.UnmarshalCBOR(data); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := u.Value.Checked.Count; got != 20 {
+			t.Errorf("expected 20, got %d", got)
+		}
+
+		data, err = TaggedUnion[TaggedHookShape]{Value: TaggedHookShape{Defaulted: &Defaulted{Name: "a"}}}.MarshalCBOR()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := u.UnmarshalCBOR(data); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := u.Value.Defaulted.Color; got != "black" {
+			t.Errorf("expected black, got %s", got)
+		}
+
+		if _, err := (TaggedUnion[TaggedHookShape]{Value: TaggedHookShape{Checked: Checked{Count: -1}}}).MarshalCBOR(); !errors.Is(err, errNegativeCount) {
+			t.Errorf("expected errNegativeCount, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package union
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// implSet holds the implementations registered for an interface type with RegisterImpl.
+type implSet struct {
+	mu    sync.RWMutex
+	types map[string]reflect.Type // concrete type by variant name
+	names map[reflect.Type]string // variant name by concrete type
+}
+
+// impls maps interface types to their registered implSet.
+var impls sync.Map // map[reflect.Type]*implSet
+
+// RegisterImpl registers the concrete type of impl as an implementation of the interface
+// type I named variant. A spec field of type I then holds any registered implementation:
+// TaggedUnion and ExternallyTagged decode a variant named like a registered implementation
+// into a new value of its concrete type, and marshal the field with the name of the
+// concrete type it holds. This lets open-ended payloads, such as plugins, be added
+// without declaring a spec field per concrete type:
+//
+//	type Shape interface{ Area() float64 }
+//
+//	func init() {
+//		union.RegisterImpl[Shape](Circle{}, "circle")
+//		union.RegisterImpl[Shape](&Square{}, "square")
+//	}
+//
+//	type Drawing struct {
+//		Shape Shape `variant:"shape"`
+//		Text  *Text `variant:"text"`
+//	}
+//
+// Variants declared by spec fields take precedence over registered implementations.
+// Registering a variant name or concrete type again replaces its previous registration.
+// RegisterImpl panics if I is not an interface type or impl is nil, and is typically
+// called from an init function.
+func RegisterImpl[I any](impl I, variant string) {
+	it := reflect.TypeFor[I]()
+	if it.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("union: RegisterImpl: %v is not an interface type", it))
+	}
+	ct := reflect.TypeOf(impl)
+	if ct == nil {
+		panic(fmt.Sprintf("union: RegisterImpl: nil implementation of %v", it))
+	}
+
+	s, _ := impls.LoadOrStore(it, &implSet{types: map[string]reflect.Type{}, names: map[reflect.Type]string{}})
+	set := s.(*implSet)
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	if old, ok := set.types[variant]; ok {
+		delete(set.names, old)
+	}
+	if old, ok := set.names[ct]; ok {
+		delete(set.types, old)
+	}
+	set.types[variant] = ct
+	set.names[ct] = variant
+}
+
+// implsOf returns the implementations registered for the interface type t, or nil.
+func implsOf(t reflect.Type) *implSet {
+	if t.Kind() != reflect.Interface {
+		return nil
+	}
+	if s, ok := impls.Load(t); ok {
+		return s.(*implSet)
+	}
+	return nil
+}
+
+// implType returns the concrete type registered for the interface type t named variant.
+func implType(t reflect.Type, variant string) (reflect.Type, bool) {
+	set := implsOf(t)
+	if set == nil {
+		return nil, false
+	}
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	ct, ok := set.types[variant]
+	return ct, ok
+}
+
+// implName returns the variant name registered for the concrete type ct as an
+// implementation of the interface type t.
+func implName(t, ct reflect.Type) (string, bool) {
+	set := implsOf(t)
+	if set == nil {
+		return "", false
+	}
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	name, ok := set.names[ct]
+	return name, ok
+}
+
+// variantOf returns the variant name of the field f holding the value fv. A raw field is
+// named after its captured variant, an interface field holding a registered implementation
+// after it, and other fields after themselves.
+func (f *fieldPlan) variantOf(fv reflect.Value) string {
+	if f.raw {
+		for fv.Kind() == reflect.Pointer && !fv.IsNil() {
+			fv = fv.Elem()
+		}
+		if r, ok := fv.Interface().(Raw); ok {
+			return r.Variant
+		}
+	}
+	if f.typ.Kind() == reflect.Interface && !fv.IsNil() {
+		if name, ok := implName(f.typ, fv.Elem().Type()); ok {
+			return name
+		}
+	}
+	return f.variant
+}
+
+// hasImpl reports whether an interface field of the spec has an implementation named variant.
+func (p *specPlan) hasImpl(variant string) bool {
+	for i := range p.fields {
+		if _, ok := implType(p.fields[i].typ, variant); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// setImpl decodes data into the implementation named variant of an interface field of the
+// spec value v, reporting false if no interface field has one. A nil data decodes into
+// the zero implementation.
+func (p *specPlan) setImpl(lib JSONLibrary, v reflect.Value, variant string, data json.RawMessage, opts decodeOptions) (bool, error) {
+	for i := range p.fields {
+		f := &p.fields[i]
+		ct, ok := implType(f.typ, variant)
+		if !ok {
+			continue
+		}
+		p.noteDeprecated(nil, variant)
+		target := zeroImpl(ct)
+		if data != nil {
+			decoded, err := decodeType(lib, ct, data, opts)
+			if err != nil {
+				return true, &DecodeError{Spec: p.typ, Variant: variant, Field: f.name, Err: err}
+			}
+			target = decoded.Elem()
+		}
+		v.FieldByIndex(f.index).Set(target)
+		return true, nil
+	}
+	return false, nil
+}
+
+// zeroImpl returns the zero value of the concrete type ct, allocated for pointer types.
+func zeroImpl(ct reflect.Type) reflect.Value {
+	if ct.Kind() == reflect.Pointer {
+		return reflect.New(ct.Elem())
+	}
+	return reflect.Zero(ct)
+}
@@ -0,0 +1,129 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type Plugin interface{ Name() string }
+
+type EchoPlugin struct {
+	Text string `json:"text"`
+}
+
+func (EchoPlugin) Name() string { return "echo" }
+
+type SleepPlugin struct {
+	Ms int `json:"ms"`
+}
+
+func (*SleepPlugin) Name() string { return "sleep" }
+
+type Task struct {
+	Plugin Plugin  `variant:"plugin"`
+	Stop   *Circle `variant:"stop"`
+}
+
+func init() {
+	RegisterImpl[Plugin](EchoPlugin{}, "echo")
+	RegisterImpl[Plugin](&SleepPlugin{}, "sleep")
+}
+
+func TestRegisterImpl(t *testing.T) {
+	t.Run("round trips registered implementations", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			jsonData string
+			expected Plugin
+		}{
+			{"value implementation", `{"type":"echo","value":{"text":"hi"}}`, EchoPlugin{Text: "hi"}},
+			{"pointer implementation", `{"type":"sleep","value":{"ms":10}}`, &SleepPlugin{Ms: 10}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var task TaggedUnion[Task]
+				if err := json.Unmarshal([]byte(tt.jsonData), &task); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !reflect.DeepEqual(task.Value.Plugin, tt.expected) {
+					t.Errorf("expected %#v, got %#v", tt.expected, task.Value.Plugin)
+				}
+				if variant, ok := task.Variant(); !ok || variant != tt.expected.Name() {
+					t.Errorf("expected variant %q, got %q (ok=%v)", tt.expected.Name(), variant, ok)
+				}
+
+				data, err := json.Marshal(task)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if string(data) != tt.jsonData {
+					t.Errorf("expected %s, got %s", tt.jsonData, data)
+				}
+			})
+		}
+	})
+
+	t.Run("externally tagged", func(t *testing.T) {
+		var task ExternallyTagged[Task]
+		if err := json.Unmarshal([]byte(`{"echo":{"text":"hi"}}`), &task); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if task.Value.Plugin != (EchoPlugin{Text: "hi"}) {
+			t.Errorf("expected echo plugin, got %#v", task.Value.Plugin)
+		}
+		if variant := task.MustVariant(); variant != "echo" {
+			t.Errorf("expected variant echo, got %q", variant)
+		}
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		var task Lazy[Task]
+		if err := json.Unmarshal([]byte(`{"type":"sleep","value":{"ms":10}}`), &task); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if variant, _ := task.Variant(); variant != "sleep" {
+			t.Errorf("expected variant sleep, got %q", variant)
+		}
+		if got, ok := As[*SleepPlugin](task); !ok || got.Ms != 10 {
+			t.Errorf("expected sleep plugin, got %#v", task.GetValue())
+		}
+	})
+
+	t.Run("declared variants take precedence", func(t *testing.T) {
+		var task TaggedUnion[Task]
+		if err := json.Unmarshal([]byte(`{"type":"stop","value":{"radius":1}}`), &task); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if task.Value.Stop == nil || task.Value.Plugin != nil {
+			t.Errorf("expected stop variant, got %v", task)
+		}
+	})
+
+	t.Run("unregistered variants are unknown", func(t *testing.T) {
+		var task TaggedUnion[Task]
+		err := json.Unmarshal([]byte(`{"type":"shout","value":{}}`), &task)
+		if !errors.Is(err, ErrUnknownVariant) {
+			t.Errorf("expected ErrUnknownVariant, got %v", err)
+		}
+	})
+
+	t.Run("decode errors name the implementation", func(t *testing.T) {
+		var task TaggedUnion[Task]
+		err := json.Unmarshal([]byte(`{"type":"sleep","value":{"ms":"long"}}`), &task)
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Variant != "sleep" || decodeErr.Field != "Plugin" {
+			t.Errorf("expected DecodeError for sleep, got %v", err)
+		}
+	})
+
+	t.Run("panics on non-interface types", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic")
+			}
+		}()
+		RegisterImpl[EchoPlugin](EchoPlugin{}, "echo")
+	})
+}
@@ -0,0 +1,38 @@
+package union
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// jsonValue returns the JSON representation of u decoded into generic values
+// (map[string]any, []any, string, json.Number, bool and nil).
+// It is the common ground for encoding unions in formats other than JSON.
+func jsonValue(u json.Marshaler) (any, error) {
+	data, err := u.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSONValue(data)
+}
+
+// decodeJSONValue decodes JSON data into generic values, keeping numbers as json.Number.
+func decodeJSONValue(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// fromJSONValue unmarshals generic values decoded from another format into u
+// through their JSON representation.
+func fromJSONValue(u json.Unmarshaler, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return u.UnmarshalJSON(data)
+}
@@ -0,0 +1,140 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// Lazy is a TaggedUnion whose payload is decoded on first access.
+//
+// UnmarshalJSON only reads the variant name and keeps the JSON data, which MarshalJSON
+// writes back verbatim until the union is decoded, so routers that inspect the variant
+// and forward most messages unchanged don't pay for decoding their payloads.
+// GetValue, and so As and Is, decode the payload once and cache the result, which
+// is shared by copies of the Lazy value.
+type Lazy[Spec any] struct {
+	variant string          // variant name read by UnmarshalJSON
+	data    json.RawMessage // JSON data read by UnmarshalJSON
+	state   *lazyState[Spec]
+}
+
+// lazyState holds the decoded union of a Lazy value.
+type lazyState[Spec any] struct {
+	once  sync.Once
+	union TaggedUnion[Spec]
+	err   error
+}
+
+// NewLazy returns a Lazy holding the already decoded union u.
+func NewLazy[Spec any](u TaggedUnion[Spec]) Lazy[Spec] {
+	state := &lazyState[Spec]{union: u}
+	state.once.Do(func() {})
+	variant, _ := u.Variant()
+	return Lazy[Spec]{variant: variant, state: state}
+}
+
+// Variant returns the name of the active variant without decoding the payload,
+// as TaggedUnion.Variant reports it once decoded. It reports false if the union is empty.
+func (l Lazy[Spec]) Variant() (string, bool) {
+	return l.variant, l.variant != ""
+}
+
+// Decode returns the union with its payload decoded, decoding it on the first call.
+func (l Lazy[Spec]) Decode() (TaggedUnion[Spec], error) {
+	if l.state == nil {
+		return TaggedUnion[Spec]{}, nil
+	}
+	l.state.once.Do(func() {
+		l.state.err = l.state.union.UnmarshalJSON(l.data)
+	})
+	return l.state.union, l.state.err
+}
+
+// GetValue returns the value of the active variant in the union, decoding the payload
+// on the first call. It returns nil if the payload cannot be decoded, see Decode for the error.
+func (l Lazy[Spec]) GetValue() any {
+	u, err := l.Decode()
+	if err != nil {
+		return nil
+	}
+	return u.GetValue()
+}
+
+// IsZero reports whether the union is empty.
+func (l Lazy[Spec]) IsZero() bool {
+	return l.variant == ""
+}
+
+// MarshalJSON implements the json.Marshaler interface. It writes the JSON data
+// read by UnmarshalJSON verbatim, or marshals the union held by a Lazy created with NewLazy.
+func (l Lazy[Spec]) MarshalJSON() ([]byte, error) {
+	if l.data != nil {
+		return l.data, nil
+	}
+	u, err := l.Decode()
+	if err != nil {
+		return nil, err
+	}
+	return u.MarshalJSON()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It reads the variant
+// name from the JSON data with the rules of TaggedUnion.UnmarshalJSON, and keeps
+// a copy of the data for decoding the payload on first access.
+//
+// Returns an error if:
+//   - The JSON data is malformed or not an object, a unit variant or null
+//   - The variant field is missing
+//   - The variant doesn't match any known variant, implementation or upcaster and the spec
+//     has no Raw field (*UnknownVariantError)
+func (l *Lazy[Spec]) UnmarshalJSON(data []byte) error {
+	*l = Lazy[Spec]{}
+
+	var u TaggedUnion[Spec]
+	p := planOf(reflect.TypeFor[Spec]())
+	if !p.isStruct {
+		return ErrSpecNotStruct
+	}
+	variant, ok, err := u.peekVariant(data)
+	if err != nil {
+		return err
+	}
+	if ok {
+		if variant, err = p.lazyVariant(variant); err != nil {
+			return err
+		}
+	}
+
+	l.variant = variant
+	l.data = append(json.RawMessage(nil), data...)
+	l.state = &lazyState[Spec]{}
+	return nil
+}
+
+// lazyVariant returns the name TaggedUnion.Variant reports for the variant once decoded:
+// the declared variant it names or is upcast to, or the variant of an implementation
+// registered for an interface field or captured by a Raw field.
+func (p *specPlan) lazyVariant(variant string) (string, error) {
+	f, err := p.lookup(variant)
+	if err == nil {
+		return f.variant, nil
+	}
+	if !errors.Is(err, ErrUnknownVariant) {
+		return "", err
+	}
+	if p.hasImpl(variant) {
+		return variant, nil
+	}
+	if f, ok, err := p.upcastTarget(variant); ok {
+		if err != nil {
+			return "", err
+		}
+		return f.variant, nil
+	}
+	if p.raw >= 0 {
+		return variant, nil
+	}
+	return "", err
+}
@@ -0,0 +1,112 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	t.Run("reads variant without decoding", func(t *testing.T) {
+		data := `{ "type": "circle", "value": {"radius": "not a number"} }`
+		var shape Lazy[Shape]
+		if err := json.Unmarshal([]byte(data), &shape); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if variant, ok := shape.Variant(); !ok || variant != "circle" {
+			t.Errorf("expected variant circle, got %q (ok=%v)", variant, ok)
+		}
+
+		out, err := json.Marshal(shape)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(out) != `{"type":"circle","value":{"radius":"not a number"}}` {
+			t.Errorf("expected data to be forwarded, got %s", out)
+		}
+
+		var decodeErr *DecodeError
+		if _, err := shape.Decode(); !errors.As(err, &decodeErr) {
+			t.Errorf("expected *DecodeError, got '%v'", err)
+		}
+		if shape.GetValue() != nil {
+			t.Errorf("expected nil value, got %v", shape.GetValue())
+		}
+	})
+
+	t.Run("decodes on access", func(t *testing.T) {
+		var shape Lazy[Shape]
+		if err := shape.UnmarshalJSON([]byte(`{"value":{"radius":5},"type":"circle"}`)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		circle, ok := As[Circle](shape)
+		if !ok || circle.Radius != 5.0 {
+			t.Errorf("expected circle with radius 5, got %+v (ok=%v)", circle, ok)
+		}
+		copied := shape
+		if copied.GetValue() != shape.GetValue() {
+			t.Error("expected copies to share the decoded payload")
+		}
+	})
+
+	t.Run("new lazy marshals union", func(t *testing.T) {
+		shape := NewLazy(TaggedUnion[Shape]{Value: Shape{Rectangle: &Rectangle{Width: 1, Height: 2}}})
+		if variant, _ := shape.Variant(); variant != "rectangle" {
+			t.Errorf("expected variant rectangle, got %q", variant)
+		}
+		out, err := shape.MarshalJSON()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(out) != `{"type":"rectangle","value":{"width":1,"height":2}}` {
+			t.Errorf("unexpected output %s", out)
+		}
+	})
+
+	t.Run("unit variant", func(t *testing.T) {
+		var shape Lazy[UnitShape]
+		if err := shape.UnmarshalJSON([]byte(`"idle"`)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if variant, _ := shape.Variant(); variant != "idle" {
+			t.Errorf("expected variant idle, got %q", variant)
+		}
+	})
+
+	t.Run("raw variant", func(t *testing.T) {
+		data := []byte(`{"type":"hexagon","value":{"sides":6}}`)
+		var shape Lazy[ForwardShape]
+		if err := shape.UnmarshalJSON(data); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var decoded TaggedUnion[ForwardShape]
+		if err := decoded.UnmarshalJSON(data); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected, _ := decoded.Variant()
+		if variant, _ := shape.Variant(); variant != expected {
+			t.Errorf("expected variant %q, got %q", expected, variant)
+		}
+	})
+
+	tests := []struct {
+		name        string
+		jsonData    string
+		expectedErr string
+	}{
+		{name: "unknown variant", jsonData: `{"type":"hexagon","value":{}}`, expectedErr: "unknown variant: hexagon"},
+		{name: "missing variant", jsonData: `{"value":{}}`, expectedErr: "missing variant field: type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var shape Lazy[Shape]
+			err := shape.UnmarshalJSON([]byte(tt.jsonData))
+			if err == nil || err.Error() != tt.expectedErr {
+				t.Errorf("expected error '%s', got '%v'", tt.expectedErr, err)
+			}
+			if !shape.IsZero() {
+				t.Error("expected empty union")
+			}
+		})
+	}
+}
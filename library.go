@@ -0,0 +1,31 @@
+package union
+
+import "encoding/json"
+
+// JSONLibrary is a JSON implementation compatible with encoding/json, such as
+// sonic.ConfigStd of github.com/bytedance/sonic or jsoniter.ConfigCompatibleWithStandardLibrary
+// of github.com/json-iterator/go. Package-level functions, such as those of
+// github.com/goccy/go-json, are adapted with JSONFuncs.
+//
+// MarshalWith and UnmarshalWith hand the variant payloads, which make up most of
+// the work, to the library set as Options.Library. Payloads of strict specs and untagged unions are checked
+// for unknown fields, so they are still decoded with encoding/json, as are nested unions.
+type JSONLibrary interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONFuncs adapts a pair of Marshal and Unmarshal functions to a JSONLibrary:
+//
+//	lib := union.JSONFuncs{MarshalFunc: gojson.Marshal, UnmarshalFunc: gojson.Unmarshal}
+type JSONFuncs struct {
+	MarshalFunc   func(v any) ([]byte, error)
+	UnmarshalFunc func(data []byte, v any) error
+}
+
+func (f JSONFuncs) Marshal(v any) ([]byte, error) { return f.MarshalFunc(v) }
+
+func (f JSONFuncs) Unmarshal(data []byte, v any) error { return f.UnmarshalFunc(data, v) }
+
+// encodingJSON is the JSONLibrary used by the MarshalJSON and UnmarshalJSON methods.
+var encodingJSON JSONLibrary = JSONFuncs{MarshalFunc: json.Marshal, UnmarshalFunc: json.Unmarshal}
@@ -0,0 +1,71 @@
+package union
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+// countingLibrary is a JSONLibrary backed by encoding/json that records which types it handled.
+type countingLibrary struct {
+	marshaled   []string
+	unmarshaled []string
+}
+
+func (l *countingLibrary) Marshal(v any) ([]byte, error) {
+	l.marshaled = append(l.marshaled, typeName(v))
+	return json.Marshal(v)
+}
+
+func (l *countingLibrary) Unmarshal(data []byte, v any) error {
+	l.unmarshaled = append(l.unmarshaled, typeName(v))
+	return json.Unmarshal(data, v)
+}
+
+func typeName(v any) string { return fmt.Sprintf("%T", v) }
+
+func TestMarshalWithLibrary(t *testing.T) {
+	lib := &countingLibrary{}
+	shape := TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 5.0}}}
+
+	data, err := MarshalWith(shape, Options{Library: lib})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected, _ := shape.MarshalJSON()
+	if string(data) != string(expected) {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+	if len(lib.marshaled) != 1 || lib.marshaled[0] != "*union.Circle" {
+		t.Errorf("expected the payload to be marshaled by the library, got %v", lib.marshaled)
+	}
+
+	external := ExternallyTagged[Shape]{Value: Shape{Rectangle: &Rectangle{Width: 2, Height: 3}}}
+	data, err = MarshalWith(&external, Options{Library: lib})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `{"rectangle":{"width":2,"height":3}}` {
+		t.Errorf("unexpected output %s", data)
+	}
+}
+
+func TestUnmarshalWithLibrary(t *testing.T) {
+	lib := &countingLibrary{}
+	var shape TaggedUnion[Shape]
+	if err := UnmarshalWith([]byte(`{"type":"circle","value":{"radius":5}}`), &shape, Options{Library: lib}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, shape.GetValue(), Circle{Radius: 5.0})
+	// the envelope, then the payload
+	if len(lib.unmarshaled) != 2 || lib.unmarshaled[1] != "**union.Circle" {
+		t.Errorf("expected the envelope and payload to be unmarshaled by the library, got %v", lib.unmarshaled)
+	}
+
+	lib = &countingLibrary{}
+	var strict TaggedUnion[StrictShape]
+	err := UnmarshalWith([]byte(`{"type":"circle","value":{"radius":5,"debug":true}}`), &strict, Options{Library: lib})
+	if err == nil {
+		t.Error("expected strict payload check to reject unknown fields")
+	}
+}
@@ -0,0 +1,184 @@
+package union
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync/atomic"
+)
+
+// DecodeLimits bounds the JSON data the union types unmarshal, so unions can decode
+// untrusted input without a separate pre-filter. Zero fields are not limited.
+type DecodeLimits struct {
+	MaxBytes      int // maximum size in bytes of the union's JSON data
+	MaxDepth      int // maximum nesting depth of objects and arrays, the union's own object being 1
+	MaxValueBytes int // maximum size in bytes of the variant's payload, such as the value field
+}
+
+// defaultLimits holds the limits set with SetDefaultDecodeLimits.
+var defaultLimits atomic.Pointer[DecodeLimits]
+
+// SetDefaultDecodeLimits sets the limits applied when unmarshaling unions of Spec types
+// without a JSONDecodeLimits() union.DecodeLimits method, which selects the limits of
+// its Spec type instead:
+//
+//	func (Shape) JSONDecodeLimits() union.DecodeLimits {
+//		return union.DecodeLimits{MaxBytes: 1 << 20, MaxDepth: 32}
+//	}
+//
+// The limits apply to the UnmarshalJSON methods of TaggedUnion, ExternallyTagged and
+// Union, including unions nested in payloads, before any data is decoded, and to DecodeFrom,
+// Decoder, DecodeAll and DecodeMap. Data exceeding them is rejected with an error wrapping
+// ErrLimitExceeded. No limits are set by default.
+func SetDefaultDecodeLimits(limits DecodeLimits) {
+	defaultLimits.Store(&limits)
+}
+
+// decodeLimits returns the limits applied when unmarshaling unions of the spec with lib,
+// which carries the limits set by the Options of a call.
+func decodeLimits(lib JSONLibrary, spec any) DecodeLimits {
+	var declared *DecodeLimits
+	if s, ok := spec.(interface{ JSONDecodeLimits() DecodeLimits }); ok {
+		limits := s.JSONDecodeLimits()
+		declared = &limits
+	}
+	return applyLimits(lib, declared)
+}
+
+// applyLimits returns the declared limits of a spec, or the default limits when nil,
+// overridden by the limits set by the Options of a call carried by lib.
+func applyLimits(lib JSONLibrary, declared *DecodeLimits) DecodeLimits {
+	var limits DecodeLimits
+	if declared != nil {
+		limits = *declared
+	} else if l := defaultLimits.Load(); l != nil {
+		limits = *l
+	}
+
+	call := callOptions(lib)
+	if call.MaxBytes > 0 {
+		limits.MaxBytes = call.MaxBytes
+	}
+	if call.MaxDepth > 0 {
+		limits.MaxDepth = call.MaxDepth
+	}
+	if call.MaxValueBytes > 0 {
+		limits.MaxValueBytes = call.MaxValueBytes
+	}
+	return limits
+}
+
+// limits returns the limits applied when unmarshaling the union, read from the plan of
+// its spec so checking them does not box the spec value.
+func (u *TaggedUnion[Spec]) limits() DecodeLimits {
+	return applyLimits(encodingJSON, planOf(reflect.TypeFor[Spec]()).json.limits)
+}
+
+// limits returns the limits applied when unmarshaling the union, read from the plan of
+// its spec so checking them does not box the spec value.
+func (u *ExternallyTagged[Spec]) limits() DecodeLimits {
+	return applyLimits(encodingJSON, planOf(reflect.TypeFor[Spec]()).json.limits)
+}
+
+// limits returns the limits applied when unmarshaling the union, read from the plan of
+// its spec so checking them does not box the spec value.
+func (u *Union[Spec]) limits() DecodeLimits {
+	return applyLimits(encodingJSON, planOf(reflect.TypeFor[Spec]()).json.limits)
+}
+
+// check reports whether the JSON data of a union exceeds the size or depth limits.
+func (l DecodeLimits) check(data []byte) error {
+	if l.MaxBytes > 0 && len(data) > l.MaxBytes {
+		return fmt.Errorf("%w: %d bytes of data, at most %d allowed", ErrLimitExceeded, len(data), l.MaxBytes)
+	}
+	if l.MaxDepth > 0 && exceedsDepth(data, l.MaxDepth) {
+		return fmt.Errorf("%w: data nested deeper than %d levels", ErrLimitExceeded, l.MaxDepth)
+	}
+	return nil
+}
+
+// checkValue reports whether the JSON payload of a variant exceeds the value size limit.
+func (l DecodeLimits) checkValue(data []byte) error {
+	if l.MaxValueBytes > 0 && len(data) > l.MaxValueBytes {
+		return fmt.Errorf("%w: %d bytes of value, at most %d allowed", ErrLimitExceeded, len(data), l.MaxValueBytes)
+	}
+	return nil
+}
+
+// exceedsDepth reports whether objects and arrays in the JSON data nest deeper than max.
+// Brackets inside strings are skipped, malformed data is left for the decoder to report.
+func exceedsDepth(data []byte, max int) bool {
+	depth := 0
+	inString, escaped := false, false
+	for _, c := range data {
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			switch c {
+			case '\\':
+				escaped = true
+			case '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			if depth++; depth > max {
+				return true
+			}
+		case c == '}' || c == ']':
+			depth--
+		}
+	}
+	return false
+}
+
+// maxNestingDepth bounds the nesting of the MessagePack and CBOR data unions decode,
+// like the nesting limit of encoding/json.
+const maxNestingDepth = 10000
+
+// limitSlack is the number of bytes a limitedReader reads past MaxBytes, for the
+// whitespace and separators ahead of a value and the byte ending a top-level number.
+const limitSlack = 64
+
+// limitedReader is the reader of a json.Decoder that stops reading a value once it
+// is longer than MaxBytes, so a stream doesn't buffer an oversized value before its
+// limits are checked. The decoded value itself is checked exactly after it is read.
+type limitedReader struct {
+	r     io.Reader
+	read  int64 // bytes read from r
+	limit int64 // position in r at which reading fails, negative for none
+	max   int   // MaxBytes of the value being read
+}
+
+// newLimitedReader returns a limitedReader reading from r without a limit.
+func newLimitedReader(r io.Reader) *limitedReader {
+	return &limitedReader{r: r, limit: -1}
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.limit >= 0 {
+		if l.read >= l.limit {
+			return 0, fmt.Errorf("%w: more than %d bytes of data, at most %d allowed", ErrLimitExceeded, l.max, l.max)
+		}
+		if rest := l.limit - l.read; int64(len(p)) > rest {
+			p = p[:rest]
+		}
+	}
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	return n, err
+}
+
+// start limits the next value dec reads from l to maxBytes, or lifts the limit if maxBytes is 0.
+// The offsets of dec and l must count from the same position of the stream.
+func (l *limitedReader) start(dec *json.Decoder, maxBytes int) {
+	if maxBytes <= 0 {
+		l.limit = -1
+		return
+	}
+	l.limit = dec.InputOffset() + int64(maxBytes) + limitSlack
+	l.max = maxBytes
+}
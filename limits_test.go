@@ -0,0 +1,180 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+type LimitedShape struct {
+	Circle *Circle          `variant:"circle"`
+	Tags   *[]any           `variant:"tags"`
+	Raw    *json.RawMessage `variant:"raw"`
+}
+
+func (LimitedShape) JSONDecodeLimits() DecodeLimits {
+	return DecodeLimits{MaxBytes: 128, MaxDepth: 3, MaxValueBytes: 64}
+}
+
+func TestDecodeLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		jsonData string
+		err      error
+	}{
+		{"within limits", `{"type":"circle","value":{"radius":1}}`, nil},
+		{"brackets in strings", `{"type":"tags","value":["[[[[","{{{{"]}`, nil},
+		{"too many bytes", `{"type":"raw","value":"` + strings.Repeat("a", 128) + `"}`, ErrLimitExceeded},
+		{"too deep", `{"type":"tags","value":[[[1]]]}`, ErrLimitExceeded},
+		{"value too large", `{"type":"raw","value":"` + strings.Repeat("a", 64) + `"}`, ErrLimitExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var u TaggedUnion[LimitedShape]
+			if err := json.Unmarshal([]byte(tt.jsonData), &u); !errors.Is(err, tt.err) || (tt.err == nil) != (err == nil) {
+				t.Errorf("expected error %v, got %v", tt.err, err)
+			}
+		})
+	}
+
+	t.Run("externally tagged", func(t *testing.T) {
+		var u ExternallyTagged[LimitedShape]
+		err := json.Unmarshal([]byte(`{"tags":[[[1]]]}`), &u)
+		if !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("expected ErrLimitExceeded, got %v", err)
+		}
+	})
+
+	t.Run("union", func(t *testing.T) {
+		var u Union[LimitedShape]
+		err := json.Unmarshal([]byte(`"`+strings.Repeat("a", 64)+`"`), &u)
+		if !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("expected ErrLimitExceeded, got %v", err)
+		}
+	})
+
+	t.Run("default limits", func(t *testing.T) {
+		SetDefaultDecodeLimits(DecodeLimits{MaxDepth: 2})
+		defer SetDefaultDecodeLimits(DecodeLimits{})
+
+		var u TaggedUnion[Shape]
+		err := json.Unmarshal([]byte(`{"type":"circle","value":{"radius":1}}`), &u)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err = json.Unmarshal([]byte(`{"type":"circle","value":{"radius":[1]}}`), &u)
+		if !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("expected ErrLimitExceeded, got %v", err)
+		}
+	})
+}
+
+type StreamLimitedShape struct {
+	Circle *Circle `variant:"circle"`
+	Tags   *[]any  `variant:"tags"`
+}
+
+func (StreamLimitedShape) JSONDecodeLimits() DecodeLimits {
+	return DecodeLimits{MaxBytes: 40, MaxDepth: 3}
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+func TestStreamDecodeLimits(t *testing.T) {
+	within := `{"type":"circle","value":{"radius":1}}`
+	deep := `{"type":"tags","value":` + strings.Repeat("[", 7) + strings.Repeat("]", 7) + `}`
+	large := `{"type":"tags","value":["` + strings.Repeat("a", 1<<20) + `"]}`
+
+	decoders := []struct {
+		name   string
+		decode func(r io.Reader) error
+	}{
+		{"DecodeFrom", func(r io.Reader) error {
+			var u TaggedUnion[StreamLimitedShape]
+			return u.DecodeFrom(json.NewDecoder(r))
+		}},
+		{"Decoder", func(r io.Reader) error {
+			var u TaggedUnion[StreamLimitedShape]
+			return NewDecoder(r).Decode(&u)
+		}},
+		{"DecodeAll", func(r io.Reader) error {
+			for _, err := range DecodeAll[StreamLimitedShape](r) {
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+		{"DecodeAll array", func(r io.Reader) error {
+			for _, err := range DecodeAll[StreamLimitedShape](io.MultiReader(strings.NewReader("["), r, strings.NewReader("]"))) {
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+		{"DecodeMap", func(r io.Reader) error {
+			var m map[string]any
+			if err := json.NewDecoder(r).Decode(&m); err != nil {
+				return err
+			}
+			_, err := DecodeMap[StreamLimitedShape](m)
+			return err
+		}},
+	}
+
+	for _, d := range decoders {
+		t.Run(d.name, func(t *testing.T) {
+			if err := d.decode(strings.NewReader(within)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := d.decode(strings.NewReader(deep)); !errors.Is(err, ErrLimitExceeded) {
+				t.Errorf("expected error '%s', got '%v'", ErrLimitExceeded, err)
+			}
+			if err := d.decode(strings.NewReader(large)); !errors.Is(err, ErrLimitExceeded) {
+				t.Errorf("expected error '%s', got '%v'", ErrLimitExceeded, err)
+			}
+		})
+	}
+
+	t.Run("stops reading", func(t *testing.T) {
+		r := &countingReader{r: strings.NewReader(large)}
+		var u TaggedUnion[StreamLimitedShape]
+		if err := NewDecoder(r).Decode(&u); !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("expected error '%s', got '%v'", ErrLimitExceeded, err)
+		}
+		if r.n > 1<<10 {
+			t.Errorf("expected at most %v bytes read, got %v", 1<<10, r.n)
+		}
+
+		r = &countingReader{r: strings.NewReader(within + "\n" + large)}
+		var n int
+		for _, err := range DecodeAll[StreamLimitedShape](r) {
+			if err != nil {
+				if !errors.Is(err, ErrLimitExceeded) {
+					t.Errorf("expected error '%s', got '%v'", ErrLimitExceeded, err)
+				}
+				break
+			}
+			n++
+		}
+		if n != 1 {
+			t.Errorf("expected %v unions, got %v", 1, n)
+		}
+		if r.n > 1<<13 {
+			t.Errorf("expected at most %v bytes read, got %v", 1<<13, r.n)
+		}
+	})
+}
@@ -0,0 +1,230 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// ToMap returns the union as a generic map with the same shape as MarshalJSON,
+// for document stores such as Firestore that accept maps but have no custom
+// serialization hooks. Numbers are converted to int64 when they are integers
+// and float64 otherwise. See the ToMap function.
+func (u TaggedUnion[Spec]) ToMap() (map[string]any, error) {
+	return u.encodeMap()
+}
+
+// FromMap sets the union from a generic map with the same rules as UnmarshalJSON,
+// such as a document read from Firestore. See DecodeMap.
+func (u *TaggedUnion[Spec]) FromMap(m map[string]any) error {
+	return u.decodeMap(m)
+}
+
+// DecodeMap returns a TaggedUnion decoded from a generic map, as handed over by config
+// loaders, template engines and NoSQL drivers, with the same variant rules as UnmarshalJSON.
+// The payload is converted directly into the variant's type following its json struct tags,
+// without a JSON round trip. Values implementing json.Unmarshaler or encoding.TextUnmarshaler,
+// such as time.Time and nested unions, are still decoded from their JSON representation,
+// as are unions of specs with a codec, member discriminators, discriminator paths, tuples,
+// Extras, Raw or interface fields, and unions with DecodeLimits, which are checked like
+// UnmarshalJSON checks them.
+func DecodeMap[Spec any](m map[string]any) (TaggedUnion[Spec], error) {
+	var u TaggedUnion[Spec]
+	err := u.decodeMap(m)
+	return u, err
+}
+
+// ToMap returns the union as a generic map with the same shape as MarshalJSON, converting
+// the payload directly from the variant's type following its json struct tags, without a
+// JSON round trip. Numbers are int64 when they are integers and float64 otherwise.
+// See DecodeMap for the values that are still converted through their JSON representation.
+// Unions that don't marshal to a JSON object, such as unit variants and unions of
+// JSONTuple specs, are rejected.
+func ToMap[Spec any](u TaggedUnion[Spec]) (map[string]any, error) {
+	return u.encodeMap()
+}
+
+// encodeMap implements ToMap.
+func (u TaggedUnion[Spec]) encodeMap() (map[string]any, error) {
+	v := reflect.ValueOf(u.Value)
+	p := planOf(v.Type())
+	_, _, paths := u.discriminatorPaths()
+	if codecOf(p.typ) != nil || paths || p.json.hasMembers || p.json.tuple || p.extras != nil || isNullable(u.Value) {
+		return toMap(u)
+	}
+	if _, ok := unitVariant(v, u.selected); ok {
+		return nil, errNotObject
+	}
+	f, err := p.current(v, u.selected)
+	if err != nil {
+		return nil, err
+	}
+	variant, value := variantValue(v, f)
+	value, err = beforeMarshal(value)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := toGeneric(reflect.ValueOf(value))
+	if err != nil {
+		return nil, err
+	}
+	discriminator, err := toGeneric(reflect.ValueOf(encodeDiscriminator(discriminatorKind(u.Value), variant)))
+	if err != nil {
+		return nil, err
+	}
+
+	variantField, valueField := u.fieldNames()
+	if valueField != "" {
+		m := map[string]any{variantField: discriminator}
+		if !hasOptionalValue(u.Value) || !isZeroPayload(reflect.ValueOf(value)) {
+			m[valueField] = payload
+		}
+		return m, nil
+	}
+	m, ok := payload.(map[string]any)
+	if !ok && payload != nil {
+		return nil, &json.UnmarshalTypeError{Value: genericKind(payload), Type: reflect.TypeFor[map[string]any]()}
+	}
+	if _, exists := m[variantField]; exists {
+		return nil, fmt.Errorf("%w: %s", ErrVariantFieldConflict, variantField)
+	}
+	flat := make(map[string]any, len(m)+1)
+	maps.Copy(flat, m)
+	flat[variantField] = discriminator
+	return flat, nil
+}
+
+// decodeMap implements DecodeMap.
+func (u *TaggedUnion[Spec]) decodeMap(m map[string]any) error {
+	var zero Spec
+	u.Value = zero
+	u.selected = nil
+
+	v := reflect.ValueOf(&u.Value).Elem()
+	p := planOf(v.Type())
+	if !p.isStruct {
+		return ErrSpecNotStruct
+	}
+	_, _, paths := u.discriminatorPaths()
+	// limits are checked on the JSON representation, as UnmarshalJSON checks them
+	if codecOf(p.typ) != nil || paths || p.json.hasMembers || p.json.tuple || p.extras != nil || p.raw >= 0 || p.hasInterfaceFields() || p.hasUpcasts() || u.limits() != (DecodeLimits{}) {
+		return fromJSONValue(u, m)
+	}
+
+	variantField, valueField := u.fieldNames()
+	rawVariant, ok := m[variantField]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrMissingVariantField, variantField)
+	}
+	strict := isStrict(u.Value)
+	if valueField != "" && strict {
+		for _, key := range slices.Sorted(maps.Keys(m)) {
+			if key != variantField && key != valueField {
+				return fmt.Errorf("%w: %s", ErrUnknownField, key)
+			}
+		}
+	}
+
+	var value any
+	hasValue, missingValue := true, false
+	if valueField != "" {
+		value, hasValue = m[valueField]
+		missingValue = !hasValue && !hasOptionalValue(u.Value)
+	} else {
+		payload := maps.Clone(m)
+		delete(payload, variantField)
+		value = payload
+	}
+
+	variantJSON, err := json.Marshal(rawVariant)
+	if err != nil {
+		return err
+	}
+	variant, err := decodeDiscriminator(discriminatorKind(u.Value), variantJSON)
+	if err != nil {
+		return err
+	}
+
+	f, err := p.resolve(variant)
+	if missingValue && (err != nil || !f.unit) {
+		return fmt.Errorf("%w: %s", ErrMissingValueField, valueField)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !hasValue {
+		v.FieldByIndex(f.index).Set(zeroPayload(f))
+		u.selected = selection(v, f)
+		return nil
+	}
+	target := reflect.New(f.typ).Elem()
+	if err := fromGeneric(value, target, strict); err != nil {
+		return &DecodeError{Spec: p.typ, Variant: variant, Field: f.name, Err: err}
+	}
+	if err := afterUnmarshal(target.Addr()); err != nil {
+		return &DecodeError{Spec: p.typ, Variant: variant, Field: f.name, Err: err}
+	}
+	v.FieldByIndex(f.index).Set(target)
+	u.selected = selection(v, f)
+	return nil
+}
+
+// hasInterfaceFields reports whether the spec has interface fields, which may hold
+// implementations registered with RegisterImpl.
+func (p *specPlan) hasInterfaceFields() bool {
+	return slices.ContainsFunc(p.fields, func(f fieldPlan) bool { return f.typ.Kind() == reflect.Interface })
+}
+
+// errNotObject is returned when a union does not marshal to a JSON object.
+var errNotObject = errors.New("union does not marshal to a JSON object")
+
+// ToMap returns the union as a generic map with the same shape as MarshalJSON.
+// See TaggedUnion.ToMap.
+func (u ExternallyTagged[Spec]) ToMap() (map[string]any, error) {
+	return toMap(u)
+}
+
+// FromMap sets the union from a generic map with the same rules as UnmarshalJSON.
+// See TaggedUnion.FromMap.
+func (u *ExternallyTagged[Spec]) FromMap(m map[string]any) error {
+	return fromJSONValue(u, m)
+}
+
+// toMap converts the JSON representation of u to a generic map.
+func toMap(u json.Marshaler) (map[string]any, error) {
+	value, err := jsonValue(u)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := numbersToGo(value).(map[string]any)
+	if !ok {
+		return nil, errNotObject
+	}
+	return m, nil
+}
+
+// numbersToGo replaces json.Number values with int64 or float64 throughout a generic value.
+func numbersToGo(value any) any {
+	switch value := value.(type) {
+	case json.Number:
+		if n, err := strconv.ParseInt(string(value), 10, 64); err == nil {
+			return n
+		}
+		f, _ := value.Float64()
+		return f
+	case []any:
+		for i, elem := range value {
+			value[i] = numbersToGo(elem)
+		}
+	case map[string]any:
+		for key, elem := range value {
+			value[key] = numbersToGo(elem)
+		}
+	}
+	return value
+}
@@ -0,0 +1,265 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestToMap(t *testing.T) {
+	tests := []struct {
+		name  string
+		shape interface {
+			ToMap() (map[string]any, error)
+		}
+		expected    map[string]any
+		expectedErr string
+	}{
+		{
+			name:  "converts tagged union",
+			shape: TaggedUnion[Shape]{Value: Shape{Rectangle: &Rectangle{Width: 10, Height: 2.5}}},
+			expected: map[string]any{
+				"type":  "rectangle",
+				"value": map[string]any{"width": int64(10), "height": 2.5},
+			},
+		},
+		{
+			name:     "converts flat tagged union",
+			shape:    TaggedUnion[FlatShape]{Value: FlatShape{Circle: &Circle{Radius: 5}}},
+			expected: map[string]any{"type": "circle", "radius": int64(5)},
+		},
+		{
+			name:     "converts externally tagged union",
+			shape:    ExternallyTagged[Shape]{Value: Shape{Circle: &Circle{Radius: 5}}},
+			expected: map[string]any{"circle": map[string]any{"radius": int64(5)}},
+		},
+		{
+			name:        "returns error when no variant is set",
+			shape:       TaggedUnion[Shape]{},
+			expectedErr: "zero variants set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := tt.shape.ToMap()
+
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("expected error '%s', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(m, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, m)
+			}
+		})
+	}
+}
+
+func TestFromMap(t *testing.T) {
+	var shape TaggedUnion[Shape]
+	err := shape.FromMap(map[string]any{
+		"type":  "triangle",
+		"value": map[string]any{"base": int64(8), "height": 4.0},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, shape.GetValue(), Triangle{Base: 8, Height: 4})
+
+	var external ExternallyTagged[Shape]
+	if err := external.FromMap(map[string]any{"circle": map[string]any{"radius": int64(5)}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, external.GetValue(), Circle{Radius: 5})
+
+	err = shape.FromMap(map[string]any{"value": map[string]any{}})
+	if !errors.Is(err, ErrMissingVariantField) {
+		t.Errorf("expected error '%v', got '%v'", ErrMissingVariantField, err)
+	}
+}
+
+type (
+	MapBase struct {
+		ID   int    `json:"id"`
+		Note string `json:"note,omitempty"`
+	}
+	MapDocument struct {
+		MapBase
+		Title   string            `json:"title"`
+		Tags    []string          `json:"tags"`
+		Labels  map[string]uint8  `json:"labels,omitempty"`
+		Created time.Time         `json:"created"`
+		Parent  *MapDocument      `json:"parent,omitempty"`
+		Meta    map[string]any    `json:"meta"`
+		Scores  [2]float32        `json:"scores"`
+		Ignored string            `json:"-"`
+		Counts  map[string]*int64 `json:"counts,omitempty"`
+	}
+	MapShape struct {
+		Circle   *Circle      `variant:"circle"`
+		Document *MapDocument `variant:"document"`
+	}
+)
+
+func TestDecodeMap(t *testing.T) {
+	t.Run("decodes payloads without a JSON round trip", func(t *testing.T) {
+		created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		u, err := DecodeMap[MapShape](map[string]any{
+			"type": "document",
+			"value": map[string]any{
+				"id":      int64(7),
+				"TITLE":   "draft",
+				"tags":    []any{"a", "b"},
+				"labels":  map[any]any{"x": 1},
+				"created": created.Format(time.RFC3339),
+				"parent":  map[string]any{"id": 6.0},
+				"meta":    map[string]any{"k": true},
+				"scores":  []any{0.5, int64(1)},
+				"unknown": "ignored",
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := &MapDocument{
+			MapBase: MapBase{ID: 7},
+			Title:   "draft",
+			Tags:    []string{"a", "b"},
+			Labels:  map[string]uint8{"x": 1},
+			Created: created,
+			Parent:  &MapDocument{MapBase: MapBase{ID: 6}},
+			Meta:    map[string]any{"k": true},
+			Scores:  [2]float32{0.5, 1},
+		}
+		if !reflect.DeepEqual(u.Value.Document, expected) {
+			t.Errorf("expected %+v, got %+v", expected, u.Value.Document)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		decode   func(m map[string]any) (any, error)
+		input    map[string]any
+		expected any
+		err      error
+	}{
+		{
+			name:     "flat representation",
+			decode:   func(m map[string]any) (any, error) { return DecodeMap[FlatShape](m) },
+			input:    map[string]any{"type": "circle", "radius": 2.5},
+			expected: TaggedUnion[FlatShape]{Value: FlatShape{Circle: &Circle{Radius: 2.5}}},
+		},
+		{
+			name:     "optional value field",
+			decode:   func(m map[string]any) (any, error) { return DecodeMap[OptionalValueShape](m) },
+			input:    map[string]any{"type": "circle"},
+			expected: TaggedUnion[OptionalValueShape]{Value: OptionalValueShape{Circle: &Circle{}}},
+		},
+		{
+			name:   "missing value field",
+			decode: func(m map[string]any) (any, error) { return DecodeMap[Shape](m) },
+			input:  map[string]any{"type": "circle"},
+			err:    ErrMissingValueField,
+		},
+		{
+			name:   "unknown variant",
+			decode: func(m map[string]any) (any, error) { return DecodeMap[Shape](m) },
+			input:  map[string]any{"type": "hexagon", "value": map[string]any{}},
+			err:    ErrUnknownVariant,
+		},
+		{
+			name:   "strict envelope",
+			decode: func(m map[string]any) (any, error) { return DecodeMap[StrictShape](m) },
+			input:  map[string]any{"type": "circle", "value": map[string]any{}, "id": 1},
+			err:    ErrUnknownField,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := tt.decode(tt.input)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("expected error %v, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(u, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, u)
+			}
+		})
+	}
+
+	t.Run("strict payload", func(t *testing.T) {
+		_, err := DecodeMap[StrictShape](map[string]any{"type": "circle", "value": map[string]any{"diameter": 1}})
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Variant != "circle" {
+			t.Errorf("expected DecodeError for circle, got %v", err)
+		}
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		_, err := DecodeMap[Shape](map[string]any{"type": "circle", "value": map[string]any{"radius": "big"}})
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) || typeErr.Value != "string" {
+			t.Errorf("expected UnmarshalTypeError, got %v", err)
+		}
+	})
+
+	t.Run("integer overflow", func(t *testing.T) {
+		_, err := DecodeMap[MapShape](map[string]any{"type": "document", "value": map[string]any{"labels": map[string]any{"x": 300}}})
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			t.Errorf("expected UnmarshalTypeError, got %v", err)
+		}
+	})
+}
+
+func TestToMapMatchesJSON(t *testing.T) {
+	count := int64(3)
+	unions := []interface {
+		json.Marshaler
+		ToMap() (map[string]any, error)
+	}{
+		TaggedUnion[Shape]{Value: Shape{Triangle: &Triangle{Base: 1.5, Height: 2}}},
+		TaggedUnion[FlatShape]{Value: FlatShape{Circle: &Circle{Radius: 5}}},
+		TaggedUnion[CustomFieldNamesShape]{Value: CustomFieldNamesShape{Circle: &Circle{}}},
+		TaggedUnion[OptionalValueShape]{Value: OptionalValueShape{Circle: &Circle{}}},
+		TaggedUnion[MapShape]{Value: MapShape{Document: &MapDocument{
+			MapBase: MapBase{ID: 1, Note: "n"},
+			Tags:    []string{"a"},
+			Labels:  map[string]uint8{"x": 255},
+			Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Parent:  &MapDocument{Title: "p"},
+			Scores:  [2]float32{0.1, 3},
+			Ignored: "skip",
+			Counts:  map[string]*int64{"c": &count, "nil": nil},
+		}}},
+	}
+	for _, u := range unions {
+		t.Run(fmt.Sprintf("%T", u), func(t *testing.T) {
+			expected, err := toMap(u)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			m, err := u.ToMap()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(m, expected) {
+				t.Errorf("expected %v, got %v", expected, m)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package union
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var unionPtrType = reflect.TypeFor[unionPtr]()
+
+// MapstructureHook is a decode hook for github.com/mitchellh/mapstructure (and
+// its forks, as used by viper and koanf) that decodes generic config values into
+// Union, TaggedUnion and ExternallyTagged values with the same variant rules as
+// UnmarshalJSON. Other target types are passed through unchanged.
+//
+// Its signature matches mapstructure.DecodeHookFuncType, so it can be used without
+// this package depending on mapstructure:
+//
+//	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+//	    DecodeHook: union.MapstructureHook,
+//	    Result:     &config,
+//	})
+//
+// Variant payloads are converted through their JSON representation, so they use
+// their json struct tags rather than mapstructure tags.
+func MapstructureHook(from, to reflect.Type, data any) (any, error) {
+	if from == to || !reflect.PointerTo(to).Implements(unionPtrType) {
+		return data, nil
+	}
+
+	target := reflect.New(to)
+	if err := fromJSONValue(target.Interface().(unionPtr), stringKeys(data)); err != nil {
+		return nil, err
+	}
+	return target.Elem().Interface(), nil
+}
+
+// stringKeys replaces maps with non-string keys (as produced by some YAML decoders)
+// with map[string]any throughout a generic value, so it can be marshaled to JSON.
+func stringKeys(value any) any {
+	switch value := value.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(value))
+		for key, elem := range value {
+			m[fmt.Sprint(key)] = stringKeys(elem)
+		}
+		return m
+	case map[string]any:
+		m := make(map[string]any, len(value))
+		for key, elem := range value {
+			m[key] = stringKeys(elem)
+		}
+		return m
+	case []any:
+		s := make([]any, len(value))
+		for i, elem := range value {
+			s[i] = stringKeys(elem)
+		}
+		return s
+	}
+	return value
+}
@@ -0,0 +1,83 @@
+package union
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMapstructureHook(t *testing.T) {
+	tests := []struct {
+		name        string
+		to          reflect.Type
+		data        any
+		expected    any
+		expectedErr error
+	}{
+		{
+			name: "decodes tagged union",
+			to:   reflect.TypeFor[TaggedUnion[Shape]](),
+			data: map[string]any{
+				"type":  "rectangle",
+				"value": map[string]any{"width": 10, "height": 5},
+			},
+			expected: Rectangle{Width: 10, Height: 5},
+		},
+		{
+			name:     "decodes externally tagged union with non-string keys",
+			to:       reflect.TypeFor[ExternallyTagged[Shape]](),
+			data:     map[any]any{"circle": map[any]any{"radius": 5}},
+			expected: Circle{Radius: 5},
+		},
+		{
+			name:     "decodes untagged union",
+			to:       reflect.TypeFor[Union[UnionShape]](),
+			data:     map[string]any{"base": 8, "height": 4},
+			expected: Triangle{Base: 8, Height: 4},
+		},
+		{
+			name:        "returns error for unknown variant",
+			to:          reflect.TypeFor[TaggedUnion[Shape]](),
+			data:        map[string]any{"type": "hexagon", "value": map[string]any{}},
+			expectedErr: ErrUnknownVariant,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := MapstructureHook(reflect.TypeOf(tt.data), tt.to, tt.data)
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("expected error '%v', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if reflect.TypeOf(result) != tt.to {
+				t.Fatalf("expected result of type %v, got %T", tt.to, result)
+			}
+			assertValueEquals(t, result.(interface{ GetValue() any }).GetValue(), tt.expected)
+		})
+	}
+}
+
+func TestMapstructureHookPassthrough(t *testing.T) {
+	data := map[string]any{"radius": 5}
+	result, err := MapstructureHook(reflect.TypeOf(data), reflect.TypeFor[Circle](), data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result, data) {
+		t.Errorf("expected data to pass through unchanged, got %v", result)
+	}
+
+	shape := TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 5}}}
+	result, err = MapstructureHook(reflect.TypeOf(shape), reflect.TypeOf(shape), shape)
+	if err != nil || !reflect.DeepEqual(result, shape) {
+		t.Errorf("expected union to pass through unchanged, got %v (err=%v)", result, err)
+	}
+}
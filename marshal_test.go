@@ -0,0 +1,110 @@
+package union
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAppendJSONString(t *testing.T) {
+	tests := []string{
+		"",
+		"circle",
+		`quote " and backslash \`,
+		"control \b\f\n\r\t\x00\x1f",
+		"html <a href=\"x\">&amp;</a>",
+		"unicode é 世界 🎉",
+		"separators    ",
+		"invalid \xff\xfe utf-8",
+	}
+	for _, s := range tests {
+		expected, err := json.Marshal(s)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := appendJSONString(nil, s); string(got) != string(expected) {
+			t.Errorf("expected %s, got %s", expected, got)
+		}
+	}
+}
+
+func TestMarshalAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not stable under the race detector")
+	}
+	payload := &Triangle{Base: 8, Height: 4}
+	base := testing.AllocsPerRun(100, func() {
+		if _, err := json.Marshal(payload); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		marshal func() ([]byte, error)
+	}{
+		{"tagged", TaggedUnion[Shape]{Value: Shape{Triangle: payload}}.MarshalJSON},
+		{"custom field names", TaggedUnion[CustomFieldNamesShape]{Value: CustomFieldNamesShape{Rectangle: &Rectangle{Width: 2, Height: 3}}}.MarshalJSON},
+		{"externally tagged", ExternallyTagged[Shape]{Value: Shape{Triangle: payload}}.MarshalJSON},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allocs := testing.AllocsPerRun(100, func() {
+				if _, err := tt.marshal(); err != nil {
+					t.Fatal(err)
+				}
+			})
+			// the spec value and the output buffer
+			if allocs > base+2 {
+				t.Errorf("expected at most %v allocations, got %v", base+2, allocs)
+			}
+		})
+	}
+}
+
+func BenchmarkMarshalJSON(b *testing.B) {
+	payload := &Triangle{Base: 8, Height: 4}
+	benchmarks := []struct {
+		name    string
+		marshal func() ([]byte, error)
+	}{
+		{"Payload", func() ([]byte, error) { return json.Marshal(payload) }},
+		{"TaggedUnion", TaggedUnion[Shape]{Value: Shape{Triangle: payload}}.MarshalJSON},
+		{"TaggedUnionFlat", TaggedUnion[FlatShape]{Value: FlatShape{Triangle: payload}}.MarshalJSON},
+		{"ExternallyTagged", ExternallyTagged[Shape]{Value: Shape{Triangle: payload}}.MarshalJSON},
+		{"Union", Union[Shape]{Value: Shape{Triangle: payload}}.MarshalJSON},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := bm.marshal(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestObjectHasKey(t *testing.T) {
+	tests := []struct {
+		data                    string
+		exists, empty, isObject bool
+	}{
+		{`{"type":1}`, true, false, true},
+		{`{"a":{"type":1},"b":["type"],"c":"type"}`, false, false, true},
+		{`{ "a" : "x\"}" , "type" : null }`, true, false, true},
+		{`{"type":true}`, true, false, true},
+		{`{ }`, false, true, true},
+		{`{"a":1,"b":[1,2],"c":-1.5e3}`, false, false, true},
+		{`[1]`, false, false, false},
+		{`null`, false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			exists, empty, isObject := objectHasKey([]byte(tt.data), "type")
+			if exists != tt.exists || empty != tt.empty || isObject != tt.isObject {
+				t.Errorf("expected %v %v %v, got %v %v %v", tt.exists, tt.empty, tt.isObject, exists, empty, isObject)
+			}
+		})
+	}
+}
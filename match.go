@@ -0,0 +1,302 @@
+package union
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// MatchCase handles the active variant of a union in Match.
+// Create one with Case or Default.
+type MatchCase struct {
+	try func(value any) bool
+}
+
+// Case returns a MatchCase that calls fn when the active variant is of type T.
+// Like As, it treats pointer and non-pointer variant fields the same.
+func Case[T any](fn func(T)) MatchCase {
+	return MatchCase{try: func(value any) bool {
+		v, ok := as[T](value)
+		if ok {
+			fn(v)
+		}
+		return ok
+	}}
+}
+
+// Default returns a MatchCase that always calls fn with the active variant's value,
+// which is nil if no variant or multiple variants are set.
+// It should be passed as the last case.
+func Default(fn func(any)) MatchCase {
+	return MatchCase{try: func(value any) bool {
+		fn(value)
+		return true
+	}}
+}
+
+// Match calls the first case that handles the active variant of the union.
+// It returns ErrNoCaseMatched if no case matched.
+//
+// Example:
+//
+//	err := union.Match(shape,
+//	    union.Case(func(c Circle) { fmt.Println("circle", c.Radius) }),
+//	    union.Case(func(r Rectangle) { fmt.Println("rectangle", r.Width, r.Height) }),
+//	    union.Default(func(v any) { fmt.Println("other", v) }),
+//	)
+func Match(u interface{ GetValue() any }, cases ...MatchCase) error {
+	value := u.GetValue()
+	for _, c := range cases {
+		if c.try(value) {
+			return nil
+		}
+	}
+	return ErrNoCaseMatched
+}
+
+// MatchCaseR handles the active variant of a union in MatchR and returns a result.
+// Create one with CaseR or DefaultR.
+type MatchCaseR[R any] struct {
+	try func(value any) (R, bool)
+}
+
+// CaseR returns a MatchCaseR that calls fn when the active variant is of type T.
+// Like As, it treats pointer and non-pointer variant fields the same.
+func CaseR[T, R any](fn func(T) R) MatchCaseR[R] {
+	return MatchCaseR[R]{try: func(value any) (R, bool) {
+		v, ok := as[T](value)
+		if !ok {
+			var zero R
+			return zero, false
+		}
+		return fn(v), true
+	}}
+}
+
+// DefaultR returns a MatchCaseR that always calls fn with the active variant's value,
+// which is nil if no variant or multiple variants are set.
+// It should be passed as the last case.
+func DefaultR[R any](fn func(any) R) MatchCaseR[R] {
+	return MatchCaseR[R]{try: func(value any) (R, bool) {
+		return fn(value), true
+	}}
+}
+
+// MatchR calls the first case that handles the active variant of the union
+// and returns its result. It returns ErrNoCaseMatched if no case matched.
+//
+// Example:
+//
+//	area, err := union.MatchR(shape,
+//	    union.CaseR(func(c Circle) float64 { return math.Pi * c.Radius * c.Radius }),
+//	    union.CaseR(func(r Rectangle) float64 { return r.Width * r.Height }),
+//	)
+func MatchR[R any](u interface{ GetValue() any }, cases ...MatchCaseR[R]) (R, error) {
+	value := u.GetValue()
+	for _, c := range cases {
+		if result, ok := c.try(value); ok {
+			return result, nil
+		}
+	}
+	var zero R
+	return zero, ErrNoCaseMatched
+}
+
+// VariantFunc transforms the payload of one variant into a result in MapValue and Fold.
+// Create one with On.
+type VariantFunc[R any] struct {
+	typ   reflect.Type
+	apply func(value any) (R, bool, error)
+}
+
+// On returns a VariantFunc that calls fn when the active variant is of type T.
+// Like As, it treats pointer and non-pointer variant fields the same.
+func On[T, R any](fn func(T) (R, error)) VariantFunc[R] {
+	return VariantFunc[R]{typ: reflect.TypeFor[T](), apply: func(value any) (R, bool, error) {
+		v, ok := as[T](value)
+		if !ok {
+			var zero R
+			return zero, false, nil
+		}
+		r, err := fn(v)
+		return r, true, err
+	}}
+}
+
+// MapValue transforms the active variant of the union into a result with the first
+// function handling it, and returns the function's result and error.
+//
+// Unlike MatchR, the functions must cover every variant of the Spec type, and MapValue
+// returns ErrNoCaseMatched naming the uncovered variants otherwise, whichever variant is
+// active. Adding a variant to the spec then fails every MapValue call missing it, rather
+// than only those meeting a value of the new variant. Raw fields need no function.
+//
+// It returns the error of Validate if the union does not hold exactly one variant.
+//
+// Example:
+//
+//	area, err := union.MapValue[Shape](shape,
+//	    union.On(func(c Circle) (float64, error) { return math.Pi * c.Radius * c.Radius, nil }),
+//	    union.On(func(r Rectangle) (float64, error) { return r.Width * r.Height, nil }),
+//	    union.On(func(t Triangle) (float64, error) { return t.Base * t.Height / 2, nil }),
+//	)
+func MapValue[Spec, R any](u interface {
+	GetValue() any
+	Validate() error
+}, fns ...VariantFunc[R]) (R, error) {
+	if err := checkCoverage[Spec](fns); err != nil {
+		var zero R
+		return zero, err
+	}
+	return mapValue(u, fns)
+}
+
+// mapValue implements MapValue once the functions are known to cover the spec.
+func mapValue[R any](u interface {
+	GetValue() any
+	Validate() error
+}, fns []VariantFunc[R]) (R, error) {
+	var zero R
+	if err := u.Validate(); err != nil {
+		return zero, err
+	}
+	value := u.GetValue()
+	for _, fn := range fns {
+		if r, ok, err := fn.apply(value); ok {
+			return r, err
+		}
+	}
+	return zero, ErrNoCaseMatched
+}
+
+// Fold reduces a sequence of unions to a single value. Each union is transformed with
+// the functions as in MapValue, and its result is combined into the accumulator, which
+// starts at init. It stops at the first error, returning the accumulator so far.
+//
+// Example:
+//
+//	total, err := union.Fold[Shape](slices.Values(shapes), 0.0,
+//	    func(total, area float64) float64 { return total + area },
+//	    union.On(circleArea), union.On(rectangleArea), union.On(triangleArea),
+//	)
+func Fold[Spec, R, A any, U interface {
+	GetValue() any
+	Validate() error
+}](unions iter.Seq[U], init A, combine func(A, R) A, fns ...VariantFunc[R]) (A, error) {
+	if err := checkCoverage[Spec](fns); err != nil {
+		return init, err
+	}
+	acc := init
+	for u := range unions {
+		r, err := mapValue(u, fns)
+		if err != nil {
+			return acc, err
+		}
+		acc = combine(acc, r)
+	}
+	return acc, nil
+}
+
+// checkCoverage reports the variants of the Spec type that none of the functions handle.
+func checkCoverage[Spec, R any](fns []VariantFunc[R]) error {
+	p := planOf(reflect.TypeFor[Spec]())
+	if !p.isStruct {
+		return ErrSpecNotStruct
+	}
+	var missing []string
+	for _, f := range p.fields {
+		if f.raw || slices.ContainsFunc(fns, func(fn VariantFunc[R]) bool { return f.handledBy(fn.typ) }) {
+			continue
+		}
+		missing = append(missing, f.variant)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: no function for %s", ErrNoCaseMatched, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// handledBy reports whether payloads of the field convert to type t, as by As.
+func (f *fieldPlan) handledBy(t reflect.Type) bool {
+	if t.Kind() == reflect.Interface {
+		return f.typ.Implements(t)
+	}
+	return f.accepts(t)
+}
+
+// Dispatch calls the handler registered for the name of the active variant with its value,
+// as returned by GetValue, and returns the handler's error. The handler registered with
+// the empty name, which is no variant's name, handles variants without one of their own:
+//
+//	err := event.Dispatch(map[string]func(any) error{
+//	    "order":  func(v any) error { return placeOrder(v.(*Order)) },
+//	    "refund": func(v any) error { return refund(v.(*Refund)) },
+//	    "":       func(v any) error { return nil }, // ignore other events
+//	})
+//
+// The handlers are checked before any is called. Returns an error if:
+//   - A handler is registered for a name the Spec type doesn't declare (*UnknownVariantError),
+//     unless it has a Raw field or interface fields with registered implementations
+//   - Without a default handler, variants of the Spec type have no handler (ErrNoCaseMatched, naming them)
+//   - No variant or multiple variants are set
+//
+// Aliases are not variant names, so handlers are registered under the declared names.
+// Unknown variants captured by a Raw field are dispatched by their own name, with their
+// JSON data as value, to the default handler unless the name has a handler, and
+// registered implementations are dispatched by their implementation name.
+func (u TaggedUnion[Spec]) Dispatch(handlers map[string]func(value any) error) error {
+	return dispatch(reflect.ValueOf(u.Value), u.selected, handlers)
+}
+
+// Dispatch calls the handler registered for the name of the active variant. See TaggedUnion.Dispatch.
+func (u ExternallyTagged[Spec]) Dispatch(handlers map[string]func(value any) error) error {
+	return dispatch(reflect.ValueOf(u.Value), u.selected, handlers)
+}
+
+// Dispatch calls the handler registered for the name of the active variant. See TaggedUnion.Dispatch.
+func (u Union[Spec]) Dispatch(handlers map[string]func(value any) error) error {
+	return dispatch(reflect.ValueOf(u.Value), u.selected, handlers)
+}
+
+// dispatch implements Dispatch for the spec value v with the selected field.
+func dispatch(v reflect.Value, selected *fieldPlan, handlers map[string]func(value any) error) error {
+	p := planOf(v.Type())
+	if !p.isStruct {
+		return ErrSpecNotStruct
+	}
+	fallback, hasDefault := handlers[""]
+
+	// names of unknown variants and registered implementations are only known at run time
+	open := p.raw >= 0 || slices.ContainsFunc(p.fields, func(f fieldPlan) bool { return f.typ.Kind() == reflect.Interface })
+	for name := range handlers {
+		if name != "" && !open && !slices.Contains(p.variants, name) {
+			return &UnknownVariantError{Spec: p.typ, Variant: name, Known: p.knownVariants()}
+		}
+	}
+	if !hasDefault {
+		var missing []string
+		for _, variant := range p.variants {
+			if _, ok := handlers[variant]; !ok {
+				missing = append(missing, variant)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("%w: no handler for %s", ErrNoCaseMatched, strings.Join(missing, ", "))
+		}
+	}
+
+	f, err := p.current(v, selected)
+	if err != nil {
+		return err
+	}
+	variant, value := variantValue(v, f)
+	if handler, ok := handlers[variant]; ok {
+		return handler(value)
+	}
+	if hasDefault {
+		return fallback(value)
+	}
+	return fmt.Errorf("%w: no handler for %s", ErrNoCaseMatched, variant)
+}
@@ -0,0 +1,294 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		shape       TaggedUnion[Shape]
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "matches circle case",
+			shape:    TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 5.0}}},
+			expected: "circle",
+		},
+		{
+			name:     "matches pointer case",
+			shape:    TaggedUnion[Shape]{Value: Shape{Rectangle: &Rectangle{Width: 10, Height: 5}}},
+			expected: "rectangle",
+		},
+		{
+			name:     "falls back to default case",
+			shape:    TaggedUnion[Shape]{Value: Shape{Triangle: &Triangle{Base: 8, Height: 4}}},
+			expected: "default",
+		},
+		{
+			name:     "calls default case when no variant is set",
+			shape:    TaggedUnion[Shape]{},
+			expected: "default",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var matched string
+			err := Match(tt.shape,
+				Case(func(c Circle) { matched = "circle" }),
+				Case(func(r *Rectangle) { matched = "rectangle" }),
+				Default(func(v any) { matched = "default" }),
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if matched != tt.expected {
+				t.Errorf("expected %s case, got %s", tt.expected, matched)
+			}
+		})
+	}
+}
+
+func TestMatchNoCase(t *testing.T) {
+	shape := Union[UnionShape]{Value: UnionShape{Triangle: &Triangle{Base: 8, Height: 4}}}
+	err := Match(shape, Case(func(c Circle) { t.Error("unexpected circle case") }))
+	if !errors.Is(err, ErrNoCaseMatched) {
+		t.Errorf("expected error '%v', got '%v'", ErrNoCaseMatched, err)
+	}
+}
+
+func TestMatchR(t *testing.T) {
+	area := func(shape TaggedUnion[Shape]) (float64, error) {
+		return MatchR(shape,
+			CaseR(func(c Circle) float64 { return 3 * c.Radius * c.Radius }),
+			CaseR(func(r Rectangle) float64 { return r.Width * r.Height }),
+		)
+	}
+
+	got, err := area(TaggedUnion[Shape]{Value: Shape{Rectangle: &Rectangle{Width: 10, Height: 5}}})
+	if err != nil || got != 50 {
+		t.Errorf("expected 50, got %v (err=%v)", got, err)
+	}
+
+	got, err = area(TaggedUnion[Shape]{Value: Shape{Triangle: &Triangle{Base: 8, Height: 4}}})
+	if !errors.Is(err, ErrNoCaseMatched) || got != 0 {
+		t.Errorf("expected error '%v', got %v (err=%v)", ErrNoCaseMatched, got, err)
+	}
+
+	label, err := MatchR(TaggedUnion[Shape]{},
+		CaseR(func(c Circle) string { return "circle" }),
+		DefaultR(func(v any) string { return "unset" }),
+	)
+	if err != nil || label != "unset" {
+		t.Errorf("expected unset, got %s (err=%v)", label, err)
+	}
+}
+
+func TestMapValue(t *testing.T) {
+	area := []VariantFunc[float64]{
+		On(func(c Circle) (float64, error) { return 3 * c.Radius * c.Radius, nil }),
+		On(func(r *Rectangle) (float64, error) { return r.Width * r.Height, nil }),
+		On(func(t Triangle) (float64, error) { return t.Base * t.Height / 2, nil }),
+	}
+	errNegative := errors.New("negative radius")
+
+	tests := []struct {
+		name        string
+		shape       TaggedUnion[Shape]
+		fns         []VariantFunc[float64]
+		expected    float64
+		expectedErr error
+	}{
+		{
+			name:     "maps active variant",
+			shape:    TaggedUnion[Shape]{Value: Shape{Rectangle: &Rectangle{Width: 10, Height: 5}}},
+			fns:      area,
+			expected: 50,
+		},
+		{
+			name:  "returns function error",
+			shape: TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: -1}}},
+			fns: append([]VariantFunc[float64]{
+				On(func(c Circle) (float64, error) { return 0, errNegative }),
+			}, area...),
+			expectedErr: errNegative,
+		},
+		{
+			name:        "rejects uncovered variants",
+			shape:       TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 1}}},
+			fns:         area[:2],
+			expectedErr: ErrNoCaseMatched,
+		},
+		{
+			name:  "covers variants with interface",
+			shape: TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 1}}},
+			fns: []VariantFunc[float64]{
+				On(func(s interface{ isShape() }) (float64, error) { return 1, nil }),
+				area[1], area[2],
+			},
+			expected: 1,
+		},
+		{
+			name:        "rejects unset union",
+			shape:       TaggedUnion[Shape]{},
+			fns:         area,
+			expectedErr: ErrZeroVariants,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MapValue[Shape](tt.shape, tt.fns...)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("names uncovered variants", func(t *testing.T) {
+		_, err := MapValue[Shape](TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 1}}}, area[0])
+		if err == nil || err.Error() != "no case matched: no function for rectangle, triangle" {
+			t.Errorf("expected uncovered variants, got %v", err)
+		}
+	})
+}
+
+func TestFold(t *testing.T) {
+	shapes := []ExternallyTagged[Shape]{
+		{Value: Shape{Circle: &Circle{Radius: 1}}},
+		{Value: Shape{Rectangle: &Rectangle{Width: 2, Height: 3}}},
+		{Value: Shape{Triangle: &Triangle{Base: 4, Height: 5}}},
+	}
+	names := []VariantFunc[string]{
+		On(func(Circle) (string, error) { return "circle", nil }),
+		On(func(Rectangle) (string, error) { return "rectangle", nil }),
+		On(func(Triangle) (string, error) { return "triangle", nil }),
+	}
+
+	got, err := Fold[Shape](slices.Values(shapes), 0, func(n int, name string) int { return n + len(name) }, names...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := len("circle") + len("rectangle") + len("triangle"); got != expected {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	t.Run("stops at first error", func(t *testing.T) {
+		invalid := append(slices.Clone(shapes[:1]), ExternallyTagged[Shape]{}, shapes[2])
+		got, err := Fold[Shape](slices.Values(invalid), 0, func(n int, name string) int { return n + len(name) }, names...)
+		if !errors.Is(err, ErrZeroVariants) {
+			t.Fatalf("expected error %v, got %v", ErrZeroVariants, err)
+		}
+		if got != len("circle") {
+			t.Errorf("expected %v, got %v", len("circle"), got)
+		}
+	})
+}
+
+func TestDispatch(t *testing.T) {
+	var called string
+	record := func(name string) func(any) error {
+		return func(v any) error {
+			called = name
+			return nil
+		}
+	}
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name  string
+		shape interface {
+			Dispatch(map[string]func(any) error) error
+		}
+		handlers    map[string]func(any) error
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:  "calls variant handler",
+			shape: TaggedUnion[Shape]{Value: Shape{Rectangle: &Rectangle{Width: 1}}},
+			handlers: map[string]func(any) error{
+				"circle": record("circle"), "rectangle": record("rectangle"), "triangle": record("triangle"),
+			},
+			expected: "rectangle",
+		},
+		{
+			name:     "calls default handler",
+			shape:    ExternallyTagged[Shape]{Value: Shape{Triangle: &Triangle{Base: 1}}},
+			handlers: map[string]func(any) error{"circle": record("circle"), "": record("default")},
+			expected: "default",
+		},
+		{
+			name:  "returns handler error",
+			shape: Union[UnionShape]{Value: UnionShape{Circle: &Circle{Radius: 1}}},
+			handlers: map[string]func(any) error{
+				"Circle": func(any) error { return errFailed }, "": record("default"),
+			},
+			expectedErr: errFailed,
+		},
+		{
+			name:        "rejects unhandled variants",
+			shape:       TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 1}}},
+			handlers:    map[string]func(any) error{"circle": record("circle")},
+			expectedErr: ErrNoCaseMatched,
+		},
+		{
+			name:        "rejects unknown handler names",
+			shape:       TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 1}}},
+			handlers:    map[string]func(any) error{"hexagon": record("hexagon"), "": record("default")},
+			expectedErr: ErrUnknownVariant,
+		},
+		{
+			name:        "rejects unset union",
+			shape:       TaggedUnion[Shape]{},
+			handlers:    map[string]func(any) error{"": record("default")},
+			expectedErr: ErrZeroVariants,
+		},
+		{
+			name:  "dispatches raw variants by name",
+			shape: TaggedUnion[ForwardShape]{Value: ForwardShape{Unknown: &Raw{Variant: "hexagon", Value: json.RawMessage(`{}`)}}},
+			handlers: map[string]func(any) error{
+				"circle": record("circle"), "hexagon": record("hexagon"),
+			},
+			expected: "hexagon",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = ""
+			err := tt.shape.Dispatch(tt.handlers)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if called != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, called)
+			}
+		})
+	}
+
+	t.Run("passes variant value", func(t *testing.T) {
+		circle := &Circle{Radius: 1}
+		shape := TaggedUnion[Shape]{Value: Shape{Circle: circle}}
+		err := shape.Dispatch(map[string]func(any) error{
+			"circle": func(v any) error {
+				if v != circle {
+					t.Errorf("expected %p, got %v", circle, v)
+				}
+				return nil
+			},
+			"": func(any) error { return nil },
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
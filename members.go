@@ -0,0 +1,184 @@
+package union
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// memberDiscriminator returns the discriminator field declared by a
+// JSONMemberDiscriminator() string method on the Spec type, defaulting to "type".
+func (u *TaggedUnion[Spec]) memberDiscriminator() (string, bool) {
+	o := &planOf(reflect.TypeFor[Spec]()).json
+	return o.members, o.hasMembers
+}
+
+// memberName returns the member field holding the payload of the variant in the
+// Kubernetes union representation, the variant name with its first letter lowercased,
+// so the variant "RollingUpdate" is held by the member "rollingUpdate".
+func memberName(variant string) string {
+	r, size := utf8.DecodeRuneInString(variant)
+	return string(unicode.ToLower(r)) + variant[size:]
+}
+
+// marshalMembers serializes the union in the Kubernetes union representation, with the
+// variant name in the discriminator field and the payload in the variant's member field.
+// Unit variants have no member field.
+func (u TaggedUnion[Spec]) marshalMembers(lib JSONLibrary, discriminator string) ([]byte, error) {
+	v := reflect.ValueOf(u.Value)
+	p := planOf(v.Type())
+	f, err := p.current(v, u.selected)
+	if err != nil {
+		return nil, err
+	}
+	variant, value := variantValue(v, f)
+	member := memberName(variant)
+
+	var raw []byte
+	if !f.unit {
+		if raw, err = marshalPayload(lib, value); err != nil {
+			return nil, err
+		}
+	}
+
+	buf := make([]byte, 0, len(discriminator)+2*len(variant)+len(raw)+16)
+	buf = appendMemberRaw(buf, '{', discriminator, appendJSONString(nil, variant))
+	if !f.unit {
+		buf = appendMemberRaw(buf, ',', member, raw)
+	}
+	if buf, err = p.appendExtras(buf, v, discriminator, member); err != nil {
+		return nil, err
+	}
+	return append(buf, '}'), nil
+}
+
+// unmarshalMembers deserializes the union from the Kubernetes union representation.
+// A missing discriminator is inferred from the only member field that is set, and
+// a missing member field decodes into the variant's zero payload.
+func (u *TaggedUnion[Spec]) unmarshalMembers(lib JSONLibrary, data []byte, discriminator string) error {
+	var zero Spec
+	u.Value = zero
+	u.selected = nil
+
+	v := reflect.ValueOf(&u.Value).Elem()
+	p := planOf(v.Type())
+	if !p.isStruct {
+		return ErrSpecNotStruct
+	}
+
+	var raw map[string]json.RawMessage
+	err := lib.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	members := p.setMembers(raw)
+	var f *fieldPlan
+	if rawVariant, ok := raw[discriminator]; ok {
+		variant, err := decodeDiscriminator(StringDiscriminator, rawVariant)
+		if err != nil {
+			return err
+		}
+		if f, err = p.resolve(variant); err != nil {
+			return err
+		}
+		if i := slices.IndexFunc(members, func(m *fieldPlan) bool { return m != f }); i >= 0 {
+			return fmt.Errorf("%w: member %s is set for variant %s", ErrMultipleVariants, memberName(members[i].variant), f.variant)
+		}
+	} else if f, err = onlyMember(members, discriminator); err != nil {
+		return err
+	}
+
+	member := memberName(f.variant)
+	if !p.setExtras(v, raw, discriminator, member) && payloadOptions(lib, u.Value).strict {
+		for _, key := range slices.Sorted(maps.Keys(raw)) {
+			if key != discriminator && key != member {
+				return fmt.Errorf("%w: %s", ErrUnknownField, key)
+			}
+		}
+	}
+
+	rawValue, ok := raw[member]
+	if err := decodeLimits(lib, u.Value).checkValue(rawValue); err != nil {
+		return err
+	}
+	if !ok || f.unit {
+		v.FieldByIndex(f.index).Set(zeroPayload(f))
+		u.selected = selection(v, f)
+		return nil
+	}
+	target, err := decodeField(lib, f, rawValue, payloadOptions(lib, u.Value))
+	if err != nil {
+		return payloadError(p.typ, f.variant, f, member, err)
+	}
+	v.FieldByIndex(f.index).Set(target.Elem())
+	u.selected = selection(v, f)
+	return nil
+}
+
+// checkMembers reports variants of the spec plan p held by the same member field,
+// or by the discriminator field, in the Kubernetes union representation.
+func checkMembers(p *specPlan, discriminator string) []error {
+	var errs []error
+	seen := make(map[string]string)
+	for _, f := range p.fields {
+		if f.raw {
+			continue
+		}
+		member := memberName(f.variant)
+		if member == discriminator {
+			errs = append(errs, fmt.Errorf("%w: member of variant %q is named like the discriminator", ErrInvalidSpec, f.variant))
+		}
+		if other, ok := seen[member]; ok {
+			errs = append(errs, fmt.Errorf("%w: fields %s and %s are both held by member %q", ErrInvalidSpec, other, f.name, member))
+		} else {
+			seen[member] = f.name
+		}
+	}
+	return errs
+}
+
+// setMembers returns the fields of the known variants whose member field is set in raw.
+func (p *specPlan) setMembers(raw map[string]json.RawMessage) []*fieldPlan {
+	var members []*fieldPlan
+	for i := range p.fields {
+		f := &p.fields[i]
+		if value, ok := raw[memberName(f.variant)]; ok && !f.raw && !isJSONNull(value) {
+			members = append(members, f)
+		}
+	}
+	return members
+}
+
+// onlyMember returns the field of the only member that is set, inferring the variant
+// of data without a discriminator field.
+func onlyMember(members []*fieldPlan, discriminator string) (*fieldPlan, error) {
+	switch len(members) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", ErrMissingVariantField, discriminator)
+	case 1:
+		return members[0], nil
+	}
+	return nil, ErrMultipleVariants
+}
+
+// peekMembers returns the variant name of JSON data in the Kubernetes union representation,
+// read from the discriminator field or inferred from the only member field that is set.
+func (p *specPlan) peekMembers(data []byte, discriminator string) (string, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return "", err
+	}
+	if rawVariant, ok := raw[discriminator]; ok {
+		return decodeDiscriminator(StringDiscriminator, rawVariant)
+	}
+	f, err := onlyMember(p.setMembers(raw), discriminator)
+	if err != nil {
+		return "", err
+	}
+	return f.variant, nil
+}
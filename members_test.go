@@ -0,0 +1,244 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type (
+	RollingUpdate struct {
+		MaxSurge int `json:"maxSurge"`
+	}
+	Recreate struct{}
+	Strategy struct {
+		RollingUpdate *RollingUpdate `variant:"RollingUpdate"`
+		Recreate      *Recreate      `variant:"Recreate" union:"unit"`
+		Extras        Extras
+	}
+)
+
+func (Strategy) JSONMemberDiscriminator() string { return "type" }
+
+type StrictStrategy struct {
+	RollingUpdate *RollingUpdate `variant:"RollingUpdate"`
+}
+
+func (StrictStrategy) JSONMemberDiscriminator() string { return "" }
+
+func (StrictStrategy) JSONStrict() bool { return true }
+
+type ConflictingMembersSpec struct {
+	Type  *Circle `variant:"Type"`
+	Upper *Circle `variant:"Circle"`
+	Lower *Circle `variant:"circle"`
+}
+
+func (ConflictingMembersSpec) JSONMemberDiscriminator() string { return "type" }
+
+func TestMemberDiscriminator(t *testing.T) {
+	t.Run("round trips", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			jsonData string
+		}{
+			{"member variant", `{"type":"RollingUpdate","rollingUpdate":{"maxSurge":2}}`},
+			{"unit variant", `{"type":"Recreate"}`},
+			{"extra members", `{"type":"RollingUpdate","rollingUpdate":{"maxSurge":1},"paused":true}`},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var u TaggedUnion[Strategy]
+				if err := json.Unmarshal([]byte(tt.jsonData), &u); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				data, err := json.Marshal(u)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if string(data) != tt.jsonData {
+					t.Errorf("expected %s, got %s", tt.jsonData, data)
+				}
+			})
+		}
+	})
+
+	t.Run("infers the discriminator from the only member", func(t *testing.T) {
+		var u TaggedUnion[Strategy]
+		if err := json.Unmarshal([]byte(`{"rollingUpdate":{"maxSurge":3}}`), &u); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := u.Value.RollingUpdate; got == nil || *got != (RollingUpdate{MaxSurge: 3}) {
+			t.Errorf("expected %v, got %v", RollingUpdate{MaxSurge: 3}, got)
+		}
+	})
+
+	t.Run("missing member decodes the zero payload", func(t *testing.T) {
+		var u TaggedUnion[Strategy]
+		if err := json.Unmarshal([]byte(`{"type":"RollingUpdate"}`), &u); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := u.Value.RollingUpdate; got == nil || *got != (RollingUpdate{}) {
+			t.Errorf("expected %v, got %v", RollingUpdate{}, got)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		jsonData string
+		err      error
+	}{
+		{"member of another variant", `{"type":"Recreate","rollingUpdate":{}}`, ErrMultipleVariants},
+		{"several members without discriminator", `{"rollingUpdate":{},"recreate":{}}`, ErrMultipleVariants},
+		{"no discriminator or member", `{"paused":true}`, ErrMissingVariantField},
+		{"unknown variant", `{"type":"BlueGreen"}`, ErrUnknownVariant},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var u TaggedUnion[Strategy]
+			if err := json.Unmarshal([]byte(tt.jsonData), &u); !errors.Is(err, tt.err) {
+				t.Errorf("expected error %v, got %v", tt.err, err)
+			}
+		})
+	}
+
+	t.Run("strict specs reject other members", func(t *testing.T) {
+		var u TaggedUnion[StrictStrategy]
+		err := json.Unmarshal([]byte(`{"type":"RollingUpdate","paused":true}`), &u)
+		if !errors.Is(err, ErrUnknownField) {
+			t.Errorf("expected ErrUnknownField, got %v", err)
+		}
+	})
+
+	t.Run("check spec reports conflicting members", func(t *testing.T) {
+		err := CheckSpec[ConflictingMembersSpec]()
+		if !errors.Is(err, ErrInvalidSpec) {
+			t.Fatalf("expected ErrInvalidSpec, got %v", err)
+		}
+		if n := len(err.(interface{ Unwrap() []error }).Unwrap()); n != 2 {
+			t.Errorf("expected 2 problems, got %d: %v", n, err)
+		}
+	})
+}
+
+type MemberShape struct {
+	Circle    *Circle    `variant:"Circle"`
+	Rectangle *Rectangle `variant:"Rectangle"`
+}
+
+func (MemberShape) JSONMemberDiscriminator() string { return "type" }
+
+func TestMemberDiscriminatorStreamsAndMaps(t *testing.T) {
+	jsonData := `{"type":"Circle","circle":{"radius":5}}`
+	expected := &Circle{Radius: 5}
+
+	t.Run("DecodeFrom", func(t *testing.T) {
+		var u TaggedUnion[MemberShape]
+		if err := u.DecodeFrom(json.NewDecoder(strings.NewReader(jsonData))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(u.GetValue(), expected) {
+			t.Errorf("expected %v, got %v", expected, u.GetValue())
+		}
+	})
+
+	t.Run("DecodeAll", func(t *testing.T) {
+		var values []any
+		for u, err := range DecodeAll[MemberShape](strings.NewReader(jsonData + "\n" + jsonData)) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			values = append(values, u.GetValue())
+		}
+		if len(values) != 2 || !reflect.DeepEqual(values[1], expected) {
+			t.Errorf("expected 2 circles, got %v", values)
+		}
+	})
+
+	t.Run("ToMap and FromMap", func(t *testing.T) {
+		u := TaggedUnion[MemberShape]{Value: MemberShape{Circle: expected}}
+		m, err := u.ToMap()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string]any{"type": "Circle", "circle": map[string]any{"radius": int64(5)}}
+		if !reflect.DeepEqual(m, want) {
+			t.Errorf("expected %v, got %v", want, m)
+		}
+
+		var decoded TaggedUnion[MemberShape]
+		if err := decoded.FromMap(m); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(decoded.GetValue(), expected) {
+			t.Errorf("expected %v, got %v", expected, decoded.GetValue())
+		}
+
+		var marshaled map[string]any
+		data, _ := json.Marshal(u)
+		if err := json.Unmarshal(data, &marshaled); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := DecodeMap[MemberShape](marshaled); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestMemberDiscriminatorPeek(t *testing.T) {
+	tests := []struct {
+		name        string
+		jsonData    string
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "reads discriminator",
+			jsonData: `{"circle":{"radius":5},"type":"Circle"}`,
+			expected: "Circle",
+		},
+		{
+			name:     "infers variant from member",
+			jsonData: `{"rectangle":{"width":2,"height":3}}`,
+			expected: "Rectangle",
+		},
+		{
+			name:        "missing discriminator and members",
+			jsonData:    `{}`,
+			expectedErr: ErrMissingVariantField,
+		},
+		{
+			name:        "multiple members",
+			jsonData:    `{"circle":{},"rectangle":{}}`,
+			expectedErr: ErrMultipleVariants,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variant, err := PeekVariant[MemberShape]([]byte(tt.jsonData))
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("expected error '%v', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if variant != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, variant)
+			}
+
+			var l Lazy[MemberShape]
+			if err := json.Unmarshal([]byte(tt.jsonData), &l); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if variant, _ := l.Variant(); variant != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, variant)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package union
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// The MessagePack methods implement the Marshaler and Unmarshaler interfaces of
+// github.com/vmihailenco/msgpack without depending on it. Unions are converted
+// through their JSON representation, so variant payloads use their json struct tags
+// and the same variant rules as MarshalJSON and UnmarshalJSON apply.
+
+// MarshalMsgpack implements the msgpack.Marshaler interface.
+// It serializes the union as a MessagePack map with the same shape as MarshalJSON.
+func (u TaggedUnion[Spec]) MarshalMsgpack() ([]byte, error) {
+	return MarshalWith(u, Options{Format: MessagePack})
+}
+
+// UnmarshalMsgpack implements the msgpack.Unmarshaler interface.
+// It deserializes MessagePack data with the same rules as UnmarshalJSON.
+func (u *TaggedUnion[Spec]) UnmarshalMsgpack(data []byte) error {
+	return UnmarshalWith(data, u, Options{Format: MessagePack})
+}
+
+// MarshalMsgpack implements the msgpack.Marshaler interface.
+// It serializes the union as a MessagePack map with the same shape as MarshalJSON.
+func (u ExternallyTagged[Spec]) MarshalMsgpack() ([]byte, error) {
+	return MarshalWith(u, Options{Format: MessagePack})
+}
+
+// UnmarshalMsgpack implements the msgpack.Unmarshaler interface.
+// It deserializes MessagePack data with the same rules as UnmarshalJSON.
+func (u *ExternallyTagged[Spec]) UnmarshalMsgpack(data []byte) error {
+	return UnmarshalWith(data, u, Options{Format: MessagePack})
+}
+
+// MarshalMsgpack implements the msgpack.Marshaler interface.
+// It serializes the union's active variant data directly as a MessagePack value.
+func (u Union[Spec]) MarshalMsgpack() ([]byte, error) {
+	return MarshalWith(u, Options{Format: MessagePack})
+}
+
+// UnmarshalMsgpack implements the msgpack.Unmarshaler interface.
+// It deserializes MessagePack data with the same rules as UnmarshalJSON.
+func (u *Union[Spec]) UnmarshalMsgpack(data []byte) error {
+	return UnmarshalWith(data, u, Options{Format: MessagePack})
+}
+
+// appendMsgpack appends the MessagePack encoding of a value decoded from JSON.
+func appendMsgpack(b []byte, value any) ([]byte, error) {
+	switch value := value.(type) {
+	case nil:
+		return append(b, 0xc0), nil
+	case bool:
+		if value {
+			return append(b, 0xc3), nil
+		}
+		return append(b, 0xc2), nil
+	case json.Number:
+		if n, err := strconv.ParseInt(string(value), 10, 64); err == nil {
+			return appendMsgpackInt(b, n), nil
+		}
+		if n, err := strconv.ParseUint(string(value), 10, 64); err == nil {
+			return binary.BigEndian.AppendUint64(append(b, 0xcf), n), nil
+		}
+		f, err := value.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return binary.BigEndian.AppendUint64(append(b, 0xcb), math.Float64bits(f)), nil
+	case string:
+		b = appendMsgpackHeader(b, len(value), 0xa0, 31, 0xd9, 0xda, 0xdb)
+		return append(b, value...), nil
+	case []any:
+		b = appendMsgpackHeader(b, len(value), 0x90, 15, 0, 0xdc, 0xdd)
+		for _, elem := range value {
+			var err error
+			if b, err = appendMsgpack(b, elem); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		b = appendMsgpackHeader(b, len(value), 0x80, 15, 0, 0xde, 0xdf)
+		for _, key := range keys {
+			var err error
+			if b, err = appendMsgpack(b, key); err != nil {
+				return nil, err
+			}
+			if b, err = appendMsgpack(b, value[key]); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("msgpack: unsupported type %T", value)
+}
+
+// appendMsgpackInt appends the smallest MessagePack encoding of an integer.
+func appendMsgpackInt(b []byte, n int64) []byte {
+	switch {
+	case n >= 0 && n <= math.MaxInt8:
+		return append(b, byte(n))
+	case n < 0 && n >= -32:
+		return append(b, byte(n))
+	case n >= math.MinInt8 && n <= math.MaxInt8:
+		return append(b, 0xd0, byte(n))
+	case n >= math.MinInt16 && n <= math.MaxInt16:
+		return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(n))
+	case n >= math.MinInt32 && n <= math.MaxInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(n))
+	}
+	return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(n))
+}
+
+// appendMsgpackHeader appends a string, array or map header for n elements,
+// using the fix format when n <= fixMax, then the 8 (if non-zero), 16 and 32 bit formats.
+func appendMsgpackHeader(b []byte, n int, fix byte, fixMax int, code8, code16, code32 byte) []byte {
+	switch {
+	case n <= fixMax:
+		return append(b, fix|byte(n))
+	case code8 != 0 && n <= math.MaxUint8:
+		return append(b, code8, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, code16), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(b, code32), uint32(n))
+}
+
+var (
+	errMsgpackShort = errors.New("msgpack: unexpected end of data")
+	errMsgpackDepth = fmt.Errorf("msgpack: %w: data nested deeper than %d levels", ErrLimitExceeded, maxNestingDepth)
+)
+
+// readMsgpack decodes a single MessagePack value, nested in depth arrays and maps,
+// into a JSON compatible value and returns the remaining data.
+func readMsgpack(b []byte, depth int) (any, []byte, error) {
+	if len(b) == 0 {
+		return nil, nil, errMsgpackShort
+	}
+	c, b := b[0], b[1:]
+
+	switch {
+	case c <= 0x7f:
+		return int64(c), b, nil
+	case c >= 0xe0:
+		return int64(int8(c)), b, nil
+	case c&0xf0 == 0x80:
+		return readMsgpackMap(b, int(c&0x0f), depth+1)
+	case c&0xf0 == 0x90:
+		return readMsgpackArray(b, int(c&0x0f), depth+1)
+	case c&0xe0 == 0xa0:
+		return readMsgpackString(b, int(c&0x1f))
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, b, nil
+	case 0xc2:
+		return false, b, nil
+	case 0xc3:
+		return true, b, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, b, err := readMsgpackLength(b, c-0xc4)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(b) < n {
+			return nil, nil, errMsgpackShort
+		}
+		return slices.Clone(b[:n]), b[n:], nil
+	case 0xca:
+		if len(b) < 4 {
+			return nil, nil, errMsgpackShort
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), b[4:], nil
+	case 0xcb:
+		if len(b) < 8 {
+			return nil, nil, errMsgpackShort
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), b[8:], nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		size := 1 << (c - 0xcc)
+		if len(b) < size {
+			return nil, nil, errMsgpackShort
+		}
+		var n uint64
+		for _, x := range b[:size] {
+			n = n<<8 | uint64(x)
+		}
+		return n, b[size:], nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (c - 0xd0)
+		if len(b) < size {
+			return nil, nil, errMsgpackShort
+		}
+		var n uint64
+		for _, x := range b[:size] {
+			n = n<<8 | uint64(x)
+		}
+		shift := 64 - 8*size
+		return int64(n<<shift) >> shift, b[size:], nil
+	case 0xd9, 0xda, 0xdb:
+		n, b, err := readMsgpackLength(b, c-0xd9)
+		if err != nil {
+			return nil, nil, err
+		}
+		return readMsgpackString(b, n)
+	case 0xdc, 0xdd:
+		n, b, err := readMsgpackLength(b, c-0xdc+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return readMsgpackArray(b, n, depth+1)
+	case 0xde, 0xdf:
+		n, b, err := readMsgpackLength(b, c-0xde+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return readMsgpackMap(b, n, depth+1)
+	}
+	return nil, nil, fmt.Errorf("msgpack: unsupported format 0x%02x", c)
+}
+
+// readMsgpackLength reads a big endian length of 1, 2 or 4 bytes (for sizes 0, 1 and 2).
+func readMsgpackLength(b []byte, size byte) (int, []byte, error) {
+	n := 1 << size
+	if len(b) < n {
+		return 0, nil, errMsgpackShort
+	}
+	var length int
+	for _, x := range b[:n] {
+		length = length<<8 | int(x)
+	}
+	return length, b[n:], nil
+}
+
+func readMsgpackString(b []byte, n int) (any, []byte, error) {
+	if len(b) < n {
+		return nil, nil, errMsgpackShort
+	}
+	return string(b[:n]), b[n:], nil
+}
+
+func readMsgpackArray(b []byte, n, depth int) (any, []byte, error) {
+	if depth > maxNestingDepth {
+		return nil, nil, errMsgpackDepth
+	}
+	// each element takes at least one byte
+	if len(b) < n {
+		return nil, nil, errMsgpackShort
+	}
+	out := make([]any, n)
+	for i := range out {
+		var err error
+		if out[i], b, err = readMsgpack(b, depth); err != nil {
+			return nil, nil, err
+		}
+	}
+	return out, b, nil
+}
+
+func readMsgpackMap(b []byte, n, depth int) (any, []byte, error) {
+	if depth > maxNestingDepth {
+		return nil, nil, errMsgpackDepth
+	}
+	// each entry takes at least two bytes
+	if len(b) < 2*n {
+		return nil, nil, errMsgpackShort
+	}
+	out := make(map[string]any, n)
+	for range n {
+		key, rest, err := readMsgpack(b, depth)
+		if err != nil {
+			return nil, nil, err
+		}
+		s, ok := key.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("msgpack: unsupported map key type %T", key)
+		}
+		if out[s], b, err = readMsgpack(rest, depth); err != nil {
+			return nil, nil, err
+		}
+	}
+	return out, b, nil
+}
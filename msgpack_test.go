@@ -0,0 +1,165 @@
+package union
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMarshalMsgpack(t *testing.T) {
+	tests := []struct {
+		name        string
+		shape       interface{ MarshalMsgpack() ([]byte, error) }
+		expected    string
+		expectedErr error
+	}{
+		{
+			// {"type":"circle","value":{"radius":5}}
+			name:     "marshals tagged union as fixmap",
+			shape:    TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 5}}},
+			expected: "82a474797065a6636972636c65a576616c756581a6726164697573" + "05",
+		},
+		{
+			// {"circle":{"radius":2.5}}
+			name:     "marshals externally tagged union with float payload",
+			shape:    ExternallyTagged[Shape]{Value: Shape{Circle: &Circle{Radius: 2.5}}},
+			expected: "81a6636972636c6581a6726164697573" + "cb4004000000000000",
+		},
+		{
+			// {"base":-8,"height":300}
+			name:     "marshals untagged union with integer payload",
+			shape:    Union[UnionShape]{Value: UnionShape{Triangle: &Triangle{Base: -8, Height: 300}}},
+			expected: "82a462617365f8a6686569676874d1012c",
+		},
+		{
+			name:        "returns error when no variant is set",
+			shape:       TaggedUnion[Shape]{},
+			expectedErr: ErrZeroVariants,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.shape.MarshalMsgpack()
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("expected error '%v', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := hex.EncodeToString(data); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestUnmarshalMsgpack(t *testing.T) {
+	tests := []struct {
+		name  string
+		shape interface {
+			GetValue() any
+			UnmarshalMsgpack([]byte) error
+		}
+		data        string
+		expected    any
+		expectedErr string
+	}{
+		{
+			// {"type":"rectangle","value":{"width":uint8(200),"height":float32(5)}}
+			name:     "unmarshals tagged union",
+			shape:    &TaggedUnion[Shape]{},
+			data:     "82a474797065a972656374616e676c65a576616c756582a57769647468ccc8a6686569676874ca40a00000",
+			expected: Rectangle{Width: 200, Height: 5},
+		},
+		{
+			// {"circle":{"radius":int16(-300)}}
+			name:     "unmarshals externally tagged union",
+			shape:    &ExternallyTagged[Shape]{},
+			data:     "81a6636972636c6581a6726164697573d1fed4",
+			expected: Circle{Radius: -300},
+		},
+		{
+			// {"base":8,"height":4}
+			name:     "unmarshals untagged union",
+			shape:    &Union[UnionShape]{},
+			data:     "82a46261736508a668656967687404",
+			expected: Triangle{Base: 8, Height: 4},
+		},
+		{
+			// {"type":"hexagon","value":{}}
+			name:        "returns error for unknown variant",
+			shape:       &TaggedUnion[Shape]{},
+			data:        "82a474797065a768657861676f6ea576616c756580",
+			expectedErr: "unknown variant: hexagon",
+		},
+		{
+			name:        "returns error for truncated data",
+			shape:       &TaggedUnion[Shape]{},
+			data:        "82a474797065",
+			expectedErr: "msgpack: unexpected end of data",
+		},
+		{
+			name:        "returns error for trailing data",
+			shape:       &Union[UnionShape]{},
+			data:        "80c0",
+			expectedErr: "msgpack: trailing data",
+		},
+		{
+			// {1:2}
+			name:        "returns error for non-string map keys",
+			shape:       &Union[UnionShape]{},
+			data:        "810102",
+			expectedErr: "msgpack: unsupported map key type int64",
+		},
+		{
+			// [[[...nil...]]]
+			name:        "returns error for deeply nested data",
+			shape:       &Union[UnionShape]{},
+			data:        strings.Repeat("91", 100000) + "c0",
+			expectedErr: "msgpack: decode limit exceeded: data nested deeper than 10000 levels",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = tt.shape.UnmarshalMsgpack(data)
+
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("expected error '%s', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertValueEquals(t, tt.shape.GetValue(), tt.expected)
+		})
+	}
+}
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	shape := TaggedUnion[FlatShape]{Value: FlatShape{Triangle: &Triangle{Base: 1.5, Height: 1 << 40}}}
+	data, err := shape.MarshalMsgpack()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded TaggedUnion[FlatShape]
+	if err := decoded.UnmarshalMsgpack(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, decoded.GetValue(), Triangle{Base: 1.5, Height: 1 << 40})
+}
@@ -0,0 +1,84 @@
+package union
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Naming selects how the names of spec fields without a `variant` struct tag
+// are turned into variant names. A Spec type selects it with a VariantNaming() Naming method.
+type Naming int
+
+const (
+	// ExactNaming uses the field name verbatim, such as "HTTPRequest". It is the default.
+	ExactNaming Naming = iota
+	// SnakeCase joins the lowercased words of the field name with underscores, such as "http_request".
+	SnakeCase
+	// CamelCase lowercases the first word of the field name, such as "httpRequest".
+	CamelCase
+	// KebabCase joins the lowercased words of the field name with hyphens, such as "http-request".
+	KebabCase
+	// LowerCase lowercases the field name, such as "httprequest".
+	LowerCase
+)
+
+// Apply returns the variant name of a spec field named field.
+func (n Naming) Apply(field string) string {
+	switch n {
+	case SnakeCase:
+		return strings.ToLower(strings.Join(splitWords(field), "_"))
+	case KebabCase:
+		return strings.ToLower(strings.Join(splitWords(field), "-"))
+	case LowerCase:
+		return strings.ToLower(field)
+	case CamelCase:
+		words := splitWords(field)
+		for i, word := range words {
+			word = strings.ToLower(word)
+			if i > 0 {
+				runes := []rune(word)
+				runes[0] = unicode.ToUpper(runes[0])
+				word = string(runes)
+			}
+			words[i] = word
+		}
+		return strings.Join(words, "")
+	}
+	return field
+}
+
+// splitWords splits a Go identifier into words at case changes and underscores,
+// keeping acronyms and trailing digits together: "HTTPRequest2" is "HTTP" and "Request2".
+func splitWords(name string) []string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+	for i := 0; i <= len(runes); i++ {
+		if i == len(runes) || runes[i] == '_' {
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+		if i == start || !unicode.IsUpper(runes[i]) {
+			continue
+		}
+		prev := runes[i-1]
+		nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return words
+}
+
+// namingOf returns the Naming selected by the spec type t.
+func namingOf(t reflect.Type) Naming {
+	if s, ok := reflect.Zero(t).Interface().(interface{ VariantNaming() Naming }); ok {
+		return s.VariantNaming()
+	}
+	return ExactNaming
+}
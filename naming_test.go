@@ -0,0 +1,63 @@
+package union
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+type SnakeCaseShape struct {
+	Circle      *Circle
+	RoundedRect *Rectangle
+	Triangle    *Triangle `variant:"tri"`
+}
+
+func (s SnakeCaseShape) VariantNaming() Naming { return SnakeCase }
+
+func TestNamingApply(t *testing.T) {
+	tests := []struct {
+		field    string
+		naming   Naming
+		expected string
+	}{
+		{field: "HTTPRequest", naming: ExactNaming, expected: "HTTPRequest"},
+		{field: "HTTPRequest", naming: SnakeCase, expected: "http_request"},
+		{field: "HTTPRequest", naming: CamelCase, expected: "httpRequest"},
+		{field: "HTTPRequest", naming: KebabCase, expected: "http-request"},
+		{field: "HTTPRequest", naming: LowerCase, expected: "httprequest"},
+		{field: "RoundedRect", naming: SnakeCase, expected: "rounded_rect"},
+		{field: "Shape2D", naming: SnakeCase, expected: "shape2_d"},
+		{field: "UserID", naming: CamelCase, expected: "userId"},
+		{field: "Circle", naming: CamelCase, expected: "circle"},
+		{field: "Legacy_Value", naming: KebabCase, expected: "legacy-value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			if got := tt.naming.Apply(tt.field); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestVariantNaming(t *testing.T) {
+	if variants := Variants[SnakeCaseShape](); !slices.Equal(variants, []string{"circle", "rounded_rect", "tri"}) {
+		t.Errorf("expected converted variant names, got %v", variants)
+	}
+
+	shape := TaggedUnion[SnakeCaseShape]{Value: SnakeCaseShape{RoundedRect: &Rectangle{Width: 10, Height: 5}}}
+	data, err := json.Marshal(shape)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `{"type":"rounded_rect","value":{"width":10,"height":5}}`; string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	var decoded TaggedUnion[SnakeCaseShape]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, decoded.GetValue(), Rectangle{Width: 10, Height: 5})
+}
@@ -0,0 +1,133 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type Expr struct {
+	Num *float64              `variant:"num"`
+	Neg *TaggedUnion[Expr]    `variant:"neg"`
+	Add *[2]TaggedUnion[Expr] `variant:"add"`
+}
+
+type NestedShape struct {
+	Shape    TaggedUnion[Shape]         `variant:"shape"`
+	Envelope TaggedUnion[EnvelopeShape] `variant:"envelope"`
+	Name     *string                    `variant:"name"`
+}
+
+type EmbeddedUnionShape struct {
+	TaggedUnion[Shape]
+	Name *string `variant:"name"`
+}
+
+type NestedUntaggedShape struct {
+	Shape Union[UnionShape]
+	Name  *string
+}
+
+func TestNestedUnions(t *testing.T) {
+	t.Run("round trips recursive unions", func(t *testing.T) {
+		input := `{"type":"add","value":[{"type":"num","value":1},{"type":"neg","value":{"type":"num","value":2}}]}`
+		var expr TaggedUnion[Expr]
+		if err := json.Unmarshal([]byte(input), &expr); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		neg := expr.Value.Add[1].Value.Neg
+		if neg == nil || neg.Value.Num == nil || *neg.Value.Num != 2 {
+			t.Fatalf("expected nested neg variant, got %v", expr)
+		}
+
+		data, err := json.Marshal(expr)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(data) != input {
+			t.Errorf("expected %s, got %s", input, data)
+		}
+	})
+
+	t.Run("empty nested union is not active", func(t *testing.T) {
+		var shape TaggedUnion[NestedShape]
+		shape.Value.Envelope.Value.Extras = Extras{"id": json.RawMessage("1")}
+		if !shape.IsZero() {
+			t.Errorf("expected union with an empty nested union to be zero, got %v", shape)
+		}
+
+		shape.Value.Name = new(string)
+		if variant, ok := shape.Variant(); !ok || variant != "name" {
+			t.Errorf("expected variant name, got %q (ok=%v)", variant, ok)
+		}
+	})
+
+	t.Run("selected nested union is active", func(t *testing.T) {
+		var shape TaggedUnion[NestedShape]
+		if err := shape.Value.Shape.Select("circle"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if variant, ok := shape.Variant(); !ok || variant != "shape" {
+			t.Errorf("expected variant shape, got %q (ok=%v)", variant, ok)
+		}
+	})
+
+	t.Run("set rejects empty nested union", func(t *testing.T) {
+		var shape TaggedUnion[NestedShape]
+		if err := Set(&shape, TaggedUnion[Shape]{}); !errors.Is(err, ErrZeroVariants) {
+			t.Errorf("expected ErrZeroVariants, got %v", err)
+		}
+	})
+
+	t.Run("nested marshal errors are wrapped once", func(t *testing.T) {
+		var shape TaggedUnion[NestedShape]
+		shape.Value.Shape.Value.Circle = &Circle{Radius: 1}
+		shape.Value.Shape.Value.Rectangle = &Rectangle{Width: 1}
+		_, err := json.Marshal(shape)
+		if !errors.Is(err, ErrMultipleVariants) {
+			t.Fatalf("expected ErrMultipleVariants, got %v", err)
+		}
+		if n := strings.Count(err.Error(), "error calling MarshalJSON"); n != 1 {
+			t.Errorf("expected a single MarshalJSON wrapper, got %d in %v", n, err)
+		}
+	})
+
+	t.Run("nested decode errors name each variant", func(t *testing.T) {
+		var shape TaggedUnion[NestedShape]
+		err := json.Unmarshal([]byte(`{"type":"shape","value":{"type":"hexagon","value":{}}}`), &shape)
+		if !errors.Is(err, ErrUnknownVariant) {
+			t.Fatalf("expected ErrUnknownVariant, got %v", err)
+		}
+		expected := `variant "shape": unknown variant: hexagon`
+		if err.Error() != expected {
+			t.Errorf("expected %q, got %q", expected, err.Error())
+		}
+	})
+
+	t.Run("embedded union is a variant", func(t *testing.T) {
+		var shape TaggedUnion[EmbeddedUnionShape]
+		if err := json.Unmarshal([]byte(`{"type":"TaggedUnion","value":{"type":"circle","value":{"radius":5}}}`), &shape); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if shape.Value.Value.Circle == nil {
+			t.Errorf("expected embedded union to hold a circle, got %v", shape)
+		}
+	})
+
+	t.Run("untagged nested union", func(t *testing.T) {
+		var shape Union[NestedUntaggedShape]
+		if err := json.Unmarshal([]byte(`{"radius":5}`), &shape); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if variant, ok := shape.Variant(); !ok || variant != "Shape" {
+			t.Errorf("expected variant Shape, got %q (ok=%v)", variant, ok)
+		}
+		if err := json.Unmarshal([]byte(`"circle"`), &shape); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if variant, ok := shape.Variant(); !ok || variant != "Name" {
+			t.Errorf("expected variant Name, got %q (ok=%v)", variant, ok)
+		}
+	})
+}
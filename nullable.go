@@ -0,0 +1,105 @@
+package union
+
+import "encoding/json"
+
+// Nullable holds a value of type T that distinguishes three states, which a plain
+// pointer cannot express together: absent, explicitly null, and set to a value
+// (including the zero value).
+//
+// Its zero value is absent. IsZero reports absent values, so a field with the omitzero
+// struct tag option is omitted while absent and written as null once set to null:
+//
+//	type Patch struct {
+//		Name union.Nullable[string] `json:"name,omitzero"`
+//	}
+//
+//	// {}               -> Name is absent, leave the name unchanged
+//	// {"name": null}   -> Name is null, clear the name
+//	// {"name": ""}     -> Name is set to "", which is a value
+type Nullable[T any] struct {
+	value T
+	state nullableState
+}
+
+// nullableState is the state of a Nullable value, absent by default.
+type nullableState uint8
+
+const (
+	nullableAbsent nullableState = iota
+	nullableNull
+	nullableValue
+)
+
+// NewNullable returns a Nullable set to value.
+func NewNullable[T any](value T) Nullable[T] {
+	return Nullable[T]{value: value, state: nullableValue}
+}
+
+// Null returns a Nullable that is explicitly null.
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{state: nullableNull}
+}
+
+// Get returns the value and reports whether one is set. It reports false if the
+// Nullable is absent or null.
+func (n Nullable[T]) Get() (T, bool) {
+	return n.value, n.state == nullableValue
+}
+
+// IsNull reports whether the Nullable is explicitly null.
+func (n Nullable[T]) IsNull() bool {
+	return n.state == nullableNull
+}
+
+// IsSet reports whether the Nullable is null or set to a value, that is not absent.
+func (n Nullable[T]) IsSet() bool {
+	return n.state != nullableAbsent
+}
+
+// IsZero reports whether the Nullable is absent, which lets the omitzero struct tag
+// option of encoding/json omit it.
+func (n Nullable[T]) IsZero() bool {
+	return n.state == nullableAbsent
+}
+
+// Set sets the Nullable to value.
+func (n *Nullable[T]) Set(value T) {
+	*n = NewNullable(value)
+}
+
+// SetNull makes the Nullable explicitly null.
+func (n *Nullable[T]) SetNull() {
+	*n = Null[T]()
+}
+
+// Unset makes the Nullable absent.
+func (n *Nullable[T]) Unset() {
+	*n = Nullable[T]{}
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// It serializes the value if one is set, and null otherwise. Absent values are
+// only left out of the JSON object by the omitzero struct tag option.
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if n.state != nullableValue {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.value)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// JSON null makes the Nullable null, and any other data is decoded into the value.
+// Members missing from the JSON object leave the Nullable unchanged, which is
+// absent for a new one.
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if isJSONNull(data) {
+		n.SetNull()
+		return nil
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	n.Set(value)
+	return nil
+}
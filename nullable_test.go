@@ -0,0 +1,85 @@
+package union
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type Patch struct {
+	Name  Nullable[string] `json:"name,omitzero"`
+	Count Nullable[int]    `json:"count,omitzero"`
+}
+
+func TestNullableType(t *testing.T) {
+	t.Run("distinguishes absent, null and zero", func(t *testing.T) {
+		var patch Patch
+		if err := json.Unmarshal([]byte(`{"name":null,"count":0}`), &patch); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !patch.Name.IsNull() || !patch.Name.IsSet() {
+			t.Errorf("expected name to be null, got %+v", patch.Name)
+		}
+		if count, ok := patch.Count.Get(); !ok || count != 0 {
+			t.Errorf("expected count 0, got %d (ok=%v)", count, ok)
+		}
+
+		var empty Patch
+		if err := json.Unmarshal([]byte(`{}`), &empty); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if empty.Name.IsSet() || empty.Count.IsSet() {
+			t.Errorf("expected absent fields, got %+v", empty)
+		}
+	})
+
+	t.Run("marshals states", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			patch    Patch
+			expected string
+		}{
+			{name: "absent", patch: Patch{}, expected: `{}`},
+			{name: "null", patch: Patch{Name: Null[string]()}, expected: `{"name":null}`},
+			{name: "zero value", patch: Patch{Count: NewNullable(0)}, expected: `{"count":0}`},
+			{name: "value", patch: Patch{Name: NewNullable("a")}, expected: `{"name":"a"}`},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				data, err := json.Marshal(tt.patch)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if string(data) != tt.expected {
+					t.Errorf("expected %s, got %s", tt.expected, data)
+				}
+			})
+		}
+	})
+
+	t.Run("setters", func(t *testing.T) {
+		var n Nullable[string]
+		n.Set("a")
+		if v, ok := n.Get(); !ok || v != "a" {
+			t.Errorf("expected a, got %q (ok=%v)", v, ok)
+		}
+		n.SetNull()
+		if _, ok := n.Get(); ok || !n.IsNull() {
+			t.Errorf("expected null, got %+v", n)
+		}
+		n.Unset()
+		if !n.IsZero() {
+			t.Errorf("expected absent, got %+v", n)
+		}
+	})
+
+	t.Run("rejects invalid value", func(t *testing.T) {
+		var n Nullable[int]
+		if err := json.Unmarshal([]byte(`"a"`), &n); err == nil {
+			t.Fatal("expected error")
+		}
+		if n.IsSet() {
+			t.Errorf("expected Nullable to be unchanged, got %+v", n)
+		}
+	})
+}
@@ -0,0 +1,462 @@
+package union
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+)
+
+// oneOf holds the active value of a positional union along with its 1-based
+// position among the type parameters, which is 0 while the union is unset.
+type oneOf struct {
+	index int
+	value any
+}
+
+// oneOfAt returns the value held by o if position i is active.
+func oneOfAt[T any](o oneOf, i int) (T, bool) {
+	if o.index != i {
+		var zero T
+		return zero, false
+	}
+	return o.value.(T), true
+}
+
+// marshalOneOf serializes the active value of o directly, like Union.
+func marshalOneOf(o oneOf) ([]byte, error) {
+	if o.index == 0 {
+		return nil, ErrZeroVariants
+	}
+	return marshalPayload(encodingJSON, o.value)
+}
+
+// unmarshalOneOf decodes data with the first of decoders that accepts it, reporting the
+// failed attempts against the union type t like Union does. JSON null decodes to the unset union.
+func unmarshalOneOf(t reflect.Type, data []byte, decoders ...func([]byte) (any, error)) (oneOf, error) {
+	if isJSONNull(data) {
+		return oneOf{}, nil
+	}
+	var attempts []error
+	for i, decode := range decoders {
+		value, err := decode(data)
+		if err != nil {
+			name := "V" + strconv.Itoa(i+1)
+			attempts = append(attempts, &DecodeError{Spec: t, Variant: name, Field: name, Err: err})
+			continue
+		}
+		return oneOf{index: i + 1, value: value}, nil
+	}
+	return oneOf{}, noFieldMatched(attempts)
+}
+
+// decodeOneOf strictly decodes data into a value of type T, rejecting unknown fields.
+func decodeOneOf[T any](data []byte) (any, error) {
+	var value T
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// OneOf2 is an untagged union of the types A and B that needs no spec struct, for
+// quick ad-hoc unions in request and response models. The active type is tracked by
+// position, so zero values and repeated types are held as well.
+//
+// Like Union, the active value is marshaled directly without a wrapper, and unmarshaling
+// strictly decodes the JSON data into each type in order, selecting the first that succeeds.
+// OneOf3 through OneOf8 accept more types.
+//
+//	var id union.OneOf2[int, string]
+//	json.Unmarshal([]byte(`"abc"`), &id)
+//	s, ok := id.V2() // "abc", true
+type OneOf2[A, B any] struct {
+	oneOf
+}
+
+// V1 returns the value of type A and reports whether it is active.
+func (o OneOf2[A, B]) V1() (A, bool) { return oneOfAt[A](o.oneOf, 1) }
+
+// V2 returns the value of type B and reports whether it is active.
+func (o OneOf2[A, B]) V2() (B, bool) { return oneOfAt[B](o.oneOf, 2) }
+
+// SetV1 makes value the active value of the union.
+func (o *OneOf2[A, B]) SetV1(value A) { o.oneOf = oneOf{index: 1, value: value} }
+
+// SetV2 makes value the active value of the union.
+func (o *OneOf2[A, B]) SetV2(value B) { o.oneOf = oneOf{index: 2, value: value} }
+
+// Index returns the 1-based position of the active type, or 0 if the union is unset.
+func (o OneOf2[A, B]) Index() int { return o.index }
+
+// GetValue returns the active value of the union, or nil if it is unset.
+func (o OneOf2[A, B]) GetValue() any { return o.value }
+
+// IsZero reports whether the union is unset, which lets the omitzero struct tag
+// option of encoding/json omit it.
+func (o OneOf2[A, B]) IsZero() bool { return o.index == 0 }
+
+// MarshalJSON implements the json.Marshaler interface.
+// It serializes the active value directly, and returns ErrZeroVariants if the union is unset.
+func (o OneOf2[A, B]) MarshalJSON() ([]byte, error) { return marshalOneOf(o.oneOf) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It decodes data into the first type, in order, that accepts it without unknown fields,
+// and returns ErrNoFieldMatched joined with a *DecodeError for each type otherwise.
+// JSON null decodes to the unset union.
+func (o *OneOf2[A, B]) UnmarshalJSON(data []byte) error {
+	var err error
+	o.oneOf, err = unmarshalOneOf(reflect.TypeFor[OneOf2[A, B]](), data,
+		decodeOneOf[A], decodeOneOf[B])
+	return err
+}
+
+// OneOf3 is an untagged union of 3 types. See OneOf2.
+type OneOf3[A, B, C any] struct {
+	oneOf
+}
+
+// V1 returns the value of type A and reports whether it is active.
+func (o OneOf3[A, B, C]) V1() (A, bool) { return oneOfAt[A](o.oneOf, 1) }
+
+// V2 returns the value of type B and reports whether it is active.
+func (o OneOf3[A, B, C]) V2() (B, bool) { return oneOfAt[B](o.oneOf, 2) }
+
+// V3 returns the value of type C and reports whether it is active.
+func (o OneOf3[A, B, C]) V3() (C, bool) { return oneOfAt[C](o.oneOf, 3) }
+
+// SetV1 makes value the active value of the union.
+func (o *OneOf3[A, B, C]) SetV1(value A) { o.oneOf = oneOf{index: 1, value: value} }
+
+// SetV2 makes value the active value of the union.
+func (o *OneOf3[A, B, C]) SetV2(value B) { o.oneOf = oneOf{index: 2, value: value} }
+
+// SetV3 makes value the active value of the union.
+func (o *OneOf3[A, B, C]) SetV3(value C) { o.oneOf = oneOf{index: 3, value: value} }
+
+// Index returns the 1-based position of the active type, or 0 if the union is unset.
+func (o OneOf3[A, B, C]) Index() int { return o.index }
+
+// GetValue returns the active value of the union, or nil if it is unset.
+func (o OneOf3[A, B, C]) GetValue() any { return o.value }
+
+// IsZero reports whether the union is unset.
+func (o OneOf3[A, B, C]) IsZero() bool { return o.index == 0 }
+
+// MarshalJSON implements the json.Marshaler interface. See OneOf2.MarshalJSON.
+func (o OneOf3[A, B, C]) MarshalJSON() ([]byte, error) { return marshalOneOf(o.oneOf) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface. See OneOf2.UnmarshalJSON.
+func (o *OneOf3[A, B, C]) UnmarshalJSON(data []byte) error {
+	var err error
+	o.oneOf, err = unmarshalOneOf(reflect.TypeFor[OneOf3[A, B, C]](), data,
+		decodeOneOf[A], decodeOneOf[B], decodeOneOf[C])
+	return err
+}
+
+// OneOf4 is an untagged union of 4 types. See OneOf2.
+type OneOf4[A, B, C, D any] struct {
+	oneOf
+}
+
+// V1 returns the value of type A and reports whether it is active.
+func (o OneOf4[A, B, C, D]) V1() (A, bool) { return oneOfAt[A](o.oneOf, 1) }
+
+// V2 returns the value of type B and reports whether it is active.
+func (o OneOf4[A, B, C, D]) V2() (B, bool) { return oneOfAt[B](o.oneOf, 2) }
+
+// V3 returns the value of type C and reports whether it is active.
+func (o OneOf4[A, B, C, D]) V3() (C, bool) { return oneOfAt[C](o.oneOf, 3) }
+
+// V4 returns the value of type D and reports whether it is active.
+func (o OneOf4[A, B, C, D]) V4() (D, bool) { return oneOfAt[D](o.oneOf, 4) }
+
+// SetV1 makes value the active value of the union.
+func (o *OneOf4[A, B, C, D]) SetV1(value A) { o.oneOf = oneOf{index: 1, value: value} }
+
+// SetV2 makes value the active value of the union.
+func (o *OneOf4[A, B, C, D]) SetV2(value B) { o.oneOf = oneOf{index: 2, value: value} }
+
+// SetV3 makes value the active value of the union.
+func (o *OneOf4[A, B, C, D]) SetV3(value C) { o.oneOf = oneOf{index: 3, value: value} }
+
+// SetV4 makes value the active value of the union.
+func (o *OneOf4[A, B, C, D]) SetV4(value D) { o.oneOf = oneOf{index: 4, value: value} }
+
+// Index returns the 1-based position of the active type, or 0 if the union is unset.
+func (o OneOf4[A, B, C, D]) Index() int { return o.index }
+
+// GetValue returns the active value of the union, or nil if it is unset.
+func (o OneOf4[A, B, C, D]) GetValue() any { return o.value }
+
+// IsZero reports whether the union is unset.
+func (o OneOf4[A, B, C, D]) IsZero() bool { return o.index == 0 }
+
+// MarshalJSON implements the json.Marshaler interface. See OneOf2.MarshalJSON.
+func (o OneOf4[A, B, C, D]) MarshalJSON() ([]byte, error) { return marshalOneOf(o.oneOf) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface. See OneOf2.UnmarshalJSON.
+func (o *OneOf4[A, B, C, D]) UnmarshalJSON(data []byte) error {
+	var err error
+	o.oneOf, err = unmarshalOneOf(reflect.TypeFor[OneOf4[A, B, C, D]](), data,
+		decodeOneOf[A], decodeOneOf[B], decodeOneOf[C], decodeOneOf[D])
+	return err
+}
+
+// OneOf5 is an untagged union of 5 types. See OneOf2.
+type OneOf5[A, B, C, D, E any] struct {
+	oneOf
+}
+
+// V1 returns the value of type A and reports whether it is active.
+func (o OneOf5[A, B, C, D, E]) V1() (A, bool) { return oneOfAt[A](o.oneOf, 1) }
+
+// V2 returns the value of type B and reports whether it is active.
+func (o OneOf5[A, B, C, D, E]) V2() (B, bool) { return oneOfAt[B](o.oneOf, 2) }
+
+// V3 returns the value of type C and reports whether it is active.
+func (o OneOf5[A, B, C, D, E]) V3() (C, bool) { return oneOfAt[C](o.oneOf, 3) }
+
+// V4 returns the value of type D and reports whether it is active.
+func (o OneOf5[A, B, C, D, E]) V4() (D, bool) { return oneOfAt[D](o.oneOf, 4) }
+
+// V5 returns the value of type E and reports whether it is active.
+func (o OneOf5[A, B, C, D, E]) V5() (E, bool) { return oneOfAt[E](o.oneOf, 5) }
+
+// SetV1 makes value the active value of the union.
+func (o *OneOf5[A, B, C, D, E]) SetV1(value A) { o.oneOf = oneOf{index: 1, value: value} }
+
+// SetV2 makes value the active value of the union.
+func (o *OneOf5[A, B, C, D, E]) SetV2(value B) { o.oneOf = oneOf{index: 2, value: value} }
+
+// SetV3 makes value the active value of the union.
+func (o *OneOf5[A, B, C, D, E]) SetV3(value C) { o.oneOf = oneOf{index: 3, value: value} }
+
+// SetV4 makes value the active value of the union.
+func (o *OneOf5[A, B, C, D, E]) SetV4(value D) { o.oneOf = oneOf{index: 4, value: value} }
+
+// SetV5 makes value the active value of the union.
+func (o *OneOf5[A, B, C, D, E]) SetV5(value E) { o.oneOf = oneOf{index: 5, value: value} }
+
+// Index returns the 1-based position of the active type, or 0 if the union is unset.
+func (o OneOf5[A, B, C, D, E]) Index() int { return o.index }
+
+// GetValue returns the active value of the union, or nil if it is unset.
+func (o OneOf5[A, B, C, D, E]) GetValue() any { return o.value }
+
+// IsZero reports whether the union is unset.
+func (o OneOf5[A, B, C, D, E]) IsZero() bool { return o.index == 0 }
+
+// MarshalJSON implements the json.Marshaler interface. See OneOf2.MarshalJSON.
+func (o OneOf5[A, B, C, D, E]) MarshalJSON() ([]byte, error) { return marshalOneOf(o.oneOf) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface. See OneOf2.UnmarshalJSON.
+func (o *OneOf5[A, B, C, D, E]) UnmarshalJSON(data []byte) error {
+	var err error
+	o.oneOf, err = unmarshalOneOf(reflect.TypeFor[OneOf5[A, B, C, D, E]](), data,
+		decodeOneOf[A], decodeOneOf[B], decodeOneOf[C], decodeOneOf[D], decodeOneOf[E])
+	return err
+}
+
+// OneOf6 is an untagged union of 6 types. See OneOf2.
+type OneOf6[A, B, C, D, E, F any] struct {
+	oneOf
+}
+
+// V1 returns the value of type A and reports whether it is active.
+func (o OneOf6[A, B, C, D, E, F]) V1() (A, bool) { return oneOfAt[A](o.oneOf, 1) }
+
+// V2 returns the value of type B and reports whether it is active.
+func (o OneOf6[A, B, C, D, E, F]) V2() (B, bool) { return oneOfAt[B](o.oneOf, 2) }
+
+// V3 returns the value of type C and reports whether it is active.
+func (o OneOf6[A, B, C, D, E, F]) V3() (C, bool) { return oneOfAt[C](o.oneOf, 3) }
+
+// V4 returns the value of type D and reports whether it is active.
+func (o OneOf6[A, B, C, D, E, F]) V4() (D, bool) { return oneOfAt[D](o.oneOf, 4) }
+
+// V5 returns the value of type E and reports whether it is active.
+func (o OneOf6[A, B, C, D, E, F]) V5() (E, bool) { return oneOfAt[E](o.oneOf, 5) }
+
+// V6 returns the value of type F and reports whether it is active.
+func (o OneOf6[A, B, C, D, E, F]) V6() (F, bool) { return oneOfAt[F](o.oneOf, 6) }
+
+// SetV1 makes value the active value of the union.
+func (o *OneOf6[A, B, C, D, E, F]) SetV1(value A) { o.oneOf = oneOf{index: 1, value: value} }
+
+// SetV2 makes value the active value of the union.
+func (o *OneOf6[A, B, C, D, E, F]) SetV2(value B) { o.oneOf = oneOf{index: 2, value: value} }
+
+// SetV3 makes value the active value of the union.
+func (o *OneOf6[A, B, C, D, E, F]) SetV3(value C) { o.oneOf = oneOf{index: 3, value: value} }
+
+// SetV4 makes value the active value of the union.
+func (o *OneOf6[A, B, C, D, E, F]) SetV4(value D) { o.oneOf = oneOf{index: 4, value: value} }
+
+// SetV5 makes value the active value of the union.
+func (o *OneOf6[A, B, C, D, E, F]) SetV5(value E) { o.oneOf = oneOf{index: 5, value: value} }
+
+// SetV6 makes value the active value of the union.
+func (o *OneOf6[A, B, C, D, E, F]) SetV6(value F) { o.oneOf = oneOf{index: 6, value: value} }
+
+// Index returns the 1-based position of the active type, or 0 if the union is unset.
+func (o OneOf6[A, B, C, D, E, F]) Index() int { return o.index }
+
+// GetValue returns the active value of the union, or nil if it is unset.
+func (o OneOf6[A, B, C, D, E, F]) GetValue() any { return o.value }
+
+// IsZero reports whether the union is unset.
+func (o OneOf6[A, B, C, D, E, F]) IsZero() bool { return o.index == 0 }
+
+// MarshalJSON implements the json.Marshaler interface. See OneOf2.MarshalJSON.
+func (o OneOf6[A, B, C, D, E, F]) MarshalJSON() ([]byte, error) { return marshalOneOf(o.oneOf) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface. See OneOf2.UnmarshalJSON.
+func (o *OneOf6[A, B, C, D, E, F]) UnmarshalJSON(data []byte) error {
+	var err error
+	o.oneOf, err = unmarshalOneOf(reflect.TypeFor[OneOf6[A, B, C, D, E, F]](), data,
+		decodeOneOf[A], decodeOneOf[B], decodeOneOf[C], decodeOneOf[D], decodeOneOf[E], decodeOneOf[F])
+	return err
+}
+
+// OneOf7 is an untagged union of 7 types. See OneOf2.
+type OneOf7[A, B, C, D, E, F, G any] struct {
+	oneOf
+}
+
+// V1 returns the value of type A and reports whether it is active.
+func (o OneOf7[A, B, C, D, E, F, G]) V1() (A, bool) { return oneOfAt[A](o.oneOf, 1) }
+
+// V2 returns the value of type B and reports whether it is active.
+func (o OneOf7[A, B, C, D, E, F, G]) V2() (B, bool) { return oneOfAt[B](o.oneOf, 2) }
+
+// V3 returns the value of type C and reports whether it is active.
+func (o OneOf7[A, B, C, D, E, F, G]) V3() (C, bool) { return oneOfAt[C](o.oneOf, 3) }
+
+// V4 returns the value of type D and reports whether it is active.
+func (o OneOf7[A, B, C, D, E, F, G]) V4() (D, bool) { return oneOfAt[D](o.oneOf, 4) }
+
+// V5 returns the value of type E and reports whether it is active.
+func (o OneOf7[A, B, C, D, E, F, G]) V5() (E, bool) { return oneOfAt[E](o.oneOf, 5) }
+
+// V6 returns the value of type F and reports whether it is active.
+func (o OneOf7[A, B, C, D, E, F, G]) V6() (F, bool) { return oneOfAt[F](o.oneOf, 6) }
+
+// V7 returns the value of type G and reports whether it is active.
+func (o OneOf7[A, B, C, D, E, F, G]) V7() (G, bool) { return oneOfAt[G](o.oneOf, 7) }
+
+// SetV1 makes value the active value of the union.
+func (o *OneOf7[A, B, C, D, E, F, G]) SetV1(value A) { o.oneOf = oneOf{index: 1, value: value} }
+
+// SetV2 makes value the active value of the union.
+func (o *OneOf7[A, B, C, D, E, F, G]) SetV2(value B) { o.oneOf = oneOf{index: 2, value: value} }
+
+// SetV3 makes value the active value of the union.
+func (o *OneOf7[A, B, C, D, E, F, G]) SetV3(value C) { o.oneOf = oneOf{index: 3, value: value} }
+
+// SetV4 makes value the active value of the union.
+func (o *OneOf7[A, B, C, D, E, F, G]) SetV4(value D) { o.oneOf = oneOf{index: 4, value: value} }
+
+// SetV5 makes value the active value of the union.
+func (o *OneOf7[A, B, C, D, E, F, G]) SetV5(value E) { o.oneOf = oneOf{index: 5, value: value} }
+
+// SetV6 makes value the active value of the union.
+func (o *OneOf7[A, B, C, D, E, F, G]) SetV6(value F) { o.oneOf = oneOf{index: 6, value: value} }
+
+// SetV7 makes value the active value of the union.
+func (o *OneOf7[A, B, C, D, E, F, G]) SetV7(value G) { o.oneOf = oneOf{index: 7, value: value} }
+
+// Index returns the 1-based position of the active type, or 0 if the union is unset.
+func (o OneOf7[A, B, C, D, E, F, G]) Index() int { return o.index }
+
+// GetValue returns the active value of the union, or nil if it is unset.
+func (o OneOf7[A, B, C, D, E, F, G]) GetValue() any { return o.value }
+
+// IsZero reports whether the union is unset.
+func (o OneOf7[A, B, C, D, E, F, G]) IsZero() bool { return o.index == 0 }
+
+// MarshalJSON implements the json.Marshaler interface. See OneOf2.MarshalJSON.
+func (o OneOf7[A, B, C, D, E, F, G]) MarshalJSON() ([]byte, error) { return marshalOneOf(o.oneOf) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface. See OneOf2.UnmarshalJSON.
+func (o *OneOf7[A, B, C, D, E, F, G]) UnmarshalJSON(data []byte) error {
+	var err error
+	o.oneOf, err = unmarshalOneOf(reflect.TypeFor[OneOf7[A, B, C, D, E, F, G]](), data,
+		decodeOneOf[A], decodeOneOf[B], decodeOneOf[C], decodeOneOf[D], decodeOneOf[E], decodeOneOf[F], decodeOneOf[G])
+	return err
+}
+
+// OneOf8 is an untagged union of 8 types. See OneOf2.
+type OneOf8[A, B, C, D, E, F, G, H any] struct {
+	oneOf
+}
+
+// V1 returns the value of type A and reports whether it is active.
+func (o OneOf8[A, B, C, D, E, F, G, H]) V1() (A, bool) { return oneOfAt[A](o.oneOf, 1) }
+
+// V2 returns the value of type B and reports whether it is active.
+func (o OneOf8[A, B, C, D, E, F, G, H]) V2() (B, bool) { return oneOfAt[B](o.oneOf, 2) }
+
+// V3 returns the value of type C and reports whether it is active.
+func (o OneOf8[A, B, C, D, E, F, G, H]) V3() (C, bool) { return oneOfAt[C](o.oneOf, 3) }
+
+// V4 returns the value of type D and reports whether it is active.
+func (o OneOf8[A, B, C, D, E, F, G, H]) V4() (D, bool) { return oneOfAt[D](o.oneOf, 4) }
+
+// V5 returns the value of type E and reports whether it is active.
+func (o OneOf8[A, B, C, D, E, F, G, H]) V5() (E, bool) { return oneOfAt[E](o.oneOf, 5) }
+
+// V6 returns the value of type F and reports whether it is active.
+func (o OneOf8[A, B, C, D, E, F, G, H]) V6() (F, bool) { return oneOfAt[F](o.oneOf, 6) }
+
+// V7 returns the value of type G and reports whether it is active.
+func (o OneOf8[A, B, C, D, E, F, G, H]) V7() (G, bool) { return oneOfAt[G](o.oneOf, 7) }
+
+// V8 returns the value of type H and reports whether it is active.
+func (o OneOf8[A, B, C, D, E, F, G, H]) V8() (H, bool) { return oneOfAt[H](o.oneOf, 8) }
+
+// SetV1 makes value the active value of the union.
+func (o *OneOf8[A, B, C, D, E, F, G, H]) SetV1(value A) { o.oneOf = oneOf{index: 1, value: value} }
+
+// SetV2 makes value the active value of the union.
+func (o *OneOf8[A, B, C, D, E, F, G, H]) SetV2(value B) { o.oneOf = oneOf{index: 2, value: value} }
+
+// SetV3 makes value the active value of the union.
+func (o *OneOf8[A, B, C, D, E, F, G, H]) SetV3(value C) { o.oneOf = oneOf{index: 3, value: value} }
+
+// SetV4 makes value the active value of the union.
+func (o *OneOf8[A, B, C, D, E, F, G, H]) SetV4(value D) { o.oneOf = oneOf{index: 4, value: value} }
+
+// SetV5 makes value the active value of the union.
+func (o *OneOf8[A, B, C, D, E, F, G, H]) SetV5(value E) { o.oneOf = oneOf{index: 5, value: value} }
+
+// SetV6 makes value the active value of the union.
+func (o *OneOf8[A, B, C, D, E, F, G, H]) SetV6(value F) { o.oneOf = oneOf{index: 6, value: value} }
+
+// SetV7 makes value the active value of the union.
+func (o *OneOf8[A, B, C, D, E, F, G, H]) SetV7(value G) { o.oneOf = oneOf{index: 7, value: value} }
+
+// SetV8 makes value the active value of the union.
+func (o *OneOf8[A, B, C, D, E, F, G, H]) SetV8(value H) { o.oneOf = oneOf{index: 8, value: value} }
+
+// Index returns the 1-based position of the active type, or 0 if the union is unset.
+func (o OneOf8[A, B, C, D, E, F, G, H]) Index() int { return o.index }
+
+// GetValue returns the active value of the union, or nil if it is unset.
+func (o OneOf8[A, B, C, D, E, F, G, H]) GetValue() any { return o.value }
+
+// IsZero reports whether the union is unset.
+func (o OneOf8[A, B, C, D, E, F, G, H]) IsZero() bool { return o.index == 0 }
+
+// MarshalJSON implements the json.Marshaler interface. See OneOf2.MarshalJSON.
+func (o OneOf8[A, B, C, D, E, F, G, H]) MarshalJSON() ([]byte, error) { return marshalOneOf(o.oneOf) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface. See OneOf2.UnmarshalJSON.
+func (o *OneOf8[A, B, C, D, E, F, G, H]) UnmarshalJSON(data []byte) error {
+	var err error
+	o.oneOf, err = unmarshalOneOf(reflect.TypeFor[OneOf8[A, B, C, D, E, F, G, H]](), data,
+		decodeOneOf[A], decodeOneOf[B], decodeOneOf[C], decodeOneOf[D], decodeOneOf[E], decodeOneOf[F], decodeOneOf[G], decodeOneOf[H])
+	return err
+}
@@ -0,0 +1,135 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestOneOf(t *testing.T) {
+	t.Run("decodes first matching type", func(t *testing.T) {
+		tests := []struct {
+			input    string
+			index    int
+			expected any
+		}{
+			{input: `5`, index: 1, expected: 5},
+			{input: `"abc"`, index: 2, expected: "abc"},
+			{input: `{"radius":5}`, index: 3, expected: Circle{Radius: 5.0}},
+			{input: `{"width":1,"height":2}`, index: 4, expected: Rectangle{Width: 1, Height: 2}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.input, func(t *testing.T) {
+				var o OneOf4[int, string, Circle, Rectangle]
+				if err := json.Unmarshal([]byte(tt.input), &o); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if o.Index() != tt.index {
+					t.Errorf("expected index %d, got %d", tt.index, o.Index())
+				}
+				if !reflect.DeepEqual(o.GetValue(), tt.expected) {
+					t.Errorf("expected %#v, got %#v", tt.expected, o.GetValue())
+				}
+
+				data, err := json.Marshal(o)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if string(data) != tt.input {
+					t.Errorf("expected %s, got %s", tt.input, data)
+				}
+			})
+		}
+	})
+
+	t.Run("typed accessors", func(t *testing.T) {
+		var o OneOf2[int, string]
+		o.SetV2("abc")
+		if s, ok := o.V2(); !ok || s != "abc" {
+			t.Errorf("expected V2 abc, got %q (ok=%v)", s, ok)
+		}
+		if n, ok := o.V1(); ok || n != 0 {
+			t.Errorf("expected V1 to be inactive, got %d (ok=%v)", n, ok)
+		}
+
+		o.SetV1(0)
+		if n, ok := o.V1(); !ok || n != 0 {
+			t.Errorf("expected zero V1 to be active, got %d (ok=%v)", n, ok)
+		}
+		if o.IsZero() {
+			t.Error("expected union holding a zero value not to be zero")
+		}
+	})
+
+	t.Run("repeated types are positional", func(t *testing.T) {
+		var o OneOf3[string, int, string]
+		o.SetV3("b")
+		if _, ok := o.V1(); ok {
+			t.Error("expected V1 to be inactive")
+		}
+		if s, ok := o.V3(); !ok || s != "b" {
+			t.Errorf("expected V3 b, got %q (ok=%v)", s, ok)
+		}
+	})
+
+	t.Run("unset union", func(t *testing.T) {
+		var o OneOf2[int, string]
+		if !o.IsZero() || o.Index() != 0 || o.GetValue() != nil {
+			t.Errorf("expected unset union, got %+v", o)
+		}
+		if _, err := json.Marshal(o); !errors.Is(err, ErrZeroVariants) {
+			t.Errorf("expected ErrZeroVariants, got %v", err)
+		}
+
+		o.SetV1(5)
+		if err := json.Unmarshal([]byte(`null`), &o); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !o.IsZero() {
+			t.Errorf("expected null to unset the union, got %+v", o)
+		}
+
+		type Request struct {
+			ID OneOf2[int, string] `json:"id,omitzero"`
+		}
+		data, err := json.Marshal(Request{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(data) != `{}` {
+			t.Errorf("expected {}, got %s", data)
+		}
+	})
+
+	t.Run("reports every failed type", func(t *testing.T) {
+		var o OneOf2[int, Circle]
+		o.SetV1(5)
+		err := json.Unmarshal([]byte(`{"radius":5,"color":"red"}`), &o)
+		if !errors.Is(err, ErrNoFieldMatched) {
+			t.Fatalf("expected ErrNoFieldMatched, got %v", err)
+		}
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Variant != "V1" {
+			t.Errorf("expected *DecodeError for V1, got %v", err)
+		}
+		if !o.IsZero() {
+			t.Errorf("expected union to be reset, got %+v", o)
+		}
+	})
+
+	t.Run("largest arity", func(t *testing.T) {
+		var o OneOf8[bool, int, string, []int, map[string]int, Circle, Rectangle, Triangle]
+		if err := json.Unmarshal([]byte(`{"a":1}`), &o); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if m, ok := o.V5(); !ok || m["a"] != 1 {
+			t.Errorf("expected V5 map, got %v (ok=%v)", m, ok)
+		}
+		o.SetV8(Triangle{Base: 1, Height: 2})
+		if _, ok := o.V8(); !ok || o.Index() != 8 {
+			t.Errorf("expected V8 to be active, got index %d", o.Index())
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package union
+
+import "encoding/json"
+
+// Options tune a single MarshalWith or UnmarshalWith call, for callers that can't add
+// methods to the Spec type, such as libraries decoding unions of their users' specs:
+//
+//	err := union.UnmarshalWith(data, &shape, union.Options{Strict: true, UseNumber: true, MaxBytes: 1 << 20})
+//
+// The options add to the methods of the Spec type: Strict and UseNumber turn on strict
+// decoding and exact numbers even if the spec doesn't, and a non-zero limit replaces the
+// spec's or default limit of the same kind. They also apply to unions that are variant
+// payloads themselves, which decode with the same JSON library.
+type Options struct {
+	Format  Format      // format of the data, JSON by default
+	Library JSONLibrary // JSON library encoding and decoding the payload, see JSONLibrary; encoding/json by default
+
+	Strict    bool // reject unknown envelope keys and payload fields, like JSONStrict
+	UseNumber bool // decode numbers in interface values of payloads as json.Number, like JSONUseNumber
+
+	MaxBytes      int // maximum size in bytes of the union's JSON data, see DecodeLimits
+	MaxDepth      int // maximum nesting depth of the union's JSON data, see DecodeLimits
+	MaxValueBytes int // maximum size in bytes of the variant's payload, see DecodeLimits
+}
+
+// MarshalWith returns the encoding of the union u like its MarshalJSON method, tuned by opts.
+// With a Format other than JSON, its JSON representation is decoded into generic values
+// and passed to the format's Marshal method.
+func MarshalWith(u interface {
+	marshalUsing(lib JSONLibrary) ([]byte, error)
+}, opts Options) ([]byte, error) {
+	data, err := u.marshalUsing(opts.library())
+	if err != nil || opts.Format == nil {
+		return data, err
+	}
+	if _, ok := opts.Format.(jsonFormat); ok {
+		return data, nil
+	}
+	value, err := decodeJSONValue(data)
+	if err != nil {
+		return nil, err
+	}
+	return opts.Format.Marshal(value)
+}
+
+// UnmarshalWith decodes data into the union u like its UnmarshalJSON method, tuned by opts.
+// With a Format other than JSON, the generic values decoded by the format's Unmarshal method
+// are unmarshaled through their JSON representation.
+func UnmarshalWith(data []byte, u interface {
+	unmarshalUsing(lib JSONLibrary, data []byte) error
+}, opts Options) error {
+	if _, ok := opts.Format.(jsonFormat); !ok && opts.Format != nil {
+		var value any
+		if err := opts.Format.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		var err error
+		if data, err = json.Marshal(value); err != nil {
+			return err
+		}
+	}
+	return u.unmarshalUsing(opts.library(), data)
+}
+
+// optionsLibrary is the JSONLibrary of a MarshalWith or UnmarshalWith call, carrying
+// its options to the unions it decodes along with the library.
+type optionsLibrary struct {
+	JSONLibrary
+	opts Options
+}
+
+// library returns the JSONLibrary of the call, carrying opts if they tune decoding.
+func (o Options) library() JSONLibrary {
+	lib := o.Library
+	if lib == nil {
+		lib = encodingJSON
+	}
+	if !o.Strict && !o.UseNumber && o.limits() == (DecodeLimits{}) {
+		return lib
+	}
+	return optionsLibrary{JSONLibrary: lib, opts: o}
+}
+
+// limits returns the decode limits set by the options.
+func (o Options) limits() DecodeLimits {
+	return DecodeLimits{MaxBytes: o.MaxBytes, MaxDepth: o.MaxDepth, MaxValueBytes: o.MaxValueBytes}
+}
+
+// callOptions returns the options of the call decoding with lib.
+func callOptions(lib JSONLibrary) Options {
+	if o, ok := lib.(optionsLibrary); ok {
+		return o.opts
+	}
+	return Options{}
+}
@@ -0,0 +1,129 @@
+package union
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type MetadataShape struct {
+	Metadata *Metadata `variant:"metadata"`
+}
+
+func TestUnmarshalWith(t *testing.T) {
+	long := strings.Repeat("x", 150)
+	tags := []any{long}
+	tests := []struct {
+		name        string
+		data        string
+		shape       interface{ GetValue() any }
+		opts        Options
+		expected    any
+		expectedErr string
+	}{
+		{
+			name:     "decodes without options",
+			data:     `{"type":"circle","value":{"radius":5,"color":"red"}}`,
+			shape:    &TaggedUnion[Shape]{},
+			expected: &Circle{Radius: 5},
+		},
+		{
+			name:        "rejects unknown payload fields",
+			data:        `{"type":"circle","value":{"radius":5,"color":"red"}}`,
+			shape:       &TaggedUnion[Shape]{},
+			opts:        Options{Strict: true},
+			expectedErr: `variant "circle": json: unknown field "color"`,
+		},
+		{
+			name:        "rejects unknown envelope keys",
+			data:        `{"type":"circle","value":{"radius":5},"debug":true}`,
+			shape:       &TaggedUnion[Shape]{},
+			opts:        Options{Strict: true},
+			expectedErr: "unknown field: debug",
+		},
+		{
+			name:     "keeps numbers exact",
+			data:     `{"metadata":{"fields":{"id":9007199254740993}}}`,
+			shape:    &ExternallyTagged[MetadataShape]{},
+			opts:     Options{UseNumber: true},
+			expected: &Metadata{Fields: map[string]any{"id": json.Number("9007199254740993")}},
+		},
+		{
+			name:     "keeps untagged numbers exact",
+			data:     `{"fields":{"id":9007199254740993}}`,
+			shape:    &Union[MetadataShape]{},
+			opts:     Options{UseNumber: true},
+			expected: &Metadata{Fields: map[string]any{"id": json.Number("9007199254740993")}},
+		},
+		{
+			name:        "limits data size",
+			data:        `{"type":"circle","value":{"radius":5}}`,
+			shape:       &TaggedUnion[LimitedShape]{},
+			opts:        Options{MaxBytes: 16},
+			expectedErr: "decode limit exceeded: 38 bytes of data, at most 16 allowed",
+		},
+		{
+			name:     "replaces spec limits",
+			data:     `{"type":"tags","value":["` + long + `"]}`,
+			shape:    &TaggedUnion[LimitedShape]{},
+			opts:     Options{MaxBytes: 1 << 20, MaxValueBytes: 1 << 10},
+			expected: &tags,
+		},
+		{
+			name:        "applies to nested unions",
+			data:        `{"type":"shape","value":{"type":"circle","value":{"radius":5,"color":"red"}}}`,
+			shape:       &TaggedUnion[NestedShape]{},
+			opts:        Options{Strict: true},
+			expectedErr: `variant "shape": variant "circle": json: unknown field "color"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := UnmarshalWith([]byte(tt.data), tt.shape.(interface {
+				unmarshalUsing(lib JSONLibrary, data []byte) error
+			}), tt.opts)
+
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("expected error '%s', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := tt.shape.GetValue(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %#v, got %#v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMarshalWith(t *testing.T) {
+	shape := TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 5}}}
+
+	data, err := MarshalWith(shape, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `{"type":"circle","value":{"radius":5}}`; string(data) != expected {
+		t.Errorf("expected %v, got %v", expected, string(data))
+	}
+
+	called := false
+	lib := JSONFuncs{
+		MarshalFunc: func(v any) ([]byte, error) {
+			called = true
+			return json.Marshal(v)
+		},
+		UnmarshalFunc: json.Unmarshal,
+	}
+	if _, err := MarshalWith(shape, Options{Library: lib}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Error("expected payload to be marshaled with the library")
+	}
+}
@@ -0,0 +1,187 @@
+package union
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// discriminatorPaths returns the variant and value paths declared by a
+// JSONDiscriminatorPath() (string, string) method on the Spec type. Paths are
+// dot-separated object keys such as "meta.type", defaulting to "type" and "value".
+// The returned paths are shared and must not be modified.
+func (u *TaggedUnion[Spec]) discriminatorPaths() (variant, value []string, ok bool) {
+	o := &planOf(reflect.TypeFor[Spec]()).json
+	return o.variantPath, o.valuePath, o.hasPaths
+}
+
+// checkPaths reports whether the variant and value paths can be written to the same object.
+func checkPaths(variant, value []string) error {
+	n := min(len(variant), len(value))
+	if slices.Equal(variant[:n], value[:n]) {
+		return fmt.Errorf("variant path %q and value path %q overlap", strings.Join(variant, "."), strings.Join(value, "."))
+	}
+	return nil
+}
+
+// pathNode is an object being built by nestEnvelope, keeping its keys in insertion order.
+type pathNode struct {
+	keys     []string
+	children map[string]*pathNode
+	values   map[string]json.RawMessage
+}
+
+// set stores value at path, creating the intermediate objects.
+func (n *pathNode) set(path []string, value json.RawMessage) {
+	key := path[0]
+	if len(path) == 1 {
+		n.keys = append(n.keys, key)
+		n.values[key] = value
+		return
+	}
+	child, ok := n.children[key]
+	if !ok {
+		child = &pathNode{children: make(map[string]*pathNode), values: make(map[string]json.RawMessage)}
+		n.keys = append(n.keys, key)
+		n.children[key] = child
+	}
+	child.set(path[1:], value)
+}
+
+// write writes the JSON object held by the node.
+func (n *pathNode) write(buf *bytes.Buffer) error {
+	sep := byte('{')
+	for _, key := range n.keys {
+		if child, ok := n.children[key]; ok {
+			var nested bytes.Buffer
+			if err := child.write(&nested); err != nil {
+				return err
+			}
+			if err := writeMember(buf, sep, key, json.RawMessage(nested.Bytes())); err != nil {
+				return err
+			}
+		} else if err := writeMember(buf, sep, key, n.values[key]); err != nil {
+			return err
+		}
+		sep = ','
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// nestEnvelope moves the variant and value fields of a TaggedUnion envelope
+// marshaled with the default field names to the given paths.
+func nestEnvelope(data []byte, variant, value []string) ([]byte, error) {
+	if err := checkPaths(variant, value); err != nil {
+		return nil, err
+	}
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+
+	root := &pathNode{children: make(map[string]*pathNode), values: make(map[string]json.RawMessage)}
+	root.set(variant, envelope["type"])
+	if rawValue, ok := envelope["value"]; ok {
+		root.set(value, rawValue)
+	}
+
+	var buf bytes.Buffer
+	if err := root.write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// flattenEnvelope reads the variant and value fields at the given paths into a
+// TaggedUnion envelope with the default field names. In strict mode objects along
+// the paths must not have keys other than the ones leading to the two fields.
+func flattenEnvelope(data []byte, variant, value []string, strict bool) ([]byte, error) {
+	if strict {
+		if err := checkKeys(data, nil, [][]string{variant, value}); err != nil {
+			return nil, err
+		}
+	}
+
+	rawVariant, ok, err := extractPath(data, variant)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMissingVariantField, strings.Join(variant, "."))
+	}
+	envelope := map[string]json.RawMessage{"type": rawVariant}
+
+	rawValue, ok, err := extractPath(data, value)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		envelope["value"] = rawValue
+	}
+	return json.Marshal(envelope)
+}
+
+// extractPath returns the value at path in the JSON object data, reporting false if it is missing.
+func extractPath(data json.RawMessage, path []string) (json.RawMessage, bool, error) {
+	for _, key := range path {
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return nil, false, err
+		}
+		next, ok := obj[key]
+		if !ok {
+			return nil, false, nil
+		}
+		data = next
+	}
+	return data, true, nil
+}
+
+// checkKeys reports a key of the JSON object data, found at prefix, that doesn't lead to any of the paths.
+func checkKeys(data json.RawMessage, prefix []string, paths [][]string) error {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	for _, key := range slices.Sorted(maps.Keys(obj)) {
+		var rest [][]string
+		known := false
+		for _, path := range paths {
+			if path[0] != key {
+				continue
+			}
+			known = true
+			if len(path) > 1 {
+				rest = append(rest, path[1:])
+			}
+		}
+		if !known {
+			return fmt.Errorf("%w: %s", ErrUnknownField, strings.Join(append(slices.Clip(prefix), key), "."))
+		}
+		if len(rest) > 0 {
+			if err := checkKeys(obj[key], append(slices.Clip(prefix), key), rest); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// atValuePath reports a missing value field and the paths of payload errors at the value
+// path instead of the default field name.
+func atValuePath(err error, value []string) error {
+	if errors.Is(err, ErrMissingValueField) {
+		return fmt.Errorf("%w: %s", ErrMissingValueField, strings.Join(value, "."))
+	}
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) && (decodeErr.Path == "value" || strings.HasPrefix(decodeErr.Path, "value.")) {
+		decodeErr.Path = strings.Join(value, ".") + strings.TrimPrefix(decodeErr.Path, "value")
+	}
+	return err
+}
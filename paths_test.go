@@ -0,0 +1,119 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type WebhookShape struct {
+	Circle    *Circle    `variant:"circle"`
+	Rectangle *Rectangle `variant:"rectangle"`
+}
+
+func (s WebhookShape) JSONDiscriminatorPath() (string, string) { return "meta.type", "payload" }
+
+type SharedPrefixShape struct {
+	Circle *Circle `variant:"circle"`
+}
+
+func (s SharedPrefixShape) JSONDiscriminatorPath() (string, string) {
+	return "data.type", "data.object"
+}
+
+func (s SharedPrefixShape) JSONStrict() bool { return true }
+
+type OverlappingPathShape struct {
+	Circle *Circle `variant:"circle"`
+}
+
+func (s OverlappingPathShape) JSONDiscriminatorPath() (string, string) { return "data", "data.object" }
+
+func TestDiscriminatorPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		shape interface {
+			json.Marshaler
+			json.Unmarshaler
+			GetValue() any
+		}
+		jsonData    string
+		expected    any
+		marshaled   string
+		expectedErr string
+	}{
+		{
+			name:      "reads and writes nested discriminator",
+			shape:     &TaggedUnion[WebhookShape]{},
+			jsonData:  `{"id":"evt_1","meta":{"type":"circle","version":2},"payload":{"radius":5}}`,
+			expected:  Circle{Radius: 5.0},
+			marshaled: `{"meta":{"type":"circle"},"payload":{"radius":5}}`,
+		},
+		{
+			name:      "shares intermediate objects",
+			shape:     &TaggedUnion[SharedPrefixShape]{},
+			jsonData:  `{"data":{"type":"circle","object":{"radius":5}}}`,
+			expected:  Circle{Radius: 5.0},
+			marshaled: `{"data":{"type":"circle","object":{"radius":5}}}`,
+		},
+		{
+			name:        "reports missing variant path",
+			shape:       &TaggedUnion[WebhookShape]{},
+			jsonData:    `{"meta":{},"payload":{"radius":5}}`,
+			expectedErr: "missing variant field: meta.type",
+		},
+		{
+			name:        "reports missing value path",
+			shape:       &TaggedUnion[WebhookShape]{},
+			jsonData:    `{"meta":{"type":"circle"}}`,
+			expectedErr: "missing value field: payload",
+		},
+		{
+			name:        "rejects unknown nested keys in strict mode",
+			shape:       &TaggedUnion[SharedPrefixShape]{},
+			jsonData:    `{"data":{"type":"circle","object":{"radius":5},"previous":{}}}`,
+			expectedErr: "unknown field: data.previous",
+		},
+		{
+			name:        "reports unknown variants",
+			shape:       &TaggedUnion[WebhookShape]{},
+			jsonData:    `{"meta":{"type":"hexagon"},"payload":{}}`,
+			expectedErr: "unknown variant: hexagon",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.shape.UnmarshalJSON([]byte(tt.jsonData))
+
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("expected error '%s', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertValueEquals(t, tt.shape.GetValue(), tt.expected)
+
+			data, err := tt.shape.MarshalJSON()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(data) != tt.marshaled {
+				t.Errorf("expected %s, got %s", tt.marshaled, data)
+			}
+		})
+	}
+
+	err := CheckSpec[OverlappingPathShape]()
+	if !errors.Is(err, ErrInvalidSpec) || !strings.Contains(err.Error(), `variant path "data" and value path "data.object" overlap`) {
+		t.Errorf("expected invalid spec error, got '%v'", err)
+	}
+	if _, err := json.Marshal(TaggedUnion[OverlappingPathShape]{Value: OverlappingPathShape{Circle: &Circle{Radius: 5}}}); err == nil {
+		t.Error("expected error marshaling overlapping paths")
+	}
+}
@@ -0,0 +1,117 @@
+package union
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// PeekVariant returns the variant name held by the JSON data of a TaggedUnion[Spec]
+// without decoding its payload, for routing, metrics or sharding decisions ahead of a full decode.
+// The variant field is located with the spec's JSONDiscriminator or JSONDiscriminatorPath
+// and decoded with its JSONDiscriminatorKind. As MarshalJSON writes the variant field first,
+// reading usually stops there. Specs with a JSONMemberDiscriminator read the discriminator
+// member, or infer the variant from the only member field that is set.
+//
+// The name is returned as written and is not checked against the spec's variants.
+// A bare JSON string is the name of a unit variant, and null data of a JSONNullable spec
+// returns an empty name.
+//
+// Returns an error if:
+//   - The JSON data is malformed or not an object
+//   - The variant field is missing
+//   - The variant field cannot be decoded as the spec's discriminator kind
+func PeekVariant[Spec any](data []byte) (string, error) {
+	var u TaggedUnion[Spec]
+	variant, _, err := u.peekVariant(data)
+	return variant, err
+}
+
+// peekVariant returns the variant name held by the JSON data of the union without
+// decoding its payload. It reports false for null data of JSONNullable specs.
+// The variant field is written first by MarshalJSON, so reading usually stops there.
+func (u *TaggedUnion[Spec]) peekVariant(data []byte) (string, bool, error) {
+	if isJSONNull(data) && isNullable(u.Value) {
+		return "", false, nil
+	}
+	if isJSONString(data) {
+		var variant string
+		if err := json.Unmarshal(data, &variant); err != nil {
+			return "", false, err
+		}
+		return variant, true, nil
+	}
+
+	p := planOf(reflect.TypeFor[Spec]())
+	if discriminator, ok := u.memberDiscriminator(); ok {
+		variant, err := p.peekMembers(data, discriminator)
+		if err != nil {
+			return "", false, err
+		}
+		return variant, true, nil
+	}
+
+	var rawVariant json.RawMessage
+	variantField, _ := u.fieldNames()
+	if p.json.tuple {
+		raw, ok, err := peekTuple(data)
+		if err != nil {
+			return "", false, err
+		}
+		if !ok {
+			return "", false, fmt.Errorf("%w: got 0 elements", ErrTupleLength)
+		}
+		rawVariant = raw
+	} else if variantPath, _, ok := u.discriminatorPaths(); ok {
+		raw, ok, err := extractPath(data, variantPath)
+		if err != nil {
+			return "", false, err
+		}
+		if !ok {
+			return "", false, fmt.Errorf("%w: %s", ErrMissingVariantField, strings.Join(variantPath, "."))
+		}
+		rawVariant = raw
+	} else {
+		raw, ok, err := findMember(data, variantField)
+		if err != nil {
+			return "", false, err
+		}
+		if !ok {
+			return "", false, fmt.Errorf("%w: %s", ErrMissingVariantField, variantField)
+		}
+		rawVariant = raw
+	}
+
+	variant, err := decodeDiscriminator(discriminatorKind(u.Value), rawVariant)
+	if err != nil {
+		return "", false, err
+	}
+	return variant, true, nil
+}
+
+// findMember returns the value of the member named key of the JSON object data,
+// reading the object only up to that member.
+func findMember(data []byte, key string) (json.RawMessage, bool, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		// report malformed and non-object data like json.Unmarshal
+		var obj map[string]json.RawMessage
+		return nil, false, json.Unmarshal(data, &obj)
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, false, err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, false, err
+		}
+		if tok == key {
+			return value, true, nil
+		}
+	}
+	return nil, false, nil
+}
@@ -0,0 +1,41 @@
+package union
+
+import "testing"
+
+func TestPeekVariant(t *testing.T) {
+	tests := []struct {
+		name        string
+		peek        func([]byte) (string, error)
+		jsonData    string
+		expected    string
+		expectedErr string
+	}{
+		{name: "default fields", peek: PeekVariant[Shape], jsonData: `{"type":"circle","value":{"radius":5}}`, expected: "circle"},
+		{name: "variant after value", peek: PeekVariant[Shape], jsonData: `{"value":{"radius":5},"type":"circle"}`, expected: "circle"},
+		{name: "unknown variant", peek: PeekVariant[Shape], jsonData: `{"type":"hexagon","value":{}}`, expected: "hexagon"},
+		{name: "custom fields", peek: PeekVariant[CustomFieldNamesShape], jsonData: `{"kind":"rectangle","data":{}}`, expected: "rectangle"},
+		{name: "flat", peek: PeekVariant[FlatForwardShape], jsonData: `{"kind":"circle","radius":5}`, expected: "circle"},
+		{name: "number discriminator", peek: PeekVariant[NumberTagShape], jsonData: `{"type":2,"value":{}}`, expected: "2"},
+		{name: "unit variant", peek: PeekVariant[UnitShape], jsonData: `"idle"`, expected: "idle"},
+		{name: "missing variant", peek: PeekVariant[Shape], jsonData: `{"value":{}}`, expectedErr: "missing variant field: type"},
+		{name: "invalid variant", peek: PeekVariant[Shape], jsonData: `{"type":1}`, expectedErr: "json: cannot unmarshal number into Go value of type string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variant, err := tt.peek([]byte(tt.jsonData))
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("expected error '%s', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if variant != tt.expected {
+				t.Errorf("expected variant %q, got %q", tt.expected, variant)
+			}
+		})
+	}
+}
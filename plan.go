@@ -0,0 +1,411 @@
+package union
+
+import (
+	"cmp"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// specPlan holds the reflection metadata of a Spec type.
+// It is built once per type and cached, so marshaling and unmarshaling
+// don't walk the struct fields and parse struct tags on every call.
+type specPlan struct {
+	typ      reflect.Type
+	isStruct bool
+	fields   []fieldPlan
+	variants []string
+	order    []int          // indices in fields in Union matching order
+	foldCase bool           // whether variant names are matched case-insensitively
+	raw      int            // index in fields of the Raw field capturing unknown variants, or -1
+	extras   []int          // field index sequence of the Extras field capturing extra envelope members, or nil
+	names    map[string]int // index in fields of the field declaring each variant name or alias, -1 if several do
+	json     jsonOptions
+
+	// variantOf is the VariantOf method of the Spec type mapping discriminators to field names, or nil
+	variantOf func(discriminator string) (fieldName string, ok bool)
+}
+
+// jsonOptions holds the TaggedUnion JSON representation selected by the optional methods
+// of a Spec type. Like CaseInsensitiveVariants, they are read once from its zero value,
+// so marshaling doesn't box the spec value to look them up on every call.
+type jsonOptions struct {
+	variantField  string            // variant field name
+	valueField    string            // value field name, "" for the flat representation
+	kind          DiscriminatorKind // JSON type of the variant field
+	nullable      bool              // whether the empty union is null, from JSONNullable
+	optionalValue bool              // whether zero payloads omit the value field, from JSONOptionalValue
+	members       string            // discriminator of the Kubernetes union representation
+	hasMembers    bool              // whether JSONMemberDiscriminator is declared
+	variantPath   []string          // variant field path from JSONDiscriminatorPath
+	valuePath     []string          // value field path from JSONDiscriminatorPath
+	hasPaths      bool              // whether JSONDiscriminatorPath is declared
+	tuple         bool              // whether the union is a [variant, value] array, from JSONTuple
+	limits        *DecodeLimits     // limits from JSONDecodeLimits, nil if not declared
+}
+
+// fieldPlan holds the reflection metadata of a single variant field.
+type fieldPlan struct {
+	index      []int        // field index sequence in the spec struct, longer for promoted fields
+	name       string       // struct field name
+	variant    string       // variant name from the `variant` struct tag or the field name
+	typ        reflect.Type // field type
+	pointer    bool         // whether the field type is a pointer
+	priority   int          // Union matching priority from the `union` struct tag
+	aliases    []string     // additional names accepted when decoding, from the `variantAliases` struct tag
+	raw        bool         // whether the field captures unknown variants as Raw
+	unit       bool         // whether the field is a unit variant marshaled as a bare string
+	zeroer     bool         // whether the non-pointer field type reports zero values with an IsZero method
+	deprecated bool         // whether the variant is deprecated, from the `deprecated` flag of the `union` struct tag
+}
+
+// plans caches the specPlan of each Spec type.
+var plans sync.Map // map[reflect.Type]*specPlan
+
+// planOf returns the cached plan for the spec type t, building it on first use.
+func planOf(t reflect.Type) *specPlan {
+	if p, ok := plans.Load(t); ok {
+		return p.(*specPlan)
+	}
+	p, _ := plans.LoadOrStore(t, buildPlan(t))
+	return p.(*specPlan)
+}
+
+func buildPlan(t reflect.Type) *specPlan {
+	if t.Kind() != reflect.Struct {
+		return &specPlan{typ: t, json: buildJSONOptions(t)}
+	}
+
+	p := &specPlan{
+		typ:      t,
+		isStruct: true,
+		fields:   make([]fieldPlan, 0, t.NumField()),
+		variants: make([]string, 0, t.NumField()),
+		raw:      -1,
+		json:     buildJSONOptions(t),
+	}
+	if s, ok := reflect.Zero(t).Interface().(interface{ CaseInsensitiveVariants() bool }); ok {
+		p.foldCase = s.CaseInsensitiveVariants()
+	}
+	if s, ok := reflect.Zero(t).Interface().(interface {
+		VariantOf(discriminator string) (string, bool)
+	}); ok {
+		p.variantOf = s.VariantOf
+	}
+	naming := namingOf(t)
+	for _, tf := range specFields(t) {
+		if skipField(tf) {
+			continue
+		}
+		if tf.Type == extrasType {
+			// multiple Extras fields are reported by CheckSpec, the first one captures
+			if p.extras == nil {
+				p.extras = tf.Index
+			}
+			continue
+		}
+		// malformed options are reported by CheckSpec
+		opts, _ := parseFieldOptions(tf)
+		f := fieldPlan{
+			index:      tf.Index,
+			name:       tf.Name,
+			variant:    variantName(tf, naming),
+			typ:        tf.Type,
+			pointer:    tf.Type.Kind() == reflect.Pointer,
+			priority:   opts.priority,
+			aliases:    variantAliases(tf),
+			raw:        isRawType(tf.Type),
+			unit:       opts.unit,
+			zeroer:     isZeroer(tf.Type),
+			deprecated: opts.deprecated,
+		}
+		p.fields = append(p.fields, f)
+		if f.raw {
+			// multiple raw fields are reported by CheckSpec, the first one captures
+			if p.raw < 0 {
+				p.raw = len(p.fields) - 1
+			}
+			continue
+		}
+		p.variants = append(p.variants, f.variant)
+		p.order = append(p.order, len(p.fields)-1)
+	}
+	p.names = indexNames(p.fields)
+	// higher priorities first, keeping declaration order between equal priorities
+	slices.SortStableFunc(p.order, func(a, b int) int {
+		return cmp.Compare(p.fields[b].priority, p.fields[a].priority)
+	})
+	return p
+}
+
+// indexNames maps the variant names and aliases of the non-raw fields to their index in fields,
+// or to -1 for names declared by several fields.
+func indexNames(fields []fieldPlan) map[string]int {
+	names := make(map[string]int, len(fields))
+	for i, f := range fields {
+		if f.raw {
+			continue
+		}
+		for _, name := range append([]string{f.variant}, f.aliases...) {
+			if j, ok := names[name]; ok && j != i {
+				names[name] = -1
+				continue
+			}
+			names[name] = i
+		}
+	}
+	return names
+}
+
+// buildJSONOptions reads the JSON options of the Spec type t from its zero value.
+func buildJSONOptions(t reflect.Type) jsonOptions {
+	spec := reflect.Zero(t).Interface()
+	o := jsonOptions{
+		kind:          discriminatorKind(spec),
+		nullable:      isNullable(spec),
+		optionalValue: hasOptionalValue(spec),
+		tuple:         isTuple(spec),
+	}
+	o.variantField, o.valueField = jsonFieldNames(spec)
+	if s, ok := spec.(interface{ JSONMemberDiscriminator() string }); ok {
+		o.members, o.hasMembers = cmp.Or(s.JSONMemberDiscriminator(), "type"), true
+	}
+	if s, ok := spec.(interface{ JSONDiscriminatorPath() (string, string) }); ok {
+		variantPath, valuePath := s.JSONDiscriminatorPath()
+		o.variantPath = strings.Split(cmp.Or(variantPath, "type"), ".")
+		o.valuePath = strings.Split(cmp.Or(valuePath, "value"), ".")
+		o.hasPaths = true
+	}
+	if s, ok := spec.(interface{ JSONDecodeLimits() DecodeLimits }); ok {
+		limits := s.JSONDecodeLimits()
+		o.limits = &limits
+	}
+	return o
+}
+
+// specFields returns the fields of the spec struct type t in declaration order,
+// with the fields of embedded variant groups promoted in place of the group.
+// The Index of each field is its index sequence in t.
+func specFields(t reflect.Type) []reflect.StructField {
+	fields := make([]reflect.StructField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		tf := t.Field(i)
+		if isGroup(tf) {
+			for _, promoted := range specFields(tf.Type) {
+				promoted.Index = append([]int{i}, promoted.Index...)
+				fields = append(fields, promoted)
+			}
+			continue
+		}
+		fields = append(fields, tf)
+	}
+	return fields
+}
+
+// isGroup reports whether a spec struct field is an embedded variant group, a
+// non-pointer struct embedded without a `variant` struct tag whose fields are
+// variants of the spec. Embedded pointers and tagged embedded fields are variants.
+func isGroup(tf reflect.StructField) bool {
+	_, tagged := tf.Tag.Lookup("variant")
+	return tf.Anonymous && !tagged && tf.Type.Kind() == reflect.Struct && tf.Type != rawType &&
+		!reflect.PointerTo(tf.Type).Implements(unionType)
+}
+
+// unionType is implemented by pointers to the union types of this package, which
+// are variants rather than groups when embedded in a spec.
+var unionType = reflect.TypeFor[interface{ specValue() reflect.Value }]()
+
+// zeroerType is implemented by types reporting their zero values with an IsZero method.
+var zeroerType = reflect.TypeFor[interface{ IsZero() bool }]()
+
+// isZeroer reports whether values of type t report whether they are zero with an IsZero
+// method, like nested unions, Nullable and time.Time. Pointers are zero only when nil.
+func isZeroer(t reflect.Type) bool {
+	return t.Kind() != reflect.Pointer && t.Kind() != reflect.Interface && t.Implements(zeroerType)
+}
+
+// isZeroValue reports whether v is zero. Like the omitzero option of encoding/json, types
+// with an IsZero method decide for themselves, so a nested union is zero while it is empty.
+func isZeroValue(v reflect.Value) bool {
+	if isZeroer(v.Type()) && v.CanInterface() {
+		return v.Interface().(interface{ IsZero() bool }).IsZero()
+	}
+	return v.IsZero()
+}
+
+// isZero reports whether the value fv of the field is zero, see isZeroValue.
+func (f *fieldPlan) isZero(fv reflect.Value) bool {
+	if f.zeroer && fv.CanInterface() {
+		return fv.Interface().(interface{ IsZero() bool }).IsZero()
+	}
+	return fv.IsZero()
+}
+
+// skipField reports whether a spec struct field is not a variant, because it is
+// unexported or tagged `variant:"-"`. Such fields can hold helper data.
+func skipField(tf reflect.StructField) bool {
+	return !tf.IsExported() || tf.Tag.Get("variant") == "-"
+}
+
+// clear zeroes the variant fields of the spec struct value v, leaving skipped fields unchanged.
+func (p *specPlan) clear(v reflect.Value) {
+	for _, f := range p.fields {
+		v.FieldByIndex(f.index).SetZero()
+	}
+}
+
+// active returns the single non-zero field of the spec struct value v.
+//
+// Returns an error if:
+//   - The Spec type is not a struct
+//   - No fields are set (zero state)
+//   - Multiple fields are set (invalid state)
+func (p *specPlan) active(v reflect.Value) (*fieldPlan, error) {
+	if !p.isStruct {
+		return nil, ErrSpecNotStruct
+	}
+
+	var active *fieldPlan
+	for i := range p.fields {
+		f := &p.fields[i]
+		if f.isZero(v.FieldByIndex(f.index)) {
+			continue
+		}
+		if active != nil {
+			// invariant violation: multiple variants set
+			return nil, ErrMultipleVariants
+		}
+		active = f
+	}
+	if active == nil {
+		return nil, ErrZeroVariants
+	}
+	return active, nil
+}
+
+// current is like active, but returns the selected field instead of ErrZeroVariants
+// when no field of v is set. A non-zero field always takes precedence over the selection,
+// which is also ignored once its field no longer holds its zero payload, such as a pointer
+// field reset to nil.
+func (p *specPlan) current(v reflect.Value, selected *fieldPlan) (*fieldPlan, error) {
+	f, err := p.active(v)
+	if selected != nil && errors.Is(err, ErrZeroVariants) && !selected.nonZeroPayload() {
+		return selected, nil
+	}
+	return f, err
+}
+
+// nonZeroPayload reports whether the zero payload Select stores in the field is not a
+// zero value, like the pointer of a pointer field, so the field is active on its own.
+// Payloads with an IsZero method may still report themselves as zero.
+func (f *fieldPlan) nonZeroPayload() bool {
+	return !f.zeroer && (f.pointer || f.typ.Kind() == reflect.Slice || f.typ.Kind() == reflect.Map)
+}
+
+// selection returns the field f to keep selected after decoding its value into v,
+// which is only needed when the decoded payload is zero.
+func selection(v reflect.Value, f *fieldPlan) *fieldPlan {
+	if f.isZero(v.FieldByIndex(f.index)) {
+		return f
+	}
+	return nil
+}
+
+// lookup returns the field declaring the variant name or alias. If the Spec type returns true
+// from a CaseInsensitiveVariants() bool method and no field declares the exact name,
+// the field whose variant name matches under Unicode case folding is returned.
+//
+// If the Spec type has a VariantOf(discriminator string) (fieldName string, ok bool) method,
+// it is consulted first, and the variant is the field it names when it reports true.
+// Names it reports false for are looked up among the variant names and aliases.
+//
+// Returns an error if:
+//   - No field declares the variant (*UnknownVariantError)
+//   - VariantOf names a field that doesn't declare a variant (*UnknownVariantError)
+//   - Multiple fields declare the variant (invalid Spec definition)
+func (p *specPlan) lookup(variant string) (*fieldPlan, error) {
+	if p.variantOf != nil {
+		if name, ok := p.variantOf(variant); ok {
+			i := slices.IndexFunc(p.fields, func(f fieldPlan) bool { return f.name == name && !f.raw })
+			if i < 0 {
+				return nil, &UnknownVariantError{Spec: p.typ, Variant: variant, Known: p.knownVariants()}
+			}
+			return &p.fields[i], nil
+		}
+	}
+	if i, ok := p.names[variant]; ok {
+		if i < 0 {
+			return nil, ErrMultipleFieldsMatched
+		}
+		return &p.fields[i], nil
+	}
+	if p.foldCase {
+		matched, err := p.find(func(name string) bool { return strings.EqualFold(name, variant) })
+		if err != nil {
+			return nil, err
+		}
+		if matched != nil {
+			return matched, nil
+		}
+	}
+	return nil, &UnknownVariantError{Spec: p.typ, Variant: variant, Known: p.knownVariants()}
+}
+
+// find returns the only field whose variant name or alias satisfies match, or nil if there is none.
+// Raw fields never match.
+func (p *specPlan) find(match func(name string) bool) (*fieldPlan, error) {
+	var matched *fieldPlan
+	for i := range p.fields {
+		f := &p.fields[i]
+		if f.raw || !match(f.variant) && !slices.ContainsFunc(f.aliases, match) {
+			continue
+		}
+		if matched != nil {
+			return nil, ErrMultipleFieldsMatched
+		}
+		matched = f
+	}
+	return matched, nil
+}
+
+// match returns the field that can hold a value of type vt.
+// Fields of exactly type vt take precedence over pointer or non-pointer equivalents.
+func (p *specPlan) match(vt reflect.Type) (*fieldPlan, error) {
+	for _, exact := range []bool{true, false} {
+		var matched *fieldPlan
+		for i := range p.fields {
+			f := &p.fields[i]
+
+			ok := f.typ == vt
+			if !exact {
+				ok = f.accepts(vt)
+			}
+			if !ok {
+				continue
+			}
+			if matched != nil {
+				return nil, ErrMultipleFieldsMatched
+			}
+			matched = f
+		}
+		if matched != nil {
+			return matched, nil
+		}
+	}
+	return nil, ErrNoFieldMatched
+}
+
+// accepts reports whether the field can hold a value of type vt,
+// either directly or as its pointer or non-pointer equivalent.
+func (f *fieldPlan) accepts(vt reflect.Type) bool {
+	return f.typ == vt ||
+		(f.pointer && f.typ.Elem() == vt) ||
+		(vt.Kind() == reflect.Pointer && vt.Elem() == f.typ)
+}
+
+// knownVariants returns a copy of the variant names declared by the spec.
+func (p *specPlan) knownVariants() []string {
+	return slices.Clone(p.variants)
+}
@@ -0,0 +1,102 @@
+package union
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPlanOf(t *testing.T) {
+	p := planOf(reflect.TypeFor[NonPointerShape]())
+	if p != planOf(reflect.TypeFor[NonPointerShape]()) {
+		t.Error("expected plan to be cached")
+	}
+	if !p.isStruct {
+		t.Fatal("expected struct plan")
+	}
+
+	expected := []fieldPlan{
+		{index: []int{0}, name: "Circle", variant: "circle", typ: reflect.TypeFor[Circle]()},
+		{index: []int{1}, name: "Rectangle", variant: "rectangle", typ: reflect.TypeFor[Rectangle]()},
+	}
+	if !reflect.DeepEqual(p.fields, expected) {
+		t.Errorf("expected fields %+v, got %+v", expected, p.fields)
+	}
+
+	if p := planOf(reflect.TypeFor[Shape]()); !p.fields[0].pointer {
+		t.Error("expected pointer field")
+	}
+	if p := planOf(reflect.TypeFor[NonStructType]()); p.isStruct {
+		t.Error("expected non-struct plan")
+	}
+}
+
+func TestPlanLookup(t *testing.T) {
+	tests := []struct {
+		name     string
+		plan     *specPlan
+		variant  string
+		expected string
+		err      error
+	}{
+		{"variant", planOf(reflect.TypeFor[Shape]()), "triangle", "Triangle", nil},
+		{"alias", planOf(reflect.TypeFor[DuplicateAliasShape]()), "round", "Circle", nil},
+		{"unknown", planOf(reflect.TypeFor[Shape]()), "hexagon", "", ErrUnknownVariant},
+		{"alias shared with variant", planOf(reflect.TypeFor[DuplicateAliasShape]()), "circle", "", ErrMultipleFieldsMatched},
+		{"duplicate variant", planOf(reflect.TypeFor[DuplicateVariantShape]()), "circle", "", ErrMultipleFieldsMatched},
+		{"non-struct", planOf(reflect.TypeFor[NonStructType]()), "circle", "", ErrUnknownVariant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := tt.plan.lookup(tt.variant)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if err == nil && f.name != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, f.name)
+			}
+		})
+	}
+}
+
+// ManyVariantsShape has enough variants for the lookup of the last one to dominate a linear scan.
+type ManyVariantsShape struct {
+	V00, V01, V02, V03, V04, V05, V06, V07, V08, V09 *Circle
+	V10, V11, V12, V13, V14, V15, V16, V17, V18, V19 *Circle
+	V20, V21, V22, V23, V24, V25, V26, V27, V28, V29 *Circle
+	V30, V31, V32, V33, V34, V35, V36, V37, V38, V39 *Circle
+	V40, V41, V42, V43, V44, V45, V46, V47, V48, V49 *Circle
+}
+
+func BenchmarkPlanLookup(b *testing.B) {
+	p := planOf(reflect.TypeFor[ManyVariantsShape]())
+	for _, variant := range []string{"V00", "V49"} {
+		b.Run(variant, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := p.lookup(variant); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkTaggedUnionUnmarshalJSON(b *testing.B) {
+	data := []byte(`{"type":"triangle","value":{"base":8,"height":4}}`)
+	for b.Loop() {
+		var shape TaggedUnion[Shape]
+		if err := shape.UnmarshalJSON(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTaggedUnionMarshalJSON(b *testing.B) {
+	shape := TaggedUnion[Shape]{Value: Shape{Triangle: &Triangle{Base: 8, Height: 4}}}
+	for b.Loop() {
+		if _, err := shape.MarshalJSON(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package union
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+// BindQuery sets the union from the query parameters of the request, for GET endpoints
+// taking polymorphic filters such as /shapes?kind=circle&radius=5. The variant name is read
+// from the param query parameter and the remaining parameters are the fields of the
+// variant's payload, with nested fields keyed like DecodeForm does with DotKeys.
+//
+// Returns an error if:
+//   - The Spec type is not a struct
+//   - The param query parameter is missing (ErrMissingVariantField)
+//   - The variant doesn't match any known variant (*UnknownVariantError)
+//   - The parameters cannot be decoded into the variant's type (*DecodeError)
+//
+// Like UnmarshalJSON, a Spec type returning true from JSONStrict rejects parameters
+// that are not fields of the variant.
+func BindQuery[Spec any](r *http.Request, param string, u *TaggedUnion[Spec]) error {
+	var zero Spec
+	u.Value = zero
+	u.selected = nil
+
+	v := reflect.ValueOf(&u.Value).Elem()
+	p := planOf(v.Type())
+	if !p.isStruct {
+		return ErrSpecNotStruct
+	}
+
+	query := r.URL.Query()
+	variants := query[param]
+	if len(variants) == 0 {
+		return fmt.Errorf("%w: %s", ErrMissingVariantField, param)
+	}
+	f, err := p.resolve(variants[0])
+	if err != nil {
+		return err
+	}
+	delete(query, param)
+
+	tree, err := formTree(query, DotKeys)
+	if err != nil {
+		return err
+	}
+	target := reflect.New(f.typ)
+	if err := fromGeneric(formValue(tree, f.typ), target.Elem(), isStrict(u.Value)); err != nil {
+		return &DecodeError{Spec: p.typ, Variant: f.variant, Field: f.name, Err: err}
+	}
+	if err := afterUnmarshal(target); err != nil {
+		return &DecodeError{Spec: p.typ, Variant: f.variant, Field: f.name, Err: err}
+	}
+	v.FieldByIndex(f.index).Set(target.Elem())
+	u.selected = selection(v, f)
+	return nil
+}
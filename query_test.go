@@ -0,0 +1,74 @@
+package union
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestBindQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected any
+		err      error
+	}{
+		{
+			name:     "binds payload fields",
+			url:      "/shapes?kind=filter&q=go&limit=5&tags=a&tags=b&owner.radius=2",
+			expected: &FormFilter{Query: "go", Limit: 5, Tags: []string{"a", "b"}, Owner: &Circle{Radius: 2}},
+		},
+		{
+			name:     "binds variant without parameters",
+			url:      "/shapes?kind=circle",
+			expected: &Circle{},
+		},
+		{
+			name: "missing variant parameter",
+			url:  "/shapes?radius=5",
+			err:  ErrMissingVariantField,
+		},
+		{
+			name: "unknown variant",
+			url:  "/shapes?kind=hexagon",
+			err:  ErrUnknownVariant,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var u TaggedUnion[FormShape]
+			err := BindQuery(httptest.NewRequest("GET", tt.url, nil), "kind", &u)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("expected error %v, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(u.GetValue(), tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, u.GetValue())
+			}
+		})
+	}
+
+	t.Run("invalid parameter", func(t *testing.T) {
+		var u TaggedUnion[FormShape]
+		err := BindQuery(httptest.NewRequest("GET", "/shapes?kind=filter&limit=many", nil), "kind", &u)
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Variant != "filter" {
+			t.Errorf("expected DecodeError for filter, got %v", err)
+		}
+	})
+
+	t.Run("strict specs reject unknown parameters", func(t *testing.T) {
+		var u TaggedUnion[StrictShape]
+		err := BindQuery(httptest.NewRequest("GET", "/shapes?kind=circle&sides=3", nil), "kind", &u)
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) {
+			t.Errorf("expected DecodeError, got %v", err)
+		}
+	})
+}
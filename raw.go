@@ -0,0 +1,108 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+)
+
+// Raw holds a variant unknown to the Spec struct, as it was read from the JSON data.
+//
+// A spec field of type Raw (or *Raw) captures unknown variants when unmarshaling
+// a TaggedUnion or ExternallyTagged union instead of failing with an *UnknownVariantError,
+// and re-emits them unchanged when marshaling, so data written by newer versions of
+// a schema survives a round trip through older code:
+//
+//	type Shape struct {
+//		Circle  *Circle    `variant:"circle"`
+//		Unknown *union.Raw `variant:"unknown"`
+//	}
+//
+// The raw field never matches a variant name of its own and is not listed by Variants.
+// Union never decodes into it.
+type Raw struct {
+	Variant string          // Variant name read from the JSON data
+	Value   json.RawMessage // Variant's data, the remaining object members in the flat representation
+}
+
+var rawType = reflect.TypeFor[Raw]()
+
+// isRawType reports whether t is Raw or a pointer to Raw.
+func isRawType(t reflect.Type) bool {
+	return indirect(t) == rawType
+}
+
+// variantValue returns the variant name and value to marshal for the active field f of the spec value v.
+// A raw field yields the captured variant name and data, and an interface field the
+// name of its registered implementation.
+func variantValue(v reflect.Value, f *fieldPlan) (string, any) {
+	fv := v.FieldByIndex(f.index)
+	if !f.raw {
+		return f.variantOf(fv), fv.Interface()
+	}
+	for fv.Kind() == reflect.Pointer {
+		fv = fv.Elem()
+	}
+	r := fv.Interface().(Raw)
+	return r.Variant, r.Value
+}
+
+// marshalPayload returns the JSON encoding of a variant payload with lib. json.RawMessage
+// payloads, including the data of Raw fields, are returned verbatim instead of being re-encoded,
+// keeping their formatting. Empty raw payloads, such as a selected zero json.RawMessage, are null.
+// Other payloads are prepared by their BeforeUnionMarshal method first.
+func marshalPayload(lib JSONLibrary, value any) ([]byte, error) {
+	raw, ok := value.(json.RawMessage)
+	if p, isPtr := value.(*json.RawMessage); isPtr && p != nil {
+		raw, ok = *p, true
+	}
+	if !ok {
+		if u, ok := value.(jsonUnion); ok && !isNilPointer(value) {
+			// nested unions are marshaled directly, so their errors are not wrapped
+			// in a *json.MarshalerError per level, and with the same library
+			return u.marshalUsing(lib)
+		}
+		value, err := beforeMarshal(value)
+		if err != nil {
+			return nil, err
+		}
+		return lib.Marshal(value)
+	}
+	if len(raw) == 0 {
+		return []byte("null"), nil
+	}
+	if !json.Valid(raw) {
+		return nil, errInvalidRawPayload
+	}
+	return raw, nil
+}
+
+// jsonUnion is implemented by the union types of this package, and by pointers to them.
+type jsonUnion interface {
+	marshalUsing(lib JSONLibrary) ([]byte, error)
+}
+
+// isNilPointer reports whether value is a nil pointer.
+func isNilPointer(value any) bool {
+	v := reflect.ValueOf(value)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+// errInvalidRawPayload is returned when marshaling a json.RawMessage payload that is not valid JSON.
+var errInvalidRawPayload = errors.New("raw payload is not valid JSON")
+
+// setRaw stores an unknown variant in the spec's raw field, reporting false if it has none.
+func (p *specPlan) setRaw(v reflect.Value, variant string, data json.RawMessage) bool {
+	if p.raw < 0 {
+		return false
+	}
+	f := &p.fields[p.raw]
+	r := reflect.ValueOf(Raw{Variant: variant, Value: append(json.RawMessage(nil), data...)})
+	if f.pointer {
+		ptr := reflect.New(rawType)
+		ptr.Elem().Set(r)
+		r = ptr
+	}
+	v.FieldByIndex(f.index).Set(r)
+	return true
+}
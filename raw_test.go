@@ -0,0 +1,225 @@
+package union
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+type ForwardShape struct {
+	Circle  *Circle `json:"circle" variant:"circle"`
+	Unknown *Raw    `variant:"unknown"`
+}
+
+type FlatForwardShape struct {
+	Circle  *Circle `variant:"circle"`
+	Unknown Raw
+}
+
+func (s FlatForwardShape) JSONDiscriminator() string { return "kind" }
+
+type MultipleRawShape struct {
+	Circle *Circle `variant:"circle"`
+	Old    *Raw
+	New    *Raw
+}
+
+func TestRawRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		shape interface {
+			json.Marshaler
+			json.Unmarshaler
+			GetValue() any
+		}
+		jsonData string
+		expected any
+	}{
+		{
+			name:     "captures unknown tagged variant",
+			shape:    &TaggedUnion[ForwardShape]{},
+			jsonData: `{"type":"hexagon","value":{"sides":6}}`,
+			expected: Raw{Variant: "hexagon", Value: json.RawMessage(`{"sides":6}`)},
+		},
+		{
+			name:     "captures unknown flat variant",
+			shape:    &TaggedUnion[FlatForwardShape]{},
+			jsonData: `{"kind":"hexagon","sides":6}`,
+			expected: Raw{Variant: "hexagon", Value: json.RawMessage(`{"sides":6}`)},
+		},
+		{
+			name:     "captures unknown externally tagged variant",
+			shape:    &ExternallyTagged[ForwardShape]{},
+			jsonData: `{"hexagon":{"sides":6}}`,
+			expected: Raw{Variant: "hexagon", Value: json.RawMessage(`{"sides":6}`)},
+		},
+		{
+			name:     "decodes known variants as usual",
+			shape:    &TaggedUnion[ForwardShape]{},
+			jsonData: `{"type":"circle","value":{"radius":5}}`,
+			expected: Circle{Radius: 5.0},
+		},
+		{
+			name:     "does not match the raw field's own variant",
+			shape:    &TaggedUnion[ForwardShape]{},
+			jsonData: `{"type":"unknown","value":[1,2]}`,
+			expected: Raw{Variant: "unknown", Value: json.RawMessage(`[1,2]`)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.shape.UnmarshalJSON([]byte(tt.jsonData)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertRawEquals(t, tt.shape.GetValue(), tt.expected)
+
+			data, err := tt.shape.MarshalJSON()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(data) != tt.jsonData {
+				t.Errorf("expected %s, got %s", tt.jsonData, data)
+			}
+		})
+	}
+}
+
+func TestRawVariant(t *testing.T) {
+	tests := []struct {
+		name  string
+		shape interface {
+			json.Unmarshaler
+			Variant() (string, bool)
+		}
+		jsonData string
+	}{
+		{
+			name:     "tagged union",
+			shape:    &TaggedUnion[FlatForwardShape]{},
+			jsonData: `{"kind":"hexagon","sides":6}`,
+		},
+		{
+			name:     "externally tagged",
+			shape:    &ExternallyTagged[ForwardShape]{},
+			jsonData: `{"hexagon":{"sides":6}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.shape.UnmarshalJSON([]byte(tt.jsonData)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			variant, ok := tt.shape.Variant()
+			if !ok || variant != "hexagon" {
+				t.Errorf("expected %v, got %v", "hexagon", variant)
+			}
+		})
+	}
+}
+
+func TestRawUnion(t *testing.T) {
+	shape := Union[ForwardShape]{Value: ForwardShape{Unknown: &Raw{Variant: "hexagon", Value: json.RawMessage(`{"sides":6}`)}}}
+	data, err := json.Marshal(shape)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `{"sides":6}`; string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"sides":6}`), &shape); err == nil {
+		t.Errorf("expected Union not to decode into the raw field, got %v", shape.GetValue())
+	}
+}
+
+func TestRawSpecMetadata(t *testing.T) {
+	if variants := Variants[ForwardShape](); !slices.Equal(variants, []string{"circle"}) {
+		t.Errorf("expected raw field to be excluded from variants, got %v", variants)
+	}
+	if _, ok := VariantTypes[ForwardShape]()["unknown"]; ok {
+		t.Error("expected raw field to be excluded from variant types")
+	}
+	if err := CheckSpec[ForwardShape](); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+type PassthroughShape struct {
+	Circle *Circle          `variant:"circle"`
+	Blob   json.RawMessage  `variant:"blob"`
+	Ref    *json.RawMessage `variant:"ref"`
+}
+
+func TestRawMessageVariants(t *testing.T) {
+	blob := `{ "z": 1, "a": "<b>" }`
+	ref := json.RawMessage(blob)
+	tests := []struct {
+		name     string
+		shape    interface{ MarshalJSON() ([]byte, error) }
+		expected string
+	}{
+		{
+			name:     "tagged",
+			shape:    TaggedUnion[PassthroughShape]{Value: PassthroughShape{Blob: json.RawMessage(blob)}},
+			expected: `{"type":"blob","value":{ "z": 1, "a": "<b>" }}`,
+		},
+		{
+			name:     "externally tagged pointer",
+			shape:    ExternallyTagged[PassthroughShape]{Value: PassthroughShape{Ref: &ref}},
+			expected: `{"ref":{ "z": 1, "a": "<b>" }}`,
+		},
+		{
+			name:     "untagged",
+			shape:    Union[PassthroughShape]{Value: PassthroughShape{Blob: json.RawMessage(blob)}},
+			expected: blob,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.shape.MarshalJSON()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, data)
+			}
+		})
+	}
+
+	t.Run("unmarshal keeps data untouched", func(t *testing.T) {
+		var shape TaggedUnion[PassthroughShape]
+		if err := shape.UnmarshalJSON([]byte(`{"type":"blob","value":` + blob + `}`)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(shape.Value.Blob) != blob {
+			t.Errorf("expected %s, got %s", blob, shape.Value.Blob)
+		}
+	})
+
+	t.Run("rejects invalid data", func(t *testing.T) {
+		shape := TaggedUnion[PassthroughShape]{Value: PassthroughShape{Blob: json.RawMessage(`{`)}}
+		if _, err := shape.MarshalJSON(); err == nil || err.Error() != "raw payload is not valid JSON" {
+			t.Errorf("expected error 'raw payload is not valid JSON', got '%v'", err)
+		}
+	})
+}
+
+// assertRawEquals is like assertValueEquals, additionally comparing Raw values.
+func assertRawEquals(t *testing.T, value, expected any) {
+	t.Helper()
+
+	raw, ok := expected.(Raw)
+	if !ok {
+		assertValueEquals(t, value, expected)
+		return
+	}
+	if ptr, ok := value.(*Raw); ok {
+		value = *ptr
+	}
+	if !reflect.DeepEqual(value, raw) {
+		t.Errorf("expected %+v, got %+v", raw, value)
+	}
+}
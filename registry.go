@@ -0,0 +1,37 @@
+package union
+
+import (
+	"reflect"
+	"sync"
+)
+
+// specCodec is a codec registered with RegisterCodec, with the Spec type erased.
+type specCodec struct {
+	marshal   func(spec any) ([]byte, error)
+	unmarshal func(data []byte, spec any) error
+}
+
+// codecs maps Spec types to their registered specCodec.
+var codecs sync.Map
+
+// RegisterCodec registers hand-written or generated JSON functions for the Spec type,
+// which the MarshalJSON and UnmarshalJSON methods of unions of that Spec type call instead
+// of the reflection-based implementation. Since the functions produce and read the whole
+// JSON representation, a Spec type with a codec should be used with a single union type.
+//
+// unmarshal is called with a pointer to a zero Spec value. Registering a Spec type
+// again replaces its codec. RegisterCodec is typically called from an init function.
+func RegisterCodec[Spec any](marshal func(spec Spec) ([]byte, error), unmarshal func(data []byte, spec *Spec) error) {
+	codecs.Store(reflect.TypeFor[Spec](), &specCodec{
+		marshal:   func(spec any) ([]byte, error) { return marshal(spec.(Spec)) },
+		unmarshal: func(data []byte, spec any) error { return unmarshal(data, spec.(*Spec)) },
+	})
+}
+
+// codecOf returns the codec registered for the Spec type t, or nil.
+func codecOf(t reflect.Type) *specCodec {
+	if c, ok := codecs.Load(t); ok {
+		return c.(*specCodec)
+	}
+	return nil
+}
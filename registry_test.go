@@ -0,0 +1,74 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type CodecShape struct {
+	Circle *Circle `variant:"circle"`
+	Square *Square `variant:"square"`
+}
+
+type Square struct {
+	Side float64 `json:"side"`
+}
+
+func init() {
+	// a hand-written codec writing circles as "c:<radius>"
+	RegisterCodec(
+		func(spec CodecShape) ([]byte, error) {
+			if spec.Circle == nil {
+				return nil, ErrZeroVariants
+			}
+			return json.Marshal("c:" + strconv.FormatFloat(spec.Circle.Radius, 'g', -1, 64))
+		},
+		func(data []byte, spec *CodecShape) error {
+			var s string
+			if err := json.Unmarshal(data, &s); err != nil {
+				return err
+			}
+			radius, ok := strings.CutPrefix(s, "c:")
+			if !ok {
+				return ErrUnknownVariant
+			}
+			r, err := strconv.ParseFloat(radius, 64)
+			if err != nil {
+				return err
+			}
+			spec.Circle = &Circle{Radius: r}
+			return nil
+		},
+	)
+}
+
+func TestRegisterCodec(t *testing.T) {
+	shape := TaggedUnion[CodecShape]{Value: CodecShape{Circle: &Circle{Radius: 5.0}}}
+	data, err := json.Marshal(shape)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `"c:5"` {
+		t.Errorf("expected %s, got %s", `"c:5"`, data)
+	}
+
+	var decoded Union[CodecShape]
+	if err := json.Unmarshal([]byte(`"c:2.5"`), &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, decoded.GetValue(), Circle{Radius: 2.5})
+
+	var external ExternallyTagged[CodecShape]
+	if err := json.Unmarshal([]byte(`"s:1"`), &external); !errors.Is(err, ErrUnknownVariant) {
+		t.Errorf("expected error '%v', got '%v'", ErrUnknownVariant, err)
+	}
+
+	var streamed TaggedUnion[CodecShape]
+	if err := streamed.DecodeFrom(json.NewDecoder(strings.NewReader(`"c:1"`))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, streamed.GetValue(), Circle{Radius: 1})
+}
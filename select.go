@@ -0,0 +1,65 @@
+package union
+
+import "reflect"
+
+// Select makes the variant named variant active with a zero payload, clearing all other variant fields.
+// Pointer fields are set to a pointer to a new zero value.
+//
+// Since the active variant is otherwise found by its non-zero field, Select is the way to
+// represent a legitimately zero payload such as Rectangle{} in a non-pointer field:
+// GetValue, Variant and marshaling report the selected variant as long as no variant field
+// is set. Setting a variant field, with Set or directly, takes precedence over the selection.
+//
+// Returns an error and leaves the union unchanged if:
+//   - The Spec type is not a struct
+//   - No field declares the variant (*UnknownVariantError)
+//   - Multiple fields declare the variant (invalid Spec definition)
+func (u *TaggedUnion[Spec]) Select(variant string) error {
+	f, err := selectVariant(u.specValue(), variant)
+	if err != nil {
+		return err
+	}
+	u.selected = f
+	return nil
+}
+
+// Select makes the variant named variant active with a zero payload, clearing all other variant fields.
+// See TaggedUnion.Select.
+func (u *ExternallyTagged[Spec]) Select(variant string) error {
+	f, err := selectVariant(u.specValue(), variant)
+	if err != nil {
+		return err
+	}
+	u.selected = f
+	return nil
+}
+
+// Select makes the variant named variant active with a zero payload, clearing all other variant fields.
+// See TaggedUnion.Select. The zero payload marshals as is, but unmarshaling never
+// matches a zero value, so it does not survive a round trip through JSON.
+func (u *Union[Spec]) Select(variant string) error {
+	f, err := selectVariant(u.specValue(), variant)
+	if err != nil {
+		return err
+	}
+	u.selected = f
+	return nil
+}
+
+// selectVariant makes the field declaring variant the only variant field of the
+// spec struct value v, holding a zero payload.
+func selectVariant(v reflect.Value, variant string) (*fieldPlan, error) {
+	p := planOf(v.Type())
+	if !p.isStruct {
+		return nil, ErrSpecNotStruct
+	}
+
+	f, err := p.lookup(variant)
+	if err != nil {
+		return nil, err
+	}
+
+	p.clear(v)
+	v.FieldByIndex(f.index).Set(zeroPayload(f))
+	return f, nil
+}
@@ -0,0 +1,139 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	t.Run("marshals selected zero payload", func(t *testing.T) {
+		var shape TaggedUnion[NonPointerShape]
+		shape.Value.Circle = Circle{Radius: 5.0}
+		if err := shape.Select("rectangle"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if shape.Value.Circle != (Circle{}) {
+			t.Errorf("expected other variants to be cleared, got %+v", shape.Value.Circle)
+		}
+		if variant, ok := shape.Variant(); !ok || variant != "rectangle" {
+			t.Errorf("expected variant rectangle, got %q (ok=%v)", variant, ok)
+		}
+		if value := shape.GetValue(); value != (Rectangle{}) {
+			t.Errorf("expected zero Rectangle, got %#v", value)
+		}
+
+		data, err := json.Marshal(shape)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := `{"type":"rectangle","value":{"width":0,"height":0}}`
+		if string(data) != expected {
+			t.Errorf("expected %s, got %s", expected, data)
+		}
+	})
+
+	t.Run("round trips zero payload", func(t *testing.T) {
+		var shape TaggedUnion[NonPointerShape]
+		if err := json.Unmarshal([]byte(`{"type":"rectangle","value":{}}`), &shape); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		data, err := json.Marshal(shape)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := `{"type":"rectangle","value":{"width":0,"height":0}}`
+		if string(data) != expected {
+			t.Errorf("expected %s, got %s", expected, data)
+		}
+	})
+
+	t.Run("allocates pointer payload", func(t *testing.T) {
+		var shape ExternallyTagged[Shape]
+		if err := shape.Select("circle"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if shape.Value.Circle == nil || *shape.Value.Circle != (Circle{}) {
+			t.Errorf("expected pointer to zero Circle, got %v", shape.Value.Circle)
+		}
+	})
+
+	t.Run("set field takes precedence", func(t *testing.T) {
+		var shape Union[UnionNonPointerShape]
+		if err := shape.Select("Rectangle"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		shape.Value.Circle = Circle{Radius: 5.0}
+		if variant, ok := shape.Variant(); !ok || variant != "Circle" {
+			t.Errorf("expected variant Circle, got %q (ok=%v)", variant, ok)
+		}
+	})
+
+	t.Run("set resets selection", func(t *testing.T) {
+		tests := []struct {
+			name string
+			set  func(u *TaggedUnion[NonPointerShape]) error
+		}{
+			{
+				name: "Set",
+				set:  func(u *TaggedUnion[NonPointerShape]) error { return Set(u, Circle{Radius: 5.0}) },
+			},
+			{
+				name: "SetVariant",
+				set:  func(u *TaggedUnion[NonPointerShape]) error { return SetVariant(u, "circle", Circle{Radius: 5.0}) },
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var shape TaggedUnion[NonPointerShape]
+				if err := shape.Select("rectangle"); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if err := tt.set(&shape); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				shape.Value = NonPointerShape{}
+				if !shape.IsZero() {
+					t.Errorf("expected zero union, got %v", shape.GetValue())
+				}
+			})
+		}
+	})
+
+	t.Run("reset pointer field ignores selection", func(t *testing.T) {
+		var shape TaggedUnion[Shape]
+		if err := shape.Select("rectangle"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		shape.Value = Shape{}
+		if _, err := json.Marshal(shape); !errors.Is(err, ErrZeroVariants) {
+			t.Errorf("expected error '%v', got '%v'", ErrZeroVariants, err)
+		}
+	})
+
+	t.Run("unmarshaling resets selection", func(t *testing.T) {
+		var shape TaggedUnion[NonPointerShape]
+		if err := shape.Select("rectangle"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := json.Unmarshal([]byte(`{"type":"unknown","value":{}}`), &shape); err == nil {
+			t.Fatal("expected error")
+		}
+		if _, ok := shape.Variant(); ok {
+			t.Error("expected no active variant")
+		}
+	})
+
+	t.Run("rejects unknown variant", func(t *testing.T) {
+		var shape TaggedUnion[Shape]
+		shape.Value.Circle = &Circle{Radius: 5.0}
+		err := shape.Select("hexagon")
+		if !errors.Is(err, ErrUnknownVariant) {
+			t.Fatalf("expected ErrUnknownVariant, got %v", err)
+		}
+		if shape.Value.Circle == nil {
+			t.Error("expected union to be unchanged")
+		}
+	})
+}
@@ -0,0 +1,237 @@
+package union
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Variants returns the variant names declared by the Spec struct, in field
+// declaration order. It returns nil if Spec is not a struct.
+func Variants[Spec any]() []string {
+	p := planOf(reflect.TypeFor[Spec]())
+	if !p.isStruct {
+		return nil
+	}
+	return p.knownVariants()
+}
+
+// VariantTypes returns the field type of each variant declared by the Spec struct,
+// keyed by variant name. Raw fields are not included. It returns nil if Spec is not a struct.
+func VariantTypes[Spec any]() map[string]reflect.Type {
+	p := planOf(reflect.TypeFor[Spec]())
+	if !p.isStruct {
+		return nil
+	}
+
+	types := make(map[string]reflect.Type, len(p.fields))
+	for _, f := range p.fields {
+		if f.raw {
+			continue
+		}
+		types[f.variant] = f.typ
+	}
+	return types
+}
+
+// DiscriminatorPath returns the path of object keys leading to the variant name in the
+// JSON object of a TaggedUnion[Spec], such as ["type"] or ["meta", "kind"], following the
+// spec's JSONDiscriminator, JSONDiscriminatorPath or JSONMemberDiscriminator method.
+// Databases storing unions in JSON columns filter by variant with it. It returns nil for
+// specs returning true from JSONTuple, whose variant name is the first array element.
+func DiscriminatorPath[Spec any]() []string {
+	var u TaggedUnion[Spec]
+	if planOf(reflect.TypeFor[Spec]()).json.tuple {
+		return nil
+	}
+	if variantPath, _, ok := u.discriminatorPaths(); ok {
+		return slices.Clone(variantPath)
+	}
+	if discriminator, ok := u.memberDiscriminator(); ok {
+		return []string{discriminator}
+	}
+	variantField, _ := u.fieldNames()
+	return []string{variantField}
+}
+
+// CheckSpec validates the Spec struct up front, so misconfigured specs can fail
+// fast (e.g. from init or a test) instead of surfacing as marshaling errors.
+//
+// It reports all problems found, each wrapping ErrInvalidSpec (or ErrSpecNotStruct):
+//   - The Spec type is not a struct
+//   - Multiple fields declare the same variant name or alias, or names that differ only
+//     in case when CaseInsensitiveVariants returns true
+//   - An unexported field declares a variant with a `variant` struct tag
+//   - Multiple fields have type Raw
+//   - A field's `union` struct tag is malformed
+//   - A unit variant field is not a pointer
+//   - A field has a kind that cannot be represented in JSON (chan, func, complex, unsafe pointer)
+//   - JSONDiscriminator returns the same name for the variant and value fields,
+//     or JSONDiscriminatorPath returns paths where one is a prefix of the other
+//   - A variant name or alias cannot be written as the JSONDiscriminatorKind
+//   - JSONMemberDiscriminator is declared and two variants are held by the same
+//     member field, or a member field is named like the discriminator
+//   - JSONTuple returns true along with JSONMemberDiscriminator or JSONDiscriminatorPath
+//
+// Fields of embedded variant groups are checked like the spec's own fields.
+func CheckSpec[Spec any]() error {
+	t := reflect.TypeFor[Spec]()
+	if t.Kind() != reflect.Struct {
+		return ErrSpecNotStruct
+	}
+
+	var errs []error
+	var raw, extras string
+	kind := discriminatorKind(reflect.Zero(t).Interface())
+	naming := namingOf(t)
+	seen := make(map[string]string)
+	for _, tf := range specFields(t) {
+		if !tf.IsExported() && tf.Tag.Get("variant") != "" && tf.Tag.Get("variant") != "-" {
+			errs = append(errs, fmt.Errorf("%w: field %s declares a variant but is unexported", ErrInvalidSpec, tf.Name))
+		}
+		if skipField(tf) {
+			continue
+		}
+		if tf.Type == extrasType {
+			if extras != "" {
+				errs = append(errs, fmt.Errorf("%w: fields %s and %s both capture extra members", ErrInvalidSpec, extras, tf.Name))
+			}
+			extras = tf.Name
+			continue
+		}
+		// raw fields don't declare a variant name of their own
+		names := append([]string{variantName(tf, naming)}, variantAliases(tf)...)
+		if isRawType(tf.Type) {
+			if raw != "" {
+				errs = append(errs, fmt.Errorf("%w: fields %s and %s both capture unknown variants", ErrInvalidSpec, raw, tf.Name))
+			}
+			raw, names = tf.Name, nil
+		}
+		for _, name := range names {
+			if err := checkDiscriminator(kind, name); err != nil {
+				errs = append(errs, fmt.Errorf("%w: field %s: %v", ErrInvalidSpec, tf.Name, err))
+			}
+			if other, ok := seen[name]; ok {
+				errs = append(errs, fmt.Errorf("%w: fields %s and %s declare the same variant %q", ErrInvalidSpec, other, tf.Name, name))
+			} else {
+				seen[name] = tf.Name
+			}
+		}
+		if opts, err := parseFieldOptions(tf); err != nil {
+			errs = append(errs, fmt.Errorf("%w: field %s: %v", ErrInvalidSpec, tf.Name, err))
+		} else if opts.unit && tf.Type.Kind() != reflect.Pointer {
+			errs = append(errs, fmt.Errorf("%w: unit variant field %s must be a pointer", ErrInvalidSpec, tf.Name))
+		}
+		switch kind := indirect(tf.Type).Kind(); kind {
+		case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+			errs = append(errs, fmt.Errorf("%w: field %s has unsupported kind %s", ErrInvalidSpec, tf.Name, kind))
+		}
+	}
+
+	if p := planOf(t); p.foldCase {
+		for i, a := range p.fields {
+			for _, b := range p.fields[i+1:] {
+				if a.raw || b.raw {
+					continue
+				}
+				if a.variant != b.variant && strings.EqualFold(a.variant, b.variant) {
+					errs = append(errs, fmt.Errorf("%w: fields %s and %s declare variants %q and %q that differ only in case", ErrInvalidSpec, a.name, b.name, a.variant, b.variant))
+				}
+			}
+		}
+	}
+
+	var u TaggedUnion[Spec]
+	if variantPath, valuePath, ok := u.discriminatorPaths(); ok {
+		if err := checkPaths(variantPath, valuePath); err != nil {
+			errs = append(errs, fmt.Errorf("%w: %v", ErrInvalidSpec, err))
+		}
+	} else if variant, value := u.fieldNames(); variant == value {
+		errs = append(errs, fmt.Errorf("%w: variant and value fields are both named %q", ErrInvalidSpec, variant))
+	}
+	if discriminator, ok := u.memberDiscriminator(); ok {
+		errs = append(errs, checkMembers(planOf(t), discriminator)...)
+	}
+	if o := planOf(t).json; o.tuple && (o.hasMembers || o.hasPaths) {
+		errs = append(errs, fmt.Errorf("%w: JSONTuple cannot be combined with JSONMemberDiscriminator or JSONDiscriminatorPath", ErrInvalidSpec))
+	}
+
+	return errors.Join(errs...)
+}
+
+// unionPtr is implemented by pointers to all union types.
+type unionPtr interface {
+	json.Marshaler
+	json.Unmarshaler
+	specValue() reflect.Value
+}
+
+// variantName returns the variant name of a spec field, which is the `variant`
+// struct tag or the field name converted by the spec's naming if no tag is provided.
+func variantName(tf reflect.StructField, naming Naming) string {
+	return cmp.Or(tf.Tag.Get("variant"), naming.Apply(tf.Name))
+}
+
+// variantAliases returns the additional variant names accepted when decoding a spec
+// field, from its comma-separated `variantAliases` struct tag.
+func variantAliases(tf reflect.StructField) []string {
+	tag := tf.Tag.Get("variantAliases")
+	if tag == "" {
+		return nil
+	}
+	return strings.Split(tag, ",")
+}
+
+// fieldOptions holds the options of a spec field's `union` struct tag,
+// a comma-separated list of key=value pairs and flags such as `union:"priority=10"`.
+type fieldOptions struct {
+	priority   int  // Union matching priority, higher is tried first
+	unit       bool // unit variant marshaled as a bare string, from the `unit` flag
+	deprecated bool // deprecated variant reported to the OnDeprecated callback, from the `deprecated` flag
+}
+
+// parseFieldOptions parses the `union` struct tag of a spec field.
+func parseFieldOptions(tf reflect.StructField) (fieldOptions, error) {
+	var opts fieldOptions
+	tag, ok := tf.Tag.Lookup("union")
+	if !ok {
+		return opts, nil
+	}
+	for opt := range strings.SplitSeq(tag, ",") {
+		key, value, hasValue := strings.Cut(opt, "=")
+		switch key {
+		case "priority":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return opts, fmt.Errorf("invalid priority %q", value)
+			}
+			opts.priority = n
+		case "unit":
+			if hasValue {
+				return opts, fmt.Errorf("option unit takes no value")
+			}
+			opts.unit = true
+		case "deprecated":
+			if hasValue {
+				return opts, fmt.Errorf("option deprecated takes no value")
+			}
+			opts.deprecated = true
+		default:
+			return opts, fmt.Errorf("unknown option %q", key)
+		}
+	}
+	return opts, nil
+}
+
+// indirect returns the element type of pointer types, or t itself otherwise.
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
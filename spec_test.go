@@ -0,0 +1,318 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestVariants(t *testing.T) {
+	tests := []struct {
+		name     string
+		variants []string
+		expected []string
+	}{
+		{
+			name:     "returns tagged variant names",
+			variants: Variants[Shape](),
+			expected: []string{"circle", "rectangle", "triangle"},
+		},
+		{
+			name:     "returns field names without struct tags",
+			variants: Variants[UnionShape](),
+			expected: []string{"Circle", "Rectangle", "Triangle"},
+		},
+		{
+			name:     "returns empty for struct with no variants",
+			variants: Variants[EmptyShape](),
+			expected: []string{},
+		},
+		{
+			name:     "returns nil for non-struct type",
+			variants: Variants[NonStructType](),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !slices.Equal(tt.variants, tt.expected) || (tt.variants == nil) != (tt.expected == nil) {
+				t.Errorf("expected %v, got %v", tt.expected, tt.variants)
+			}
+		})
+	}
+}
+
+func TestVariantTypes(t *testing.T) {
+	types := VariantTypes[NonPointerShape]()
+	expected := map[string]reflect.Type{
+		"circle":    reflect.TypeFor[Circle](),
+		"rectangle": reflect.TypeFor[Rectangle](),
+	}
+	if !reflect.DeepEqual(types, expected) {
+		t.Errorf("expected %v, got %v", expected, types)
+	}
+
+	if types := VariantTypes[NonStructType](); types != nil {
+		t.Errorf("expected nil, got %v", types)
+	}
+}
+
+func TestDiscriminatorPathOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     []string
+		expected []string
+	}{
+		{"default", DiscriminatorPath[Shape](), []string{"type"}},
+		{"custom field names", DiscriminatorPath[CustomFieldNamesShape](), []string{"kind"}},
+		{"nested path", DiscriminatorPath[WebhookShape](), []string{"meta", "type"}},
+		{"member discriminator", DiscriminatorPath[Strategy](), []string{"type"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.path, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, tt.path)
+			}
+		})
+	}
+}
+
+type UnexportedFieldShape struct {
+	Circle *Circle    `variant:"circle"`
+	square *Rectangle `variant:"square"`
+}
+
+type HelperFieldShape struct {
+	Circle *Circle    `variant:"circle"`
+	Cached *Rectangle `variant:"-"`
+	hits   int
+}
+
+type (
+	RoundShapes struct {
+		Circle *Circle `variant:"circle"`
+	}
+	polygonShapes struct {
+		Rectangle *Rectangle `variant:"rectangle"`
+		Triangle  *Triangle  `variant:"triangle"`
+	}
+	GroupedShape struct {
+		RoundShapes
+		polygonShapes
+		Legacy *Circle `variant:"legacy"`
+	}
+	ConflictingGroupShape struct {
+		RoundShapes
+		Round *Circle `variant:"circle"`
+	}
+)
+
+type UnsupportedKindShape struct {
+	Circle   *Circle `variant:"circle"`
+	Callback func()  `variant:"callback"`
+}
+
+type ConflictingFieldNamesShape struct {
+	Circle *Circle `variant:"circle"`
+}
+
+func (s ConflictingFieldNamesShape) JSONDiscriminator() (string, string) {
+	return "kind", "kind"
+}
+
+type DuplicateAliasShape struct {
+	Circle    *Circle    `variant:"circle" variantAliases:"round"`
+	Rectangle *Rectangle `variant:"rectangle" variantAliases:"circle"`
+}
+
+type MalformedOptionsShape struct {
+	Circle    *Circle    `variant:"circle" union:"priority=high"`
+	Rectangle *Rectangle `variant:"rectangle" union:"first"`
+}
+
+type NonPointerUnitShape struct {
+	Idle   Idle    `variant:"idle" union:"unit"`
+	Circle *Circle `variant:"circle" union:"unit=yes"`
+}
+
+func TestCheckSpec(t *testing.T) {
+	tests := []struct {
+		name        string
+		check       func() error
+		expectedErr error
+		contains    []string
+	}{
+		{
+			name:  "accepts valid spec",
+			check: CheckSpec[Shape],
+		},
+		{
+			name:  "accepts flat spec",
+			check: CheckSpec[FlatShape],
+		},
+		{
+			name:        "rejects non-struct spec",
+			check:       CheckSpec[NonStructType],
+			expectedErr: ErrSpecNotStruct,
+		},
+		{
+			name:        "rejects duplicate variant tags",
+			check:       CheckSpec[DuplicateVariantShape],
+			expectedErr: ErrInvalidSpec,
+			contains:    []string{`fields Circle1 and Circle2 declare the same variant "circle"`},
+		},
+		{
+			name:        "rejects unexported variant fields",
+			check:       CheckSpec[UnexportedFieldShape],
+			expectedErr: ErrInvalidSpec,
+			contains:    []string{"field square declares a variant but is unexported"},
+		},
+		{
+			name:  "accepts embedded variant groups",
+			check: CheckSpec[GroupedShape],
+		},
+		{
+			name:        "rejects variants conflicting with embedded groups",
+			check:       CheckSpec[ConflictingGroupShape],
+			expectedErr: ErrInvalidSpec,
+			contains:    []string{`fields Circle and Round declare the same variant "circle"`},
+		},
+		{
+			name:  "accepts skipped helper fields",
+			check: CheckSpec[HelperFieldShape],
+		},
+		{
+			name:        "rejects unsupported field kinds",
+			check:       CheckSpec[UnsupportedKindShape],
+			expectedErr: ErrInvalidSpec,
+			contains:    []string{"field Callback has unsupported kind func"},
+		},
+		{
+			name:        "rejects conflicting field names",
+			check:       CheckSpec[ConflictingFieldNamesShape],
+			expectedErr: ErrInvalidSpec,
+			contains:    []string{`variant and value fields are both named "kind"`},
+		},
+		{
+			name:        "rejects aliases conflicting with variants",
+			check:       CheckSpec[DuplicateAliasShape],
+			expectedErr: ErrInvalidSpec,
+			contains:    []string{`fields Circle and Rectangle declare the same variant "circle"`},
+		},
+		{
+			name:        "rejects variants differing only in case",
+			check:       CheckSpec[FoldConflictShape],
+			expectedErr: ErrInvalidSpec,
+			contains:    []string{`fields Circle and Round declare variants "circle" and "CIRCLE" that differ only in case`},
+		},
+		{
+			name:        "rejects malformed unit variants",
+			check:       CheckSpec[NonPointerUnitShape],
+			expectedErr: ErrInvalidSpec,
+			contains: []string{
+				"unit variant field Idle must be a pointer",
+				"field Circle: option unit takes no value",
+			},
+		},
+		{
+			name:        "rejects multiple raw fields",
+			check:       CheckSpec[MultipleRawShape],
+			expectedErr: ErrInvalidSpec,
+			contains:    []string{"fields Old and New both capture unknown variants"},
+		},
+		{
+			name:        "rejects malformed union tags",
+			check:       CheckSpec[MalformedOptionsShape],
+			expectedErr: ErrInvalidSpec,
+			contains: []string{
+				`field Circle: invalid priority "high"`,
+				`field Rectangle: unknown option "first"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.check()
+
+			if tt.expectedErr == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error '%v', got '%v'", tt.expectedErr, err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(err.Error(), s) {
+					t.Errorf("expected error to contain '%s', got '%v'", s, err)
+				}
+			}
+		})
+	}
+}
+
+func TestSkippedFields(t *testing.T) {
+	if variants := Variants[HelperFieldShape](); !slices.Equal(variants, []string{"circle"}) {
+		t.Errorf("expected skipped fields to be excluded from variants, got %v", variants)
+	}
+
+	shape := TaggedUnion[HelperFieldShape]{Value: HelperFieldShape{Cached: &Rectangle{Width: 1}, hits: 3}}
+	if err := Set(&shape, Circle{Radius: 5}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if shape.Value.Cached == nil || shape.Value.hits != 3 {
+		t.Errorf("expected Set to keep skipped fields, got %+v", shape.Value)
+	}
+	assertValueEquals(t, shape.GetValue(), Circle{Radius: 5})
+
+	data, err := json.Marshal(shape)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `{"type":"circle","value":{"radius":5}}`; string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	var decoded TaggedUnion[HelperFieldShape]
+	err = json.Unmarshal([]byte(`{"type":"Cached","value":{"width":1}}`), &decoded)
+	if !errors.Is(err, ErrUnknownVariant) {
+		t.Errorf("expected error '%v', got '%v'", ErrUnknownVariant, err)
+	}
+}
+
+func TestEmbeddedGroups(t *testing.T) {
+	if variants := Variants[GroupedShape](); !slices.Equal(variants, []string{"circle", "rectangle", "triangle", "legacy"}) {
+		t.Errorf("expected promoted variants, got %v", variants)
+	}
+
+	var shape TaggedUnion[GroupedShape]
+	if err := json.Unmarshal([]byte(`{"type":"triangle","value":{"base":8,"height":4}}`), &shape); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if shape.Value.Triangle == nil {
+		t.Fatal("expected promoted field to be set")
+	}
+	assertValueEquals(t, shape.GetValue(), Triangle{Base: 8, Height: 4})
+
+	if err := SetVariant(&shape, "circle", &Circle{Radius: 5}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if shape.Value.Triangle != nil {
+		t.Error("expected SetVariant to clear promoted fields")
+	}
+	data, err := json.Marshal(shape)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `{"type":"circle","value":{"radius":5}}`; string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+}
@@ -0,0 +1,205 @@
+package union
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"reflect"
+)
+
+// DecodeAll returns an iterator over the TaggedUnion values read from r, which holds
+// either a stream of JSON values such as newline-delimited JSON, or a single JSON array.
+// For JSONTuple specs, whose unions are arrays, r always holds a stream of JSON values.
+// Each value is decoded with DecodeFrom, and reading stops at the first value longer
+// than the MaxBytes limit of the spec.
+//
+// The sequence ends after the first error, which is yielded with a zero union,
+// since the position in the stream is unknown after it.
+func DecodeAll[Spec any](r io.Reader) iter.Seq2[TaggedUnion[Spec], error] {
+	return func(yield func(TaggedUnion[Spec], error) bool) {
+		br := bufio.NewReader(r)
+		array, err := startsArray(br)
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				yield(TaggedUnion[Spec]{}, err)
+			}
+			return
+		}
+		// the arrays of tuple unions are the unions themselves
+		array = array && !planOf(reflect.TypeFor[Spec]()).json.tuple
+
+		lr := newLimitedReader(br)
+		dec := json.NewDecoder(lr)
+		maxBytes := (&TaggedUnion[Spec]{}).limits().MaxBytes
+		if array {
+			if _, err := dec.Token(); err != nil {
+				yield(TaggedUnion[Spec]{}, err)
+				return
+			}
+		}
+		for !array || dec.More() {
+			var u TaggedUnion[Spec]
+			lr.start(dec, maxBytes)
+			err := u.DecodeFrom(dec)
+			lr.start(dec, 0)
+			if !array && errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(TaggedUnion[Spec]{}, err)
+				return
+			}
+			if !yield(u, nil) {
+				return
+			}
+		}
+		if _, err := dec.Token(); err != nil {
+			yield(TaggedUnion[Spec]{}, err)
+		}
+	}
+}
+
+// startsArray reports whether the first non-whitespace byte of br opens a JSON array, without consuming it.
+func startsArray(br *bufio.Reader) (bool, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return false, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b == '[', br.UnreadByte()
+	}
+}
+
+// DecodeFrom reads the next JSON value from dec into the union, with the same
+// rules as UnmarshalJSON.
+//
+// Unlike UnmarshalJSON, which buffers the whole object into a map before decoding
+// the payload, DecodeFrom reads the object token by token and decodes the value field
+// straight into the variant's spec field once the variant field has been read.
+// MarshalJSON writes the variant field first, so large payloads are not held twice in memory.
+//
+// Objects with the value field ahead of the variant field, unknown variants, the flat
+// representation, JSONMemberDiscriminator, JSONDiscriminatorPath, JSONTuple and JSONStrict
+// specs, and Spec types with a codec
+// registered with RegisterCodec are decoded by buffering the value as UnmarshalJSON does.
+// So are unions with DecodeLimits, whose value is checked against them before it is
+// decoded; the reader of dec is not limited, unlike those of Decoder and DecodeAll.
+func (u *TaggedUnion[Spec]) DecodeFrom(dec *json.Decoder) error {
+	var zero Spec
+	u.Value = zero
+	u.selected = nil
+
+	v := reflect.ValueOf(&u.Value).Elem()
+	t := v.Type()
+	p := planOf(t)
+
+	if !p.isStruct {
+		return ErrSpecNotStruct
+	}
+
+	variantField, valueField := u.fieldNames()
+	if _, _, ok := u.discriminatorPaths(); ok || p.json.hasMembers || p.json.tuple || valueField == "" || isStrict(u.Value) || usesNumber(u.Value) || codecOf(t) != nil || u.limits() != (DecodeLimits{}) {
+		var data json.RawMessage
+		if err := dec.Decode(&data); err != nil {
+			return err
+		}
+		return u.UnmarshalJSON(data)
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok := tok.(type) {
+	case json.Delim:
+		if tok != '{' {
+			return &json.UnmarshalTypeError{Value: "array", Type: reflect.TypeFor[map[string]json.RawMessage](), Offset: dec.InputOffset()}
+		}
+	default:
+		// unit variants and null, which are small enough to decode as a whole
+		data, err := json.Marshal(tok)
+		if err != nil {
+			return err
+		}
+		return u.UnmarshalJSON(data)
+	}
+
+	var rawVariant, rawValue json.RawMessage
+	var decoded *fieldPlan
+	others := make(map[string]json.RawMessage)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch key := tok.(string); {
+		case key == variantField:
+			if err := dec.Decode(&rawVariant); err != nil {
+				return err
+			}
+		case key == valueField && rawVariant != nil:
+			variant, err := decodeDiscriminator(discriminatorKind(u.Value), rawVariant)
+			if err != nil {
+				return err
+			}
+			f, err := p.resolve(variant)
+			if err != nil {
+				// unknown variants are buffered for the raw field or the error
+				if err := dec.Decode(&rawValue); err != nil {
+					return err
+				}
+				continue
+			}
+			target := reflect.New(f.typ)
+			if err := dec.Decode(target.Interface()); err != nil {
+				return payloadError(t, variant, f, valueField, err)
+			}
+			if err := afterUnmarshal(target); err != nil {
+				return &DecodeError{Spec: t, Variant: variant, Field: f.name, Err: err}
+			}
+			v.FieldByIndex(f.index).Set(target.Elem())
+			decoded = f
+		case key == valueField:
+			if err := dec.Decode(&rawValue); err != nil {
+				return err
+			}
+		default:
+			var member json.RawMessage
+			if err := dec.Decode(&member); err != nil {
+				return err
+			}
+			if p.extras != nil {
+				others[key] = member
+			}
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	if decoded != nil {
+		p.setExtras(v, others, variantField, valueField)
+		u.selected = selection(v, decoded)
+		return nil
+	}
+
+	// decode the buffered fields, reporting missing ones as UnmarshalJSON does
+	envelope := others
+	if rawVariant != nil {
+		envelope[variantField] = rawVariant
+	}
+	if rawValue != nil {
+		envelope[valueField] = rawValue
+	}
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+	return u.unmarshalJSON(encodingJSON, data, variantField, valueField)
+}
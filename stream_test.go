@@ -0,0 +1,157 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDecodeFrom(t *testing.T) {
+	tests := []struct {
+		name        string
+		jsonData    string
+		expected    any
+		expectedErr string
+	}{
+		{name: "variant first", jsonData: `{"type":"circle","value":{"radius":5}}`, expected: Circle{Radius: 5.0}},
+		{name: "value first", jsonData: `{"value":{"width":10,"height":5},"type":"rectangle"}`, expected: Rectangle{Width: 10, Height: 5}},
+		{name: "extra keys", jsonData: `{"id":1,"type":"triangle","value":{"base":8,"height":4},"ts":[1,2]}`, expected: Triangle{Base: 8, Height: 4}},
+		{name: "unknown variant", jsonData: `{"type":"hexagon","value":{}}`, expectedErr: "unknown variant: hexagon"},
+		{name: "missing variant", jsonData: `{"value":{}}`, expectedErr: "missing variant field: type"},
+		{name: "missing value", jsonData: `{"type":"circle"}`, expectedErr: "missing value field: value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var shape TaggedUnion[Shape]
+			err := shape.DecodeFrom(json.NewDecoder(strings.NewReader(tt.jsonData)))
+
+			var unmarshaled TaggedUnion[Shape]
+			unmarshalErr := unmarshaled.UnmarshalJSON([]byte(tt.jsonData))
+
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("expected error '%s', got '%v'", tt.expectedErr, err)
+				}
+				if unmarshalErr == nil || unmarshalErr.Error() != tt.expectedErr {
+					t.Errorf("expected UnmarshalJSON error '%s', got '%v'", tt.expectedErr, unmarshalErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertValueEquals(t, shape.GetValue(), tt.expected)
+		})
+	}
+
+	t.Run("invalid payload", func(t *testing.T) {
+		var shape TaggedUnion[Shape]
+		err := shape.DecodeFrom(json.NewDecoder(strings.NewReader(`{"type":"circle","value":{"radius":"5"}}`)))
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Variant != "circle" {
+			t.Errorf("expected *DecodeError for variant circle, got '%v'", err)
+		}
+	})
+
+	t.Run("array", func(t *testing.T) {
+		var shape TaggedUnion[Shape]
+		if err := shape.DecodeFrom(json.NewDecoder(strings.NewReader(`[]`))); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestDecodeFromStream(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(`
+		"idle"
+		{"type":"circle","value":{"radius":5}}
+		{"type":"square","value":{}}
+	`))
+
+	var shape TaggedUnion[UnitShape]
+	if err := shape.DecodeFrom(dec); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if variant, _ := shape.Variant(); variant != "idle" {
+		t.Errorf("expected variant idle, got %q", variant)
+	}
+
+	if err := shape.DecodeFrom(dec); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, shape.GetValue(), Circle{Radius: 5.0})
+
+	if err := shape.DecodeFrom(dec); !errors.Is(err, ErrUnknownVariant) {
+		t.Errorf("expected error '%v', got '%v'", ErrUnknownVariant, err)
+	}
+	if err := shape.DecodeFrom(dec); !errors.Is(err, io.EOF) {
+		t.Errorf("expected error '%v', got '%v'", io.EOF, err)
+	}
+}
+
+func TestDecodeAll(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    []any
+		expectErr   bool
+		expectedErr error
+	}{
+		{
+			name:     "newline-delimited",
+			input:    "{\"type\":\"circle\",\"value\":{\"radius\":5}}\n{\"type\":\"rectangle\",\"value\":{\"width\":10,\"height\":5}}\n",
+			expected: []any{Circle{Radius: 5.0}, Rectangle{Width: 10, Height: 5}},
+		},
+		{
+			name:     "array",
+			input:    ` [{"type":"circle","value":{"radius":5}}, {"type":"triangle","value":{"base":8,"height":4}}]`,
+			expected: []any{Circle{Radius: 5.0}, Triangle{Base: 8, Height: 4}},
+		},
+		{name: "empty input", input: "  \n"},
+		{name: "empty array", input: "[]"},
+		{
+			name:        "stops at first error",
+			input:       `{"type":"circle","value":{"radius":5}} {"type":"hexagon","value":{}} {"type":"circle","value":{"radius":1}}`,
+			expected:    []any{Circle{Radius: 5.0}},
+			expectErr:   true,
+			expectedErr: ErrUnknownVariant,
+		},
+		{
+			name:      "unterminated array",
+			input:     `[{"type":"circle","value":{"radius":5}}`,
+			expected:  []any{Circle{Radius: 5.0}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var values []any
+			var err error
+			for shape, e := range DecodeAll[Shape](strings.NewReader(tt.input)) {
+				if e != nil {
+					err = e
+					continue
+				}
+				values = append(values, shape.GetValue())
+			}
+
+			if tt.expectErr != (err != nil) {
+				t.Errorf("expected error %v, got '%v'", tt.expectErr, err)
+			}
+			if tt.expectedErr != nil && !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error '%v', got '%v'", tt.expectedErr, err)
+			}
+			if len(values) != len(tt.expected) {
+				t.Fatalf("expected %d values, got %d", len(tt.expected), len(values))
+			}
+			for i, value := range values {
+				assertValueEquals(t, value, tt.expected[i])
+			}
+		})
+	}
+}
@@ -1,9 +1,10 @@
 // Package union provides generic union type implementations for Go with JSON
 // marshaling and unmarshaling support.
 //
-// This package offers two union types:
+// This package offers three union types:
 //
 //   - TaggedUnion: A discriminated union with explicit variant/value wrapper
+//   - ExternallyTagged: A discriminated union keyed by the variant name
 //   - Union: An untagged union that marshals data directly
 //
 // # TaggedUnion
@@ -37,8 +38,8 @@
 //
 //	// Marshals to: {"kind": "circle", "data": {...}}
 //
-// For a flat representation (variant fields merged into the top-level object),
-// implement JSONDiscriminator() returning a single string:
+// For a flat, internally tagged representation (variant fields merged into the
+// top-level object), implement JSONDiscriminator() returning a single string:
 //
 //	func (s Shape) JSONDiscriminator() string {
 //	    return "kind"
@@ -46,6 +47,20 @@
 //
 //	// Marshals to: {"kind": "circle", "radius": 5.0}
 //
+// # ExternallyTagged
+//
+// ExternallyTagged represents a discriminated union where the JSON representation
+// is an object with a single key, the variant name, containing the variant's data.
+// It uses the same Spec structs and `variant` struct tags as TaggedUnion.
+//
+// Example usage:
+//
+//	var shape union.ExternallyTagged[Shape]
+//	shape.Value.Circle = &Circle{Radius: 5.0}
+//
+//	// Marshals to: {"circle": {"radius": 5.0}}
+//	data, _ := json.Marshal(shape)
+//
 // # Union
 //
 // Union represents an untagged union where the JSON representation is the data
@@ -72,10 +87,14 @@
 package union
 
 import (
-	"cmp"
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"maps"
 	"reflect"
+	"slices"
+	"unicode/utf8"
 )
 
 // TaggedUnion represents a discriminated union type that can hold one of several
@@ -85,18 +104,37 @@ import (
 // Only one field in the Spec struct should be non-zero at any time. When marshaling
 // to JSON, the union is represented as an object with a variant field (indicating which
 // variant is active) and a value field (containing the variant's data).
-type TaggedUnion[Spec any] struct{ Value Spec }
+type TaggedUnion[Spec any] struct {
+	Value Spec
+
+	selected *fieldPlan // field made active by Select, see current
+}
+
+func (u *TaggedUnion[Spec]) spec() *Spec { return &u.Value }
+
+func (u *TaggedUnion[Spec]) specValue() reflect.Value { return reflect.ValueOf(&u.Value).Elem() }
 
-// fieldNames returns the names of the variant and value fields to use in JSON marshaling.
+func (u *TaggedUnion[Spec]) selectedField() *fieldPlan { return u.selected }
+
+func (u *TaggedUnion[Spec]) setSelected(f *fieldPlan) { u.selected = f }
+
+// fieldNames returns the names of the variant and value fields to use in JSON marshaling,
+// see jsonFieldNames.
+func (u *TaggedUnion[Spec]) fieldNames() (variant, value string) {
+	o := &planOf(reflect.TypeFor[Spec]()).json
+	return o.variantField, o.valueField
+}
+
+// jsonFieldNames returns the names of the variant and value fields selected by the spec.
 // It checks if the Spec type implements JSONDiscriminator() string for flat representation (value is ""),
 // then JSONDiscriminator() (string, string) for custom envelope names, otherwise defaults to "type" and "value".
-func (u *TaggedUnion[Spec]) fieldNames() (variant, value string) {
-	if tf, ok := any(u.Value).(interface{ JSONDiscriminator() string }); ok {
+func jsonFieldNames(spec any) (variant, value string) {
+	if tf, ok := spec.(interface{ JSONDiscriminator() string }); ok {
 		if name := tf.JSONDiscriminator(); name != "" {
 			return name, ""
 		}
 	}
-	if tu, ok := any(u.Value).(interface{ JSONDiscriminator() (string, string) }); ok {
+	if tu, ok := spec.(interface{ JSONDiscriminator() (string, string) }); ok {
 		variant, value = tu.JSONDiscriminator()
 		if variant == "" {
 			variant = "type"
@@ -115,27 +153,45 @@ func (u *TaggedUnion[Spec]) fieldNames() (variant, value string) {
 // it returns nil (indicating an invalid state).
 func (u TaggedUnion[Spec]) GetValue() any {
 	v := reflect.ValueOf(u.Value)
-	t := v.Type()
-
-	if t.Kind() != reflect.Struct {
+	f, err := planOf(v.Type()).current(v, u.selected)
+	if err != nil {
 		return nil
 	}
+	return v.FieldByIndex(f.index).Interface()
+}
 
-	var value any
-	for i := 0; i < t.NumField(); i++ {
-		vf := v.Field(i)
-
-		if vf.IsZero() {
-			continue
-		}
-		if value != nil {
-			// invariant violation: multiple variants set
-			return nil
-		}
-		value = vf.Interface()
+// Variant returns the name of the active variant in the union, which is the
+// field's `variant` struct tag or the field name, converted by the spec's VariantNaming, if no tag is provided.
+// It reports false if no fields are set or multiple fields are set.
+func (u TaggedUnion[Spec]) Variant() (string, bool) {
+	v := reflect.ValueOf(u.Value)
+	f, err := planOf(v.Type()).current(v, u.selected)
+	if err != nil {
+		return "", false
 	}
+	return f.variantOf(v.FieldByIndex(f.index)), true
+}
 
-	return value
+// MustGetValue is like GetValue but panics if no fields are set or multiple fields are set.
+func (u TaggedUnion[Spec]) MustGetValue() any {
+	v := reflect.ValueOf(u.Value)
+	f := mustActive(v, u.selected)
+	return v.FieldByIndex(f.index).Interface()
+}
+
+// MustVariant is like Variant but panics if no fields are set or multiple fields are set.
+func (u TaggedUnion[Spec]) MustVariant() string {
+	v := reflect.ValueOf(u.Value)
+	f := mustActive(v, u.selected)
+	return f.variantOf(v.FieldByIndex(f.index))
+}
+
+// IsZero reports whether no variant is set or selected in the union, which
+// lets the omitzero struct tag option of encoding/json omit unset unions.
+func (u TaggedUnion[Spec]) IsZero() bool {
+	v := reflect.ValueOf(u.Value)
+	_, err := planOf(v.Type()).current(v, u.selected)
+	return errors.Is(err, ErrZeroVariants)
 }
 
 // MarshalJSON implements the json.Marshaler interface.
@@ -143,67 +199,294 @@ func (u TaggedUnion[Spec]) GetValue() any {
 //   - A variant field (default "type") containing the variant name
 //   - A value field (default "value") containing the variant's data
 //
+// The variant field is always written first. In the flat representation the
+// variant's own fields follow the variant field in their marshaled order.
+//
 // The variant name is determined by the struct field's `variant` struct tag,
-// or the field name if no variant is specified.
+// or the field name (see Naming) if no variant is specified. Unit variants, tagged `union:"unit"`,
+// are serialized as a bare JSON string holding the variant name.
+//
+// If the Spec type implements JSONDiscriminatorPath() (string, string), the variant
+// and value fields are written at the returned dot-separated paths, such as "meta.type"
+// and "data.object", nesting them in intermediate objects. If it returns true from
+// JSONTuple() bool, the union is serialized as a two-element array of the variant name
+// and the variant's data instead, such as ["circle",{"radius":5}].
 //
 // Returns an error if:
 //   - The Spec type is not a struct
-//   - No fields are set (zero state)
+//   - No fields are set (zero state), unless the Spec type returns true from JSONNullable
 //   - Multiple fields are set (invalid state)
 func (u TaggedUnion[Spec]) MarshalJSON() ([]byte, error) {
-	v := reflect.ValueOf(u.Value)
-	t := v.Type()
+	return u.marshalUsing(encodingJSON)
+}
+
+// marshalUsing implements MarshalJSON, encoding the payload with lib.
+func (u TaggedUnion[Spec]) marshalUsing(lib JSONLibrary) ([]byte, error) {
+	return u.appendUsing(nil, lib)
+}
+
+// appendUsing appends the JSON encoding of the union to dst like MarshalJSON,
+// encoding the payload with lib.
+func (u TaggedUnion[Spec]) appendUsing(dst []byte, lib JSONLibrary) ([]byte, error) {
+	p := planOf(reflect.TypeFor[Spec]())
+	if c := codecOf(p.typ); c != nil {
+		return appendResult(dst)(c.marshal(u.Value))
+	}
+	if p.json.nullable && u.IsZero() {
+		return append(dst, "null"...), nil
+	}
+	if p.json.hasMembers {
+		return appendResult(dst)(u.marshalMembers(lib, p.json.members))
+	}
+	// u is a copy, so addressing its value boxes the spec only once
+	v := reflect.ValueOf(&u.Value).Elem()
+	if variant, ok := unitVariant(v, u.selected); ok {
+		return appendJSONString(dst, variant), nil
+	}
+	if p.json.tuple {
+		return u.appendTuple(dst, lib, v)
+	}
+	if p.json.hasPaths {
+		data, err := u.appendJSON(nil, lib, v, "type", "value")
+		if err != nil {
+			return nil, err
+		}
+		return appendResult(dst)(nestEnvelope(data, p.json.variantPath, p.json.valuePath))
+	}
+	return u.appendJSON(dst, lib, v, p.json.variantField, p.json.valueField)
+}
 
-	if t.Kind() != reflect.Struct {
-		return nil, errors.New("spec must be a struct")
+// appendResult returns a function appending the JSON data returned by a marshal
+// function to dst, for use as appendResult(dst)(marshal()).
+func appendResult(dst []byte) func(data []byte, err error) ([]byte, error) {
+	return func(data []byte, err error) ([]byte, error) {
+		if err != nil {
+			return nil, err
+		}
+		return append(dst, data...), nil
 	}
+}
 
-	var value any
-	var variant string
-	for i := 0; i < t.NumField(); i++ {
-		vf := v.Field(i)
-		tf := t.Field(i)
+// appendJSON appends the union, whose spec value is v, to dst using the given variant and
+// value field names. An empty value field selects the flat representation. Once the payload
+// is marshaled, dst is grown to fit the object, so the envelope itself costs no allocations
+// beyond the output.
+func (u TaggedUnion[Spec]) appendJSON(dst []byte, lib JSONLibrary, v reflect.Value, variantField, valueField string) ([]byte, error) {
+	p := planOf(v.Type())
+	f, err := p.current(v, u.selected)
+	if err != nil {
+		return nil, err
+	}
+	variant, value := variantValue(v, f)
 
-		if vf.IsZero() {
-			continue
+	raw, err := marshalPayload(lib, value)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := slices.Grow(dst, len(variantField)+len(valueField)+len(variant)+len(raw)+16)
+	buf = append(buf, '{')
+	buf = appendJSONString(buf, variantField)
+	buf = append(buf, ':')
+	if buf, err = appendDiscriminator(buf, p.json.kind, variant); err != nil {
+		return nil, err
+	}
+	if valueField != "" {
+		if !p.json.optionalValue || !isZeroPayload(reflect.ValueOf(value)) {
+			buf = appendMemberRaw(buf, ',', valueField, raw)
 		}
-		if value != nil {
-			// invariant violation: multiple variants set
-			return nil, errors.New("multiple variants set")
+		if buf, err = p.appendExtras(buf, v, variantField, valueField); err != nil {
+			return nil, err
 		}
-		value = vf.Interface()
-		variant = cmp.Or(tf.Tag.Get("variant"), tf.Name)
+		return append(buf, '}'), nil
 	}
-	if value == nil {
-		return nil, errors.New("zero variants set")
+
+	exists, empty, ok := objectHasKey(raw, variantField)
+	if !ok {
+		// report payloads that are not objects as decoding them into one does
+		var out map[string]json.RawMessage
+		if err := lib.Unmarshal(raw, &out); err != nil {
+			return nil, err
+		}
+		exists, empty = out != nil && out[variantField] != nil, len(out) == 0
+	}
+	if exists {
+		return nil, fmt.Errorf("%w: %s", ErrVariantFieldConflict, variantField)
+	}
+	// splice the variant's own fields after the discriminator, keeping their order
+	if !empty {
+		buf = append(buf, ',')
+		buf = append(buf, bytes.TrimSpace(raw)[1:]...)
+	} else {
+		buf = append(buf, '}')
 	}
+	return buf, nil
+}
 
-	variantField, valueField := u.fieldNames()
-	if valueField != "" {
-		out := map[string]any{
-			variantField: variant,
-			valueField:   value,
+// objectHasKey reports whether the valid JSON data is an object with a top-level member
+// named key, and whether the object is empty, without decoding it. It reports false
+// for ok if data is not an object.
+func objectHasKey(data []byte, key string) (exists, empty, ok bool) {
+	i := skipJSONSpace(data, 0)
+	if i == len(data) || data[i] != '{' {
+		return false, false, false
+	}
+	empty = true
+	for i = skipJSONSpace(data, i+1); i < len(data) && data[i] != '}'; i = skipJSONSpace(data, i) {
+		if data[i] == ',' {
+			i = skipJSONSpace(data, i+1)
+		}
+		empty = false
+		end := skipJSONValue(data, i)
+		name := data[i+1 : end-1]
+		if bytes.IndexByte(name, '\\') >= 0 {
+			var unquoted string
+			if json.Unmarshal(data[i:end], &unquoted) == nil && unquoted == key {
+				return true, false, true
+			}
+		} else if string(name) == key {
+			return true, false, true
 		}
-		return json.Marshal(out)
+		i = skipJSONSpace(data, end) + 1 // the colon
+		i = skipJSONValue(data, skipJSONSpace(data, i))
 	}
+	return false, empty, true
+}
 
-	raw, err := json.Marshal(value)
-	if err != nil {
-		return nil, err
+// skipJSONSpace returns the index of the first non-whitespace byte of data at or after i.
+func skipJSONSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r') {
+		i++
 	}
-	var out map[string]json.RawMessage
-	if err := json.Unmarshal(raw, &out); err != nil {
-		return nil, err
+	return i
+}
+
+// skipJSONValue returns the index following the valid JSON value starting at data[i].
+func skipJSONValue(data []byte, i int) int {
+	depth := 0
+	inString := false
+	for ; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case inString:
+			switch c {
+			case '\\':
+				i++
+			case '"':
+				inString = false
+				if depth == 0 {
+					return i + 1
+				}
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			if depth--; depth == 0 {
+				return i + 1
+			}
+			if depth < 0 {
+				return i
+			}
+		case depth == 0 && (c == ',' || c == ' ' || c == '\t' || c == '\n' || c == '\r'):
+			return i
+		}
+	}
+	return i
+}
+
+// writeMember writes the separator followed by an object member with the given key and value,
+// see appendMember.
+func writeMember(buf *bytes.Buffer, sep byte, key string, value any) error {
+	b, err := appendMember(buf.AvailableBuffer(), sep, key, value)
+	if err != nil {
+		return err
 	}
-	if _, exists := out[variantField]; exists {
-		return nil, errors.New("variant field conflicts with discriminator: " + variantField)
+	buf.Write(b)
+	return nil
+}
+
+// appendMember appends the separator followed by an object member with the given key and value.
+// A json.RawMessage value is written verbatim.
+func appendMember(dst []byte, sep byte, key string, value any) ([]byte, error) {
+	switch value := value.(type) {
+	case json.RawMessage:
+		return appendMemberRaw(dst, sep, key, value), nil
+	case string:
+		dst = append(dst, sep)
+		dst = appendJSONString(dst, key)
+		dst = append(dst, ':')
+		return appendJSONString(dst, value), nil
 	}
-	variantJSON, err := json.Marshal(variant)
+	valueJSON, err := json.Marshal(value)
 	if err != nil {
 		return nil, err
 	}
-	out[variantField] = variantJSON
-	return json.Marshal(out)
+	return appendMemberRaw(dst, sep, key, valueJSON), nil
+}
+
+// appendMemberRaw appends the separator followed by an object member with the given
+// key and the JSON encoded value.
+func appendMemberRaw(dst []byte, sep byte, key string, value []byte) []byte {
+	dst = append(dst, sep)
+	dst = appendJSONString(dst, key)
+	dst = append(dst, ':')
+	return append(dst, value...)
+}
+
+// appendJSONString appends the JSON string encoding of s, escaped like encoding/json does:
+// HTML characters and the U+2028 and U+2029 line separators are escaped, and strings
+// with invalid UTF-8 are encoded by encoding/json.
+func appendJSONString(dst []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	mark := len(dst)
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= ' ' && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			// invalid UTF-8 is rare, leave its replacement to encoding/json
+			data, _ := json.Marshal(s)
+			return append(dst[:mark], data...)
+		}
+		if r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hex[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
@@ -211,43 +494,134 @@ func (u TaggedUnion[Spec]) MarshalJSON() ([]byte, error) {
 //  1. Reading the variant field to determine which variant is active
 //  2. Unmarshaling the value field into the corresponding struct field
 //
-// The method handles both pointer and non-pointer fields correctly.
+// The method handles both pointer and non-pointer fields correctly. A bare JSON
+// string decodes into the unit variant it names, as does an object without the value field.
+// The variant and value fields are read from the paths returned by JSONDiscriminatorPath
+// if the Spec type implements it, and from the elements of a [variant, value] array if
+// it returns true from JSONTuple, where a one-element array is an envelope without the
+// value field and other lengths fail with ErrTupleLength.
 //
 // Returns an error if:
 //   - The JSON data is malformed
 //   - The Spec type is not a struct
 //   - The variant or value fields are missing
-//   - The variant field doesn't match any known variant
+//   - The variant field doesn't match any known variant (*UnknownVariantError)
 //   - Multiple struct fields match the same variant (invalid Spec definition)
-//   - The value cannot be unmarshaled into the target field type
+//   - The value cannot be unmarshaled into the target field type (*DecodeError)
+//
+// A Spec type returning true from a JSONOptionalValue() bool method accepts objects
+// without the value field, decoding them into the variant's zero payload (a pointer
+// to the zero value for pointer fields). Zero payloads are then marshaled without
+// the value field.
+//
+// A Spec type returning true from a JSONStrict() bool method additionally rejects
+// objects with keys other than the variant and value fields (ErrUnknownField)
+// and payloads with fields unknown to the variant's type (*DecodeError).
+//
+// A Spec type returning true from a JSONNullable() bool method decodes JSON null
+// into the empty union, which is then marshaled as null instead of failing.
+//
+// A Spec type returning true from a JSONUseNumber() bool method decodes numbers in
+// interface values of payloads, such as map[string]any fields, as json.Number instead
+// of float64, so large integer IDs keep their precision.
+//
+// A Spec type with a JSONMemberDiscriminator() string method uses the Kubernetes union
+// representation instead, where the payload is held by a member field named after the
+// variant next to the discriminator field, as in {"type": "RollingUpdate", "rollingUpdate": {...}}.
+// The member field is the variant name with its first letter lowercased. When the
+// discriminator field is missing, the variant is inferred from the only member field set,
+// and members of other variants are rejected with ErrMultipleVariants.
 func (u *TaggedUnion[Spec]) UnmarshalJSON(data []byte) error {
+	return u.unmarshalUsing(encodingJSON, data)
+}
+
+// unmarshalUsing implements UnmarshalJSON, decoding the payload with lib.
+func (u *TaggedUnion[Spec]) unmarshalUsing(lib JSONLibrary, data []byte) error {
+	if err := decodeLimits(lib, u.Value).check(data); err != nil {
+		var zero Spec
+		u.Value = zero
+		u.selected = nil
+		return err
+	}
+	if c := codecOf(reflect.TypeFor[Spec]()); c != nil {
+		var zero Spec
+		u.Value = zero
+		u.selected = nil
+		return c.unmarshal(data, &u.Value)
+	}
+	if isJSONNull(data) && isNullable(u.Value) {
+		var zero Spec
+		u.Value = zero
+		u.selected = nil
+		return nil
+	}
+	if discriminator, ok := u.memberDiscriminator(); ok {
+		return u.unmarshalMembers(lib, data, discriminator)
+	}
+	if isJSONString(data) {
+		var zero Spec
+		u.Value = zero
+		u.selected = nil
+		return unmarshalUnit(reflect.ValueOf(&u.Value).Elem(), data)
+	}
+	if planOf(reflect.TypeFor[Spec]()).json.tuple {
+		return u.unmarshalTuple(lib, data)
+	}
+	if variantPath, valuePath, ok := u.discriminatorPaths(); ok {
+		envelope, err := flattenEnvelope(data, variantPath, valuePath, payloadOptions(lib, u.Value).strict)
+		if err != nil {
+			var zero Spec
+			u.Value = zero
+			u.selected = nil
+			return err
+		}
+		return atValuePath(u.unmarshalJSON(lib, envelope, "type", "value"), valuePath)
+	}
+	variantField, valueField := u.fieldNames()
+	return u.unmarshalJSON(lib, data, variantField, valueField)
+}
+
+// unmarshalJSON deserializes the union using the given variant and value field names.
+// An empty value field selects the flat representation.
+func (u *TaggedUnion[Spec]) unmarshalJSON(lib JSONLibrary, data []byte, variantField, valueField string) error {
 	var zero Spec
 	u.Value = zero
+	u.selected = nil
 
 	v := reflect.ValueOf(&u.Value).Elem()
 	t := v.Type()
+	p := planOf(t)
 
-	if t.Kind() != reflect.Struct {
-		return errors.New("spec must be a struct")
+	if !p.isStruct {
+		return ErrSpecNotStruct
 	}
 
 	var raw map[string]json.RawMessage
-	if err := json.Unmarshal(data, &raw); err != nil {
+	if err := lib.Unmarshal(data, &raw); err != nil {
 		return err
 	}
 
-	variantField, valueField := u.fieldNames()
 	rawVariant, ok := raw[variantField]
 	if !ok {
-		return errors.New("missing variant field: " + variantField)
+		return fmt.Errorf("%w: %s", ErrMissingVariantField, variantField)
+	}
+
+	opts := payloadOptions(lib, u.Value)
+	// other members are captured by an Extras field, or rejected in strict mode
+	if valueField != "" && !p.setExtras(v, raw, variantField, valueField) && opts.strict {
+		for _, key := range slices.Sorted(maps.Keys(raw)) {
+			if key != variantField && key != valueField {
+				return fmt.Errorf("%w: %s", ErrUnknownField, key)
+			}
+		}
 	}
 
+	// the value field may be missing for unit variants and JSONOptionalValue specs
 	var rawValue json.RawMessage
+	missingValue := false
 	if valueField != "" {
 		rawValue, ok = raw[valueField]
-		if !ok {
-			return errors.New("missing value field: " + valueField)
-		}
+		missingValue = !ok && !hasOptionalValue(u.Value)
 	} else {
 		delete(raw, variantField)
 		payload, err := json.Marshal(raw)
@@ -257,34 +631,156 @@ func (u *TaggedUnion[Spec]) UnmarshalJSON(data []byte) error {
 		rawValue = payload
 	}
 
-	var variant string
-	if err := json.Unmarshal(rawVariant, &variant); err != nil {
+	if err := decodeLimits(lib, u.Value).checkValue(rawValue); err != nil {
 		return err
 	}
 
-	var matched bool
-	for i := 0; i < t.NumField(); i++ {
-		vf := v.Field(i)
-		tf := t.Field(i)
+	variant, err := decodeDiscriminator(discriminatorKind(u.Value), rawVariant)
+	if err != nil {
+		return err
+	}
 
-		if cmp.Or(tf.Tag.Get("variant"), tf.Name) != variant {
-			continue
-		}
-		if matched {
-			return errors.New("multiple fields matched")
+	f, err := p.resolve(variant)
+	if missingValue && (err != nil || !f.unit) {
+		return fmt.Errorf("%w: %s", ErrMissingValueField, valueField)
+	}
+	if err != nil {
+		if errors.Is(err, ErrUnknownVariant) {
+			if ok, err := p.setImpl(lib, v, variant, rawValue, opts); ok {
+				return err
+			}
+			if f, ok, err := p.setUpcast(lib, v, variant, rawValue, opts); ok {
+				if err == nil {
+					u.selected = selection(v, f)
+				}
+				return err
+			}
+			if p.setRaw(v, variant, rawValue) {
+				return nil
+			}
 		}
+		return err
+	}
 
-		target := reflect.New(tf.Type)
-		if err := json.Unmarshal(rawValue, target.Interface()); err != nil {
-			return err
-		}
+	if rawValue == nil {
+		v.FieldByIndex(f.index).Set(zeroPayload(f))
+		u.selected = selection(v, f)
+		return nil
+	}
 
-		vf.Set(target.Elem())
-		matched = true
+	target, err := decodeField(lib, f, rawValue, opts)
+	if err != nil {
+		return payloadError(t, variant, f, valueField, err)
 	}
-	if !matched {
-		return errors.New("unknown variant: " + variant)
+
+	v.FieldByIndex(f.index).Set(target.Elem())
+	u.selected = selection(v, f)
+	return nil
+}
+
+// unitVariant returns the variant name of the active field of the spec value v,
+// or the selected field if no field is set, if it is a unit variant, which is marshaled as a bare JSON string.
+func unitVariant(v reflect.Value, selected *fieldPlan) (string, bool) {
+	f, err := planOf(v.Type()).current(v, selected)
+	if err != nil || !f.unit {
+		return "", false
 	}
+	return f.variant, true
+}
+
+// isJSONString reports whether data holds a JSON string.
+func isJSONString(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '"'
+}
+
+// errNotUnit is returned when a bare JSON string names a variant with a payload.
+var errNotUnit = errors.New("not a unit variant")
 
+// unmarshalUnit decodes a bare JSON string naming a unit variant into the spec value v.
+func unmarshalUnit(v reflect.Value, data []byte) error {
+	p := planOf(v.Type())
+	if !p.isStruct {
+		return ErrSpecNotStruct
+	}
+
+	var variant string
+	if err := json.Unmarshal(data, &variant); err != nil {
+		return err
+	}
+	f, err := p.resolve(variant)
+	if err != nil {
+		return err
+	}
+	if !f.unit {
+		return &DecodeError{Spec: p.typ, Variant: variant, Field: f.name, Err: errNotUnit}
+	}
+	v.FieldByIndex(f.index).Set(zeroPayload(f))
 	return nil
 }
+
+// hasOptionalValue reports whether the Spec type opts into omitting the value field
+// of zero payloads with a JSONOptionalValue() bool method.
+func hasOptionalValue(spec any) bool {
+	s, ok := spec.(interface{ JSONOptionalValue() bool })
+	return ok && s.JSONOptionalValue()
+}
+
+// zeroPayload returns the zero payload of the field, allocated for pointer fields
+// so the variant is active. Slice and map fields get an empty, non-nil value.
+func zeroPayload(f *fieldPlan) reflect.Value {
+	switch {
+	case f.pointer:
+		return reflect.New(f.typ.Elem())
+	case f.typ.Kind() == reflect.Slice:
+		return reflect.MakeSlice(f.typ, 0, 0)
+	case f.typ.Kind() == reflect.Map:
+		return reflect.MakeMap(f.typ)
+	}
+	return reflect.Zero(f.typ)
+}
+
+// isZeroPayload reports whether the variant value v, or the value it points to, is zero.
+func isZeroPayload(v reflect.Value) bool {
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	return !v.IsValid() || isZeroValue(v)
+}
+
+// isStrict reports whether the Spec type opts into strict decoding with a JSONStrict() bool method.
+func isStrict(spec any) bool {
+	s, ok := spec.(interface{ JSONStrict() bool })
+	return ok && s.JSONStrict()
+}
+
+// usesNumber reports whether the Spec type opts into decoding numbers in interface values
+// of variant payloads as json.Number, like json.Decoder.UseNumber, with a JSONUseNumber() bool method.
+func usesNumber(spec any) bool {
+	s, ok := spec.(interface{ JSONUseNumber() bool })
+	return ok && s.JSONUseNumber()
+}
+
+// decodeOptions control how variant payloads are decoded.
+type decodeOptions struct {
+	strict    bool // reject unknown fields, see isStrict
+	useNumber bool // keep numbers in interface values as json.Number, see usesNumber
+}
+
+// payloadOptions returns the decodeOptions the Spec type or the Options of the call decoding with lib opt into.
+func payloadOptions(lib JSONLibrary, spec any) decodeOptions {
+	o := callOptions(lib)
+	return decodeOptions{strict: o.Strict || isStrict(spec), useNumber: o.UseNumber || usesNumber(spec)}
+}
+
+// isNullable reports whether the Spec type opts into representing an empty union
+// as JSON null with a JSONNullable() bool method.
+func isNullable(spec any) bool {
+	s, ok := spec.(interface{ JSONNullable() bool })
+	return ok && s.JSONNullable()
+}
+
+// isJSONNull reports whether data holds a JSON null.
+func isJSONNull(data []byte) bool {
+	return string(bytes.TrimSpace(data)) == "null"
+}
@@ -2,6 +2,8 @@ package union
 
 import (
 	"encoding/json"
+	"errors"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -88,6 +90,87 @@ type ConflictingFlatShape struct {
 
 func (s ConflictingFlatShape) JSONDiscriminator() string { return "type" }
 
+type FlatEmptyPayloadShape struct {
+	Ping *struct{} `variant:"ping"`
+}
+
+func (s FlatEmptyPayloadShape) JSONDiscriminator() string { return "type" }
+
+type StrictShape struct {
+	Circle *Circle `variant:"circle"`
+}
+
+func (s StrictShape) JSONStrict() bool { return true }
+
+type MappedShape struct {
+	Circle    *Circle    `variant:"circle"`
+	Rectangle *Rectangle `variant:"rectangle"`
+}
+
+func (MappedShape) VariantOf(discriminator string) (string, bool) {
+	switch {
+	case strings.HasPrefix(discriminator, "shapes.v1.circle"):
+		return "Circle", true
+	case discriminator == "rect":
+		return "Rectangle", true
+	case discriminator == "square":
+		return "Square", true
+	}
+	return "", false
+}
+
+type Metadata struct {
+	Fields map[string]any `json:"fields"`
+}
+
+type NumberShape struct {
+	Metadata *Metadata `variant:"metadata"`
+	Value    *any      `variant:"value"`
+}
+
+func (s NumberShape) JSONUseNumber() bool { return true }
+
+type OptionalValueShape struct {
+	Circle *Circle `variant:"circle"`
+}
+
+func (s OptionalValueShape) JSONOptionalValue() bool { return true }
+
+type (
+	Idle      struct{}
+	UnitShape struct {
+		Idle   *Idle   `variant:"idle" union:"unit"`
+		Circle *Circle `variant:"circle"`
+	}
+)
+
+type StrictFlatShape struct {
+	Circle *Circle `variant:"circle"`
+}
+
+func (s StrictFlatShape) JSONDiscriminator() string { return "type" }
+
+func (s StrictFlatShape) JSONStrict() bool { return true }
+
+type CaseInsensitiveShape struct {
+	Circle    *Circle    `variant:"circle"`
+	Rectangle *Rectangle `variant:"rectangle"`
+}
+
+func (s CaseInsensitiveShape) CaseInsensitiveVariants() bool { return true }
+
+type FoldConflictShape struct {
+	Circle *Circle `variant:"circle"`
+	Round  *Circle `variant:"CIRCLE"`
+}
+
+func (s FoldConflictShape) CaseInsensitiveVariants() bool { return true }
+
+type AliasShape struct {
+	Circle    *Circle    `variant:"circle" variantAliases:"circ,round"`
+	Rectangle *Rectangle `variant:"rectangle"`
+}
+
 type ConflictingCircle struct {
 	Type   string  `json:"type"`
 	Radius float64 `json:"radius"`
@@ -198,7 +281,7 @@ func TestMarshalJSON(t *testing.T) {
 					Circle: &Circle{Radius: 5.0},
 				},
 			},
-			expected: `{"radius":5,"type":"circle"}`,
+			expected: `{"type":"circle","radius":5}`,
 		},
 		{
 			name: "marshals flat rectangle variant",
@@ -207,7 +290,7 @@ func TestMarshalJSON(t *testing.T) {
 					Rectangle: &Rectangle{Width: 10, Height: 5},
 				},
 			},
-			expected: `{"height":5,"type":"rectangle","width":10}`,
+			expected: `{"type":"rectangle","width":10,"height":5}`,
 		},
 		{
 			name: "marshals flat triangle variant",
@@ -216,7 +299,16 @@ func TestMarshalJSON(t *testing.T) {
 					Triangle: &Triangle{Base: 8, Height: 4},
 				},
 			},
-			expected: `{"base":8,"height":4,"type":"triangle"}`,
+			expected: `{"type":"triangle","base":8,"height":4}`,
+		},
+		{
+			name: "marshals flat variant with empty payload",
+			shape: TaggedUnion[FlatEmptyPayloadShape]{
+				Value: FlatEmptyPayloadShape{
+					Ping: &struct{}{},
+				},
+			},
+			expected: `{"type":"ping"}`,
 		},
 		{
 			name: "returns error when flat variant field conflicts with discriminator",
@@ -252,7 +344,7 @@ func TestMarshalJSON(t *testing.T) {
 					Circle: &Circle{Radius: 5.0},
 				},
 			},
-			expected: `{"data":{"radius":5},"kind":"circle"}`,
+			expected: `{"kind":"circle","data":{"radius":5}}`,
 		},
 		{
 			name: "marshals with custom variant name",
@@ -270,7 +362,7 @@ func TestMarshalJSON(t *testing.T) {
 					Circle: &Circle{Radius: 5.0},
 				},
 			},
-			expected: `{"data":{"radius":5},"type":"circle"}`,
+			expected: `{"type":"circle","data":{"radius":5}}`,
 		},
 		{
 			name: "marshals non-pointer variant",
@@ -583,3 +675,570 @@ func assertValueEquals(t *testing.T, value, expected any) {
 		t.Fatalf("unexpected expected type: %T", expected)
 	}
 }
+
+func TestVariant(t *testing.T) {
+	tests := []struct {
+		name     string
+		shape    interface{ Variant() (string, bool) }
+		expected string
+		ok       bool
+	}{
+		{
+			name:     "returns tagged variant name",
+			shape:    TaggedUnion[Shape]{Value: Shape{Rectangle: &Rectangle{Width: 10, Height: 5}}},
+			expected: "rectangle",
+			ok:       true,
+		},
+		{
+			name:     "returns field name without struct tags",
+			shape:    TaggedUnion[NonStructTagsShape]{Value: NonStructTagsShape{Circle: &Circle{Radius: 5.0}}},
+			expected: "Circle",
+			ok:       true,
+		},
+		{
+			name:     "returns externally tagged variant name",
+			shape:    ExternallyTagged[Shape]{Value: Shape{Triangle: &Triangle{Base: 8, Height: 4}}},
+			expected: "triangle",
+			ok:       true,
+		},
+		{
+			name:     "returns untagged variant name",
+			shape:    Union[UnionShape]{Value: UnionShape{Circle: &Circle{Radius: 5.0}}},
+			expected: "Circle",
+			ok:       true,
+		},
+		{
+			name:  "returns false when no variant is set",
+			shape: TaggedUnion[Shape]{},
+		},
+		{
+			name: "returns false when multiple variants are set",
+			shape: TaggedUnion[Shape]{Value: Shape{
+				Circle:    &Circle{Radius: 5.0},
+				Rectangle: &Rectangle{Width: 10, Height: 5},
+			}},
+		},
+		{
+			name:  "returns false for non-struct type",
+			shape: TaggedUnion[NonStructType]{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variant, ok := tt.shape.Variant()
+			if ok != tt.ok || variant != tt.expected {
+				t.Errorf("expected (%q, %v), got (%q, %v)", tt.expected, tt.ok, variant, ok)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSONStrict(t *testing.T) {
+	tests := []struct {
+		name        string
+		shape       interface{ GetValue() any }
+		jsonData    string
+		expected    any
+		expectedErr string
+	}{
+		{
+			name:     "accepts exact envelope",
+			shape:    &TaggedUnion[StrictShape]{},
+			jsonData: `{"type":"circle","value":{"radius":5}}`,
+			expected: Circle{Radius: 5.0},
+		},
+		{
+			name:        "rejects unknown envelope keys",
+			shape:       &TaggedUnion[StrictShape]{},
+			jsonData:    `{"type":"circle","value":{"radius":5},"debug":true}`,
+			expectedErr: "unknown field: debug",
+		},
+		{
+			name:        "rejects unknown payload fields",
+			shape:       &TaggedUnion[StrictShape]{},
+			jsonData:    `{"type":"circle","value":{"radius":5,"color":"red"}}`,
+			expectedErr: `variant "circle": json: unknown field "color"`,
+		},
+		{
+			name:     "accepts exact flat object",
+			shape:    &TaggedUnion[StrictFlatShape]{},
+			jsonData: `{"type":"circle","radius":5}`,
+			expected: Circle{Radius: 5.0},
+		},
+		{
+			name:        "rejects unknown flat fields",
+			shape:       &TaggedUnion[StrictFlatShape]{},
+			jsonData:    `{"type":"circle","radius":5,"color":"red"}`,
+			expectedErr: `variant "circle": json: unknown field "color"`,
+		},
+		{
+			name:        "rejects unknown externally tagged payload fields",
+			shape:       &ExternallyTagged[StrictShape]{},
+			jsonData:    `{"circle":{"radius":5,"color":"red"}}`,
+			expectedErr: `variant "circle": json: unknown field "color"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := json.Unmarshal([]byte(tt.jsonData), tt.shape)
+
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("expected error '%s', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertValueEquals(t, tt.shape.GetValue(), tt.expected)
+		})
+	}
+}
+
+func TestUseNumber(t *testing.T) {
+	number := any(json.Number("1.50"))
+	tests := []struct {
+		name     string
+		shape    interface{ GetValue() any }
+		jsonData string
+		expected any
+	}{
+		{
+			name:     "keeps large integers in maps",
+			shape:    &TaggedUnion[NumberShape]{},
+			jsonData: `{"type":"metadata","value":{"fields":{"id":9007199254740993}}}`,
+			expected: &Metadata{Fields: map[string]any{"id": json.Number("9007199254740993")}},
+		},
+		{
+			name:     "keeps interface payloads",
+			shape:    &ExternallyTagged[NumberShape]{},
+			jsonData: `{"value":1.50}`,
+			expected: &number,
+		},
+		{
+			name:     "keeps untagged payloads",
+			shape:    &Union[NumberShape]{},
+			jsonData: `{"fields":{"id":12345678901234567890}}`,
+			expected: &Metadata{Fields: map[string]any{"id": json.Number("12345678901234567890")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(tt.jsonData), tt.shape); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := tt.shape.GetValue(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %#v, got %#v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestVariantOf(t *testing.T) {
+	tests := []struct {
+		name        string
+		shape       interface{ GetValue() any }
+		jsonData    string
+		expected    any
+		expectedErr string
+	}{
+		{
+			name:     "maps prefixed discriminators",
+			shape:    &TaggedUnion[MappedShape]{},
+			jsonData: `{"type":"shapes.v1.circle.Circle","value":{"radius":5}}`,
+			expected: Circle{Radius: 5.0},
+		},
+		{
+			name:     "maps table entries",
+			shape:    &ExternallyTagged[MappedShape]{},
+			jsonData: `{"rect":{"width":10,"height":5}}`,
+			expected: Rectangle{Width: 10, Height: 5},
+		},
+		{
+			name:     "falls back to variant names",
+			shape:    &TaggedUnion[MappedShape]{},
+			jsonData: `{"type":"rectangle","value":{"width":10,"height":5}}`,
+			expected: Rectangle{Width: 10, Height: 5},
+		},
+		{
+			name:        "rejects unmapped discriminators",
+			shape:       &TaggedUnion[MappedShape]{},
+			jsonData:    `{"type":"hexagon","value":{}}`,
+			expectedErr: "unknown variant: hexagon",
+		},
+		{
+			name:        "rejects unknown field names",
+			shape:       &TaggedUnion[MappedShape]{},
+			jsonData:    `{"type":"square","value":{}}`,
+			expectedErr: "unknown variant: square",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := json.Unmarshal([]byte(tt.jsonData), tt.shape)
+
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("expected error '%s', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertValueEquals(t, tt.shape.GetValue(), tt.expected)
+		})
+	}
+
+	data, err := json.Marshal(TaggedUnion[MappedShape]{Value: MappedShape{Circle: &Circle{Radius: 5}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `{"type":"circle","value":{"radius":5}}`; string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+}
+
+func TestUnmarshalJSONCaseInsensitive(t *testing.T) {
+	tests := []struct {
+		name        string
+		shape       interface{ GetValue() any }
+		jsonData    string
+		expected    any
+		expectedErr string
+	}{
+		{
+			name:     "matches exact case",
+			shape:    &TaggedUnion[CaseInsensitiveShape]{},
+			jsonData: `{"type":"circle","value":{"radius":5}}`,
+			expected: Circle{Radius: 5.0},
+		},
+		{
+			name:     "matches title case",
+			shape:    &TaggedUnion[CaseInsensitiveShape]{},
+			jsonData: `{"type":"Circle","value":{"radius":5}}`,
+			expected: Circle{Radius: 5.0},
+		},
+		{
+			name:     "matches upper case",
+			shape:    &ExternallyTagged[CaseInsensitiveShape]{},
+			jsonData: `{"RECTANGLE":{"width":10,"height":5}}`,
+			expected: Rectangle{Width: 10, Height: 5},
+		},
+		{
+			name:        "is case sensitive by default",
+			shape:       &TaggedUnion[Shape]{},
+			jsonData:    `{"type":"Circle","value":{"radius":5}}`,
+			expectedErr: "unknown variant: Circle",
+		},
+		{
+			name:     "prefers exact match",
+			shape:    &TaggedUnion[FoldConflictShape]{},
+			jsonData: `{"type":"CIRCLE","value":{"radius":5}}`,
+			expected: Circle{Radius: 5.0},
+		},
+		{
+			name:        "returns error for ambiguous folded match",
+			shape:       &TaggedUnion[FoldConflictShape]{},
+			jsonData:    `{"type":"Circle","value":{"radius":5}}`,
+			expectedErr: "multiple fields matched",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := json.Unmarshal([]byte(tt.jsonData), tt.shape)
+
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("expected error '%s', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertValueEquals(t, tt.shape.GetValue(), tt.expected)
+		})
+	}
+}
+
+func TestVariantAliases(t *testing.T) {
+	for _, variant := range []string{"circle", "circ", "round"} {
+		t.Run(variant, func(t *testing.T) {
+			var shape TaggedUnion[AliasShape]
+			data := `{"type":"` + variant + `","value":{"radius":5}}`
+			if err := json.Unmarshal([]byte(data), &shape); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertValueEquals(t, shape.GetValue(), Circle{Radius: 5.0})
+
+			out, err := json.Marshal(shape)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expected := `{"type":"circle","value":{"radius":5}}`; string(out) != expected {
+				t.Errorf("expected %s, got %s", expected, out)
+			}
+		})
+	}
+
+	var shape ExternallyTagged[AliasShape]
+	if err := json.Unmarshal([]byte(`{"round":{"radius":5}}`), &shape); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, shape.GetValue(), Circle{Radius: 5.0})
+
+	if variants := Variants[AliasShape](); len(variants) != 2 {
+		t.Errorf("expected aliases to be excluded from variants, got %v", variants)
+	}
+}
+
+func TestOptionalValue(t *testing.T) {
+	var shape TaggedUnion[OptionalValueShape]
+	if err := json.Unmarshal([]byte(`{"type":"circle"}`), &shape); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, shape.GetValue(), Circle{})
+
+	data, err := json.Marshal(shape)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `{"type":"circle"}`; string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	shape.Value.Circle.Radius = 5
+	data, err = json.Marshal(shape)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `{"type":"circle","value":{"radius":5}}`; string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	var required TaggedUnion[Shape]
+	err = json.Unmarshal([]byte(`{"type":"circle"}`), &required)
+	if !errors.Is(err, ErrMissingValueField) {
+		t.Errorf("expected error '%v', got '%v'", ErrMissingValueField, err)
+	}
+}
+
+func TestUnitVariants(t *testing.T) {
+	tests := []struct {
+		name  string
+		shape interface {
+			json.Marshaler
+			json.Unmarshaler
+			GetValue() any
+		}
+		jsonData    string
+		expected    any
+		marshaled   string
+		expectedErr string
+	}{
+		{
+			name:      "decodes tagged unit variant from string",
+			shape:     &TaggedUnion[UnitShape]{},
+			jsonData:  `"idle"`,
+			expected:  &Idle{},
+			marshaled: `"idle"`,
+		},
+		{
+			name:      "decodes tagged unit variant without value field",
+			shape:     &TaggedUnion[UnitShape]{},
+			jsonData:  `{"type":"idle"}`,
+			expected:  &Idle{},
+			marshaled: `"idle"`,
+		},
+		{
+			name:      "decodes externally tagged unit variant from string",
+			shape:     &ExternallyTagged[UnitShape]{},
+			jsonData:  `"idle"`,
+			expected:  &Idle{},
+			marshaled: `"idle"`,
+		},
+		{
+			name:      "keeps data variants as objects",
+			shape:     &TaggedUnion[UnitShape]{},
+			jsonData:  `{"type":"circle","value":{"radius":5}}`,
+			expected:  Circle{Radius: 5.0},
+			marshaled: `{"type":"circle","value":{"radius":5}}`,
+		},
+		{
+			name:        "rejects string naming a data variant",
+			shape:       &TaggedUnion[UnitShape]{},
+			jsonData:    `"circle"`,
+			expectedErr: `variant "circle": not a unit variant`,
+		},
+		{
+			name:        "rejects string naming an unknown variant",
+			shape:       &ExternallyTagged[UnitShape]{},
+			jsonData:    `"square"`,
+			expectedErr: "unknown variant: square",
+		},
+		{
+			name:        "requires value field for data variants",
+			shape:       &TaggedUnion[UnitShape]{},
+			jsonData:    `{"type":"circle"}`,
+			expectedErr: "missing value field: value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.shape.UnmarshalJSON([]byte(tt.jsonData))
+
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("expected error '%s', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if idle, ok := tt.expected.(*Idle); ok {
+				if got, ok := tt.shape.GetValue().(*Idle); !ok || *got != *idle {
+					t.Errorf("expected %v, got %v", idle, tt.shape.GetValue())
+				}
+			} else {
+				assertValueEquals(t, tt.shape.GetValue(), tt.expected)
+			}
+
+			data, err := tt.shape.MarshalJSON()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(data) != tt.marshaled {
+				t.Errorf("expected %s, got %s", tt.marshaled, data)
+			}
+		})
+	}
+}
+
+func TestIsZero(t *testing.T) {
+	tests := []struct {
+		name     string
+		shape    interface{ IsZero() bool }
+		expected bool
+	}{
+		{name: "empty tagged union", shape: TaggedUnion[Shape]{}, expected: true},
+		{name: "set tagged union", shape: TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 5.0}}}, expected: false},
+		{name: "empty externally tagged", shape: ExternallyTagged[Shape]{}, expected: true},
+		{name: "set externally tagged", shape: ExternallyTagged[Shape]{Value: Shape{Rectangle: &Rectangle{Width: 1}}}, expected: false},
+		{name: "empty union", shape: Union[UnionNonPointerShape]{}, expected: true},
+		{name: "set union", shape: Union[UnionNonPointerShape]{Value: UnionNonPointerShape{Circle: Circle{Radius: 5.0}}}, expected: false},
+		{name: "multiple variants", shape: TaggedUnion[Shape]{Value: Shape{Circle: &Circle{}, Rectangle: &Rectangle{}}}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.shape.IsZero(); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("selected zero payload", func(t *testing.T) {
+		var shape TaggedUnion[NonPointerShape]
+		if err := shape.Select("rectangle"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if shape.IsZero() {
+			t.Error("expected selected union not to be zero")
+		}
+	})
+
+	t.Run("omitzero", func(t *testing.T) {
+		type response struct {
+			Shape TaggedUnion[Shape] `json:"shape,omitzero"`
+		}
+		data, err := json.Marshal(response{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(data) != `{}` {
+			t.Errorf("expected {}, got %s", data)
+		}
+	})
+}
+
+type NullableShape struct {
+	Circle    *Circle    `variant:"circle"`
+	Rectangle *Rectangle `variant:"rectangle"`
+}
+
+func (NullableShape) JSONNullable() bool { return true }
+
+func TestNullable(t *testing.T) {
+	t.Run("marshals empty union as null", func(t *testing.T) {
+		type response struct {
+			Tagged   TaggedUnion[NullableShape]      `json:"tagged"`
+			External ExternallyTagged[NullableShape] `json:"external"`
+			Untagged Union[NullableShape]            `json:"untagged"`
+		}
+		data, err := json.Marshal(response{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := `{"tagged":null,"external":null,"untagged":null}`
+		if string(data) != expected {
+			t.Errorf("expected %s, got %s", expected, data)
+		}
+	})
+
+	t.Run("unmarshals null as empty union", func(t *testing.T) {
+		shape := TaggedUnion[NullableShape]{Value: NullableShape{Circle: &Circle{Radius: 5.0}}}
+		if err := json.Unmarshal([]byte(`null`), &shape); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !shape.IsZero() {
+			t.Errorf("expected empty union, got %+v", shape.Value)
+		}
+
+		external := ExternallyTagged[NullableShape]{Value: NullableShape{Circle: &Circle{Radius: 5.0}}}
+		if err := external.UnmarshalJSON([]byte(` null `)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !external.IsZero() {
+			t.Errorf("expected empty union, got %+v", external.Value)
+		}
+
+		var untagged Union[NullableShape]
+		if err := untagged.UnmarshalJSON([]byte(`null`)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !untagged.IsZero() {
+			t.Errorf("expected empty union, got %+v", untagged.Value)
+		}
+	})
+
+	t.Run("marshals set union as usual", func(t *testing.T) {
+		shape := TaggedUnion[NullableShape]{Value: NullableShape{Circle: &Circle{Radius: 5.0}}}
+		data, err := json.Marshal(shape)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := `{"type":"circle","value":{"radius":5}}`
+		if string(data) != expected {
+			t.Errorf("expected %s, got %s", expected, data)
+		}
+	})
+
+	t.Run("fails without option", func(t *testing.T) {
+		if _, err := json.Marshal(TaggedUnion[Shape]{}); !errors.Is(err, ErrZeroVariants) {
+			t.Errorf("expected ErrZeroVariants, got %v", err)
+		}
+	})
+}
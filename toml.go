@@ -0,0 +1,128 @@
+package union
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"slices"
+)
+
+// The TOML methods implement the Marshaler and Unmarshaler interfaces of
+// github.com/BurntSushi/toml without depending on it. Unions are converted
+// through their JSON representation, so variant payloads use their json struct tags
+// and the same variant rules as MarshalJSON and UnmarshalJSON apply.
+
+// MarshalTOML implements the toml.Marshaler interface.
+// It serializes the union as an inline TOML table with the same shape as MarshalJSON.
+func (u TaggedUnion[Spec]) MarshalTOML() ([]byte, error) {
+	return marshalTOML(u)
+}
+
+// UnmarshalTOML implements the toml.Unmarshaler interface.
+// It deserializes a decoded TOML table with the same rules as UnmarshalJSON.
+func (u *TaggedUnion[Spec]) UnmarshalTOML(data any) error {
+	return unmarshalTOML(u, data)
+}
+
+// MarshalTOML implements the toml.Marshaler interface.
+// It serializes the union as an inline TOML table with the same shape as MarshalJSON.
+func (u ExternallyTagged[Spec]) MarshalTOML() ([]byte, error) {
+	return marshalTOML(u)
+}
+
+// UnmarshalTOML implements the toml.Unmarshaler interface.
+// It deserializes a decoded TOML table with the same rules as UnmarshalJSON.
+func (u *ExternallyTagged[Spec]) UnmarshalTOML(data any) error {
+	return unmarshalTOML(u, data)
+}
+
+// MarshalTOML implements the toml.Marshaler interface.
+// It serializes the union's active variant data directly as an inline TOML value.
+func (u Union[Spec]) MarshalTOML() ([]byte, error) {
+	return marshalTOML(u)
+}
+
+// UnmarshalTOML implements the toml.Unmarshaler interface.
+// It deserializes a decoded TOML value with the same rules as UnmarshalJSON.
+func (u *Union[Spec]) UnmarshalTOML(data any) error {
+	return unmarshalTOML(u, data)
+}
+
+// marshalTOML converts the JSON representation of u to an inline TOML value.
+func marshalTOML(u json.Marshaler) ([]byte, error) {
+	value, err := jsonValue(u)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := writeTOML(&buf, value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unmarshalTOML converts a decoded TOML value to JSON and unmarshals it into u.
+func unmarshalTOML(u json.Unmarshaler, value any) error {
+	return fromJSONValue(u, value)
+}
+
+// bareKey matches TOML keys that don't need quoting.
+var bareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// writeTOML writes a value decoded from JSON as an inline TOML value.
+func writeTOML(buf *bytes.Buffer, value any) error {
+	switch value := value.(type) {
+	case nil:
+		return errors.New("toml: null values are not supported")
+	case bool, json.Number:
+		// JSON booleans and numbers are valid TOML
+		data, _ := json.Marshal(value)
+		buf.Write(data)
+	case string:
+		// JSON string escapes are a subset of TOML basic string escapes
+		data, _ := json.Marshal(value)
+		buf.Write(data)
+	case []any:
+		buf.WriteByte('[')
+		for i, elem := range value {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			if err := writeTOML(buf, elem); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte(' ')
+			if bareKey.MatchString(key) {
+				buf.WriteString(key)
+			} else {
+				data, _ := json.Marshal(key)
+				buf.Write(data)
+			}
+			buf.WriteString(" = ")
+			if err := writeTOML(buf, value[key]); err != nil {
+				return err
+			}
+		}
+		if len(keys) > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteByte('}')
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package union
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMarshalTOML(t *testing.T) {
+	tests := []struct {
+		name        string
+		shape       interface{ MarshalTOML() ([]byte, error) }
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "marshals tagged union as inline table",
+			shape:    TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 5.5}}},
+			expected: `{ type = "circle", value = { radius = 5.5 } }`,
+		},
+		{
+			name:     "marshals flat tagged union as inline table",
+			shape:    TaggedUnion[FlatShape]{Value: FlatShape{Rectangle: &Rectangle{Width: 10, Height: 5}}},
+			expected: `{ height = 5, type = "rectangle", width = 10 }`,
+		},
+		{
+			name:     "marshals externally tagged union as inline table",
+			shape:    ExternallyTagged[Shape]{Value: Shape{Triangle: &Triangle{Base: 8, Height: 4}}},
+			expected: `{ triangle = { base = 8, height = 4 } }`,
+		},
+		{
+			name:     "marshals untagged union as inline table",
+			shape:    Union[UnionShape]{Value: UnionShape{Circle: &Circle{Radius: 5}}},
+			expected: `{ radius = 5 }`,
+		},
+		{
+			name:     "quotes keys and escapes strings",
+			shape:    Union[StringShape]{Value: StringShape{Labeled: &Labeled{Label: "say \"hi\"\n"}}},
+			expected: `{ "the label" = "say \"hi\"\n" }`,
+		},
+		{
+			name:        "returns error when no variant is set",
+			shape:       TaggedUnion[Shape]{},
+			expectedErr: ErrZeroVariants,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.shape.MarshalTOML()
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("expected error '%v', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, string(data))
+			}
+		})
+	}
+}
+
+type Labeled struct {
+	Label string `json:"the label"`
+}
+
+type StringShape struct {
+	Labeled *Labeled
+}
+
+func TestUnmarshalTOML(t *testing.T) {
+	var shape TaggedUnion[Shape]
+	err := shape.UnmarshalTOML(map[string]any{
+		"type":  "rectangle",
+		"value": map[string]any{"width": int64(10), "height": 5.0},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, shape.GetValue(), Rectangle{Width: 10, Height: 5})
+
+	var external ExternallyTagged[Shape]
+	if err := external.UnmarshalTOML(map[string]any{"circle": map[string]any{"radius": 5.0}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, external.GetValue(), Circle{Radius: 5})
+
+	var untagged Union[UnionShape]
+	if err := untagged.UnmarshalTOML(map[string]any{"base": int64(8), "height": int64(4)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValueEquals(t, untagged.GetValue(), Triangle{Base: 8, Height: 4})
+
+	err = shape.UnmarshalTOML(map[string]any{"type": "hexagon", "value": map[string]any{}})
+	if !errors.Is(err, ErrUnknownVariant) {
+		t.Errorf("expected error '%v', got '%v'", ErrUnknownVariant, err)
+	}
+}
@@ -0,0 +1,89 @@
+package union
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// isTuple reports whether the Spec type selects the tuple representation with a JSONTuple() bool method.
+func isTuple(spec any) bool {
+	s, ok := spec.(interface{ JSONTuple() bool })
+	return ok && s.JSONTuple()
+}
+
+// appendTuple appends the union, whose spec value is v, to dst in the tuple representation,
+// a two-element array of the variant name and the payload such as ["circle",{"radius":5}].
+// Zero payloads of JSONOptionalValue specs are omitted, leaving a one-element array.
+func (u TaggedUnion[Spec]) appendTuple(dst []byte, lib JSONLibrary, v reflect.Value) ([]byte, error) {
+	p := planOf(v.Type())
+	f, err := p.current(v, u.selected)
+	if err != nil {
+		return nil, err
+	}
+	variant, value := variantValue(v, f)
+
+	raw, err := marshalPayload(lib, value)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := slices.Grow(dst, len(variant)+len(raw)+8)
+	buf = append(buf, '[')
+	if buf, err = appendDiscriminator(buf, p.json.kind, variant); err != nil {
+		return nil, err
+	}
+	if !p.json.optionalValue || !isZeroPayload(reflect.ValueOf(value)) {
+		buf = append(buf, ',')
+		buf = append(buf, raw...)
+	}
+	return append(buf, ']'), nil
+}
+
+// unmarshalTuple deserializes the union from the tuple representation by decoding
+// the envelope {"type": variant, "value": payload} the array holds. Errors about the
+// value field and payload paths refer to the payload by its array index, 1.
+func (u *TaggedUnion[Spec]) unmarshalTuple(lib JSONLibrary, data []byte) error {
+	var elems []json.RawMessage
+	if err := lib.Unmarshal(data, &elems); err != nil {
+		var zero Spec
+		u.Value = zero
+		u.selected = nil
+		return err
+	}
+
+	if len(elems) == 0 || len(elems) > 2 {
+		var zero Spec
+		u.Value = zero
+		u.selected = nil
+		return fmt.Errorf("%w: got %d elements", ErrTupleLength, len(elems))
+	}
+
+	// the value member is left out of one-element arrays, as for missing value fields
+	envelope := append([]byte(`{"type":`), elems[0]...)
+	if len(elems) == 2 {
+		envelope = append(append(envelope, `,"value":`...), elems[1]...)
+	}
+	envelope = append(envelope, '}')
+	return atValuePath(u.unmarshalJSON(lib, envelope, "type", "value"), []string{"1"})
+}
+
+// peekTuple returns the first element of the JSON array data, reading no further.
+func peekTuple(data []byte) (json.RawMessage, bool, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		// report malformed and non-array data like json.Unmarshal
+		var elems []json.RawMessage
+		return nil, false, json.Unmarshal(data, &elems)
+	}
+	if !dec.More() {
+		return nil, false, nil
+	}
+	var first json.RawMessage
+	if err := dec.Decode(&first); err != nil {
+		return nil, false, err
+	}
+	return first, true, nil
+}
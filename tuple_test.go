@@ -0,0 +1,193 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type TupleShape struct {
+	Idle      *Idle      `variant:"idle" union:"unit"`
+	Circle    *Circle    `variant:"circle"`
+	Rectangle *Rectangle `variant:"rectangle"`
+}
+
+func (TupleShape) JSONTuple() bool { return true }
+
+type OptionalTupleShape struct {
+	Circle *Circle `variant:"circle"`
+}
+
+func (OptionalTupleShape) JSONTuple() bool         { return true }
+func (OptionalTupleShape) JSONOptionalValue() bool { return true }
+
+type ConflictingTupleShape struct {
+	Circle *Circle `variant:"circle"`
+}
+
+func (ConflictingTupleShape) JSONTuple() bool                 { return true }
+func (ConflictingTupleShape) JSONMemberDiscriminator() string { return "type" }
+
+func TestTupleMarshal(t *testing.T) {
+	tests := []struct {
+		name     string
+		shape    any
+		expected string
+	}{
+		{
+			name:     "variant and payload",
+			shape:    TaggedUnion[TupleShape]{Value: TupleShape{Circle: &Circle{Radius: 5}}},
+			expected: `["circle",{"radius":5}]`,
+		},
+		{
+			name:     "unit variant",
+			shape:    TaggedUnion[TupleShape]{Value: TupleShape{Idle: &Idle{}}},
+			expected: `"idle"`,
+		},
+		{
+			name:     "zero optional payload",
+			shape:    TaggedUnion[OptionalTupleShape]{Value: OptionalTupleShape{Circle: &Circle{}}},
+			expected: `["circle"]`,
+		},
+		{
+			name:     "optional payload",
+			shape:    TaggedUnion[OptionalTupleShape]{Value: OptionalTupleShape{Circle: &Circle{Radius: 5}}},
+			expected: `["circle",{"radius":5}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.shape)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, string(data))
+			}
+		})
+	}
+}
+
+func TestTupleUnmarshal(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		shape       interface{ GetValue() any }
+		expected    any
+		expectedErr string
+	}{
+		{
+			name:     "variant and payload",
+			data:     `["rectangle",{"width":2,"height":3}]`,
+			shape:    &TaggedUnion[TupleShape]{},
+			expected: &Rectangle{Width: 2, Height: 3},
+		},
+		{
+			name:     "unit variant",
+			data:     `"idle"`,
+			shape:    &TaggedUnion[TupleShape]{},
+			expected: &Idle{},
+		},
+		{
+			name:     "missing optional payload",
+			data:     `["circle"]`,
+			shape:    &TaggedUnion[OptionalTupleShape]{},
+			expected: &Circle{},
+		},
+		{
+			name:        "missing payload",
+			data:        `["circle"]`,
+			shape:       &TaggedUnion[TupleShape]{},
+			expectedErr: "missing value field: 1",
+		},
+		{
+			name:        "empty array",
+			data:        `[]`,
+			shape:       &TaggedUnion[TupleShape]{},
+			expectedErr: "expected a [variant, value] array: got 0 elements",
+		},
+		{
+			name:        "extra elements",
+			data:        `["circle",{"radius":5},true]`,
+			shape:       &TaggedUnion[TupleShape]{},
+			expectedErr: "expected a [variant, value] array: got 3 elements",
+		},
+		{
+			name:        "unknown variant",
+			data:        `["hexagon",{}]`,
+			shape:       &TaggedUnion[TupleShape]{},
+			expectedErr: "unknown variant: hexagon",
+		},
+		{
+			name:        "payload error",
+			data:        `["circle",{"radius":"5"}]`,
+			shape:       &TaggedUnion[TupleShape]{},
+			expectedErr: `variant "circle": 1.radius: cannot unmarshal string into float64 at offset 13`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := json.Unmarshal([]byte(tt.data), tt.shape)
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("expected error '%s', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := tt.shape.GetValue(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %#v, got %#v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTupleSpec(t *testing.T) {
+	variant, err := PeekVariant[TupleShape]([]byte(`["circle",{"radius":5}]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if variant != "circle" {
+		t.Errorf("expected %v, got %v", "circle", variant)
+	}
+	if _, err := PeekVariant[TupleShape]([]byte(`[]`)); !errors.Is(err, ErrTupleLength) {
+		t.Errorf("expected error '%s', got '%v'", ErrTupleLength, err)
+	}
+
+	if paths := DiscriminatorPath[TupleShape](); paths != nil {
+		t.Errorf("expected %v, got %v", nil, paths)
+	}
+
+	if err := CheckSpec[TupleShape](); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := CheckSpec[ConflictingTupleShape](); !errors.Is(err, ErrInvalidSpec) {
+		t.Errorf("expected error '%s', got '%v'", ErrInvalidSpec, err)
+	}
+}
+
+func TestTupleStreamsAndMaps(t *testing.T) {
+	var got []any
+	data := `["circle",{"radius":5}]` + "\n" + `["rectangle",{"width":2,"height":3}]`
+	for u, err := range DecodeAll[TupleShape](strings.NewReader(data)) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, u.GetValue())
+	}
+	expected := []any{&Circle{Radius: 5}, &Rectangle{Width: 2, Height: 3}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %#v, got %#v", expected, got)
+	}
+
+	u := TaggedUnion[TupleShape]{Value: TupleShape{Circle: &Circle{Radius: 5}}}
+	if _, err := ToMap(u); !errors.Is(err, errNotObject) {
+		t.Errorf("expected error '%s', got '%v'", errNotObject, err)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"reflect"
+	"strings"
 )
 
 // Union represents an untagged union type that can hold one of several
@@ -13,8 +15,26 @@ import (
 //
 // Only one field in the Spec struct should be non-zero at any time. When marshaling
 // to JSON, the union's data is marshaled directly without a wrapper. When unmarshaling,
-// each field is tried in order until one successfully deserializes to a non-zero value.
-type Union[Spec any] struct{ Value Spec }
+// each field is tried in order until one successfully deserializes the data.
+//
+// Fields may hold any JSON type, so a Union can decode payloads that are either an
+// object or a bare scalar with fields such as *string, *float64 or *bool. A scalar
+// decoded into a non-pointer field stays the active variant even when it is zero, like "" or false.
+// Slice and map fields decode JSON arrays and objects, such as a variant holding
+// a single object next to one holding an array of them.
+type Union[Spec any] struct {
+	Value Spec
+
+	selected *fieldPlan // field made active by Select, see current
+}
+
+func (u *Union[Spec]) spec() *Spec { return &u.Value }
+
+func (u *Union[Spec]) specValue() reflect.Value { return reflect.ValueOf(&u.Value).Elem() }
+
+func (u *Union[Spec]) selectedField() *fieldPlan { return u.selected }
+
+func (u *Union[Spec]) setSelected(f *fieldPlan) { u.selected = f }
 
 // GetValue returns the value of the active variant in the union.
 // It iterates through all fields in the Spec struct and returns the value
@@ -22,27 +42,43 @@ type Union[Spec any] struct{ Value Spec }
 // it returns nil (indicating an invalid state).
 func (u Union[Spec]) GetValue() any {
 	v := reflect.ValueOf(u.Value)
-	t := v.Type()
-
-	if t.Kind() != reflect.Struct {
+	f, err := planOf(v.Type()).current(v, u.selected)
+	if err != nil {
 		return nil
 	}
+	return v.FieldByIndex(f.index).Interface()
+}
 
-	var value any
-	for i := 0; i < t.NumField(); i++ {
-		vf := v.Field(i)
-
-		if vf.IsZero() {
-			continue
-		}
-		if value != nil {
-			// invariant violation: multiple variants set
-			return nil
-		}
-		value = vf.Interface()
+// Variant returns the name of the active variant in the union, which is the
+// field's `variant` struct tag or the field name, converted by the spec's VariantNaming, if no tag is provided.
+// It reports false if no fields are set or multiple fields are set.
+func (u Union[Spec]) Variant() (string, bool) {
+	v := reflect.ValueOf(u.Value)
+	f, err := planOf(v.Type()).current(v, u.selected)
+	if err != nil {
+		return "", false
 	}
+	return f.variant, true
+}
+
+// MustGetValue is like GetValue but panics if no fields are set or multiple fields are set.
+func (u Union[Spec]) MustGetValue() any {
+	v := reflect.ValueOf(u.Value)
+	f := mustActive(v, u.selected)
+	return v.FieldByIndex(f.index).Interface()
+}
+
+// MustVariant is like Variant but panics if no fields are set or multiple fields are set.
+func (u Union[Spec]) MustVariant() string {
+	return mustActive(reflect.ValueOf(u.Value), u.selected).variant
+}
 
-	return value
+// IsZero reports whether no variant is set or selected in the union, which
+// lets the omitzero struct tag option of encoding/json omit unset unions.
+func (u Union[Spec]) IsZero() bool {
+	v := reflect.ValueOf(u.Value)
+	_, err := planOf(v.Type()).current(v, u.selected)
+	return errors.Is(err, ErrZeroVariants)
 }
 
 // MarshalJSON implements the json.Marshaler interface.
@@ -50,73 +86,312 @@ func (u Union[Spec]) GetValue() any {
 //
 // Returns an error if:
 //   - The Spec type is not a struct
-//   - No fields are set (zero state)
+//   - No fields are set (zero state), unless the Spec type returns true from JSONNullable
 //   - Multiple fields are set (invalid state)
 func (u Union[Spec]) MarshalJSON() ([]byte, error) {
+	return u.marshalUsing(encodingJSON)
+}
+
+// marshalUsing implements MarshalJSON, encoding the payload with lib.
+func (u Union[Spec]) marshalUsing(lib JSONLibrary) ([]byte, error) {
+	return u.appendUsing(nil, lib)
+}
+
+// appendUsing appends the JSON encoding of the union to dst like MarshalJSON,
+// encoding the payload with lib.
+func (u Union[Spec]) appendUsing(dst []byte, lib JSONLibrary) ([]byte, error) {
+	p := planOf(reflect.TypeFor[Spec]())
+	if c := codecOf(p.typ); c != nil {
+		return appendResult(dst)(c.marshal(u.Value))
+	}
+	if p.json.nullable && u.IsZero() {
+		return append(dst, "null"...), nil
+	}
 	v := reflect.ValueOf(u.Value)
-	t := v.Type()
+	f, err := p.current(v, u.selected)
+	if err != nil {
+		return nil, err
+	}
 
-	if t.Kind() != reflect.Struct {
-		return nil, errors.New("spec must be a struct")
+	_, value := variantValue(v, f)
+	if dst == nil {
+		return marshalPayload(lib, value)
 	}
+	return appendResult(dst)(marshalPayload(lib, value))
+}
 
-	var value any
-	for i := 0; i < t.NumField(); i++ {
-		vf := v.Field(i)
+// Matching selects how Union.UnmarshalJSON chooses between spec fields that can
+// all decode the same JSON data. A Spec type selects it with a UnionMatching() Matching method.
+type Matching int
 
-		if vf.IsZero() {
-			continue
-		}
-		if value != nil {
-			// invariant violation: multiple variants set
-			return nil, errors.New("multiple variants set")
-		}
-		value = vf.Interface()
-	}
-	if value == nil {
-		return nil, errors.New("zero variants set")
-	}
+const (
+	// FirstMatch selects the first spec field, in declaration order, that decodes
+	// the JSON data without unknown fields. It is the default.
+	FirstMatch Matching = iota
+	// BestMatch decodes the JSON data into every spec field and selects the
+	// non-zero result that matches the most JSON keys, then the one missing the
+	// fewest of its own fields. Equally good results are rejected with ErrAmbiguousMatch.
+	BestMatch
+	// LenientMatch is like BestMatch but allows JSON keys that are not fields of the
+	// variant, so payloads gaining new keys keep decoding. The result matching the
+	// most JSON keys wins.
+	LenientMatch
+)
 
-	return json.Marshal(value)
+// matching returns the Matching declared by the Spec type.
+func (u *Union[Spec]) matching() Matching {
+	if m, ok := any(u.Value).(interface{ UnionMatching() Matching }); ok {
+		return m.UnionMatching()
+	}
+	return FirstMatch
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
 // It deserializes JSON data into the union by trying each field in order
 // until one successfully unmarshals to a non-zero value.
-// Uses strict matching to ensure all JSON fields map to struct fields.
+// Uses strict matching to ensure all JSON fields map to struct fields, and JSON
+// scalars only match fields of a compatible type.
+//
+// Fields are tried by descending `union:"priority=N"` struct tag value (0 by default),
+// then in declaration order. With BestMatch the priority breaks ties between equal scores.
+// A Spec type returning BestMatch or LenientMatch from UnionMatching selects
+// the best matching field instead of the first.
 //
 // Returns an error if:
 //   - The JSON data is malformed
 //   - The Spec type is not a struct
-//   - No field successfully unmarshals to a non-zero value
+//   - No field successfully unmarshals to a non-zero value (ErrNoFieldMatched joined
+//     with a *DecodeError for each attempted field explaining why it failed)
+//   - Several fields match equally well with BestMatch (ErrAmbiguousMatch)
+//
+// Like TaggedUnion, a Spec type returning true from JSONNullable decodes JSON null
+// into the empty union, which is then marshaled as null, and one returning true from
+// JSONUseNumber keeps numbers in interface values as json.Number.
 func (u *Union[Spec]) UnmarshalJSON(data []byte) error {
+	return u.unmarshalUsing(encodingJSON, data)
+}
+
+// unmarshalUsing implements UnmarshalJSON. Variants are matched strictly,
+// so payloads are always decoded with encoding/json, and lib only carries the Options of the call.
+func (u *Union[Spec]) unmarshalUsing(lib JSONLibrary, data []byte) error {
 	var zero Spec
 	u.Value = zero
+	u.selected = nil
+	limits := decodeLimits(lib, u.Value)
+	if err := limits.check(data); err != nil {
+		return err
+	}
+	if err := limits.checkValue(data); err != nil {
+		return err
+	}
+	if c := codecOf(reflect.TypeFor[Spec]()); c != nil {
+		return c.unmarshal(data, &u.Value)
+	}
 
 	v := reflect.ValueOf(&u.Value).Elem()
-	t := v.Type()
+	p := planOf(v.Type())
+
+	if !p.isStruct {
+		return ErrSpecNotStruct
+	}
+	if isJSONNull(data) && isNullable(u.Value) {
+		return nil
+	}
+
+	useNumber := payloadOptions(lib, u.Value).useNumber
+	if m := u.matching(); m == BestMatch || m == LenientMatch {
+		f, target, err := bestMatch(p, data, decodeOptions{strict: m == BestMatch, useNumber: useNumber})
+		if err != nil {
+			return err
+		}
+		v.FieldByIndex(f.index).Set(target.Elem())
+		u.selected = selection(v, f)
+		return nil
+	}
+
+	var attempts []error
+	for _, i := range p.order {
+		f := &p.fields[i]
+		target, err := decodeField(encodingJSON, f, data, decodeOptions{strict: true, useNumber: useNumber})
+		if err != nil {
+			attempts = append(attempts, payloadError(p.typ, f.variant, f, "", err))
+			continue
+		}
 
-	if t.Kind() != reflect.Struct {
-		return errors.New("spec must be a struct")
+		v.FieldByIndex(f.index).Set(target.Elem())
+		if !isJSONNull(data) {
+			// zero scalars such as "", 0 and false are still the decoded variant
+			u.selected = selection(v, f)
+		}
+		return nil
 	}
 
-	for i := 0; i < t.NumField(); i++ {
-		vf := v.Field(i)
-		tf := t.Field(i)
+	return noFieldMatched(attempts)
+}
 
-		target := reflect.New(tf.Type)
+// errZeroPayload is recorded for BestMatch candidates that decode to a zero value.
+var errZeroPayload = errors.New("decoded to a zero value")
 
-		// Use decoder with DisallowUnknownFields for strict matching
-		decoder := json.NewDecoder(bytes.NewReader(data))
+// noFieldMatched returns ErrNoFieldMatched joined with the error of each attempted field.
+func noFieldMatched(attempts []error) error {
+	if len(attempts) == 0 {
+		return ErrNoFieldMatched
+	}
+	return errors.Join(append([]error{ErrNoFieldMatched}, attempts...)...)
+}
+
+// decodeField decodes data into a new value of the field's type with lib.
+// Unknown fields are rejected when opts.strict is set and numbers in interface values
+// are kept as json.Number when opts.useNumber is set, both of which use encoding/json.
+func decodeField(lib JSONLibrary, f *fieldPlan, data []byte, opts decodeOptions) (reflect.Value, error) {
+	return decodeType(lib, f.typ, data, opts)
+}
+
+// decodeType decodes data into a new value of type t with lib, see decodeField.
+// The decoded payload is completed by its AfterUnionUnmarshal method.
+func decodeType(lib JSONLibrary, t reflect.Type, data []byte, opts decodeOptions) (reflect.Value, error) {
+	target, err := decodeJSON(lib, t, data, opts)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return target, afterUnmarshal(target)
+}
+
+// decodeJSON is decodeType without the AfterUnionUnmarshal method.
+func decodeJSON(lib JSONLibrary, t reflect.Type, data []byte, opts decodeOptions) (reflect.Value, error) {
+	target := reflect.New(t)
+	if u, ok := target.Interface().(interface {
+		unmarshalUsing(lib JSONLibrary, data []byte) error
+	}); ok {
+		// nested unions decode with the same library and apply their own options
+		if err := u.unmarshalUsing(lib, data); err != nil {
+			return reflect.Value{}, err
+		}
+		return target, nil
+	}
+	if opts == (decodeOptions{}) {
+		if err := lib.Unmarshal(data, target.Interface()); err != nil {
+			return reflect.Value{}, err
+		}
+		return target, nil
+	}
+
+	// Use decoder with DisallowUnknownFields for strict matching
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if opts.strict {
 		decoder.DisallowUnknownFields()
+	}
+	if opts.useNumber {
+		decoder.UseNumber()
+	}
+	if err := decoder.Decode(target.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	return target, nil
+}
+
+// matchScore measures how well a decoded variant covers the JSON object it was decoded from.
+type matchScore struct {
+	matched int // JSON keys that are fields of the variant
+	missing int // fields of the variant absent from the JSON object
+}
+
+// better reports whether s is a better match than o.
+func (s matchScore) better(o matchScore) bool {
+	if s.matched != o.matched {
+		return s.matched > o.matched
+	}
+	return s.missing < o.missing
+}
 
-		if err := decoder.Decode(target.Interface()); err != nil {
+// bestMatch decodes data into every spec field and returns the best matching field
+// along with a pointer to its decoded value. Unknown fields are rejected when opts.strict is set.
+func bestMatch(p *specPlan, data []byte, opts decodeOptions) (*fieldPlan, reflect.Value, error) {
+	input := objectKeys(data)
+	// scalars and arrays are meaningful matches even when they decode to a zero value, such as false
+	scalar := input == nil && !isJSONNull(data)
+
+	var (
+		best     *fieldPlan
+		target   reflect.Value
+		score    matchScore
+		tied     []string
+		attempts []error
+	)
+	for _, i := range p.order {
+		f := &p.fields[i]
+		decoded, err := decodeField(encodingJSON, f, data, opts)
+		if err == nil && !scalar {
+			// a pointer to an empty payload is not a meaningful match either
+			if isZeroPayload(decoded.Elem()) {
+				err = errZeroPayload
+			}
+		}
+		if err != nil {
+			attempts = append(attempts, payloadError(p.typ, f.variant, f, "", err))
 			continue
 		}
 
-		vf.Set(target.Elem())
-		return nil
+		s := scoreMatch(input, decoded.Interface())
+		switch {
+		case best == nil || s.better(score):
+			best, target, score, tied = f, decoded, s, []string{f.variant}
+		case !score.better(s) && f.priority == best.priority:
+			// fields are visited by descending priority, so a lower priority loses the tie
+			tied = append(tied, f.variant)
+		}
+	}
+
+	if best == nil {
+		return nil, reflect.Value{}, noFieldMatched(attempts)
 	}
+	if len(tied) > 1 {
+		return nil, reflect.Value{}, fmt.Errorf("%w: %s", ErrAmbiguousMatch, strings.Join(tied, ", "))
+	}
+	return best, target, nil
+}
 
-	return errors.New("no field matched")
+// scoreMatch compares the keys of the input JSON object with the keys the decoded
+// value marshals to. Keys are compared case-insensitively like encoding/json does.
+//
+// Maps accept any key, so a map variant scores no matched keys and only wins
+// objects that no struct variant matches.
+func scoreMatch(input map[string]bool, decoded any) matchScore {
+	var s matchScore
+	v := reflect.ValueOf(decoded)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Map {
+		return s
+	}
+	data, err := json.Marshal(decoded)
+	if err != nil {
+		return s
+	}
+	output := objectKeys(data)
+	for key := range input {
+		if output[key] {
+			s.matched++
+		}
+	}
+	for key := range output {
+		if !input[key] {
+			s.missing++
+		}
+	}
+	return s
+}
+
+// objectKeys returns the lowercased keys of a JSON object, or nil if data is not an object.
+func objectKeys(data []byte) map[string]bool {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+	keys := make(map[string]bool, len(raw))
+	for key := range raw {
+		keys[strings.ToLower(key)] = true
+	}
+	return keys
 }
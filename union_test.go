@@ -2,6 +2,7 @@ package union
 
 import (
 	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 )
@@ -22,6 +23,76 @@ type UnionEmptyShape struct {
 
 type UnionNonStructType int
 
+type LabeledCircle struct {
+	Radius float64 `json:"radius"`
+	Label  string  `json:"label"`
+}
+
+type BestMatchShape struct {
+	LabeledCircle *LabeledCircle
+	Circle        *Circle
+	Rectangle     *Rectangle
+	Triangle      *Triangle
+}
+
+func (s BestMatchShape) UnionMatching() Matching { return BestMatch }
+
+type LenientShape struct {
+	LabeledCircle *LabeledCircle
+	Circle        *Circle
+	Rectangle     *Rectangle
+	Triangle      *Triangle
+}
+
+func (s LenientShape) UnionMatching() Matching { return LenientMatch }
+
+type PriorityShape struct {
+	Circle    *Circle
+	Rectangle *Rectangle
+	Triangle  *Triangle `union:"priority=10"`
+}
+
+type BestMatchPriorityShape struct {
+	Rectangle *Rectangle `union:"priority=1"`
+	Triangle  *Triangle
+}
+
+func (s BestMatchPriorityShape) UnionMatching() Matching { return BestMatch }
+
+type ScalarShape struct {
+	Name   *string
+	Circle *Circle
+	Scale  *float64
+	Filled *bool
+}
+
+type NonPointerScalarShape struct {
+	Name   string
+	Scale  float64
+	Filled bool
+}
+
+type BestMatchScalarShape struct {
+	Name   *string
+	Filled *bool
+	Circle *Circle
+}
+
+func (s BestMatchScalarShape) UnionMatching() Matching { return BestMatch }
+
+type CollectionShape struct {
+	Circle  *Circle
+	Circles []Circle
+	Named   map[string]Circle
+}
+
+type BestMatchCollectionShape struct {
+	Named  map[string]float64
+	Circle *Circle
+}
+
+func (s BestMatchCollectionShape) UnionMatching() Matching { return BestMatch }
+
 func TestUnionGetValue(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -213,11 +284,14 @@ func TestUnionUnmarshalJSON(t *testing.T) {
 			expected: Triangle{Base: 8, Height: 4},
 		},
 		{
-			name:        "returns error when no field matches",
-			shape:       &Union[UnionShape]{},
-			jsonData:    `{"sides":6}`,
-			expectErr:   true,
-			expectedErr: "no field matched",
+			name:      "returns error when no field matches",
+			shape:     &Union[UnionShape]{},
+			jsonData:  `{"sides":6}`,
+			expectErr: true,
+			expectedErr: "no field matched\n" +
+				`variant "Circle": json: unknown field "sides"` + "\n" +
+				`variant "Rectangle": json: unknown field "sides"` + "\n" +
+				`variant "Triangle": json: unknown field "sides"`,
 		},
 		{
 			name:      "returns error for malformed JSON",
@@ -270,11 +344,14 @@ func TestUnionUnmarshalJSON(t *testing.T) {
 			expectErr: true,
 		},
 		{
-			name:        "returns error when value cannot be unmarshaled",
-			shape:       &Union[UnionShape]{},
-			jsonData:    `{"radius":"not a number"}`,
-			expectErr:   true,
-			expectedErr: "no field matched",
+			name:      "returns error when value cannot be unmarshaled",
+			shape:     &Union[UnionShape]{},
+			jsonData:  `{"radius":"not a number"}`,
+			expectErr: true,
+			expectedErr: "no field matched\n" +
+				`variant "Circle": radius: cannot unmarshal string into float64 at offset 24` + "\n" +
+				`variant "Rectangle": json: unknown field "radius"` + "\n" +
+				`variant "Triangle": json: unknown field "radius"`,
 		},
 	}
 
@@ -362,3 +439,271 @@ func assertUnionValueEquals(t *testing.T, value, expected any) {
 		t.Fatalf("unexpected expected type: %T", expected)
 	}
 }
+
+func TestUnionUnmarshalJSONBestMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		jsonData    string
+		expected    any
+		expectedErr string
+	}{
+		{
+			name:     "prefers variant with fewest missing fields",
+			jsonData: `{"radius":5}`,
+			expected: Circle{Radius: 5.0},
+		},
+		{
+			name:     "selects variant covering all keys",
+			jsonData: `{"radius":5,"label":"wheel"}`,
+			expected: LabeledCircle{Radius: 5.0, Label: "wheel"},
+		},
+		{
+			name:     "selects only decodable variant",
+			jsonData: `{"base":8,"height":4}`,
+			expected: Triangle{Base: 8, Height: 4},
+		},
+		{
+			name:        "returns error for equally good variants",
+			jsonData:    `{"height":10}`,
+			expectedErr: "ambiguous match: Rectangle, Triangle",
+		},
+		{
+			name:     "returns error when no field matches",
+			jsonData: `{"sides":6}`,
+			expectedErr: "no field matched\n" +
+				`variant "LabeledCircle": json: unknown field "sides"` + "\n" +
+				`variant "Circle": json: unknown field "sides"` + "\n" +
+				`variant "Rectangle": json: unknown field "sides"` + "\n" +
+				`variant "Triangle": json: unknown field "sides"`,
+		},
+		{
+			name:     "skips variants decoding to zero values",
+			jsonData: `{}`,
+			expectedErr: "no field matched\n" +
+				`variant "LabeledCircle": decoded to a zero value` + "\n" +
+				`variant "Circle": decoded to a zero value` + "\n" +
+				`variant "Rectangle": decoded to a zero value` + "\n" +
+				`variant "Triangle": decoded to a zero value`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var shape Union[BestMatchShape]
+			err := json.Unmarshal([]byte(tt.jsonData), &shape)
+
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("expected error '%s', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if labeled, ok := tt.expected.(LabeledCircle); ok {
+				if shape.Value.LabeledCircle == nil || *shape.Value.LabeledCircle != labeled {
+					t.Errorf("expected %v, got %v", labeled, shape.GetValue())
+				}
+				return
+			}
+			assertUnionValueEquals(t, shape.GetValue(), tt.expected)
+		})
+	}
+}
+
+func TestUnionUnmarshalJSONPriority(t *testing.T) {
+	var shape Union[PriorityShape]
+	if err := json.Unmarshal([]byte(`{"height":10}`), &shape); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertUnionValueEquals(t, shape.GetValue(), Triangle{Height: 10})
+
+	if err := json.Unmarshal([]byte(`{"radius":5}`), &shape); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertUnionValueEquals(t, shape.GetValue(), Circle{Radius: 5})
+
+	var best Union[BestMatchPriorityShape]
+	if err := json.Unmarshal([]byte(`{"height":10}`), &best); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertUnionValueEquals(t, best.GetValue(), Rectangle{Height: 10})
+}
+
+func TestUnionUnmarshalJSONCandidateErrors(t *testing.T) {
+	var shape Union[UnionShape]
+	err := json.Unmarshal([]byte(`{"radius":"not a number"}`), &shape)
+	if !errors.Is(err, ErrNoFieldMatched) {
+		t.Fatalf("expected error '%v', got '%v'", ErrNoFieldMatched, err)
+	}
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected *DecodeError, got %T", err)
+	}
+	if decodeErr.Variant != "Circle" || decodeErr.Field != "Circle" {
+		t.Errorf("expected Circle attempt first, got %+v", decodeErr)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(decodeErr, &typeErr) {
+		t.Errorf("expected *json.UnmarshalTypeError, got %T", decodeErr.Err)
+	}
+
+	attempts := err.(interface{ Unwrap() []error }).Unwrap()
+	if len(attempts) != 4 {
+		t.Errorf("expected sentinel and 3 attempts, got %d", len(attempts))
+	}
+}
+
+func TestUnionUnmarshalJSONLenientMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		jsonData    string
+		expected    any
+		expectedErr string
+	}{
+		{
+			name:     "ignores unknown fields",
+			jsonData: `{"radius":5,"color":"red"}`,
+			expected: Circle{Radius: 5.0},
+		},
+		{
+			name:     "selects variant matching most keys",
+			jsonData: `{"base":8,"height":4,"color":"red"}`,
+			expected: Triangle{Base: 8, Height: 4},
+		},
+		{
+			name:        "returns error for equally good variants",
+			jsonData:    `{"height":10,"color":"red"}`,
+			expectedErr: "ambiguous match: Rectangle, Triangle",
+		},
+		{
+			name:     "returns error when only unknown fields are present",
+			jsonData: `{"sides":6}`,
+			expectedErr: "no field matched\n" +
+				`variant "LabeledCircle": decoded to a zero value` + "\n" +
+				`variant "Circle": decoded to a zero value` + "\n" +
+				`variant "Rectangle": decoded to a zero value` + "\n" +
+				`variant "Triangle": decoded to a zero value`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var shape Union[LenientShape]
+			err := json.Unmarshal([]byte(tt.jsonData), &shape)
+
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("expected error '%s', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertUnionValueEquals(t, shape.GetValue(), tt.expected)
+		})
+	}
+}
+
+func TestUnionUnmarshalJSONScalars(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		decode   func([]byte) (string, error)
+		expected string
+	}{
+		{name: "string or object", input: `"circle"`, decode: decodeVariant[ScalarShape], expected: "Name"},
+		{name: "object or string", input: `{"radius":5}`, decode: decodeVariant[ScalarShape], expected: "Circle"},
+		{name: "number", input: `2.5`, decode: decodeVariant[ScalarShape], expected: "Scale"},
+		{name: "bool", input: `true`, decode: decodeVariant[ScalarShape], expected: "Filled"},
+		{name: "empty string", input: `""`, decode: decodeVariant[NonPointerScalarShape], expected: "Name"},
+		{name: "zero number", input: `0`, decode: decodeVariant[NonPointerScalarShape], expected: "Scale"},
+		{name: "false", input: `false`, decode: decodeVariant[NonPointerScalarShape], expected: "Filled"},
+		{name: "best match false", input: `false`, decode: decodeVariant[BestMatchScalarShape], expected: "Filled"},
+		{name: "best match empty string", input: `""`, decode: decodeVariant[BestMatchScalarShape], expected: "Name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.decode([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if data != tt.expected+" "+tt.input {
+				t.Errorf("expected %s %s, got %s", tt.expected, tt.input, data)
+			}
+		})
+	}
+
+	t.Run("null matches no scalar", func(t *testing.T) {
+		var shape Union[NonPointerScalarShape]
+		if err := json.Unmarshal([]byte(`null`), &shape); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !shape.IsZero() {
+			t.Errorf("expected empty union, got %v", shape)
+		}
+	})
+
+	t.Run("rejects unmatched scalar", func(t *testing.T) {
+		var shape Union[UnionShape]
+		if err := json.Unmarshal([]byte(`"circle"`), &shape); !errors.Is(err, ErrNoFieldMatched) {
+			t.Errorf("expected ErrNoFieldMatched, got %v", err)
+		}
+	})
+}
+
+func TestUnionUnmarshalJSONCollections(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		decode   func([]byte) (string, error)
+		expected string
+	}{
+		{name: "object", input: `{"radius":5}`, decode: decodeVariant[CollectionShape], expected: "Circle"},
+		{name: "array", input: `[{"radius":5},{"radius":1}]`, decode: decodeVariant[CollectionShape], expected: "Circles"},
+		{name: "empty array", input: `[]`, decode: decodeVariant[CollectionShape], expected: "Circles"},
+		{name: "map", input: `{"small":{"radius":1}}`, decode: decodeVariant[CollectionShape], expected: "Named"},
+		{name: "best match prefers struct", input: `{"radius":5}`, decode: decodeVariant[BestMatchCollectionShape], expected: "Circle"},
+		{name: "best match falls back to map", input: `{"width":5}`, decode: decodeVariant[BestMatchCollectionShape], expected: "Named"},
+		{name: "best match empty object", input: `{}`, decode: decodeVariant[BestMatchCollectionShape], expected: "Named"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.decode([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if data != tt.expected+" "+tt.input {
+				t.Errorf("expected %s %s, got %s", tt.expected, tt.input, data)
+			}
+		})
+	}
+
+	t.Run("rejects mismatched elements", func(t *testing.T) {
+		var shape Union[CollectionShape]
+		if err := json.Unmarshal([]byte(`[{"width":5}]`), &shape); !errors.Is(err, ErrNoFieldMatched) {
+			t.Errorf("expected ErrNoFieldMatched, got %v", err)
+		}
+	})
+
+	t.Run("selected collections marshal empty", func(t *testing.T) {
+		var shape Union[CollectionShape]
+		if err := shape.Select("Circles"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		data, err := json.Marshal(shape)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(data) != `[]` {
+			t.Errorf("expected [], got %s", data)
+		}
+	})
+}
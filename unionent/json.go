@@ -0,0 +1,104 @@
+// Package unionent stores unions in JSON columns of ent schemas, without depending on ent.
+//
+// JSON wraps a union as a database/sql value, declared with field.Other and the column
+// types of SchemaType:
+//
+//	func (Drawing) Fields() []ent.Field {
+//		return []ent.Field{
+//			field.Other("shape", &unionent.JSON[union.TaggedUnion[Shape]]{}).
+//				SchemaType(unionent.SchemaType()).
+//				Optional(),
+//		}
+//	}
+//
+// An unset union is stored as NULL and NULL scans into an unset union, so optional
+// fields need no pointer. JSON also marshals like the union it holds, so it can be
+// declared with field.JSON instead, where the union is stored with ent's JSON codec.
+//
+// Predicates on the variant query the discriminator with ent's sqljson package:
+//
+//	client.Drawing.Query().
+//		Where(func(s *sql.Selector) {
+//			s.Where(sqljson.ValueEQ(drawing.FieldShape, "circle",
+//				sqljson.Path(union.DiscriminatorPath[Shape]()...)))
+//		})
+package unionent
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// JSON holds a union, such as a union.TaggedUnion[Spec], stored in a JSON column.
+// It implements driver.Valuer and sql.Scanner, the ValueScanner ent requires of field.Other.
+type JSON[U any] struct {
+	Union U
+}
+
+// SchemaType returns the column types of JSON fields for field.Other's SchemaType,
+// keyed by ent dialect name: jsonb for PostgreSQL and json for MySQL and SQLite.
+func SchemaType() map[string]string {
+	return map[string]string{
+		"mysql":    "json",
+		"postgres": "jsonb",
+		"sqlite3":  "json",
+	}
+}
+
+// IsZero reports whether the union is unset, which lets the omitzero struct tag
+// option of encoding/json omit it.
+func (j JSON[U]) IsZero() bool {
+	z, ok := any(j.Union).(interface{ IsZero() bool })
+	return ok && z.IsZero()
+}
+
+// Value implements the driver.Valuer interface, returning the JSON encoding of the union
+// as a string, or nil for an unset union.
+func (j JSON[U]) Value() (driver.Value, error) {
+	if j.IsZero() {
+		return nil, nil
+	}
+	data, err := json.Marshal(j.Union)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements the sql.Scanner interface, decoding the JSON encoding of a union
+// from a string or []byte column value. NULL resets the union.
+func (j *JSON[U]) Scan(src any) error {
+	var data []byte
+	switch src := src.(type) {
+	case nil:
+		var zero U
+		j.Union = zero
+		return nil
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	default:
+		return fmt.Errorf("unionent: cannot scan %T into %T", src, j)
+	}
+	return json.Unmarshal(data, &j.Union)
+}
+
+// MarshalJSON implements the json.Marshaler interface, encoding the union, or null if it is unset.
+func (j JSON[U]) MarshalJSON() ([]byte, error) {
+	if j.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(j.Union)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Null resets the union.
+func (j *JSON[U]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		var zero U
+		j.Union = zero
+		return nil
+	}
+	return json.Unmarshal(data, &j.Union)
+}
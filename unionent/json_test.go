@@ -0,0 +1,96 @@
+package unionent
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+
+	"github.com/eriicafes/union"
+)
+
+type Circle struct {
+	Radius float64 `json:"radius"`
+}
+
+type Rectangle struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+type Shape struct {
+	Circle    *Circle    `variant:"circle"`
+	Rectangle *Rectangle `variant:"rectangle"`
+}
+
+var (
+	_ driver.Valuer = JSON[union.TaggedUnion[Shape]]{}
+	_ sql.Scanner   = (*JSON[union.TaggedUnion[Shape]])(nil)
+)
+
+func TestJSON(t *testing.T) {
+	t.Run("round trips through column value", func(t *testing.T) {
+		in := JSON[union.TaggedUnion[Shape]]{Union: union.TaggedUnion[Shape]{Value: Shape{Circle: &Circle{Radius: 5}}}}
+		value, err := in.Value()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := `{"type":"circle","value":{"radius":5}}`
+		if value != expected {
+			t.Errorf("expected %v, got %v", expected, value)
+		}
+
+		for _, src := range []any{value, []byte(value.(string))} {
+			var out JSON[union.TaggedUnion[Shape]]
+			if err := out.Scan(src); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !union.Equal(in.Union, out.Union) {
+				t.Errorf("expected %v, got %v", in.Union, out.Union)
+			}
+		}
+	})
+
+	t.Run("stores unset union as null", func(t *testing.T) {
+		var in JSON[union.ExternallyTagged[Shape]]
+		if value, err := in.Value(); err != nil || value != nil {
+			t.Errorf("expected nil, got %v (err=%v)", value, err)
+		}
+
+		out := JSON[union.ExternallyTagged[Shape]]{Union: union.ExternallyTagged[Shape]{Value: Shape{Circle: &Circle{}}}}
+		if err := out.Scan(nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !out.IsZero() {
+			t.Errorf("expected unset union, got %v", out.Union)
+		}
+	})
+
+	t.Run("marshals like the union", func(t *testing.T) {
+		type row struct {
+			Shape JSON[union.TaggedUnion[Shape]] `json:"shape"`
+		}
+		data, err := json.Marshal(row{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(data) != `{"shape":null}` {
+			t.Errorf("expected null shape, got %s", data)
+		}
+
+		var r row
+		if err := json.Unmarshal([]byte(`{"shape":{"type":"rectangle","value":{"width":2,"height":3}}}`), &r); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rect, ok := union.As[Rectangle](r.Shape.Union); !ok || rect.Width != 2 {
+			t.Errorf("expected rectangle, got %v", r.Shape.Union)
+		}
+	})
+
+	t.Run("rejects other column types", func(t *testing.T) {
+		var out JSON[union.TaggedUnion[Shape]]
+		if err := out.Scan(42); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
@@ -0,0 +1,145 @@
+// Package unionhttp binds JSON request bodies to union types in HTTP handlers,
+// rejecting bad requests with client error statuses:
+//
+//	var shape union.TaggedUnion[Shape]
+//	if err := unionhttp.Bind(r, &shape); err != nil {
+//		http.Error(w, err.Error(), unionhttp.StatusCode(err))
+//		return
+//	}
+//
+// Binder has the methods of the Binding interface of gin's binding package, so it
+// can be used with gin without this package depending on gin:
+//
+//	err := c.ShouldBindWith(&shape, unionhttp.Default)
+//
+// Echo binders receive an echo.Context, so they are adapted with a small type
+// forwarding the request, and errors converted to an *echo.HTTPError:
+//
+//	type binder struct{}
+//
+//	func (binder) Bind(i any, c echo.Context) error {
+//		if err := unionhttp.Bind(c.Request(), i); err != nil {
+//			return echo.NewHTTPError(unionhttp.StatusCode(err), err.Error())
+//		}
+//		return nil
+//	}
+//
+// Chi and other net/http routers call Bind directly.
+package unionhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/eriicafes/union"
+)
+
+// DefaultMaxBytes is the request body size limit of Default.
+const DefaultMaxBytes = 1 << 20
+
+// Default is the Binder used by Bind, limiting request bodies to DefaultMaxBytes.
+var Default = Binder{MaxBytes: DefaultMaxBytes}
+
+// Binder decodes JSON request bodies into unions or other values.
+type Binder struct {
+	// MaxBytes limits the size of request bodies. Zero or less means no limit.
+	MaxBytes int64
+}
+
+// Error is returned by Binder.Bind when the request cannot be bound, holding the
+// HTTP status to respond with.
+type Error struct {
+	Status int   // HTTP status code, such as http.StatusBadRequest
+	Err    error // Underlying error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %v", http.StatusText(e.Status), e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode returns the HTTP status to respond with for an error returned by Bind:
+// the status of an *Error, or http.StatusInternalServerError for other errors.
+func StatusCode(err error) int {
+	var bindErr *Error
+	if errors.As(err, &bindErr) {
+		return bindErr.Status
+	}
+	return http.StatusInternalServerError
+}
+
+// Bind decodes the JSON body of the request into u with Default.
+func Bind(r *http.Request, u any) error {
+	return Default.Bind(r, u)
+}
+
+// Name returns the name of the binding, for gin.
+func (b Binder) Name() string {
+	return "union"
+}
+
+// Bind decodes the JSON body of the request into u, typically a pointer to a union,
+// with its UnmarshalJSON method.
+//
+// Returns an *Error with status:
+//   - http.StatusUnsupportedMediaType if the Content-Type is set and is not JSON
+//   - http.StatusRequestEntityTooLarge if the body is larger than MaxBytes
+//   - http.StatusBadRequest if the body is empty, malformed, holds more than one JSON
+//     value or cannot be decoded into u, such as an unknown variant
+//   - http.StatusInternalServerError if u cannot be decoded into at all, such as a
+//     non-pointer or a union whose Spec type is not a struct
+func (b Binder) Bind(r *http.Request, u any) error {
+	if ct := r.Header.Get("Content-Type"); ct != "" && !isJSON(ct) {
+		return &Error{Status: http.StatusUnsupportedMediaType, Err: fmt.Errorf("unsupported content type %q", ct)}
+	}
+	if r.Body == nil {
+		return &Error{Status: http.StatusBadRequest, Err: io.EOF}
+	}
+
+	body := r.Body
+	if b.MaxBytes > 0 {
+		body = http.MaxBytesReader(nil, body, b.MaxBytes)
+	}
+	decoder := json.NewDecoder(body)
+	if err := decoder.Decode(u); err != nil {
+		return bindError(err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		if err == nil {
+			err = errors.New("unexpected data after JSON value")
+		}
+		return bindError(err)
+	}
+	return nil
+}
+
+// bindError wraps a decoding error in an *Error with its status.
+func bindError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return &Error{Status: http.StatusRequestEntityTooLarge, Err: err}
+	}
+	var invalidErr *json.InvalidUnmarshalError
+	if errors.As(err, &invalidErr) || errors.Is(err, union.ErrSpecNotStruct) {
+		return &Error{Status: http.StatusInternalServerError, Err: err}
+	}
+	return &Error{Status: http.StatusBadRequest, Err: err}
+}
+
+// isJSON reports whether the media type of the Content-Type header is JSON,
+// such as application/json or application/problem+json.
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,99 @@
+package unionhttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eriicafes/union"
+)
+
+type Circle struct {
+	Radius float64 `json:"radius"`
+}
+
+type Shape struct {
+	Circle *Circle `variant:"circle"`
+}
+
+func TestBind(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		binder      Binder
+		status      int
+	}{
+		{name: "binds json", contentType: "application/json", body: `{"type":"circle","value":{"radius":5}}`},
+		{name: "binds json with parameters", contentType: "application/json; charset=utf-8", body: `{"type":"circle","value":{"radius":5}}`},
+		{name: "binds without content type", body: `{"type":"circle","value":{"radius":5}}`},
+		{name: "rejects other content types", contentType: "text/plain", body: `{}`, status: http.StatusUnsupportedMediaType},
+		{name: "rejects unknown variants", contentType: "application/json", body: `{"type":"square","value":{}}`, status: http.StatusBadRequest},
+		{name: "rejects malformed json", contentType: "application/json", body: `{"type":`, status: http.StatusBadRequest},
+		{name: "rejects empty bodies", contentType: "application/json", status: http.StatusBadRequest},
+		{name: "rejects trailing data", contentType: "application/json", body: `{"type":"circle","value":{}} {}`, status: http.StatusBadRequest},
+		{
+			name:        "rejects large bodies",
+			contentType: "application/json",
+			body:        `{"type":"circle","value":{"radius":5}}`,
+			binder:      Binder{MaxBytes: 10},
+			status:      http.StatusRequestEntityTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/shapes", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			binder := tt.binder
+			if binder == (Binder{}) {
+				binder = Default
+			}
+
+			var shape union.TaggedUnion[Shape]
+			err := binder.Bind(r, &shape)
+			if tt.status != 0 {
+				var bindErr *Error
+				if !errors.As(err, &bindErr) || StatusCode(err) != tt.status {
+					t.Errorf("expected status %d, got %v", tt.status, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if shape.Value.Circle == nil || shape.Value.Circle.Radius != 5 {
+				t.Errorf("expected circle, got %v", shape)
+			}
+		})
+	}
+
+	t.Run("unwraps union errors", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/shapes", strings.NewReader(`{"type":"square","value":{}}`))
+		var shape union.TaggedUnion[Shape]
+		if err := Bind(r, &shape); !errors.Is(err, union.ErrUnknownVariant) {
+			t.Errorf("expected ErrUnknownVariant, got %v", err)
+		}
+	})
+
+	t.Run("invalid targets are server errors", func(t *testing.T) {
+		body := `{"type":"circle","value":{"radius":5}}`
+		var shape union.TaggedUnion[Shape]
+		if err := Bind(httptest.NewRequest("POST", "/shapes", strings.NewReader(body)), shape); StatusCode(err) != http.StatusInternalServerError {
+			t.Errorf("expected status 500, got %v", err)
+		}
+		var notStruct union.TaggedUnion[int]
+		if err := Bind(httptest.NewRequest("POST", "/shapes", strings.NewReader(body)), &notStruct); StatusCode(err) != http.StatusInternalServerError {
+			t.Errorf("expected status 500, got %v", err)
+		}
+	})
+
+	t.Run("other errors are server errors", func(t *testing.T) {
+		if status := StatusCode(errors.New("boom")); status != http.StatusInternalServerError {
+			t.Errorf("expected status 500, got %d", status)
+		}
+	})
+}
@@ -0,0 +1,254 @@
+package unionproto
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+
+	"github.com/eriicafes/union"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// MarshalJSON returns the JSON representation protojson gives the oneof held by the union:
+// an object with the active field under its JSON name, the lowerCamelCase form of the
+// variant name (so `variant:"number_value"` is written as "numberValue"). An empty
+// union, like an unset oneof, is written as an empty object.
+//
+// Payloads follow the protojson rules: proto messages are marshaled with protojson,
+// 64-bit integers are written as strings, non-finite floats as "NaN", "Infinity"
+// and "-Infinity", and enums as their value names. The output is compact, so it
+// matches protojson output with its whitespace removed.
+func MarshalJSON[Spec any](u union.TaggedUnion[Spec]) ([]byte, error) {
+	variant, ok := u.Variant()
+	if !ok {
+		if u.IsZero() {
+			return []byte("{}"), nil
+		}
+		return nil, union.ErrMultipleVariants
+	}
+	payload, err := marshalProtoJSON(reflect.ValueOf(u.GetValue()))
+	if err != nil {
+		return nil, fmt.Errorf("variant %q: %w", variant, err)
+	}
+	key, err := json.Marshal(jsonName(variant))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	buf.Write(key)
+	buf.WriteByte(':')
+	buf.Write(payload)
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes the protojson representation of a oneof, written by MarshalJSON,
+// into the union. Like protojson, the field is read under its JSON name or its original
+// variant name, and an empty object or a field holding null leaves the union empty.
+//
+// Returns an error if:
+//   - The JSON data is malformed or not an object
+//   - The object holds more than one field (union.ErrVariantKeyCount)
+//   - The field doesn't match any known variant (*union.UnknownVariantError)
+//   - The value cannot be decoded into the variant's type (*union.DecodeError)
+func UnmarshalJSON[Spec any](data []byte, u *union.TaggedUnion[Spec]) error {
+	*u = union.TaggedUnion[Spec]{}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) > 1 {
+		return union.ErrVariantKeyCount
+	}
+
+	types := union.VariantTypes[Spec]()
+	for key, value := range raw {
+		variant, ok := variantOf(key, types)
+		if !ok {
+			return &union.UnknownVariantError{Spec: reflect.TypeFor[Spec](), Variant: key, Known: union.Variants[Spec]()}
+		}
+		if string(bytes.TrimSpace(value)) == "null" {
+			return nil
+		}
+		target := reflect.New(types[variant])
+		if err := unmarshalProtoJSON(value, target.Elem()); err != nil {
+			return &union.DecodeError{Spec: reflect.TypeFor[Spec](), Variant: variant, Err: err}
+		}
+		err := union.SetVariant(u, variant, target.Elem().Interface())
+		if errors.Is(err, union.ErrZeroVariants) {
+			// zero payloads are still the decoded variant
+			return u.Select(variant)
+		}
+		return err
+	}
+	return nil
+}
+
+// RegisterJSON registers MarshalJSON and UnmarshalJSON as the codec of the Spec type
+// with union.RegisterCodec, so TaggedUnion values of that Spec type, including those
+// nested in other structs, are marshaled like protojson marshals the oneof.
+func RegisterJSON[Spec any]() {
+	union.RegisterCodec(
+		func(spec Spec) ([]byte, error) { return MarshalJSON(union.TaggedUnion[Spec]{Value: spec}) },
+		func(data []byte, spec *Spec) error {
+			var u union.TaggedUnion[Spec]
+			if err := UnmarshalJSON(data, &u); err != nil {
+				return err
+			}
+			*spec = u.Value
+			return nil
+		},
+	)
+}
+
+// jsonName returns the protojson name of the field named name, which removes
+// underscores and uppercases the lowercase letters following them.
+func jsonName(name string) string {
+	b := make([]byte, 0, len(name))
+	upper := false
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '_':
+			upper = true
+			continue
+		case upper && 'a' <= c && c <= 'z':
+			c -= 'a' - 'A'
+		}
+		upper = false
+		b = append(b, c)
+	}
+	return string(b)
+}
+
+// variantOf returns the variant whose JSON name or name is key.
+func variantOf(key string, types map[string]reflect.Type) (string, bool) {
+	if _, ok := types[key]; ok {
+		return key, true
+	}
+	for variant := range types {
+		if jsonName(variant) == key {
+			return variant, true
+		}
+	}
+	return "", false
+}
+
+var (
+	messageType = reflect.TypeFor[proto.Message]()
+	enumType    = reflect.TypeFor[protoreflect.Enum]()
+)
+
+// marshalProtoJSON returns the protojson encoding of a variant payload.
+func marshalProtoJSON(v reflect.Value) ([]byte, error) {
+	if v.Type().Implements(messageType) {
+		data, err := protojson.Marshal(v.Interface().(proto.Message))
+		if err != nil {
+			return nil, err
+		}
+		// protojson randomizes whitespace, which is removed for stable output
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, data); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return []byte("null"), nil
+		}
+		if !v.Elem().Type().Implements(messageType) {
+			return marshalProtoJSON(v.Elem())
+		}
+	}
+
+	switch {
+	case v.Type().Implements(enumType):
+		e := v.Interface().(protoreflect.Enum)
+		if ev := e.Descriptor().Values().ByNumber(e.Number()); ev != nil {
+			return json.Marshal(string(ev.Name()))
+		}
+		return json.Marshal(int32(e.Number()))
+	case v.Kind() == reflect.Int64:
+		return json.Marshal(strconv.FormatInt(v.Int(), 10))
+	case v.Kind() == reflect.Uint64:
+		return json.Marshal(strconv.FormatUint(v.Uint(), 10))
+	case v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64:
+		switch f := v.Float(); {
+		case math.IsNaN(f):
+			return json.Marshal("NaN")
+		case math.IsInf(f, 1):
+			return json.Marshal("Infinity")
+		case math.IsInf(f, -1):
+			return json.Marshal("-Infinity")
+		}
+	}
+	return json.Marshal(v.Interface())
+}
+
+// unmarshalProtoJSON decodes the protojson encoding of a variant payload into v.
+func unmarshalProtoJSON(data []byte, v reflect.Value) error {
+	if v.Kind() == reflect.Pointer && v.Type().Implements(messageType) {
+		v.Set(reflect.New(v.Type().Elem()))
+		return protojson.Unmarshal(data, v.Interface().(proto.Message))
+	}
+	if v.Kind() == reflect.Pointer {
+		v.Set(reflect.New(v.Type().Elem()))
+		return unmarshalProtoJSON(data, v.Elem())
+	}
+
+	var s string
+	quoted := json.Unmarshal(data, &s) == nil
+	switch {
+	case v.Type().Implements(enumType) && v.CanInt():
+		if quoted {
+			ev := v.Interface().(protoreflect.Enum).Descriptor().Values().ByName(protoreflect.Name(s))
+			if ev == nil {
+				return fmt.Errorf("invalid enum value %q", s)
+			}
+			v.SetInt(int64(ev.Number()))
+			return nil
+		}
+	case quoted && v.CanInt():
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+		return nil
+	case quoted && v.CanUint():
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+		return nil
+	case quoted && v.CanFloat():
+		var f float64
+		switch s {
+		case "NaN":
+			f = math.NaN()
+		case "Infinity":
+			f = math.Inf(1)
+		case "-Infinity":
+			f = math.Inf(-1)
+		default:
+			var err error
+			if f, err = strconv.ParseFloat(s, v.Type().Bits()); err != nil {
+				return err
+			}
+		}
+		v.SetFloat(f)
+		return nil
+	}
+	return json.Unmarshal(data, v.Addr().Interface())
+}
@@ -0,0 +1,201 @@
+package unionproto
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/eriicafes/union"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type EventSpec struct {
+	Number float64            `variant:"number_value"`
+	Count  int64              `variant:"event_count"`
+	Null   structpb.NullValue `variant:"null_value"`
+	Struct *structpb.Struct   `variant:"struct_value"`
+}
+
+// eventDescriptor describes a message with a oneof holding the EventSpec variants:
+//
+//	message Event {
+//	  oneof payload {
+//	    double number_value = 1;
+//	    int64 event_count = 2;
+//	    google.protobuf.NullValue null_value = 3;
+//	    google.protobuf.Struct struct_value = 4;
+//	  }
+//	}
+func eventDescriptor(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:       proto.String(name),
+			Number:     proto.Int32(number),
+			Label:      descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:       typ.Enum(),
+			OneofIndex: proto.Int32(0),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("event.proto"),
+		Package:    proto.String("test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/struct.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Event"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("number_value", 1, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, ""),
+				field("event_count", 2, descriptorpb.FieldDescriptorProto_TYPE_INT64, ""),
+				field("null_value", 3, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".google.protobuf.NullValue"),
+				field("struct_value", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Struct"),
+			},
+			OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("payload")}},
+		}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return fd.Messages().Get(0)
+}
+
+func TestProtoJSON(t *testing.T) {
+	s, _ := structpb.NewStruct(map[string]any{"radius": 5})
+	md := eventDescriptor(t)
+
+	tests := []struct {
+		name     string
+		spec     EventSpec
+		expected string
+	}{
+		{name: "double", spec: EventSpec{Number: 1.5}, expected: `{"numberValue":1.5}`},
+		{name: "non-finite double", spec: EventSpec{Number: math.Inf(-1)}, expected: `{"numberValue":"-Infinity"}`},
+		{name: "int64", spec: EventSpec{Count: 1 << 60}, expected: `{"eventCount":"1152921504606846976"}`},
+		{name: "message", spec: EventSpec{Struct: s}, expected: `{"structValue":{"radius":5}}`},
+		{name: "empty union", expected: `{}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := union.TaggedUnion[EventSpec]{Value: tt.spec}
+			data, err := MarshalJSON(u)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, data)
+			}
+
+			// the output matches protojson for a message holding the oneof
+			msg := dynamicpb.NewMessage(md)
+			if !u.IsZero() {
+				if err := ToOneof(u, msg); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			expected, err := protojson.Marshal(msg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, expected); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(data) != compact.String() {
+				t.Errorf("expected protojson output %s, got %s", compact.String(), data)
+			}
+
+			var decoded union.TaggedUnion[EventSpec]
+			if err := UnmarshalJSON(data, &decoded); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !union.Equal(decoded, u) {
+				t.Errorf("expected %v, got %v", u, decoded)
+			}
+		})
+	}
+
+	t.Run("enum names", func(t *testing.T) {
+		var u union.TaggedUnion[EventSpec]
+		if err := UnmarshalJSON([]byte(`{"null_value":"NULL_VALUE"}`), &u); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if variant, ok := u.Variant(); !ok || variant != "null_value" {
+			t.Errorf("expected variant null_value, got %q (ok=%v)", variant, ok)
+		}
+		data, err := MarshalJSON(u)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(data) != `{"nullValue":"NULL_VALUE"}` {
+			t.Errorf("expected enum name, got %s", data)
+		}
+	})
+
+	t.Run("accepts numbers for 64-bit integers", func(t *testing.T) {
+		var u union.TaggedUnion[EventSpec]
+		if err := UnmarshalJSON([]byte(`{"eventCount":7}`), &u); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if u.Value.Count != 7 {
+			t.Errorf("expected count 7, got %v", u.Value.Count)
+		}
+	})
+
+	t.Run("rejects several fields", func(t *testing.T) {
+		var u union.TaggedUnion[EventSpec]
+		err := UnmarshalJSON([]byte(`{"numberValue":1,"eventCount":"2"}`), &u)
+		if !errors.Is(err, union.ErrVariantKeyCount) {
+			t.Errorf("expected ErrVariantKeyCount, got %v", err)
+		}
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		var u union.TaggedUnion[EventSpec]
+		err := UnmarshalJSON([]byte(`{"boolValue":true}`), &u)
+		if !errors.Is(err, union.ErrUnknownVariant) {
+			t.Errorf("expected ErrUnknownVariant, got %v", err)
+		}
+	})
+}
+
+type RegisteredEventSpec struct {
+	Number float64 `variant:"number_value"`
+	Count  int64   `variant:"event_count"`
+}
+
+func TestRegisterJSON(t *testing.T) {
+	RegisterJSON[RegisteredEventSpec]()
+
+	type Envelope struct {
+		ID    string                                 `json:"id"`
+		Event union.TaggedUnion[RegisteredEventSpec] `json:"event"`
+	}
+	input := `{"id":"a","event":{"eventCount":"3"}}`
+	var env Envelope
+	if err := json.Unmarshal([]byte(input), &env); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if env.Event.Value.Count != 3 {
+		t.Errorf("expected count 3, got %v", env.Event)
+	}
+	data, err := json.Marshal(env)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != input {
+		t.Errorf("expected %s, got %s", input, data)
+	}
+}
@@ -0,0 +1,234 @@
+// Package unionproto converts between union types and protobuf oneof fields.
+//
+// Each field of a oneof maps to the spec field whose variant name equals the
+// proto field name (for example `variant:"string_value"`):
+//
+//	type ValueSpec struct {
+//		Number float64          `variant:"number_value"`
+//		String string           `variant:"string_value"`
+//		Struct *structpb.Struct `variant:"struct_value"`
+//	}
+//
+//	u, err := unionproto.FromOneof[ValueSpec](msg)
+//	err = unionproto.ToOneof(u, msg)
+//
+// The oneof is selected by a ProtoOneof() string method on the Spec type.
+// Without it the message must declare exactly one oneof.
+//
+// MarshalJSON and UnmarshalJSON encode unions like protojson encodes the oneof
+// inside its message, for sharing spec structs between gRPC-gateway and plain JSON APIs.
+package unionproto
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/eriicafes/union"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ErrOneofNotFound is returned when the message does not declare the oneof
+// selected by the Spec type, or declares several oneofs and none was selected.
+var ErrOneofNotFound = errors.New("oneof not found")
+
+// FromOneof returns a TaggedUnion holding the populated field of the message's oneof.
+// Message fields are stored as the generated message pointers, enum fields are
+// converted to the spec field's type and scalar fields are converted to the spec
+// field's type when it is a named type of the same kind. A populated field holding
+// a zero scalar, such as 0, "" or false, is selected with Select, so pointer fields
+// hold a pointer to the zero value.
+//
+// Returns an error if:
+//   - The oneof cannot be found (ErrOneofNotFound)
+//   - No field of the oneof is populated (union.ErrZeroVariants)
+//   - The populated field doesn't match any known variant (*union.UnknownVariantError)
+//   - The field's value doesn't match the variant's type (union.ErrNoFieldMatched)
+func FromOneof[Spec any](msg proto.Message) (union.TaggedUnion[Spec], error) {
+	var u union.TaggedUnion[Spec]
+
+	m := msg.ProtoReflect()
+	od, err := oneofOf[Spec](m.Descriptor())
+	if err != nil {
+		return u, err
+	}
+	fd := m.WhichOneof(od)
+	if fd == nil {
+		return u, union.ErrZeroVariants
+	}
+
+	variant := string(fd.Name())
+	value := fromProtoValue(fd, m.Get(fd))
+	if ft, ok := union.VariantTypes[Spec]()[variant]; ok {
+		value = convertTo(value, ft)
+	}
+	err = union.SetVariant(&u, variant, value)
+	if errors.Is(err, union.ErrZeroVariants) {
+		// zero scalars are still the populated field
+		return u, u.Select(variant)
+	}
+	return u, err
+}
+
+// ToOneof populates the message's oneof field named like the union's active variant,
+// replacing whichever field of the oneof was previously set.
+//
+// Returns an error if:
+//   - The oneof cannot be found (ErrOneofNotFound)
+//   - No variant or multiple variants are set (union.ErrZeroVariants)
+//   - The oneof has no field named like the active variant (*union.UnknownVariantError)
+//   - The variant's value cannot be stored in the proto field (union.ErrNoFieldMatched)
+func ToOneof[Spec any](u union.TaggedUnion[Spec], msg proto.Message) error {
+	m := msg.ProtoReflect()
+	od, err := oneofOf[Spec](m.Descriptor())
+	if err != nil {
+		return err
+	}
+
+	variant, ok := u.Variant()
+	if !ok {
+		return union.ErrZeroVariants
+	}
+	fd := od.Fields().ByName(protoreflect.Name(variant))
+	if fd == nil {
+		return &union.UnknownVariantError{
+			Spec:    reflect.TypeFor[Spec](),
+			Variant: variant,
+			Known:   fieldNames(od),
+		}
+	}
+
+	value, err := toProtoValue(fd, reflect.ValueOf(u.GetValue()))
+	if err != nil {
+		return fmt.Errorf("%w: variant %s: %v", union.ErrNoFieldMatched, variant, err)
+	}
+	m.Set(fd, value)
+	return nil
+}
+
+// oneofOf returns the oneof selected by the Spec type's ProtoOneof method,
+// or the only oneof declared by the message.
+func oneofOf[Spec any](md protoreflect.MessageDescriptor) (protoreflect.OneofDescriptor, error) {
+	var zero Spec
+	if s, ok := any(zero).(interface{ ProtoOneof() string }); ok {
+		name := s.ProtoOneof()
+		if od := md.Oneofs().ByName(protoreflect.Name(name)); od != nil {
+			return od, nil
+		}
+		return nil, fmt.Errorf("%w: %s.%s", ErrOneofNotFound, md.FullName(), name)
+	}
+
+	var found protoreflect.OneofDescriptor
+	oneofs := md.Oneofs()
+	for i := 0; i < oneofs.Len(); i++ {
+		od := oneofs.Get(i)
+		// skip the synthetic oneofs generated for proto3 optional fields
+		if od.IsSynthetic() {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("%w: %s declares multiple oneofs", ErrOneofNotFound, md.FullName())
+		}
+		found = od
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: %s declares no oneof", ErrOneofNotFound, md.FullName())
+	}
+	return found, nil
+}
+
+// fieldNames returns the names of the fields of the oneof.
+func fieldNames(od protoreflect.OneofDescriptor) []string {
+	fields := od.Fields()
+	names := make([]string, fields.Len())
+	for i := range names {
+		names[i] = string(fields.Get(i).Name())
+	}
+	return names
+}
+
+// fromProtoValue returns the Go value of a populated oneof field.
+func fromProtoValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) any {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return v.Message().Interface()
+	case protoreflect.EnumKind:
+		return int32(v.Enum())
+	}
+	return v.Interface()
+}
+
+// convertTo converts scalar values to the variant's type (or its element type
+// for pointer fields) when they differ only by name, such as generated enums.
+func convertTo(value any, ft reflect.Type) any {
+	v := reflect.ValueOf(value)
+	target := ft
+	if target.Kind() == reflect.Pointer {
+		target = target.Elem()
+	}
+	if v.Type() == ft || v.Type() == target || v.Kind() == reflect.Pointer {
+		return value
+	}
+	if v.Kind() == target.Kind() && v.CanConvert(target) {
+		return v.Convert(target).Interface()
+	}
+	return value
+}
+
+// scalarTypes maps proto scalar kinds to the Go types protoreflect stores them as.
+var scalarTypes = map[protoreflect.Kind]reflect.Type{
+	protoreflect.BoolKind:     reflect.TypeFor[bool](),
+	protoreflect.Int32Kind:    reflect.TypeFor[int32](),
+	protoreflect.Sint32Kind:   reflect.TypeFor[int32](),
+	protoreflect.Sfixed32Kind: reflect.TypeFor[int32](),
+	protoreflect.Int64Kind:    reflect.TypeFor[int64](),
+	protoreflect.Sint64Kind:   reflect.TypeFor[int64](),
+	protoreflect.Sfixed64Kind: reflect.TypeFor[int64](),
+	protoreflect.Uint32Kind:   reflect.TypeFor[uint32](),
+	protoreflect.Fixed32Kind:  reflect.TypeFor[uint32](),
+	protoreflect.Uint64Kind:   reflect.TypeFor[uint64](),
+	protoreflect.Fixed64Kind:  reflect.TypeFor[uint64](),
+	protoreflect.FloatKind:    reflect.TypeFor[float32](),
+	protoreflect.DoubleKind:   reflect.TypeFor[float64](),
+	protoreflect.StringKind:   reflect.TypeFor[string](),
+	protoreflect.BytesKind:    reflect.TypeFor[[]byte](),
+}
+
+// toProtoValue converts a variant value to the protoreflect value of the oneof field.
+func toProtoValue(fd protoreflect.FieldDescriptor, v reflect.Value) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if v.Kind() != reflect.Pointer {
+			// take the address of a copy so non-pointer message fields are supported
+			ptr := reflect.New(v.Type())
+			ptr.Elem().Set(v)
+			v = ptr
+		}
+		pm, ok := v.Interface().(proto.Message)
+		if !ok {
+			return protoreflect.Value{}, fmt.Errorf("%s is not a proto message", v.Type())
+		}
+		m := pm.ProtoReflect()
+		if m.Descriptor().FullName() != fd.Message().FullName() {
+			return protoreflect.Value{}, fmt.Errorf("message %s is not %s", m.Descriptor().FullName(), fd.Message().FullName())
+		}
+		return protoreflect.ValueOfMessage(m), nil
+	}
+
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if fd.Kind() == protoreflect.EnumKind {
+		if !v.CanInt() {
+			return protoreflect.Value{}, fmt.Errorf("%s is not an enum", v.Type())
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(v.Int())), nil
+	}
+
+	target := scalarTypes[fd.Kind()]
+	if target == nil || v.Kind() != target.Kind() || !v.CanConvert(target) {
+		return protoreflect.Value{}, fmt.Errorf("%s cannot be stored as %s", v.Type(), fd.Kind())
+	}
+	return protoreflect.ValueOf(v.Convert(target).Interface()), nil
+}
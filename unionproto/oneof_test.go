@@ -0,0 +1,193 @@
+package unionproto
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eriicafes/union"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type ValueSpec struct {
+	Number float64          `variant:"number_value"`
+	String string           `variant:"string_value"`
+	Bool   bool             `variant:"bool_value"`
+	Struct *structpb.Struct `variant:"struct_value"`
+}
+
+type Label string
+
+type LabelSpec struct {
+	Label Label `variant:"string_value"`
+}
+
+type PointerValueSpec struct {
+	Number *float64 `variant:"number_value"`
+	String *string  `variant:"string_value"`
+}
+
+type MissingOneofSpec struct {
+	Number float64 `variant:"number_value"`
+}
+
+func (MissingOneofSpec) ProtoOneof() string { return "missing" }
+
+func TestFromOneof(t *testing.T) {
+	s, _ := structpb.NewStruct(map[string]any{"radius": 5})
+
+	tests := []struct {
+		name        string
+		msg         *structpb.Value
+		expected    any
+		expectedErr error
+	}{
+		{
+			name:     "reads scalar field",
+			msg:      structpb.NewNumberValue(5),
+			expected: 5.0,
+		},
+		{
+			name:     "reads message field",
+			msg:      structpb.NewStructValue(s),
+			expected: s,
+		},
+		{
+			name:        "returns error for unknown field",
+			msg:         structpb.NewNullValue(),
+			expectedErr: union.ErrUnknownVariant,
+		},
+		{
+			name:        "returns error when oneof is not set",
+			msg:         &structpb.Value{},
+			expectedErr: union.ErrZeroVariants,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := FromOneof[ValueSpec](tt.msg)
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if m, ok := tt.expected.(proto.Message); ok {
+				if !proto.Equal(u.GetValue().(proto.Message), m) {
+					t.Errorf("expected %v, got %v", m, u.GetValue())
+				}
+				return
+			}
+			if u.GetValue() != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, u.GetValue())
+			}
+		})
+	}
+}
+
+func TestFromOneofConvertsNamedTypes(t *testing.T) {
+	u, err := FromOneof[LabelSpec](structpb.NewStringValue("circle"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if u.Value.Label != "circle" {
+		t.Errorf("expected circle, got %q", u.Value.Label)
+	}
+}
+
+func TestFromOneofZeroScalars(t *testing.T) {
+	u, err := FromOneof[ValueSpec](structpb.NewNumberValue(0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if variant, _ := u.Variant(); variant != "number_value" {
+		t.Errorf("expected number_value, got %q", variant)
+	}
+
+	p, err := FromOneof[PointerValueSpec](structpb.NewStringValue(""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Value.String == nil || *p.Value.String != "" {
+		t.Errorf("expected pointer to empty string, got %v", p.Value.String)
+	}
+
+	msg := structpb.NewNumberValue(5)
+	if err := ToOneof(p, msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !proto.Equal(msg, structpb.NewStringValue("")) {
+		t.Errorf("expected %v, got %v", structpb.NewStringValue(""), msg)
+	}
+}
+
+func TestToOneof(t *testing.T) {
+	s, _ := structpb.NewStruct(map[string]any{"radius": 5})
+
+	tests := []struct {
+		name        string
+		shape       union.TaggedUnion[ValueSpec]
+		expected    *structpb.Value
+		expectedErr error
+	}{
+		{
+			name:     "writes scalar field",
+			shape:    union.TaggedUnion[ValueSpec]{Value: ValueSpec{String: "circle"}},
+			expected: structpb.NewStringValue("circle"),
+		},
+		{
+			name:     "writes message field",
+			shape:    union.TaggedUnion[ValueSpec]{Value: ValueSpec{Struct: s}},
+			expected: structpb.NewStructValue(s),
+		},
+		{
+			name:        "returns error when no variant is set",
+			shape:       union.TaggedUnion[ValueSpec]{},
+			expectedErr: union.ErrZeroVariants,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := structpb.NewBoolValue(true)
+			err := ToOneof(tt.shape, msg)
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !proto.Equal(msg, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, msg)
+			}
+		})
+	}
+}
+
+func TestToOneofUnknownVariant(t *testing.T) {
+	type ExtraSpec struct {
+		Hexagon string `variant:"hexagon"`
+	}
+	err := ToOneof(union.TaggedUnion[ExtraSpec]{Value: ExtraSpec{Hexagon: "six"}}, &structpb.Value{})
+	if !errors.Is(err, union.ErrUnknownVariant) {
+		t.Errorf("expected error %v, got %v", union.ErrUnknownVariant, err)
+	}
+}
+
+func TestOneofNotFound(t *testing.T) {
+	_, err := FromOneof[MissingOneofSpec](structpb.NewNumberValue(5))
+	if !errors.Is(err, ErrOneofNotFound) {
+		t.Errorf("expected error %v, got %v", ErrOneofNotFound, err)
+	}
+}
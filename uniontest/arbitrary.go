@@ -0,0 +1,41 @@
+// Package uniontest generates random unions for property-based tests and fuzzers.
+//
+// The union types implement the quick.Generator interface, so testing/quick passes
+// random valid unions to properties taking them as arguments:
+//
+//	err := quick.Check(func(u union.TaggedUnion[Shape]) bool {
+//		data, _ := json.Marshal(u)
+//		var got union.TaggedUnion[Shape]
+//		return json.Unmarshal(data, &got) == nil && union.Equal(u, got)
+//	}, nil)
+//
+// Arbitrary returns the Spec value of such a union. Fuzz targets derive it from a
+// fuzzed seed, so the fuzzer explores variants and payloads through the seed:
+//
+//	f.Fuzz(func(t *testing.T, seed int64) {
+//		shape := uniontest.Arbitrary[Shape](rand.New(rand.NewSource(seed)))
+//		// ...
+//	})
+package uniontest
+
+import (
+	"math/rand"
+
+	"github.com/eriicafes/union"
+)
+
+// Size is the size hint Arbitrary generates values with, the default size of testing/quick.
+// Numbers are within ±Size and strings, slices and maps hold up to Size elements.
+const Size = 50
+
+// Arbitrary returns a Spec value with one randomly chosen variant set to a random payload,
+// like the Generate method of the union types. It panics if the Spec type is not a struct
+// or none of its variants can hold a non-zero payload.
+func Arbitrary[Spec any](r *rand.Rand) Spec {
+	return ArbitrarySize[Spec](r, Size)
+}
+
+// ArbitrarySize is like Arbitrary with the given size hint.
+func ArbitrarySize[Spec any](r *rand.Rand, size int) Spec {
+	return union.TaggedUnion[Spec]{}.Generate(r, size).Interface().(union.TaggedUnion[Spec]).Value
+}
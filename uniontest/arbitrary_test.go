@@ -0,0 +1,71 @@
+package uniontest
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/eriicafes/union"
+)
+
+type Circle struct {
+	Radius float64 `json:"radius"`
+}
+
+type Rectangle struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+type Shape struct {
+	Circle    *Circle    `variant:"circle"`
+	Rectangle *Rectangle `variant:"rectangle"`
+	Label     string     `variant:"label"`
+}
+
+func TestArbitrary(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 50 {
+		u := union.TaggedUnion[Shape]{Value: Arbitrary[Shape](r)}
+		if err := u.Validate(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	t.Run("size", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		for range 50 {
+			shape := ArbitrarySize[Shape](r, 0)
+			if shape.Circle != nil && shape.Circle.Radius != 0 {
+				t.Errorf("expected zero radius, got %v", shape.Circle.Radius)
+			}
+		}
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		a := Arbitrary[Shape](rand.New(rand.NewSource(7)))
+		b := Arbitrary[Shape](rand.New(rand.NewSource(7)))
+		if !union.Equal(union.TaggedUnion[Shape]{Value: a}, union.TaggedUnion[Shape]{Value: b}) {
+			t.Errorf("expected %v, got %v", a, b)
+		}
+	})
+}
+
+func FuzzArbitrary(f *testing.F) {
+	f.Add(int64(0))
+	f.Add(int64(42))
+	f.Fuzz(func(t *testing.T, seed int64) {
+		u := union.TaggedUnion[Shape]{Value: Arbitrary[Shape](rand.New(rand.NewSource(seed)))}
+		data, err := json.Marshal(u)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var got union.TaggedUnion[Shape]
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !union.Equal(u, got) {
+			t.Errorf("expected %v, got %v", u, got)
+		}
+	})
+}
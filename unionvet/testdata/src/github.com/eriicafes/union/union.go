@@ -0,0 +1,25 @@
+// Package union is a stub of the union types used by the analyzer tests.
+package union
+
+type TaggedUnion[Spec any] struct{ Value Spec }
+
+type ExternallyTagged[Spec any] struct{ Value Spec }
+
+type Union[Spec any] struct{ Value Spec }
+
+type Extras map[string][]byte
+
+type Raw struct {
+	Variant string
+	Value   []byte
+}
+
+type Naming int
+
+const (
+	ExactNaming Naming = iota
+	SnakeCase
+	CamelCase
+	KebabCase
+	LowerCase
+)
@@ -0,0 +1,117 @@
+package shapes
+
+import "github.com/eriicafes/union"
+
+type Circle struct{ Radius float64 }
+
+type Rectangle struct{ Width, Height float64 }
+
+type Empty struct{}
+
+type Shape struct {
+	Circle    *Circle    `variant:"circle"`
+	Rectangle *Rectangle `variant:"rectangle"`
+}
+
+type TaggedShape = union.TaggedUnion[Shape]
+
+type DuplicateShape struct {
+	Circle *Circle `variant:"circle"`
+	Round  *Circle `variant:"circle"` // want `union spec shapes.DuplicateShape: fields Circle and Round declare the same variant "circle"`
+}
+
+var _ union.ExternallyTagged[DuplicateShape]
+
+// Zero scalars and empty structs are active variants once selected with Select.
+type ScalarShape struct {
+	Circle Circle
+	Count  int
+	Marker Empty
+}
+
+var _ union.Union[ScalarShape]
+
+type NamedShape struct {
+	FooBar *Circle
+	Foo    *Circle    `variant:"foo_bar"` // want `union spec shapes.NamedShape: fields FooBar and Foo declare the same variant "foo_bar"`
+	Square *Rectangle `variant:"FooBar"`
+}
+
+func (NamedShape) VariantNaming() union.Naming { return union.SnakeCase } // want VariantNaming:"naming 1"
+
+var _ union.TaggedUnion[NamedShape]
+
+type AliasShape struct {
+	Circle  *Circle    `variant:"circle"`
+	Round   *Circle    `variant:"round" variantAliases:"ring,circle"` // want `union spec shapes.AliasShape: fields Circle and Round declare the same variant "circle"`
+	Unknown *union.Raw `variant:"round"`
+}
+
+var _ union.TaggedUnion[AliasShape]
+
+var _ union.TaggedUnion[string] // want `union spec string is not a struct`
+
+type HelperShape struct {
+	Circle *Circle `variant:"circle"`
+	Cached *Circle `variant:"-"`
+	count  int
+}
+
+var _ union.TaggedUnion[HelperShape]
+
+type RoundShapes struct {
+	Circle *Circle `variant:"circle"`
+}
+
+type GroupedShape struct {
+	RoundShapes
+	Round *Circle `variant:"circle"` // want `union spec shapes.GroupedShape: fields Circle and Round declare the same variant "circle"`
+}
+
+var _ union.TaggedUnion[GroupedShape]
+
+var _ = GroupedShape{RoundShapes: RoundShapes{}, Round: &Circle{}}
+
+type EnvelopeShape struct {
+	Circle *Circle `variant:"circle"`
+	Extras union.Extras
+}
+
+var _ union.TaggedUnion[EnvelopeShape]
+
+var _ = EnvelopeShape{Circle: &Circle{}, Extras: union.Extras{}}
+
+func helpers(u *union.TaggedUnion[HelperShape]) {
+	_ = HelperShape{Circle: &Circle{}, Cached: &Circle{}, count: 1}
+	u.Value.Circle = &Circle{}
+	u.Value.Cached = &Circle{}
+}
+
+// Generic wrappers are checked where they are instantiated.
+type wrapper[S any] struct{ u union.TaggedUnion[S] }
+
+func literals() {
+	_ = Shape{Circle: &Circle{Radius: 1}}
+	_ = Shape{Circle: &Circle{Radius: 1}, Rectangle: nil}
+	_ = TaggedShape{Value: Shape{Circle: &Circle{}, Rectangle: &Rectangle{}}} // want `union spec shapes.Shape literal sets multiple variants: \[Circle Rectangle\]`
+	_ = Circle{Radius: 1}
+}
+
+func assignments(u *TaggedShape, other Shape) {
+	u.Value.Circle = &Circle{Radius: 1}
+	u.Value.Rectangle = &Rectangle{Width: 1} // want `u.Value.Rectangle is set after u.Value.Circle, leaving multiple variants set`
+
+	u.Value = Shape{}
+	u.Value.Circle = &Circle{Radius: 1}
+	u.Value.Circle = nil
+	u.Value.Rectangle = &Rectangle{Width: 1}
+
+	other.Circle = &Circle{Radius: 1}
+	u.Value.Rectangle = &Rectangle{Width: 1}
+
+	switch {
+	case other.Circle != nil:
+		other.Circle = &Circle{}
+		other.Rectangle = &Rectangle{} // want `other.Rectangle is set after other.Circle, leaving multiple variants set`
+	}
+}
@@ -0,0 +1,340 @@
+// Package unionvet defines an analyzer that reports misuse of union spec structs.
+//
+// A spec struct is any type used as the Spec type argument of union.TaggedUnion,
+// union.ExternallyTagged or union.Union in the analyzed package. The analyzer reports:
+//
+//   - Spec types that are not structs
+//   - Fields of the same spec declaring the same variant name or alias, with untagged
+//     fields named by the spec's VariantNaming method when it returns a constant
+//   - Composite literals of a spec that set more than one variant field
+//   - Consecutive assignments that set more than one variant field of the same spec value
+//
+// Unexported fields, fields tagged `variant:"-"` and union.Extras fields are not variants and are not checked,
+// and union.Raw fields declare no variant name of their own.
+// The fields of embedded variant groups are checked as fields of the spec.
+//
+// The analyzer can be run with go vet through the cmd/unionvet command:
+//
+//	go install github.com/eriicafes/union/cmd/unionvet@latest
+//	go vet -vettool=$(which unionvet) ./...
+package unionvet
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/eriicafes/union"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Analyzer reports invalid union spec structs and code that sets multiple variants.
+var Analyzer = &analysis.Analyzer{
+	Name:      "unionvet",
+	Doc:       "report invalid union spec structs and values with multiple variants set",
+	URL:       "https://pkg.go.dev/github.com/eriicafes/union/unionvet",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	Run:       run,
+	FactTypes: []analysis.Fact{new(namingFact)},
+}
+
+// namingFact records the constant Naming returned by a VariantNaming method,
+// so specs declared in other packages are named like in their own package.
+type namingFact struct{ Naming union.Naming }
+
+func (*namingFact) AFact() {}
+
+func (f *namingFact) String() string { return fmt.Sprintf("naming %d", f.Naming) }
+
+// unionPath is the import path of the union package.
+const unionPath = "github.com/eriicafes/union"
+
+// unionTypes are the generic union types whose type argument is a spec.
+var unionTypes = map[string]bool{"TaggedUnion": true, "ExternallyTagged": true, "Union": true}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	insp.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		exportNaming(pass, n.(*ast.FuncDecl))
+	})
+
+	specs := make(map[types.Type]bool)
+	for _, use := range specUses(pass) {
+		if specs[use.typ] {
+			continue
+		}
+		specs[use.typ] = true
+		checkSpec(pass, use.typ, use.pos)
+	}
+
+	insp.Preorder([]ast.Node{(*ast.CompositeLit)(nil)}, func(n ast.Node) {
+		checkCompositeLit(pass, specs, n.(*ast.CompositeLit))
+	})
+	insp.Preorder([]ast.Node{(*ast.BlockStmt)(nil), (*ast.CaseClause)(nil), (*ast.CommClause)(nil)}, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.BlockStmt:
+			checkAssignments(pass, specs, n.List)
+		case *ast.CaseClause:
+			checkAssignments(pass, specs, n.Body)
+		case *ast.CommClause:
+			checkAssignments(pass, specs, n.Body)
+		}
+	})
+	return nil, nil
+}
+
+// specUse records a spec type and where it was first used as a union type argument.
+type specUse struct {
+	typ types.Type
+	pos token.Pos
+}
+
+// specUses returns the spec types used as type arguments of the union types, in source order.
+func specUses(pass *analysis.Pass) []specUse {
+	var uses []specUse
+	for id, inst := range pass.TypesInfo.Instances {
+		obj, ok := pass.TypesInfo.Uses[id].(*types.TypeName)
+		if !ok || obj.Pkg() == nil || obj.Pkg().Path() != unionPath || !unionTypes[obj.Name()] {
+			continue
+		}
+		if inst.TypeArgs.Len() != 1 {
+			continue
+		}
+		typ := inst.TypeArgs.At(0)
+		if _, ok := typ.(*types.TypeParam); ok {
+			continue
+		}
+		uses = append(uses, specUse{typ: typ, pos: id.Pos()})
+	}
+	slices.SortFunc(uses, func(a, b specUse) int { return cmp.Compare(a.pos, b.pos) })
+	return uses
+}
+
+// exportNaming exports a namingFact for decl if it is a VariantNaming method returning a constant.
+func exportNaming(pass *analysis.Pass, decl *ast.FuncDecl) {
+	if decl.Recv == nil || decl.Name.Name != "VariantNaming" || decl.Body == nil || len(decl.Body.List) != 1 {
+		return
+	}
+	ret, ok := decl.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return
+	}
+	value := pass.TypesInfo.Types[ret.Results[0]].Value
+	if value == nil || value.Kind() != constant.Int {
+		return
+	}
+	if n, ok := constant.Int64Val(value); ok {
+		pass.ExportObjectFact(pass.TypesInfo.Defs[decl.Name], &namingFact{Naming: union.Naming(n)})
+	}
+}
+
+// namingOf returns the Naming selected by the spec type typ, reporting false if its
+// VariantNaming method doesn't return a constant.
+func namingOf(pass *analysis.Pass, typ types.Type) (union.Naming, bool) {
+	obj, _, _ := types.LookupFieldOrMethod(typ, false, nil, "VariantNaming")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return union.ExactNaming, true
+	}
+	var fact namingFact
+	if !pass.ImportObjectFact(fn, &fact) {
+		return 0, false
+	}
+	return fact.Naming, true
+}
+
+// checkSpec reports problems with the spec type typ, first used at pos.
+func checkSpec(pass *analysis.Pass, typ types.Type, pos token.Pos) {
+	st, ok := typ.Underlying().(*types.Struct)
+	if !ok {
+		pass.Reportf(pos, "union spec %s is not a struct", typ)
+		return
+	}
+
+	// report at the field when the spec is declared in this package
+	at := func(f *types.Var) token.Pos {
+		if f.Pkg() == pass.Pkg {
+			return f.Pos()
+		}
+		return pos
+	}
+
+	naming, known := namingOf(pass, typ)
+	seen := make(map[string]string)
+	var checkFields func(st *types.Struct)
+	checkFields = func(st *types.Struct) {
+		for i := 0; i < st.NumFields(); i++ {
+			f := st.Field(i)
+			if group, ok := isGroup(st, i); ok {
+				checkFields(group)
+				continue
+			}
+			if skipped(st, i) {
+				continue
+			}
+			// raw fields don't declare a variant name of their own
+			if isRaw(f.Type()) {
+				continue
+			}
+			tag := reflect.StructTag(st.Tag(i))
+			variant := tag.Get("variant")
+			if variant == "" && known {
+				variant = naming.Apply(f.Name())
+			}
+			names := []string{variant}
+			if aliases := tag.Get("variantAliases"); aliases != "" {
+				names = append(names, strings.Split(aliases, ",")...)
+			}
+			for _, name := range names {
+				// untagged fields of specs whose naming is unknown have no known name
+				if name == "" {
+					continue
+				}
+				if prev, ok := seen[name]; ok {
+					pass.Reportf(at(f), "union spec %s: fields %s and %s declare the same variant %q", typ, prev, f.Name(), name)
+				} else {
+					seen[name] = f.Name()
+				}
+			}
+		}
+	}
+	checkFields(st)
+}
+
+// isGroup returns the struct type of field i of the spec struct st if it is an
+// embedded variant group, a non-pointer struct embedded without a `variant` struct tag.
+func isGroup(st *types.Struct, i int) (*types.Struct, bool) {
+	f := st.Field(i)
+	if _, tagged := reflect.StructTag(st.Tag(i)).Lookup("variant"); !f.Embedded() || tagged {
+		return nil, false
+	}
+	group, ok := f.Type().Underlying().(*types.Struct)
+	return group, ok
+}
+
+// skipped reports whether field i of the spec struct st is not a variant,
+// because it is unexported, tagged `variant:"-"` or captures extra envelope members.
+func skipped(st *types.Struct, i int) bool {
+	f := st.Field(i)
+	if named, ok := f.Type().(*types.Named); ok && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == unionPath && named.Obj().Name() == "Extras" {
+		return true
+	}
+	return !f.Exported() || reflect.StructTag(st.Tag(i)).Get("variant") == "-"
+}
+
+// isRaw reports whether typ is union.Raw or a pointer to it.
+func isRaw(typ types.Type) bool {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	named, ok := typ.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == unionPath && named.Obj().Name() == "Raw"
+}
+
+// specField returns the name of the spec field selected by expr and the spec value
+// it belongs to, if expr selects a field of a spec type.
+func specField(pass *analysis.Pass, specs map[types.Type]bool, expr ast.Expr) (field string, base ast.Expr, ok bool) {
+	sel, ok := ast.Unparen(expr).(*ast.SelectorExpr)
+	if !ok {
+		return "", nil, false
+	}
+	selection, ok := pass.TypesInfo.Selections[sel]
+	if !ok || selection.Kind() != types.FieldVal || len(selection.Index()) != 1 {
+		return "", nil, false
+	}
+	recv := selection.Recv()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	if !specs[recv] {
+		return "", nil, false
+	}
+	if st, ok := recv.Underlying().(*types.Struct); !ok || skipped(st, selection.Index()[0]) {
+		return "", nil, false
+	}
+	return sel.Sel.Name, sel.X, true
+}
+
+// isNil reports whether expr is the predeclared nil.
+func isNil(pass *analysis.Pass, expr ast.Expr) bool {
+	return pass.TypesInfo.Types[expr].IsNil()
+}
+
+// checkCompositeLit reports spec composite literals that set more than one variant field.
+func checkCompositeLit(pass *analysis.Pass, specs map[types.Type]bool, lit *ast.CompositeLit) {
+	typ := pass.TypesInfo.TypeOf(lit)
+	if typ == nil || !specs[typ] {
+		return
+	}
+
+	st, ok := typ.Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+	var set []string
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok || isNil(pass, kv.Value) {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		for i := 0; i < st.NumFields(); i++ {
+			if _, group := isGroup(st, i); st.Field(i).Name() == key.Name && !skipped(st, i) && !group {
+				set = append(set, key.Name)
+			}
+		}
+	}
+	if len(set) > 1 {
+		pass.Reportf(lit.Pos(), "union spec %s literal sets multiple variants: %v", typ, set)
+	}
+}
+
+// checkAssignments reports consecutive statements that assign different variant
+// fields of the same spec value, leaving multiple variants set.
+func checkAssignments(pass *analysis.Pass, specs map[types.Type]bool, stmts []ast.Stmt) {
+	set := make(map[string]string) // spec value expression to the variant field last assigned
+
+	for _, stmt := range stmts {
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok || assign.Tok != token.ASSIGN || len(assign.Lhs) != len(assign.Rhs) {
+			clear(set)
+			continue
+		}
+
+		matched := false
+		for i, lhs := range assign.Lhs {
+			field, base, ok := specField(pass, specs, lhs)
+			if !ok {
+				continue
+			}
+			matched = true
+			key := types.ExprString(base)
+			if isNil(pass, assign.Rhs[i]) {
+				if set[key] == field {
+					delete(set, key)
+				}
+				continue
+			}
+			if prev, ok := set[key]; ok && prev != field {
+				pass.Reportf(assign.Pos(), "%s.%s is set after %s.%s, leaving multiple variants set", key, field, key, prev)
+			}
+			set[key] = field
+		}
+		if !matched {
+			clear(set)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package union
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// upcaster is an upcast function registered with RegisterUpcast, with its types erased.
+type upcaster struct {
+	from   reflect.Type
+	to     string
+	upcast func(reflect.Value) (reflect.Value, error)
+}
+
+// variantKey identifies a variant name of a Spec type.
+type variantKey struct {
+	spec    reflect.Type
+	variant string
+}
+
+// upcasts maps Spec types and variant names to their registered upcaster.
+var upcasts sync.Map // map[variantKey]*upcaster
+
+// RegisterUpcast registers a function migrating payloads of the old variant named from
+// to the variant named to, for schema evolution of long-lived data such as event stores.
+// Versions are usually part of the variant names, with the spec declaring only the
+// newest version of each variant:
+//
+//	type Shape struct {
+//		Circle *CircleV2 `variant:"circle@v2"`
+//	}
+//
+//	func init() {
+//		union.RegisterUpcast[Shape]("circle@v1", "circle@v2", func(c CircleV1) (CircleV2, error) {
+//			return CircleV2{Radius: c.Diameter / 2}, nil
+//		})
+//	}
+//
+// TaggedUnion and ExternallyTagged decode a variant with an upcaster that is not declared
+// by the spec into a From value, then upcast it until a declared variant is reached, so
+// upcasters chain: with circle@v1 upcast to circle@v2 and circle@v2 to circle@v3, data
+// written as circle@v1 decodes into the circle@v3 field. The union then holds the newest
+// variant and marshals under its name. Payloads upcast to the declared variant must
+// match its field type, as Set requires.
+//
+// Variants declared by spec fields are decoded without upcasting. Registering a variant
+// again replaces its upcaster. RegisterUpcast panics if from and to are the same variant,
+// and is typically called from an init function.
+func RegisterUpcast[Spec, From, To any](from, to string, upcast func(From) (To, error)) {
+	if from == to {
+		panic(fmt.Sprintf("union: RegisterUpcast: variant %q upcast to itself", from))
+	}
+	upcasts.Store(variantKey{reflect.TypeFor[Spec](), from}, &upcaster{
+		from: reflect.TypeFor[From](),
+		to:   to,
+		upcast: func(v reflect.Value) (reflect.Value, error) {
+			out, err := upcast(v.Interface().(From))
+			return reflect.ValueOf(&out).Elem(), err
+		},
+	})
+}
+
+// upcasterOf returns the upcaster registered for the variant of the Spec type t, or nil.
+func upcasterOf(t reflect.Type, variant string) *upcaster {
+	if u, ok := upcasts.Load(variantKey{t, variant}); ok {
+		return u.(*upcaster)
+	}
+	return nil
+}
+
+// hasUpcasts reports whether upcasters are registered for the spec with RegisterUpcast.
+func (p *specPlan) hasUpcasts() bool {
+	found := false
+	upcasts.Range(func(key, _ any) bool {
+		found = key.(variantKey).spec == p.typ
+		return !found
+	})
+	return found
+}
+
+// setUpcast decodes data into the payload type of the variant's upcaster and upcasts it
+// to a variant declared by the spec value v, storing it in that variant's field. It
+// returns the field, and reports false if the variant has no upcaster. A nil data
+// decodes into the zero payload.
+func (p *specPlan) setUpcast(lib JSONLibrary, v reflect.Value, variant string, data json.RawMessage, opts decodeOptions) (*fieldPlan, bool, error) {
+	up := upcasterOf(p.typ, variant)
+	if up == nil {
+		return nil, false, nil
+	}
+
+	value := zeroImpl(up.from)
+	if data != nil {
+		decoded, err := decodeType(lib, up.from, data, opts)
+		if err != nil {
+			return nil, true, &DecodeError{Spec: p.typ, Variant: variant, Err: err}
+		}
+		value = decoded.Elem()
+	}
+
+	seen := map[string]bool{variant: true}
+	for {
+		out, err := up.upcast(value)
+		if err != nil {
+			return nil, true, &DecodeError{Spec: p.typ, Variant: variant, Err: fmt.Errorf("upcast to %s: %w", up.to, err)}
+		}
+		if f, err := p.lookup(up.to); err == nil {
+			if !f.accepts(out.Type()) || (out.Kind() == reflect.Pointer && out.IsNil()) {
+				return nil, true, &DecodeError{Spec: p.typ, Variant: variant, Field: f.name,
+					Err: fmt.Errorf("upcast to %s returned %v, not a value of %v", up.to, out.Type(), f.typ)}
+			}
+			p.noteDeprecated(nil, variant)
+			v.FieldByIndex(f.index).Set(adapt(f, out))
+			return f, true, nil
+		}
+
+		next := upcasterOf(p.typ, up.to)
+		if next == nil || seen[up.to] {
+			return nil, true, &UnknownVariantError{Spec: p.typ, Variant: up.to, Known: p.knownVariants()}
+		}
+		if !out.Type().AssignableTo(next.from) {
+			return nil, true, &DecodeError{Spec: p.typ, Variant: variant,
+				Err: fmt.Errorf("upcast to %s returned %v, not a value of %v", up.to, out.Type(), next.from)}
+		}
+		seen[up.to] = true
+		value, up = out, next
+	}
+}
+
+// upcastTarget returns the field of the declared variant the upcasters of the variant lead
+// to, without decoding a payload. It reports false if the variant has no upcaster.
+func (p *specPlan) upcastTarget(variant string) (*fieldPlan, bool, error) {
+	up := upcasterOf(p.typ, variant)
+	if up == nil {
+		return nil, false, nil
+	}
+	seen := map[string]bool{variant: true}
+	for {
+		if f, err := p.lookup(up.to); err == nil {
+			return f, true, nil
+		}
+		next := upcasterOf(p.typ, up.to)
+		if next == nil || seen[up.to] {
+			return nil, true, &UnknownVariantError{Spec: p.typ, Variant: up.to, Known: p.knownVariants()}
+		}
+		seen[up.to] = true
+		up = next
+	}
+}
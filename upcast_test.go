@@ -0,0 +1,128 @@
+package union
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type (
+	CircleV1 struct {
+		Diameter float64 `json:"diameter"`
+	}
+	CircleV2 struct {
+		Radius float64 `json:"radius"`
+	}
+	CircleV3 struct {
+		Radius float64 `json:"radius"`
+		Unit   string  `json:"unit"`
+	}
+	VersionedShape struct {
+		Circle *CircleV3 `variant:"circle@v3"`
+		Square *Square   `variant:"square"`
+	}
+)
+
+func init() {
+	RegisterUpcast[VersionedShape]("circle@v1", "circle@v2", func(c CircleV1) (CircleV2, error) {
+		if c.Diameter < 0 {
+			return CircleV2{}, errors.New("negative diameter")
+		}
+		return CircleV2{Radius: c.Diameter / 2}, nil
+	})
+	RegisterUpcast[VersionedShape]("circle@v2", "circle@v3", func(c CircleV2) (*CircleV3, error) {
+		return &CircleV3{Radius: c.Radius, Unit: "cm"}, nil
+	})
+	RegisterUpcast[VersionedShape]("circle@v0", "circle@v9", func(c CircleV1) (CircleV1, error) {
+		return c, nil
+	})
+}
+
+func TestRegisterUpcast(t *testing.T) {
+	tests := []struct {
+		name     string
+		jsonData string
+		expected CircleV3
+	}{
+		{"declared variant", `{"type":"circle@v3","value":{"radius":1,"unit":"mm"}}`, CircleV3{Radius: 1, Unit: "mm"}},
+		{"single upcast", `{"type":"circle@v2","value":{"radius":2}}`, CircleV3{Radius: 2, Unit: "cm"}},
+		{"chained upcasts", `{"type":"circle@v1","value":{"diameter":6}}`, CircleV3{Radius: 3, Unit: "cm"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var u TaggedUnion[VersionedShape]
+			if err := json.Unmarshal([]byte(tt.jsonData), &u); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := u.Value.Circle; got == nil || *got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+			if variant, _ := u.Variant(); variant != "circle@v3" {
+				t.Errorf("expected variant circle@v3, got %s", variant)
+			}
+		})
+	}
+
+	t.Run("externally tagged", func(t *testing.T) {
+		var u ExternallyTagged[VersionedShape]
+		if err := json.Unmarshal([]byte(`{"circle@v1":{"diameter":2}}`), &u); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := u.Value.Circle; got == nil || *got != (CircleV3{Radius: 1, Unit: "cm"}) {
+			t.Errorf("expected %v, got %v", CircleV3{Radius: 1, Unit: "cm"}, got)
+		}
+	})
+
+	t.Run("decode map", func(t *testing.T) {
+		u, err := DecodeMap[VersionedShape](map[string]any{"type": "circle@v2", "value": map[string]any{"radius": 4}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := u.Value.Circle; got == nil || *got != (CircleV3{Radius: 4, Unit: "cm"}) {
+			t.Errorf("expected %v, got %v", CircleV3{Radius: 4, Unit: "cm"}, got)
+		}
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		var l Lazy[VersionedShape]
+		if err := json.Unmarshal([]byte(`{"type":"circle@v1","value":{"diameter":2}}`), &l); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if variant, _ := l.Variant(); variant != "circle@v3" {
+			t.Errorf("expected variant circle@v3, got %s", variant)
+		}
+		if got, ok := As[CircleV3](l); !ok || got != (CircleV3{Radius: 1, Unit: "cm"}) {
+			t.Errorf("expected %v, got %v", CircleV3{Radius: 1, Unit: "cm"}, got)
+		}
+
+		if err := json.Unmarshal([]byte(`{"type":"circle@v0","value":{}}`), &l); !errors.Is(err, ErrUnknownVariant) {
+			t.Errorf("expected ErrUnknownVariant, got %v", err)
+		}
+	})
+
+	t.Run("upcast error", func(t *testing.T) {
+		var u TaggedUnion[VersionedShape]
+		err := json.Unmarshal([]byte(`{"type":"circle@v1","value":{"diameter":-1}}`), &u)
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Variant != "circle@v1" {
+			t.Errorf("expected *DecodeError for circle@v1, got %v", err)
+		}
+	})
+
+	t.Run("upcast to undeclared variant", func(t *testing.T) {
+		var u TaggedUnion[VersionedShape]
+		err := json.Unmarshal([]byte(`{"type":"circle@v0","value":{}}`), &u)
+		if !errors.Is(err, ErrUnknownVariant) {
+			t.Errorf("expected ErrUnknownVariant, got %v", err)
+		}
+	})
+
+	t.Run("same variant panics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic")
+			}
+		}()
+		RegisterUpcast[VersionedShape]("circle@v3", "circle@v3", func(c CircleV3) (CircleV3, error) { return c, nil })
+	})
+}
@@ -0,0 +1,53 @@
+package union
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Validate reports whether the union holds exactly one variant, without marshaling it.
+// Unlike GetValue, which returns nil for both states, it tells an unset union from a
+// corrupt one:
+//   - ErrZeroVariants if no variant is set or selected
+//   - ErrMultipleVariants, naming the fields that are set, if more than one variant is set
+//   - ErrSpecNotStruct if the Spec type is not a struct
+//
+// An empty union of a spec returning true from JSONNullable is valid, since it marshals to null.
+func (u TaggedUnion[Spec]) Validate() error {
+	return validateSpec(reflect.ValueOf(u.Value), u.selected)
+}
+
+// Validate reports whether the union holds exactly one variant. See TaggedUnion.Validate.
+func (u ExternallyTagged[Spec]) Validate() error {
+	return validateSpec(reflect.ValueOf(u.Value), u.selected)
+}
+
+// Validate reports whether the union holds exactly one variant. See TaggedUnion.Validate.
+func (u Union[Spec]) Validate() error {
+	return validateSpec(reflect.ValueOf(u.Value), u.selected)
+}
+
+// validateSpec implements Validate for the spec value v with the selected field.
+func validateSpec(v reflect.Value, selected *fieldPlan) error {
+	p := planOf(v.Type())
+	_, err := p.current(v, selected)
+	switch err {
+	case nil:
+		return nil
+	case ErrZeroVariants:
+		if isNullable(v.Interface()) {
+			return nil
+		}
+		return fmt.Errorf("%w: none of %s is set", ErrZeroVariants, p.typ)
+	case ErrMultipleVariants:
+		var set []string
+		for _, f := range p.fields {
+			if !f.isZero(v.FieldByIndex(f.index)) {
+				set = append(set, f.name)
+			}
+		}
+		return fmt.Errorf("%w: fields %s of %s are set", ErrMultipleVariants, strings.Join(set, ", "), p.typ)
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+package union
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  Shape
+		err    error
+		fields string
+	}{
+		{"one variant", Shape{Circle: &Circle{Radius: 1}}, nil, ""},
+		{"zero variants", Shape{}, ErrZeroVariants, ""},
+		{"multiple variants", Shape{Circle: &Circle{}, Triangle: &Triangle{}}, ErrMultipleVariants, "Circle, Triangle"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := []error{
+				TaggedUnion[Shape]{Value: tt.value}.Validate(),
+				ExternallyTagged[Shape]{Value: tt.value}.Validate(),
+				Union[Shape]{Value: tt.value}.Validate(),
+			}
+			for _, err := range errs {
+				if !errors.Is(err, tt.err) || (tt.err == nil) != (err == nil) {
+					t.Errorf("expected error %v, got %v", tt.err, err)
+				}
+				if err != nil && !strings.Contains(err.Error(), tt.fields) {
+					t.Errorf("expected error naming %s, got %v", tt.fields, err)
+				}
+			}
+		})
+	}
+
+	t.Run("selected zero payload", func(t *testing.T) {
+		var u TaggedUnion[Shape]
+		if err := u.Select("circle"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := u.Validate(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("spec not struct", func(t *testing.T) {
+		if err := (TaggedUnion[int]{}).Validate(); !errors.Is(err, ErrSpecNotStruct) {
+			t.Errorf("expected ErrSpecNotStruct, got %v", err)
+		}
+	})
+}
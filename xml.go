@@ -0,0 +1,113 @@
+package union
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// xmlAttr returns the name of the XML attribute holding the variant name.
+// It checks if the Spec type implements XMLDiscriminator() string, otherwise
+// returns "" to use the variant name as the element name.
+func (u *TaggedUnion[Spec]) xmlAttr() string {
+	if tx, ok := any(u.Value).(interface{ XMLDiscriminator() string }); ok {
+		return tx.XMLDiscriminator()
+	}
+	return ""
+}
+
+// MarshalXML implements the xml.Marshaler interface.
+// By default it serializes the active variant as an element named after the
+// variant, replacing the union's own element:
+//
+//	<circle><radius>5</radius></circle>
+//
+// If the Spec type implements XMLDiscriminator() string, the variant is
+// serialized in the union's own element with the variant name in the returned attribute:
+//
+//	<shape type="circle"><radius>5</radius></shape>
+//
+// Returns an error if:
+//   - The Spec type is not a struct
+//   - No fields are set (zero state)
+//   - Multiple fields are set (invalid state)
+func (u TaggedUnion[Spec]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	v := reflect.ValueOf(u.Value)
+	f, err := planOf(v.Type()).current(v, u.selected)
+	if err != nil {
+		return err
+	}
+	value, err := beforeMarshal(v.FieldByIndex(f.index).Interface())
+	if err != nil {
+		return err
+	}
+
+	attr := u.xmlAttr()
+	if attr == "" {
+		return e.EncodeElement(value, xml.StartElement{Name: xml.Name{Local: f.variant}})
+	}
+
+	// encoding/xml names top-level values after their Go type, use the spec's name instead
+	if start.Name.Local == reflect.TypeOf(u).Name() {
+		start.Name.Local = v.Type().Name()
+	}
+	start.Attr = append(slices.Clip(start.Attr), xml.Attr{Name: xml.Name{Local: attr}, Value: f.variant})
+	return e.EncodeElement(value, start)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+// It deserializes the element into the union by reading the variant name from
+// the element name, or from the attribute returned by XMLDiscriminator() string
+// if the Spec type implements it, and decoding the element into the corresponding struct field.
+//
+// When the union is a field of a parent struct in the element name representation,
+// tag the field with `xml:",any"` so elements of any variant name are decoded into it.
+//
+// Returns an error if:
+//   - The XML data is malformed
+//   - The Spec type is not a struct
+//   - The variant attribute is missing
+//   - The variant doesn't match any known variant (*UnknownVariantError)
+//   - Multiple struct fields match the same variant (invalid Spec definition)
+//   - The element cannot be unmarshaled into the target field type (*DecodeError)
+func (u *TaggedUnion[Spec]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var zero Spec
+	u.Value = zero
+	u.selected = nil
+
+	v := reflect.ValueOf(&u.Value).Elem()
+	t := v.Type()
+	p := planOf(t)
+
+	if !p.isStruct {
+		return ErrSpecNotStruct
+	}
+
+	variant := start.Name.Local
+	if attr := u.xmlAttr(); attr != "" {
+		i := slices.IndexFunc(start.Attr, func(a xml.Attr) bool { return a.Name.Local == attr })
+		if i < 0 {
+			return fmt.Errorf("%w: %s", ErrMissingVariantField, attr)
+		}
+		variant = start.Attr[i].Value
+		start.Attr = slices.Delete(slices.Clone(start.Attr), i, i+1)
+	}
+
+	f, err := p.resolve(variant)
+	if err != nil {
+		return err
+	}
+
+	target := reflect.New(f.typ)
+	if err := d.DecodeElement(target.Interface(), &start); err != nil {
+		return &DecodeError{Spec: t, Variant: variant, Field: f.name, Err: err}
+	}
+	if err := afterUnmarshal(target); err != nil {
+		return &DecodeError{Spec: t, Variant: variant, Field: f.name, Err: err}
+	}
+
+	v.FieldByIndex(f.index).Set(target.Elem())
+	u.selected = selection(v, f)
+	return nil
+}
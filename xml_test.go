@@ -0,0 +1,152 @@
+package union
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type XMLCircle struct {
+	Radius float64 `xml:"radius"`
+}
+
+type XMLRectangle struct {
+	Width  float64 `xml:"width"`
+	Height float64 `xml:"height"`
+}
+
+type XMLShape struct {
+	Circle    *XMLCircle    `variant:"circle"`
+	Rectangle *XMLRectangle `variant:"rectangle"`
+}
+
+type XMLAttrShape struct {
+	Circle    *XMLCircle    `variant:"circle"`
+	Rectangle *XMLRectangle `variant:"rectangle"`
+}
+
+func (s XMLAttrShape) XMLDiscriminator() string { return "type" }
+
+type XMLDrawing struct {
+	XMLName xml.Name                  `xml:"drawing"`
+	Shape   TaggedUnion[XMLShape]     `xml:",any"`
+	Border  TaggedUnion[XMLAttrShape] `xml:"border"`
+}
+
+func TestMarshalXML(t *testing.T) {
+	tests := []struct {
+		name        string
+		shape       any
+		expected    string
+		expectErr   bool
+		expectedErr string
+	}{
+		{
+			name:     "marshals variant as element name",
+			shape:    TaggedUnion[XMLShape]{Value: XMLShape{Circle: &XMLCircle{Radius: 5}}},
+			expected: `<circle><radius>5</radius></circle>`,
+		},
+		{
+			name:     "marshals variant as attribute",
+			shape:    TaggedUnion[XMLAttrShape]{Value: XMLAttrShape{Rectangle: &XMLRectangle{Width: 10, Height: 5}}},
+			expected: `<XMLAttrShape type="rectangle"><width>10</width><height>5</height></XMLAttrShape>`,
+		},
+		{
+			name: "marshals unions in parent struct",
+			shape: XMLDrawing{
+				Shape:  TaggedUnion[XMLShape]{Value: XMLShape{Circle: &XMLCircle{Radius: 5}}},
+				Border: TaggedUnion[XMLAttrShape]{Value: XMLAttrShape{Circle: &XMLCircle{Radius: 6}}},
+			},
+			expected: `<drawing><circle><radius>5</radius></circle><border type="circle"><radius>6</radius></border></drawing>`,
+		},
+		{
+			name:        "returns error when no variant is set",
+			shape:       TaggedUnion[XMLShape]{},
+			expectErr:   true,
+			expectedErr: "zero variants set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := xml.Marshal(tt.shape)
+
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				if tt.expectedErr != "" && !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Errorf("expected error '%s', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(data) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, string(data))
+			}
+		})
+	}
+}
+
+func TestUnmarshalXML(t *testing.T) {
+	t.Run("unmarshals variant from element name", func(t *testing.T) {
+		var shape TaggedUnion[XMLShape]
+		if err := xml.Unmarshal([]byte(`<rectangle><width>10</width><height>5</height></rectangle>`), &shape); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if r := shape.Value.Rectangle; r == nil || *r != (XMLRectangle{Width: 10, Height: 5}) {
+			t.Errorf("expected rectangle variant, got %+v", shape.Value)
+		}
+	})
+
+	t.Run("unmarshals variant from attribute", func(t *testing.T) {
+		var shape TaggedUnion[XMLAttrShape]
+		if err := xml.Unmarshal([]byte(`<shape type="circle"><radius>5</radius></shape>`), &shape); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c := shape.Value.Circle; c == nil || c.Radius != 5 {
+			t.Errorf("expected circle variant, got %+v", shape.Value)
+		}
+	})
+
+	t.Run("unmarshals unions in parent struct", func(t *testing.T) {
+		var drawing XMLDrawing
+		data := `<drawing><circle><radius>5</radius></circle><border type="rectangle"><width>1</width><height>2</height></border></drawing>`
+		if err := xml.Unmarshal([]byte(data), &drawing); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c := drawing.Shape.Value.Circle; c == nil || c.Radius != 5 {
+			t.Errorf("expected circle shape, got %+v", drawing.Shape.Value)
+		}
+		if r := drawing.Border.Value.Rectangle; r == nil || *r != (XMLRectangle{Width: 1, Height: 2}) {
+			t.Errorf("expected rectangle border, got %+v", drawing.Border.Value)
+		}
+	})
+
+	t.Run("returns error for unknown variant", func(t *testing.T) {
+		err := xml.Unmarshal([]byte(`<hexagon><sides>6</sides></hexagon>`), &TaggedUnion[XMLShape]{})
+		if !errors.Is(err, ErrUnknownVariant) {
+			t.Errorf("expected error '%v', got '%v'", ErrUnknownVariant, err)
+		}
+	})
+
+	t.Run("returns error for missing variant attribute", func(t *testing.T) {
+		err := xml.Unmarshal([]byte(`<shape><radius>5</radius></shape>`), &TaggedUnion[XMLAttrShape]{})
+		if !errors.Is(err, ErrMissingVariantField) {
+			t.Errorf("expected error '%v', got '%v'", ErrMissingVariantField, err)
+		}
+	})
+
+	t.Run("returns error when element cannot be unmarshaled", func(t *testing.T) {
+		err := xml.Unmarshal([]byte(`<circle><radius>big</radius></circle>`), &TaggedUnion[XMLShape]{})
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Variant != "circle" {
+			t.Errorf("expected *DecodeError for circle, got '%v'", err)
+		}
+	})
+}